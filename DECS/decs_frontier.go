@@ -1,7 +1,6 @@
 package decs
 
 import (
-	"encoding/binary"
 	"errors"
 	"sort"
 )
@@ -167,8 +166,7 @@ func EnsureMerkleDecoded(op *DECSOpening) error {
 		return errors.New("decs: missing frontier depth")
 	}
 	numLeaves := op.EntryCount()
-	totalBits := numLeaves * depth
-	if len(op.FrontierProof) < (totalBits+7)/8 {
+	if numLeaves < 0 || numLeaves > (len(op.FrontierProof)*8)/depth {
 		return errors.New("decs: truncated frontier proof bitmap")
 	}
 
@@ -363,32 +361,27 @@ func computeLeafHash(op *DECSOpening, leafIdx int) ([16]byte, error) {
 	if nonceBytes <= 0 && len(op.Nonces) > 0 && len(op.Nonces[0]) > 0 {
 		nonceBytes = len(op.Nonces[0])
 	}
-	buf := make([]byte, 4*(r+eta)+2+nonceBytes)
-	off := 0
-	for j := 0; j < r; j++ {
-		val := GetOpeningPval(op, leafIdx, j)
-		binary.LittleEndian.PutUint32(buf[off:], uint32(val))
-		off += 4
-	}
-	for k := 0; k < eta; k++ {
-		val := GetOpeningMval(op, leafIdx, k)
-		binary.LittleEndian.PutUint32(buf[off:], uint32(val))
-		off += 4
-	}
 	idx := op.IndexAt(leafIdx)
 	if idx < 0 {
 		return out, errors.New("decs: invalid index in opening")
 	}
-	binary.LittleEndian.PutUint16(buf[off:], uint16(idx))
-	off += 2
+	pv := make([]uint64, r)
+	for j := range pv {
+		pv[j] = GetOpeningPval(op, leafIdx, j)
+	}
+	mv := make([]uint64, eta)
+	for k := range mv {
+		mv[k] = GetOpeningMval(op, leafIdx, k)
+	}
+	nonce := make([]byte, nonceBytes)
 	if nonceBytes > 0 {
 		if len(op.Nonces) > leafIdx && len(op.Nonces[leafIdx]) >= nonceBytes {
-			copy(buf[off:], op.Nonces[leafIdx][:nonceBytes])
+			copy(nonce, op.Nonces[leafIdx][:nonceBytes])
 		} else if len(op.NonceSeed) > 0 {
-			rho := deriveNonce(op.NonceSeed, idx, nonceBytes)
-			copy(buf[off:], rho)
+			copy(nonce, deriveNonce(op.NonceSeed, idx, nonceBytes))
 		}
 	}
+	buf := EncodeLeaf(pv, mv, idx, nonce, op.leafResidueBytes())
 	withPrefix := make([]byte, 1+len(buf))
 	withPrefix[0] = leafPrefix
 	copy(withPrefix[1:], buf)
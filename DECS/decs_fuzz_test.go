@@ -0,0 +1,206 @@
+package decs
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+	"github.com/tuneinsight/lattigo/v4/utils"
+)
+
+// fuzzOpening is one honest DECS commitment and its opening on fuzzE; the
+// fuzz targets below seed their corpora from it.
+type fuzzOpening struct {
+	ringQ    *ring.Ring
+	params   Params
+	root     [16]byte
+	gamma    [][]uint64
+	rPolys   []*ring.Poly
+	evals    [][]uint64 // evals[j][i] = P_j at NTT slot i
+	open     *DECSOpening
+	unpacked *DECSOpening
+}
+
+var fuzzE = []int{1, 7, 42, 200}
+
+var (
+	fuzzOpeningOnce sync.Once
+	fuzzOpeningVal  *fuzzOpening
+)
+
+func loadFuzzOpening(tb testing.TB) *fuzzOpening {
+	tb.Helper()
+	fuzzOpeningOnce.Do(func() {
+		ringQ, err := ring.NewRing(1<<8, []uint64{1<<32 - (1 << 20) + 1})
+		if err != nil {
+			return
+		}
+		params := testParams(ringQ, 2, 0)
+		Ps := make([]*ring.Poly, 3)
+		prng, _ := utils.NewPRNG()
+		us := ring.NewUniformSampler(prng, ringQ)
+		evals := make([][]uint64, len(Ps))
+		for j := range Ps {
+			Ps[j] = ringQ.NewPoly()
+			us.Read(Ps[j])
+			e := ringQ.NewPoly()
+			ringQ.NTT(Ps[j], e)
+			evals[j] = e.Coeffs[0]
+		}
+		prover := NewProverWithParams(ringQ, Ps, params)
+		root, err := prover.CommitInit()
+		if err != nil {
+			return
+		}
+		gamma := NewVerifierWithParams(ringQ, len(Ps), params).DeriveGamma(root)
+		rPolys := prover.CommitStep2(gamma)
+		open := prover.EvalOpen(fuzzE)
+		unpacked := cloneOpening(open)
+		PackOpening(open)
+		fuzzOpeningVal = &fuzzOpening{ringQ, params, root, gamma, rPolys, evals, open, unpacked}
+	})
+	if fuzzOpeningVal == nil {
+		tb.Fatal("fuzz fixture: DECS commitment failed")
+	}
+	return fuzzOpeningVal
+}
+
+func cloneOpening(op *DECSOpening) *DECSOpening {
+	raw, _ := json.Marshal(op)
+	var out DECSOpening
+	_ = json.Unmarshal(raw, &out)
+	return &out
+}
+
+func FuzzUnpackUintMatrix(f *testing.F) {
+	fx := loadFuzzOpening(f)
+	for _, mat := range [][][]uint64{fx.unpacked.Pvals, fx.unpacked.Mvals, {{0}}, {{1 << 63, 1}, {5}}} {
+		packed, _, _, _ := PackUintMatrix(mat)
+		f.Add(packed)
+	}
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 64, 0})
+	f.Fuzz(func(t *testing.T, bits []byte) {
+		mat, rows, cols, width, err := UnpackUintMatrix(bits)
+		if err != nil {
+			return
+		}
+		if len(mat) != rows || rows > 0 && len(mat[rows-1]) != cols {
+			t.Fatalf("decoded %d rows, header says %d×%d", len(mat), rows, cols)
+		}
+		if rows == 0 || cols == 0 {
+			return
+		}
+		repacked, _, _, _ := PackUintMatrixWithWidth(mat, width)
+		back, _, _, _, err := UnpackUintMatrix(repacked)
+		if err != nil {
+			t.Fatalf("re-unpack: %v", err)
+		}
+		for i := range mat {
+			for j := range mat[i] {
+				if back[i][j] != mat[i][j] {
+					t.Fatalf("round trip changed [%d][%d]: %d -> %d", i, j, mat[i][j], back[i][j])
+				}
+			}
+		}
+	})
+}
+
+func FuzzUnpackPathMatrix(f *testing.F) {
+	fx := loadFuzzOpening(f)
+	f.Add(fx.open.FrontierRefsBits, 1, fx.open.FrontierRefCount, int(fx.open.FrontierRefWidth))
+	f.Add(packPathMatrix([][]int{{1, 2, 3}, {4, 5, 6}}, 3, 3), 2, 3, 3)
+	f.Add([]byte{0xff}, 1<<62, 1<<62, 64)
+	f.Fuzz(func(t *testing.T, bits []byte, rows, depth, width int) {
+		mat, err := unpackPathMatrix(bits, rows, depth, width)
+		if err != nil || mat == nil {
+			return
+		}
+		if len(mat) != rows || len(mat[0]) != depth {
+			t.Fatalf("decoded %d×%d, want %d×%d", len(mat), len(mat[0]), rows, depth)
+		}
+		for r := range mat {
+			row, err := unpackPathRow(bits, r, rows, depth, width)
+			if err != nil {
+				t.Fatalf("unpackPathRow(%d): %v", r, err)
+			}
+			for c := range row {
+				if row[c] != mat[r][c] || row[c] < 0 {
+					t.Fatalf("row %d col %d: matrix %d, row %d", r, c, mat[r][c], row[c])
+				}
+			}
+		}
+	})
+}
+
+func FuzzUnpackIndexAt(f *testing.F) {
+	fx := loadFuzzOpening(f)
+	f.Add(fx.open.IndexBits, 0)
+	f.Add(packIndexBits13([]int{1, 8191, 4096}), 2)
+	f.Add([]byte{0xff, 0xff}, 1<<62)
+	f.Fuzz(func(t *testing.T, bits []byte, pos int) {
+		v, ok := unpackIndexAt(bits, pos)
+		if !ok {
+			return
+		}
+		if v < 0 || v > indexBitsMask {
+			t.Fatalf("index %d outside %d bits", v, indexBitsPerValue)
+		}
+	})
+}
+
+// FuzzEnsureMerkleDecoded feeds JSON-encoded openings through the frontier
+// decoder and the evaluation check. Decoding must never panic, and an opening
+// that still verifies must open the committed values on fuzzE.
+func FuzzEnsureMerkleDecoded(f *testing.F) {
+	fx := loadFuzzOpening(f)
+	for _, op := range []*DECSOpening{fx.open, fx.unpacked} {
+		raw, err := json.Marshal(op)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(raw)
+	}
+	v := NewVerifierWithParams(fx.ringQ, len(fx.evals), fx.params)
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		var op DECSOpening
+		if err := json.Unmarshal(raw, &op); err != nil {
+			return
+		}
+		if err := EnsureMerkleDecoded(&op); err != nil {
+			return
+		}
+		n := op.EntryCount()
+		if n != len(fuzzE) {
+			return
+		}
+		for i := 0; i < n; i++ {
+			op.IndexAt(i)
+		}
+		if op.Pvals == nil {
+			op.Pvals = make([][]uint64, n)
+			op.Mvals = make([][]uint64, n)
+			for i := 0; i < n; i++ {
+				op.Pvals[i] = make([]uint64, len(fx.evals))
+				for j := range op.Pvals[i] {
+					op.Pvals[i][j] = GetOpeningPval(&op, i, j)
+				}
+				op.Mvals[i] = make([]uint64, fx.params.Eta)
+				for k := range op.Mvals[i] {
+					op.Mvals[i][k] = GetOpeningMval(&op, i, k)
+				}
+			}
+		}
+		if v.CheckEvalAt(fx.root, fx.gamma, fx.rPolys, &op, fuzzE) != nil {
+			return
+		}
+		for i := 0; i < n; i++ {
+			idx := op.IndexAt(i)
+			for j, ev := range fx.evals {
+				if op.Pvals[i][j] != ev[idx] {
+					t.Fatalf("accepted opening claims P_%d(ω_%d)=%d, committed %d", j, idx, op.Pvals[i][j], ev[idx])
+				}
+			}
+		}
+	})
+}
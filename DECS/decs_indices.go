@@ -108,7 +108,7 @@ func packIndexBits13(values []int) []byte {
 }
 
 func unpackIndexAt(bits []byte, pos int) (int, bool) {
-	if pos < 0 {
+	if pos < 0 || pos > len(bits)*8/indexBitsPerValue {
 		return 0, false
 	}
 	bitPos := pos * indexBitsPerValue
@@ -45,15 +45,13 @@ func packPathMatrix(rows [][]int, depth, width int) []byte {
 }
 
 func unpackPathMatrix(bits []byte, rows, depth, width int) ([][]int, error) {
-	if rows < 0 || depth < 0 || width <= 0 {
+	if rows < 0 || depth < 0 || width <= 0 || width > 32 {
 		return nil, errors.New("invalid path matrix parameters")
 	}
 	if rows == 0 || depth == 0 {
 		return nil, nil
 	}
-	totalValues := rows * depth
-	totalBits := totalValues * width
-	if len(bits)*8 < totalBits {
+	if rows > len(bits)*8/width/depth {
 		return nil, errors.New("truncated path bitstream")
 	}
 	out := make([][]int, rows)
@@ -82,10 +80,10 @@ func unpackPathRow(bits []byte, rowIndex, rows, depth, width int) ([]int, error)
 	if rowIndex < 0 || rowIndex >= rows {
 		return nil, errors.New("row out of range")
 	}
-	if depth <= 0 || width <= 0 {
+	if depth <= 0 || width <= 0 || width > 32 {
 		return nil, errors.New("invalid path row parameters")
 	}
-	if len(bits)*8 < (rowIndex+1)*depth*width {
+	if rowIndex+1 > len(bits)*8/width/depth {
 		return nil, errors.New("truncated path bitstream")
 	}
 	row := make([]int, depth)
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
+	"io"
+	"math/bits"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 	"github.com/tuneinsight/lattigo/v4/utils"
@@ -73,18 +75,43 @@ func NewProverWithParams(ringQ *ring.Ring, P []*ring.Poly, params Params) *Prove
 		panic("decs: invalid NonceBytes (must be > 0)")
 	}
 	if len(ringQ.Modulus) != 1 {
-		panic("decs: only single-modulus rings are supported (len(Modulus) must be 1)")
+		panic("decs: only single-modulus rings are supported (len(Modulus) must be 1); prove RNS rings per limb")
 	}
 	return &Prover{ringQ: ringQ, P: P, params: params}
 }
 
+// coins returns the prover's randomness source.
+func (pr *Prover) coins() io.Reader {
+	if pr.params.Rand == nil {
+		return rand.Reader
+	}
+	return pr.params.Rand
+}
+
+// maskPRNG returns the sampler of the mask polynomials, keyed from
+// params.Rand when it is set.
+func (pr *Prover) maskPRNG() (utils.PRNG, error) {
+	if pr.params.Rand == nil {
+		return utils.NewPRNG()
+	}
+	key := make([]byte, 64)
+	if _, err := io.ReadFull(pr.params.Rand, key); err != nil {
+		return nil, err
+	}
+	prng, err := utils.NewKeyedPRNG(key)
+	if err != nil {
+		return nil, err
+	}
+	return prng, nil
+}
+
 // CommitInit does DECS.Commit step 1: sample M, nonces; build Merkle tree; NTT(P,M).
 func (pr *Prover) CommitInit() ([16]byte, error) {
 	r := len(pr.P)
 	N := pr.ringQ.N
 
 	// sampler
-	prng, err := utils.NewPRNG()
+	prng, err := pr.maskPRNG()
 	if err != nil {
 		return [16]byte{}, err
 	}
@@ -115,29 +142,20 @@ func (pr *Prover) CommitInit() ([16]byte, error) {
 	// 1c) build leaves
 	leaves := make([][]byte, N)
 	pr.nonceSeed = make([]byte, pr.params.NonceBytes)
-	if _, err := rand.Read(pr.nonceSeed); err != nil {
+	if _, err := io.ReadFull(pr.coins(), pr.nonceSeed); err != nil {
 		return [16]byte{}, err
 	}
 	for i := 0; i < N; i++ {
-		// pack P and M evaluations as uint32 (q < 2^32), index as uint16
-		buf := make([]byte, 4*(r+pr.params.Eta)+2+pr.params.NonceBytes)
-		off := 0
+		pv := make([]uint64, r)
 		for j := 0; j < r; j++ {
-			binary.LittleEndian.PutUint32(buf[off:], uint32(pr.Pvals[j].Coeffs[0][i]))
-			off += 4
+			pv[j] = pr.Pvals[j].Coeffs[0][i]
 		}
+		mv := make([]uint64, pr.params.Eta)
 		for k := 0; k < pr.params.Eta; k++ {
-			binary.LittleEndian.PutUint32(buf[off:], uint32(pr.Mvals[k].Coeffs[0][i]))
-			off += 4
+			mv[k] = pr.Mvals[k].Coeffs[0][i]
 		}
-		// index as uint16
-		binary.LittleEndian.PutUint16(buf[off:], uint16(i))
-		off += 2
-		rho := deriveNonce(pr.nonceSeed, i, pr.params.NonceBytes)
-		copy(buf[off:], rho)
-
 		// store the raw buffer; BuildMerkleTree will hash it
-		leaves[i] = append([]byte(nil), buf...)
+		leaves[i] = EncodeLeaf(pv, mv, i, deriveNonce(pr.nonceSeed, i, pr.params.NonceBytes), LeafResidueBytes(pr.ringQ.Modulus[0]))
 	}
 
 	// 1d) Merkle tree
@@ -182,6 +200,9 @@ func (pr *Prover) EvalOpen(E []int) *DECSOpening {
 		NonceSeed:  append([]byte(nil), pr.nonceSeed...),
 		NonceBytes: pr.params.NonceBytes,
 	}
+	if w := ResidueWidth(pr.ringQ.Modulus[0]); w != 20 {
+		open.ResidueBits = w
+	}
 	// Deduplicate sibling nodes across all paths
 	nodeIdx := make(map[string]int)
 	addNode := func(b []byte) int {
@@ -226,7 +247,7 @@ func PackOpening(op *DECSOpening) {
 	if op == nil {
 		return
 	}
-	op.packResidues20()
+	op.packResidues()
 	op.packTailIndices()
 	op.packFrontier()
 	op.packPathIndexBits()
@@ -235,15 +256,27 @@ func PackOpening(op *DECSOpening) {
 	}
 }
 
-// packResidues20 packs Pvals and Mvals into 20-bit streams (row-major: t then j/k).
-func (op *DECSOpening) packResidues20() {
+// packResidues packs Pvals and Mvals into ResidueBits-wide streams (row-major:
+// t then j/k). The width only grows when a residue does not fit, so openings
+// over q < 2^20 keep the original 20-bit encoding.
+func (op *DECSOpening) packResidues() {
+	width := op.residueWidth()
+	if width > 64 {
+		return // malformed; CheckResidueWidth rejects it
+	}
+	if w := bits.Len64(max(maxMatrixValue(op.Pvals), maxMatrixValue(op.Mvals))); w > width {
+		width = w
+	}
+	if width != 20 {
+		op.ResidueBits = width
+	}
 	if len(op.Pvals) > 0 {
 		if op.R <= 0 {
 			if len(op.Pvals) > 0 {
 				op.R = len(op.Pvals[0])
 			}
 		}
-		op.PvalsBits = packU20Mat(op.Pvals, op.R)
+		op.PvalsBits = packResidueMat(op.Pvals, op.R, width)
 		op.Pvals = nil
 	}
 	if len(op.Mvals) > 0 {
@@ -252,40 +285,79 @@ func (op *DECSOpening) packResidues20() {
 				op.Eta = len(op.Mvals[0])
 			}
 		}
-		op.MvalsBits = packU20Mat(op.Mvals, op.Eta)
+		op.MvalsBits = packResidueMat(op.Mvals, op.Eta, width)
 		op.Mvals = nil
 	}
 }
 
-// packU20Mat packs len(rows)×rowLen residues (each <2^20) into a compact bitstream.
-func packU20Mat(rows [][]uint64, rowLen int) []byte {
-	count := len(rows) * rowLen
-	totalBits := count * 20
-	out := make([]byte, (totalBits+7)/8)
-	bitpos := 0
-	for t := 0; t < len(rows); t++ {
-		row := rows[t]
-		for j := 0; j < rowLen; j++ {
-			v := uint32(row[j] & 0xFFFFF)
-			bytePos := bitpos >> 3
-			shift := uint(bitpos & 7)
-			// write 20 bits starting at (bytePos, shift)
-			var tmp uint64 = uint64(v) << shift
-			// write up to 4 bytes
-			for k := 0; k < 4; k++ {
-				b := byte(tmp & 0xFF)
-				if bytePos+k < len(out) {
-					out[bytePos+k] |= b
-				}
-				tmp >>= 8
-				if (shift + 20) <= uint(8*(k+1)) {
-					break
-				}
+// residueWidth returns the packed width of the opening's residues.
+func (op *DECSOpening) residueWidth() int {
+	if op.ResidueBits <= 0 {
+		return 20
+	}
+	return op.ResidueBits
+}
+
+// ResidueWidth returns the packed width of residues mod q: 20 bits up to
+// 2^20 (the NTRU modulus and every legacy proof) and bits.Len64(q−1) above.
+func ResidueWidth(q uint64) int {
+	if q <= 1<<20 {
+		return 20
+	}
+	return bits.Len64(q - 1)
+}
+
+// LeafResidueBytes is the width of one residue in a Merkle leaf: 4 bytes
+// below 2^32 (every proof over the NTRU modulus and RNS limbs) and 8 above.
+func LeafResidueBytes(q uint64) int {
+	if q < 1<<32 {
+		return 4
+	}
+	return 8
+}
+
+// leafResidueBytes is LeafResidueBytes for the modulus the opening was
+// produced under, recovered from its residue width.
+func (op *DECSOpening) leafResidueBytes() int {
+	if op.residueWidth() > 32 {
+		return 8
+	}
+	return 4
+}
+
+// EncodeLeaf serialises the Merkle leaf at idx: the P and M evaluations at
+// width bytes each (see LeafResidueBytes), the index as uint16, then the
+// nonce.
+func EncodeLeaf(pvals, mvals []uint64, idx int, nonce []byte, width int) []byte {
+	buf := make([]byte, width*(len(pvals)+len(mvals))+2+len(nonce))
+	off := 0
+	for _, vals := range [][]uint64{pvals, mvals} {
+		for _, v := range vals {
+			if width == 4 {
+				binary.LittleEndian.PutUint32(buf[off:], uint32(v))
+			} else {
+				binary.LittleEndian.PutUint64(buf[off:], v)
 			}
-			bitpos += 20
+			off += width
 		}
 	}
-	return out
+	binary.LittleEndian.PutUint16(buf[off:], uint16(idx))
+	copy(buf[off+2:], nonce)
+	return buf
+}
+
+// packU20Mat packs len(rows)×rowLen residues (each <2^20) into a compact bitstream.
+func packU20Mat(rows [][]uint64, rowLen int) []byte {
+	return packResidueMat(rows, rowLen, 20)
+}
+
+// packResidueMat packs len(rows)×rowLen residues at width bits each; the
+// layout is the little-endian bitstream of packUintMatrixBody.
+func packResidueMat(rows [][]uint64, rowLen, width int) []byte {
+	if rowLen <= 0 {
+		return []byte{}
+	}
+	return packUintMatrixBody(rows, rowLen, width)
 }
 
 func (op *DECSOpening) packPathIndexBits() {
@@ -2,6 +2,7 @@ package decs
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
 	mrand "math/rand"
 	"testing"
@@ -199,3 +200,154 @@ func Test_mulMod64_Correctness(t *testing.T) {
 		}
 	}
 }
+
+func TestDECS_CheckEvalClassifiesFailures(t *testing.T) {
+	ringQ, err := ring.NewRing(1<<10, []uint64{(1<<32 - (1 << 20) + 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := Params{Degree: int(ringQ.N - 1), Eta: 2, NonceBytes: 16}
+	r := 3
+	Ps := make([]*ring.Poly, r)
+	prng, _ := utils.NewPRNG()
+	us := ring.NewUniformSampler(prng, ringQ)
+	for j := range Ps {
+		Ps[j] = ringQ.NewPoly()
+		us.Read(Ps[j])
+	}
+	prover := NewProverWithParams(ringQ, Ps, params)
+	root, err := prover.CommitInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewVerifierWithParams(ringQ, r, params)
+	Gamma := verifier.DeriveGamma(root)
+	R := prover.CommitStep2(Gamma)
+	if err := verifier.CheckCommit(root, R, Gamma); err != nil {
+		t.Fatalf("CheckCommit: %v", err)
+	}
+	E := []int{3, 17, 200}
+
+	open := prover.EvalOpen(E)
+	open.Mvals[0][0] ^= 1
+	if err := verifier.CheckEvalAt(root, Gamma, R, open, E); !errors.Is(err, ErrMerklePath) {
+		t.Fatalf("expected ErrMerklePath, got %v", err)
+	}
+
+	open = prover.EvalOpen(E)
+	if err := verifier.CheckEvalAt(root, Gamma, R, open, E[:2]); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected ErrMalformedProof, got %v", err)
+	}
+
+	badGamma := [][]uint64{append([]uint64(nil), Gamma[0]...), Gamma[1]}
+	badGamma[0][0] ^= 1
+	if err := verifier.CheckCommit(root, R, badGamma); !errors.Is(err, ErrFSDigest) {
+		t.Fatalf("expected ErrFSDigest, got %v", err)
+	}
+}
+
+// TestDECS_PackedOpeningWideModulus packs an opening over a 32-bit prime and
+// checks that the residues survive the wider packing and the frontier
+// re-derivation still authenticates against the root.
+func TestDECS_PackedOpeningWideModulus(t *testing.T) {
+	N := 1 << 8
+	for _, q := range []uint64{1<<32 - (1 << 20) + 1, ring.GenerateNTTPrimes(61, 2*N, 1)[0]} {
+		ringQ, err := ring.NewRing(N, []uint64{q})
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := testParams(ringQ, 2, 0)
+		r := 3
+		Ps := make([]*ring.Poly, r)
+		prng, _ := utils.NewPRNG()
+		us := ring.NewUniformSampler(prng, ringQ)
+		for j := range Ps {
+			Ps[j] = ringQ.NewPoly()
+			us.Read(Ps[j])
+		}
+		prover := NewProverWithParams(ringQ, Ps, params)
+		root, err := prover.CommitInit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifier := NewVerifierWithParams(ringQ, r, params)
+		Gamma := verifier.DeriveGamma(root)
+		R := prover.CommitStep2(Gamma)
+
+		E := []int{1, 7, 42, 200}
+		open := prover.EvalOpen(E)
+		width := ResidueWidth(q)
+		if got := open.ResidueBits; got != width || width <= 20 {
+			t.Fatalf("q=%d: ResidueBits = %d, want %d", q, got, width)
+		}
+		plainP := append([][]uint64(nil), open.Pvals...)
+		plainM := append([][]uint64(nil), open.Mvals...)
+		PackOpening(open)
+		if open.Pvals != nil || len(open.PvalsBits) != (len(E)*r*width+7)/8 {
+			t.Fatalf("q=%d: opening not packed at %d bits: %d bytes", q, width, len(open.PvalsBits))
+		}
+		for i := range E {
+			for j := 0; j < r; j++ {
+				if got := GetOpeningPval(open, i, j); got != plainP[i][j] {
+					t.Fatalf("q=%d: Pval[%d][%d] = %d, want %d", q, i, j, got, plainP[i][j])
+				}
+			}
+			for k := 0; k < params.Eta; k++ {
+				if got := GetOpeningMval(open, i, k); got != plainM[i][k] {
+					t.Fatalf("q=%d: Mval[%d][%d] = %d, want %d", q, i, k, got, plainM[i][k])
+				}
+			}
+		}
+		if err := EnsureMerkleDecoded(open); err != nil {
+			t.Fatalf("q=%d: EnsureMerkleDecoded: %v", q, err)
+		}
+		open.Pvals, open.Mvals = plainP, plainM
+		if err := verifier.CheckEval(root, Gamma, R, open); err != nil {
+			t.Fatalf("q=%d: CheckEval on re-expanded opening: %v", q, err)
+		}
+		// Any other width is rejected before the streams are read, and a
+		// forged width never indexes past them.
+		for _, bad := range []int{-1, 20, width + 1, 1 << 62} {
+			forged := *open
+			forged.Pvals, forged.Mvals = nil, nil
+			forged.ResidueBits = bad
+			_ = GetOpeningPval(&forged, len(E)-1, r-1)
+			_ = GetOpeningMval(&forged, len(E)-1, params.Eta-1)
+			if err := verifier.CheckEval(root, Gamma, R, &forged); !errors.Is(err, ErrMalformedProof) {
+				t.Fatalf("q=%d: ResidueBits=%d: expected ErrMalformedProof, got %v", q, bad, err)
+			}
+		}
+	}
+}
+
+func TestPackUintMatrixWideWidths(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(3))
+	for _, width := range []int{21, 30, 32, 45, 61, 64} {
+		rows := make([][]uint64, 3)
+		for i := range rows {
+			rows[i] = make([]uint64, 5)
+			for j := range rows[i] {
+				rows[i][j] = rng.Uint64() >> (64 - width)
+			}
+		}
+		rows[0][0] = 1<<(width-1) | 1
+		packed, _, _, got := PackUintMatrix(rows)
+		if got != width {
+			t.Fatalf("selectBitWidth = %d, want %d", got, width)
+		}
+		if size := PackedUintMatrixSize(3, 5, rows[0][0]); size != len(packed) {
+			t.Fatalf("width %d: PackedUintMatrixSize = %d, packed %d bytes", width, size, len(packed))
+		}
+		back, _, _, _, err := UnpackUintMatrix(packed)
+		if err != nil {
+			t.Fatalf("width %d: %v", width, err)
+		}
+		for i := range rows {
+			for j := range rows[i] {
+				if back[i][j] != rows[i][j] {
+					t.Fatalf("width %d: entry (%d,%d) = %d, want %d", width, i, j, back[i][j], rows[i][j])
+				}
+			}
+		}
+	}
+}
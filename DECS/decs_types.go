@@ -1,5 +1,7 @@
 package decs
 
+import "io"
+
 // DECSOpening holds the data sent by the prover in DECS.Eval.
 type DECSOpening struct {
 	// Mask indices form the contiguous range [MaskBase, MaskBase+MaskCount).
@@ -11,11 +13,14 @@ type DECSOpening struct {
 	IndexBits []byte     // packed tail indices (13-bit per entry; optional)
 	Pvals     [][]uint64 // optional: P_j(e) for each e∈E, j∈[0..r)
 	Mvals     [][]uint64 // optional: M_k(e) for each e∈E, k∈[0..η)
-	// Packed residues (20-bit per element); when set, Pvals/Mvals may be nil.
+	// Packed residues (ResidueBits per element); when set, Pvals/Mvals may be nil.
 	PvalsBits []byte
 	MvalsBits []byte
-	R         int // number of P columns (rows committed)
-	Eta       int // number of mask polys (η)
+	// ResidueBits is the packed width of PvalsBits/MvalsBits; 0 means the
+	// original 20-bit encoding. EvalOpen sets it from the modulus.
+	ResidueBits int
+	R           int // number of P columns (rows committed)
+	Eta         int // number of mask polys (η)
 	// Multiproof encoding:
 	// Nodes holds the unique list of sibling hashes used to authenticate all indices.
 	// PathIndex[t][lvl] is an index into Nodes for the sibling at level lvl of leaf t.
@@ -71,7 +76,7 @@ func (op *DECSOpening) AllIndices() []int {
 		return nil
 	}
 	total := op.EntryCount()
-	if total == 0 {
+	if total <= 0 || op.MaskCount < 0 {
 		return nil
 	}
 	out := make([]int, total)
@@ -92,6 +97,9 @@ type Params struct {
 	Degree     int // max degree d ≤ N-1
 	Eta        int // number of mask polynomials η
 	NonceBytes int // size of each nonce ρ_e in bytes
+	// Rand supplies the prover's coins (mask polynomials, nonce seed and the
+	// LVCS row tails); nil uses crypto/rand.
+	Rand io.Reader
 }
 
 // DefaultParams provides legacy parameters for callers that do not
@@ -1,7 +1,7 @@
 package decs
 
 import (
-	"encoding/binary"
+	"fmt"
 	"math/bits"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
@@ -27,7 +27,7 @@ func NewVerifierWithParams(ringQ *ring.Ring, r int, params Params) *Verifier {
 		panic("decs: invalid NonceBytes (must be > 0)")
 	}
 	if len(ringQ.Modulus) != 1 {
-		panic("decs: only single-modulus rings are supported (len(Modulus) must be 1)")
+		panic("decs: only single-modulus rings are supported (len(Modulus) must be 1); prove RNS rings per limb")
 	}
 	return &Verifier{ringQ: ringQ, r: r, params: params}
 }
@@ -73,20 +73,26 @@ func equalGamma(a, b [][]uint64) bool {
 	return true
 }
 
-// VerifyCommit checks deg R_k <= Degree (DECS §3 Step 3).
+// VerifyCommit checks deg R_k <= Degree (DECS §3 Step 3).
 func (v *Verifier) VerifyCommit(root [16]byte, R []*ring.Poly, Gamma [][]uint64) bool {
+	return v.CheckCommit(root, R, Gamma) == nil
+}
+
+// CheckCommit is VerifyCommit reporting the failure as ErrFSDigest (Γ not
+// derived from root) or ErrDegreeBound.
+func (v *Verifier) CheckCommit(root [16]byte, R []*ring.Poly, Gamma [][]uint64) error {
 	if !equalGamma(v.DeriveGamma(root), Gamma) {
-		return false
+		return fmt.Errorf("decs: Γ does not match root: %w", ErrFSDigest)
 	}
-	for _, p := range R {
+	for k, p := range R {
 		coeffs := p.Coeffs[0] // coeff domain
 		for i := v.params.Degree + 1; i < len(coeffs); i++ {
 			if coeffs[i] != 0 {
-				return false // degree too large
+				return fmt.Errorf("decs: R[%d] has degree %d > %d: %w", k, i, v.params.Degree, ErrDegreeBound)
 			}
 		}
 	}
-	return true
+	return nil
 }
 
 // VerifyEval runs DECS.Eval checks: Merkle paths + masked relation.
@@ -94,25 +100,38 @@ func (v *Verifier) VerifyEval(
 	root [16]byte, Gamma [][]uint64, R []*ring.Poly,
 	open *DECSOpening,
 ) bool {
+	return v.CheckEval(root, Gamma, R, open) == nil
+}
+
+// CheckEval is VerifyEval reporting why the opening was rejected:
+// ErrMalformedProof, ErrMerklePath or *ErrConstraint (Row = repetition k).
+func (v *Verifier) CheckEval(
+	root [16]byte, Gamma [][]uint64, R []*ring.Poly,
+	open *DECSOpening,
+) error {
 	if open == nil {
-		return false
+		return fmt.Errorf("decs: nil opening: %w", ErrMalformedProof)
+	}
+	if err := open.CheckResidueWidth(v.ringQ.Modulus[0]); err != nil {
+		return fmt.Errorf("%w: %w", err, ErrMalformedProof)
 	}
 	if err := EnsureMerkleDecoded(open); err != nil {
-		return false
+		return fmt.Errorf("decs: %v: %w", err, ErrMalformedProof)
 	}
 	n := open.EntryCount()
 	if len(open.Pvals) != n || len(open.Mvals) != n || len(open.PathIndex) != n {
-		return false
+		return fmt.Errorf("decs: opening has %d entries but Pvals=%d Mvals=%d paths=%d: %w",
+			n, len(open.Pvals), len(open.Mvals), len(open.PathIndex), ErrMalformedProof)
 	}
 	if len(open.Nonces) > 0 && len(open.Nonces) != n {
-		return false
+		return fmt.Errorf("decs: nonce count %d != %d: %w", len(open.Nonces), n, ErrMalformedProof)
 	}
 	if len(Gamma) != v.params.Eta || len(R) != v.params.Eta {
-		return false
+		return fmt.Errorf("decs: expected η=%d Γ rows and R polys: %w", v.params.Eta, ErrMalformedProof)
 	}
 	for k := 0; k < v.params.Eta; k++ {
 		if len(Gamma[k]) != v.r {
-			return false
+			return fmt.Errorf("decs: Γ[%d] has %d columns, want %d: %w", k, len(Gamma[k]), v.r, ErrMalformedProof)
 		}
 	}
 
@@ -127,10 +146,10 @@ func (v *Verifier) VerifyEval(
 	for t := 0; t < n; t++ {
 		idx := open.IndexAt(t)
 		if idx < 0 || idx >= int(v.ringQ.N) {
-			return false
+			return fmt.Errorf("decs: index %d out of range: %w", idx, ErrMalformedProof)
 		}
 		if len(open.Pvals[t]) != v.r || len(open.Mvals[t]) != v.params.Eta {
-			return false
+			return fmt.Errorf("decs: entry %d has wrong Pvals/Mvals width: %w", t, ErrMalformedProof)
 		}
 		var nonce []byte
 		if len(open.Nonces) > t && len(open.Nonces[t]) > 0 {
@@ -139,39 +158,32 @@ func (v *Verifier) VerifyEval(
 			nonce = deriveNonce(open.NonceSeed, idx, open.NonceBytes)
 		}
 		if len(nonce) != v.params.NonceBytes {
-			return false
+			return fmt.Errorf("decs: nonce length %d != %d at entry %d: %w", len(nonce), v.params.NonceBytes, t, ErrMalformedProof)
 		}
 
-		// pack field elements as uint32 and index as uint16 (matching prover)
-		buf := make([]byte, 4*(v.r+v.params.Eta)+2+v.params.NonceBytes)
-		off := 0
-		for j := 0; j < v.r; j++ {
-			pv := getPval(open, t, j)
-			binary.LittleEndian.PutUint32(buf[off:], uint32(pv))
-			off += 4
+		pv := make([]uint64, v.r)
+		for j := range pv {
+			pv[j] = getPval(open, t, j)
 		}
-		for k := 0; k < v.params.Eta; k++ {
-			mv := getMval(open, t, k)
-			binary.LittleEndian.PutUint32(buf[off:], uint32(mv))
-			off += 4
+		mv := make([]uint64, v.params.Eta)
+		for k := range mv {
+			mv[k] = getMval(open, t, k)
 		}
-		binary.LittleEndian.PutUint16(buf[off:], uint16(idx))
-		off += 2
-		copy(buf[off:], nonce[:v.params.NonceBytes])
+		buf := EncodeLeaf(pv, mv, idx, nonce[:v.params.NonceBytes], LeafResidueBytes(v.ringQ.Modulus[0]))
 		// Reconstruct per-index path from union
 		ids, ok := pathRowIndices(open, t)
 		if !ok {
-			return false
+			return fmt.Errorf("decs: missing path for entry %d: %w", t, ErrMalformedProof)
 		}
 		path := make([][]byte, len(ids))
 		for lvl, id := range ids {
 			if id < 0 || id >= len(open.Nodes) {
-				return false
+				return fmt.Errorf("decs: path node %d out of range at entry %d: %w", id, t, ErrMalformedProof)
 			}
 			path[lvl] = open.Nodes[id]
 		}
 		if !VerifyPath(buf, path, root, idx) {
-			return false
+			return fmt.Errorf("decs: leaf %d: %w", idx, ErrMerklePath)
 		}
 
 		for k := 0; k < v.params.Eta; k++ {
@@ -182,11 +194,24 @@ func (v *Verifier) VerifyEval(
 				rhs = addMod64(rhs, mul, mod)
 			}
 			if lhs != rhs {
-				return false
+				return &ErrConstraint{Name: "DECS.eval", Row: k}
 			}
 		}
 	}
-	return true
+	return nil
+}
+
+// CheckResidueWidth rejects an opening whose residues are not packed at
+// ResidueWidth(q). ResidueBits 0, the original 20-bit encoding, is accepted
+// where that width is 20.
+func (op *DECSOpening) CheckResidueWidth(q uint64) error {
+	if op == nil {
+		return nil
+	}
+	if want := ResidueWidth(q); op.ResidueBits < 0 || op.residueWidth() != want {
+		return fmt.Errorf("decs: residue width %d, want %d", op.ResidueBits, want)
+	}
+	return nil
 }
 
 // getPval returns Pvals[t][j], reading from packed form if necessary.
@@ -194,9 +219,8 @@ func getPval(open *DECSOpening, t, j int) uint64 {
 	if open.Pvals != nil {
 		return open.Pvals[t][j]
 	}
-	// unpack from 20-bit stream
 	idx := t*open.R + j
-	return unpackU20(open.PvalsBits, idx)
+	return unpackResidue(open.PvalsBits, idx, open.residueWidth())
 }
 
 func getMval(open *DECSOpening, t, k int) uint64 {
@@ -204,7 +228,32 @@ func getMval(open *DECSOpening, t, k int) uint64 {
 		return open.Mvals[t][k]
 	}
 	idx := t*open.Eta + k
-	return unpackU20(open.MvalsBits, idx)
+	return unpackResidue(open.MvalsBits, idx, open.residueWidth())
+}
+
+// unpackResidue reads entry index of a width-bit residue stream. Widths
+// outside [1,64] and entries past the end of the stream read as 0.
+func unpackResidue(bits []byte, index, width int) uint64 {
+	if width <= 0 || width > 64 || index < 0 || index >= len(bits)*8/width {
+		return 0
+	}
+	if width == 20 {
+		return unpackU20(bits, index)
+	}
+	off := index * width
+	bytePos := off >> 3
+	shift := uint(off & 7)
+	var chunk uint64
+	bytesNeeded := (width + int(shift) + 7) / 8
+	for k := 0; k < bytesNeeded && k < 8 && bytePos+k < len(bits); k++ {
+		chunk |= uint64(bits[bytePos+k]) << (8 * k)
+	}
+	val := chunk >> shift
+	// Widths above 56 bits can straddle a ninth byte.
+	if bytesNeeded > 8 && bytePos+8 < len(bits) {
+		val |= uint64(bits[bytePos+8]) << (64 - shift)
+	}
+	return val & (uint64(1)<<width - 1)
 }
 
 func unpackU20(bits []byte, index int) uint64 {
@@ -227,7 +276,7 @@ func unpackU20(bits []byte, index int) uint64 {
 }
 
 // GetOpeningPval returns the P value at (t,j) from the opening, reading from
-// the packed 20-bit stream if the plain matrix is nil.
+// the packed residue stream if the plain matrix is nil.
 func GetOpeningPval(open *DECSOpening, t, j int) uint64 {
 	if open == nil || t < 0 || j < 0 {
 		return 0
@@ -242,11 +291,11 @@ func GetOpeningPval(open *DECSOpening, t, j int) uint64 {
 		return 0
 	}
 	idx := t*open.R + j
-	return unpackU20(open.PvalsBits, idx)
+	return unpackResidue(open.PvalsBits, idx, open.residueWidth())
 }
 
 // GetOpeningMval returns the M value at (t,k) from the opening, reading from
-// the packed 20-bit stream if the plain matrix is nil.
+// the packed residue stream if the plain matrix is nil.
 func GetOpeningMval(open *DECSOpening, t, k int) uint64 {
 	if open == nil || t < 0 || k < 0 {
 		return 0
@@ -261,7 +310,7 @@ func GetOpeningMval(open *DECSOpening, t, k int) uint64 {
 		return 0
 	}
 	idx := t*open.Eta + k
-	return unpackU20(open.MvalsBits, idx)
+	return unpackResidue(open.MvalsBits, idx, open.residueWidth())
 }
 
 func pathRowIndices(open *DECSOpening, row int) ([]int, bool) {
@@ -287,28 +336,43 @@ func (v *Verifier) VerifyEvalAt(
 	root [16]byte, Gamma [][]uint64, R []*ring.Poly,
 	open *DECSOpening, E []int,
 ) bool {
+	return v.CheckEvalAt(root, Gamma, R, open, E) == nil
+}
+
+// CheckEvalAt is VerifyEvalAt with the failure classified as in CheckEval; an
+// opening that does not cover exactly E is ErrMalformedProof.
+func (v *Verifier) CheckEvalAt(
+	root [16]byte, Gamma [][]uint64, R []*ring.Poly,
+	open *DECSOpening, E []int,
+) error {
+	if open == nil {
+		return fmt.Errorf("decs: nil opening: %w", ErrMalformedProof)
+	}
+	if open.EntryCount() != len(E) {
+		return fmt.Errorf("decs: opened %d indices, challenged %d: %w", open.EntryCount(), len(E), ErrMalformedProof)
+	}
 	indices := open.AllIndices()
 	if len(indices) != len(E) {
-		return false
+		return fmt.Errorf("decs: opened %d indices, challenged %d: %w", len(indices), len(E), ErrMalformedProof)
 	}
 	seen := make(map[int]struct{}, len(E))
 	for _, x := range E {
 		if x < 0 || x >= int(v.ringQ.N) {
-			return false
+			return fmt.Errorf("decs: challenge %d out of range: %w", x, ErrMalformedProof)
 		}
 		if _, dup := seen[x]; dup {
-			return false
+			return fmt.Errorf("decs: duplicate challenge %d: %w", x, ErrMalformedProof)
 		}
 		seen[x] = struct{}{}
 	}
 	for _, y := range indices {
 		if _, ok := seen[y]; !ok {
-			return false
+			return fmt.Errorf("decs: opened index %d not challenged: %w", y, ErrMalformedProof)
 		}
 		delete(seen, y)
 	}
 	if len(seen) != 0 {
-		return false
+		return fmt.Errorf("decs: %d challenged indices not opened: %w", len(seen), ErrMalformedProof)
 	}
-	return v.VerifyEval(root, Gamma, R, open)
+	return v.CheckEval(root, Gamma, R, open)
 }
@@ -0,0 +1,36 @@
+package decs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Verification failures are classified with the sentinels below so callers
+// can tell a malformed proof from a cryptographic rejection. LVCS and PIOP
+// re-export the same values; match them with errors.Is / errors.As.
+var (
+	// ErrMalformedProof reports missing fields or inconsistent dimensions.
+	ErrMalformedProof = errors.New("malformed proof")
+	// ErrMerklePath reports an authentication path that does not hash to the root.
+	ErrMerklePath = errors.New("merkle path verification failed")
+	// ErrFSDigest reports a Fiat–Shamir digest or derived challenge that does
+	// not match the transcript replayed by the verifier.
+	ErrFSDigest = errors.New("fiat-shamir digest mismatch")
+	// ErrDegreeBound reports a committed polynomial above its degree bound.
+	ErrDegreeBound = errors.New("degree bound violated")
+)
+
+// ErrConstraint reports a failed algebraic relation. Name identifies the
+// check (e.g. "DECS.mask", "LVCS.tail", "Eq4") and Row the offending row or
+// repetition, or -1 when the check is not row-specific.
+type ErrConstraint struct {
+	Name string
+	Row  int
+}
+
+func (e *ErrConstraint) Error() string {
+	if e.Row < 0 {
+		return fmt.Sprintf("constraint %s failed", e.Name)
+	}
+	return fmt.Sprintf("constraint %s failed at row %d", e.Name, e.Row)
+}
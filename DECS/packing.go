@@ -3,6 +3,7 @@ package decs
 import (
 	"encoding/binary"
 	"errors"
+	"math/bits"
 )
 
 // PackUint20Matrix packs a rectangular matrix of residues (<2^20) into a tight
@@ -34,7 +35,7 @@ const packedMatrixHeaderSize = 10
 
 // PackUintMatrix encodes a matrix into a bitstream prefixed with a 10-byte header:
 // 4 bytes rows, 4 bytes cols, 1 byte bit width, 1 reserved byte. The bit width
-// is chosen automatically (16, 20, 32 or 64 bits) depending on the largest entry.
+// is chosen automatically from the largest entry (see selectBitWidth).
 // The returned slice contains the header followed by the packed payload.
 func PackUintMatrix(rows [][]uint64) ([]byte, int, int, int) {
 	if len(rows) == 0 {
@@ -104,9 +105,12 @@ func parsePackedMatrix(bits []byte) (int, int, int, []byte, error) {
 	if width <= 0 || width > 64 {
 		return 0, 0, 0, nil, errors.New("decs: invalid matrix bit width")
 	}
+	if rows > 0 && cols == 0 {
+		return 0, 0, 0, nil, errors.New("decs: invalid matrix dimensions")
+	}
 	payload := bits[packedMatrixHeaderSize:]
-	expectedBits := rows * cols * width
-	if len(payload)*8 < expectedBits {
+	// Compare in values rather than bits so hostile headers cannot overflow.
+	if cols > 0 && rows > len(payload)*8/width/cols {
 		return 0, 0, 0, nil, errors.New("decs: truncated packed matrix payload")
 	}
 	return rows, cols, width, payload, nil
@@ -136,10 +140,14 @@ func packUintMatrixBody(rows [][]uint64, rowLen, width int) []byte {
 			shift := uint(bitPos & 7)
 			chunk := uint64(val) << shift
 			bytesNeeded := (width + int(shift) + 7) / 8
-			for k := 0; k < bytesNeeded && (bytePos+k) < len(out); k++ {
+			for k := 0; k < bytesNeeded && k < 8 && (bytePos+k) < len(out); k++ {
 				out[bytePos+k] |= byte(chunk & 0xFF)
 				chunk >>= 8
 			}
+			// Widths above 56 bits can straddle a ninth byte.
+			if bytesNeeded > 8 && bytePos+8 < len(out) {
+				out[bytePos+8] |= byte(val >> (64 - shift))
+			}
 			bitPos += width
 		}
 	}
@@ -165,10 +173,14 @@ func unpackUintMatrixBody(bits []byte, rows, rowLen, width int) [][]uint64 {
 			shift := uint(bitPos & 7)
 			var chunk uint64
 			bytesNeeded := (width + int(shift) + 7) / 8
-			for k := 0; k < bytesNeeded && (bytePos+k) < len(bits); k++ {
+			for k := 0; k < bytesNeeded && k < 8 && (bytePos+k) < len(bits); k++ {
 				chunk |= uint64(bits[bytePos+k]) << (8 * k)
 			}
-			row[c] = (chunk >> shift) & mask
+			val := chunk >> shift
+			if bytesNeeded > 8 && bytePos+8 < len(bits) {
+				val |= uint64(bits[bytePos+8]) << (64 - shift)
+			}
+			row[c] = val & mask
 			bitPos += width
 		}
 		out[r] = row
@@ -198,15 +210,45 @@ func maxRowLen(rows [][]uint64) int {
 	return rowLen
 }
 
+// selectBitWidth keeps the 16- and 20-bit widths used over the NTRU modulus
+// and packs wider residues (RNS limbs, larger primes) at their exact length.
 func selectBitWidth(max uint64) int {
 	switch {
 	case max < (1 << 16):
 		return 16
 	case max < (1 << 20):
 		return 20
-	case max < (1 << 32):
-		return 32
 	default:
-		return 64
+		return bits.Len64(max)
 	}
 }
+
+// PackedUint20Size is the length of PackUint20Matrix's stream for count residues.
+func PackedUint20Size(count int) int {
+	return (count*20 + 7) / 8
+}
+
+// PackedUintMatrixSize is the length of PackUintMatrix's output for a
+// rows×cols matrix whose largest entry is maxValue (header included).
+func PackedUintMatrixSize(rows, cols int, maxValue uint64) int {
+	if rows <= 0 || cols <= 0 {
+		return 0
+	}
+	width := selectBitWidth(maxValue)
+	return packedMatrixHeaderSize + (rows*cols*width+7)/8
+}
+
+// PackedIndexSize is the length of the 13-bit tail index stream for count indices.
+func PackedIndexSize(count int) int {
+	return (count*indexBitsPerValue + 7) / 8
+}
+
+// PackedRefSize returns the bit width and stream length of count frontier
+// references into a table of tableSize union nodes.
+func PackedRefSize(count, tableSize int) (width, size int) {
+	if count <= 0 || tableSize <= 0 {
+		return 0, 0
+	}
+	width = pathBitWidth(tableSize - 1)
+	return width, (count*width + 7) / 8
+}
@@ -44,10 +44,10 @@ func interpolateRow(
 	for _, xj := range xs {
 		for k := m; k >= 1; k-- {
 			// T[k] = T[k-1] - xj*T[k] mod q
-			T[k] = (T[k-1] + mod - (xj * T[k] % mod)) % mod
+			T[k] = (T[k-1] + mod - MulModReduced(xj, T[k], mod)) % mod
 		}
 		// T[0] = - xj * T[0]
-		T[0] = (mod - (xj * T[0] % mod)) % mod
+		T[0] = (mod - MulModReduced(xj, T[0], mod)) % mod
 	}
 
 	// 4) Interpolate via sum_i [ y_i * Qi(X) * invDenom_i ]
@@ -57,7 +57,7 @@ func interpolateRow(
 		// 4.1 synthetic‐division: Qi = T/(X - xi)
 		tmp[m-1] = T[m]
 		for k := m - 2; k >= 0; k-- {
-			tmp[k] = (T[k+1] + xi*tmp[k+1]) % mod
+			tmp[k] = (T[k+1] + MulModReduced(xi, tmp[k+1], mod)) % mod
 		}
 		// 4.2 denom_i = ∏_{j≠i}(xi - xj)
 		denom := uint64(1)
@@ -66,7 +66,7 @@ func interpolateRow(
 				continue
 			}
 			diff := (xi + mod - xj) % mod
-			denom = (denom * diff) % mod
+			denom = MulModReduced(denom, diff, mod)
 		}
 		// 4.3 invert denom
 		inv := new(big.Int).ModInverse(
@@ -79,9 +79,9 @@ func interpolateRow(
 		invDen := inv.Uint64()
 
 		// 4.4 accumulate: Pcoefs += (y_i * invDen) * tmp
-		scale := (ys[i] * invDen) % mod
+		scale := MulModReduced(ys[i]%mod, invDen, mod)
 		for k := 0; k < m; k++ {
-			Pcoefs[k] = (Pcoefs[k] + tmp[k]*scale) % mod
+			Pcoefs[k] = (Pcoefs[k] + MulModReduced(tmp[k], scale, mod)) % mod
 		}
 	}
 
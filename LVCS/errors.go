@@ -0,0 +1,14 @@
+package lvcs
+
+import decs "vSIS-Signature/DECS"
+
+// Verification errors shared with DECS; see decs.ErrMalformedProof et al.
+var (
+	ErrMalformedProof = decs.ErrMalformedProof
+	ErrMerklePath     = decs.ErrMerklePath
+	ErrFSDigest       = decs.ErrFSDigest
+	ErrDegreeBound    = decs.ErrDegreeBound
+)
+
+// ErrConstraint reports a failed LVCS or DECS relation.
+type ErrConstraint = decs.ErrConstraint
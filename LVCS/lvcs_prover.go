@@ -3,6 +3,7 @@ package lvcs
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	decs "vSIS-Signature/DECS"
@@ -92,6 +93,10 @@ func CommitInitWithParams(
 		return
 	}
 	q0 := ringQ.Modulus[0]
+	var coins io.Reader = rand.Reader
+	if params.Rand != nil {
+		coins = params.Rand
+	}
 
 	normalised := make([]RowInput, nrows)
 
@@ -111,7 +116,7 @@ func CommitInitWithParams(
 		switch {
 		case in.Tail == nil:
 			for i := 0; i < ell; i++ {
-				x, _ := rand.Int(rand.Reader, big.NewInt(int64(q0)))
+				x, _ := rand.Int(coins, big.NewInt(int64(q0)))
 				tailCopy[i] = uint64(x.Int64())
 			}
 		case len(in.Tail) != ell:
@@ -230,7 +235,7 @@ func EvalInitMany(
 			cij := req.Coeffs[j] % q0
 			row := prover.Rows[j].Tail
 			for i := 0; i < ell; i++ {
-				bar[k][i] = (bar[k][i] + cij*row[i]) % q0
+				bar[k][i] = (bar[k][i] + MulModReduced(cij, row[i]%q0, q0)) % q0
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package lvcs
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math/big"
 
 	decs "vSIS-Signature/DECS"
@@ -59,14 +60,20 @@ func (v *VerifierState) AcceptGamma(gamma [][]uint64) {
 
 // CommitStep2 stores the prover's R_k polynomials.
 func (v *VerifierState) CommitStep2(R []*ring.Poly) bool {
+	return v.CheckCommitStep2(R) == nil
+}
+
+// CheckCommitStep2 is CommitStep2 returning ErrDegreeBound for an R_k above
+// the DECS degree.
+func (v *VerifierState) CheckCommitStep2(R []*ring.Poly) error {
 	v.R = R
-	for _, p := range R {
+	for k, p := range R {
 		// quick degree bound using coeff-form length
-		if deg(p) > v.params.Degree {
-			return false
+		if d := deg(p); d > v.params.Degree {
+			return fmt.Errorf("lvcs: R[%d] has degree %d > %d: %w", k, d, v.params.Degree, ErrDegreeBound)
 		}
 	}
-	return true
+	return nil
 }
 
 // tiny local helper
@@ -109,7 +116,9 @@ func (v *VerifierState) ChooseE(ell, ncols int) []int {
 }
 
 // EvalStep2 – §4.1 step 4:
-// Verify Merkle + low-degree + linear checks, leaking only the ℓ masked positions.
+// Verify Merkle + low-degree + linear checks on the opening at E. The masked
+// positions [ncols, ncols+ℓ) are never opened: ¯v_k is checked only through
+// the interpolation of (v_k, ¯v_k) at E.
 func (v *VerifierState) EvalStep2(
 	bar [][]uint64, // prover’s ¯v_k
 	E []int, // challenge set (tail-only)
@@ -117,14 +126,26 @@ func (v *VerifierState) EvalStep2(
 	C [][]uint64, // coefficient matrix
 	vTargets [][]uint64, // public v_k on Ω
 ) bool {
+	return v.CheckEvalStep2(bar, E, open, C, vTargets) == nil
+}
+
+// CheckEvalStep2 is EvalStep2 reporting why the opening was rejected:
+// ErrMalformedProof, ErrMerklePath or *ErrConstraint ("LVCS.tail", Row = k).
+func (v *VerifierState) CheckEvalStep2(
+	bar [][]uint64,
+	E []int,
+	open *decs.DECSOpening,
+	C [][]uint64,
+	vTargets [][]uint64,
+) error {
 	if open == nil {
-		return false
+		return fmt.Errorf("lvcs: nil opening: %w", ErrMalformedProof)
 	}
 	if err := decs.EnsureMerkleDecoded(open); err != nil {
-		return false
+		return fmt.Errorf("lvcs: %v: %w", err, ErrMalformedProof)
 	}
 	if len(bar) == 0 || len(bar[0]) == 0 {
-		return false
+		return fmt.Errorf("lvcs: empty masked evaluations: %w", ErrMalformedProof)
 	}
 	m := len(bar)
 	ell := len(bar[0])
@@ -133,184 +154,70 @@ func (v *VerifierState) EvalStep2(
 	maskStart := ncols
 	maskEnd := ncols + ell
 	if maskEnd > N {
-		return false
+		return fmt.Errorf("lvcs: mask window [%d,%d) exceeds N=%d: %w", maskStart, maskEnd, N, ErrMalformedProof)
 	}
 	if len(E) != ell {
-		return false
+		return fmt.Errorf("lvcs: |E|=%d, want ℓ=%d: %w", len(E), ell, ErrMalformedProof)
 	}
 	tailSeen := make(map[int]struct{}, len(E))
 	for _, idx := range E {
 		if idx < maskEnd || idx >= N {
-			return false
+			return fmt.Errorf("lvcs: challenge %d outside tail: %w", idx, ErrMalformedProof)
 		}
 		if _, dup := tailSeen[idx]; dup {
-			return false
+			return fmt.Errorf("lvcs: duplicate challenge %d: %w", idx, ErrMalformedProof)
 		}
 		tailSeen[idx] = struct{}{}
 	}
 	if len(C) != m {
-		return false
+		return fmt.Errorf("lvcs: coefficient matrix has %d rows, want %d: %w", len(C), m, ErrMalformedProof)
 	}
 	if len(vTargets) != m {
-		return false
+		return fmt.Errorf("lvcs: %d targets, want %d: %w", len(vTargets), m, ErrMalformedProof)
 	}
 	for k := 0; k < m; k++ {
 		if len(bar[k]) != ell {
-			return false
+			return fmt.Errorf("lvcs: bar[%d] has %d entries, want %d: %w", k, len(bar[k]), ell, ErrMalformedProof)
 		}
 		if len(C[k]) != v.r {
-			return false
+			return fmt.Errorf("lvcs: C[%d] has %d columns, want %d: %w", k, len(C[k]), v.r, ErrMalformedProof)
 		}
 		if len(vTargets[k]) != ncols {
-			return false
+			return fmt.Errorf("lvcs: target %d has %d entries, want %d: %w", k, len(vTargets[k]), ncols, ErrMalformedProof)
 		}
 	}
 
-	if open.EntryCount() != len(E)+ell {
-		return false
-	}
-
-	if err := decs.EnsureMerkleDecoded(open); err != nil {
-		return false
-	}
-	maskOpen := &decs.DECSOpening{
-		Indices:    make([]int, 0, ell),
-		Pvals:      make([][]uint64, 0, ell),
-		Mvals:      make([][]uint64, 0, ell),
-		Nodes:      open.Nodes,
-		PathIndex:  make([][]int, 0, ell),
-		NonceSeed:  append([]byte(nil), open.NonceSeed...),
-		NonceBytes: open.NonceBytes,
-		R:          open.R,
-		Eta:        open.Eta,
-	}
-	tailOpen := &decs.DECSOpening{
-		Indices:    make([]int, 0, len(E)),
-		Pvals:      make([][]uint64, 0, len(E)),
-		Mvals:      make([][]uint64, 0, len(E)),
-		Nodes:      open.Nodes,
-		PathIndex:  make([][]int, 0, len(E)),
-		NonceSeed:  append([]byte(nil), open.NonceSeed...),
-		NonceBytes: open.NonceBytes,
-		R:          open.R,
-		Eta:        open.Eta,
-	}
-	maskSeen := make(map[int]struct{}, ell)
-	tailSeenOpen := make(map[int]struct{}, len(E))
-	allIdx := open.AllIndices()
-	for i, idx := range allIdx {
-		switch {
-		case idx >= maskStart && idx < maskEnd:
-			if _, dup := maskSeen[idx]; dup {
-				return false
-			}
-			maskSeen[idx] = struct{}{}
-			maskOpen.Indices = append(maskOpen.Indices, idx)
-			maskOpen.Pvals = append(maskOpen.Pvals, open.Pvals[i])
-			maskOpen.Mvals = append(maskOpen.Mvals, open.Mvals[i])
-			maskOpen.PathIndex = append(maskOpen.PathIndex, append([]int(nil), open.PathIndex[i]...))
-		case idx >= maskEnd && idx < N:
-			if _, dup := tailSeenOpen[idx]; dup {
-				return false
-			}
-			tailSeenOpen[idx] = struct{}{}
-			tailOpen.Indices = append(tailOpen.Indices, idx)
-			tailOpen.Pvals = append(tailOpen.Pvals, open.Pvals[i])
-			tailOpen.Mvals = append(tailOpen.Mvals, open.Mvals[i])
-			tailOpen.PathIndex = append(tailOpen.PathIndex, append([]int(nil), open.PathIndex[i]...))
-		default:
-			return false
-		}
-	}
-	if len(maskOpen.PathIndex) > 0 {
-		maskOpen.PathDepth = len(maskOpen.PathIndex[0])
-	}
-	if len(tailOpen.PathIndex) > 0 {
-		tailOpen.PathDepth = len(tailOpen.PathIndex[0])
-	}
-
-	if len(maskOpen.Indices) != ell {
-		return false
-	}
-	if len(tailOpen.Indices) != len(E) {
-		return false
-	}
-	if !equalSets(tailOpen.Indices, E) {
-		return false
-	}
-
 	decv := decs.NewVerifierWithParams(v.RingQ, v.r, v.params)
-	maskIdx := make([]int, ell)
-	for i := 0; i < ell; i++ {
-		maskIdx[i] = ncols + i
-	}
-	if !decv.VerifyEvalAt(v.Root, v.Gamma, v.R, maskOpen, maskIdx) {
-		return false
-	}
-	if !decv.VerifyEvalAt(v.Root, v.Gamma, v.R, tailOpen, E) {
-		return false
+	if err := decv.CheckEvalAt(v.Root, v.Gamma, v.R, open, E); err != nil {
+		return err
 	}
 
 	mod := v.RingQ.Modulus[0]
-	for t, idx := range maskOpen.Indices {
-		maskedPos := idx - ncols
-		for k := 0; k < m; k++ {
-			if len(maskOpen.Pvals[t]) != v.r {
-				return false
-			}
-			sum := uint64(0)
-			for j := 0; j < v.r; j++ {
-				sum = MulAddMod64(sum, C[k][j], maskOpen.Pvals[t][j], mod)
-			}
-			if sum != bar[k][maskedPos] {
-				return false
-			}
-		}
-	}
-
 	Qvals := make([]*ring.Poly, m)
 	for k := 0; k < m; k++ {
 		Qk, err := interpolateRow(v.RingQ, vTargets[k], bar[k], ncols, ell)
 		if err != nil {
-			return false
+			return fmt.Errorf("lvcs: interpolate row %d: %v: %w", k, err, ErrMalformedProof)
 		}
 		Qvals[k] = v.RingQ.NewPoly()
 		v.RingQ.NTT(Qk, Qvals[k])
 	}
 
-	for t, idx := range tailOpen.Indices {
-		if len(tailOpen.Pvals[t]) != v.r {
-			return false
+	for t, idx := range open.AllIndices() {
+		if len(open.Pvals[t]) != v.r {
+			return fmt.Errorf("lvcs: tail entry %d has wrong width: %w", t, ErrMalformedProof)
 		}
 		for k := 0; k < m; k++ {
 			lhs := Qvals[k].Coeffs[0][idx]
 			rhs := uint64(0)
 			for j := 0; j < v.r; j++ {
-				rhs = MulAddMod64(rhs, C[k][j], tailOpen.Pvals[t][j], mod)
+				rhs = MulAddMod64(rhs, C[k][j], open.Pvals[t][j], mod)
 			}
 			if lhs != rhs {
-				return false
+				return &ErrConstraint{Name: "LVCS.tail", Row: k}
 			}
 		}
 	}
 
-	return true
-}
-
-// equalSets checks multisets equality of int slices.
-func equalSets(a, b []int) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	seen := make(map[int]int, len(a))
-	for _, x := range a {
-		seen[x]++
-	}
-	for _, y := range b {
-		if seen[y] == 0 {
-			return false
-		}
-		seen[y]--
-	}
-	return true
+	return nil
 }
@@ -35,3 +35,14 @@ func AddMod64(a, b, mod uint64) uint64 {
 	}
 	return s
 }
+
+// MulModReduced returns (a*b) mod mod for residues a, b < mod. Moduli up to
+// 2^32 use the native product; wider ones go through a 128-bit product.
+func MulModReduced(a, b, mod uint64) uint64 {
+	if mod <= 1<<32 {
+		return a * b % mod
+	}
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi, lo, mod)
+	return rem
+}
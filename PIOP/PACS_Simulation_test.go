@@ -1005,7 +1005,7 @@ func TestVerifierRejectsTightLinfBound(t *testing.T) {
 	}
 	tamperedFpar := append([]*ring.Poly(nil), ctx.Fpar...)
 	copy(tamperedFpar[normStart:], tightFpar)
-	if checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, ctx.maskOpen, tamperedFpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeScalars, ctx.omega, ctx.Eprime) {
+	if checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, nil, tamperedFpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeScalars, ctx.omega, ctx.Eprime) {
 		t.Fatalf("Eq.(4) verifier accepted chain with tightened β∞=%d", betaTight)
 	}
 }
@@ -1037,7 +1037,7 @@ func TestPACSTampering(t *testing.T) {
 		Ehead := append([]int(nil), ctx.E...)
 		Ehead[0] = 0
 		openHeadTail := lvcs.EvalFinish(ctx.pk, Ehead)
-		combinedHead := combineOpenings(ctx.maskOpen.DECSOpen, openHeadTail.DECSOpen)
+		combinedHead := combineOpenings(nil, openHeadTail.DECSOpen)
 		if ctx.vrf.EvalStep2(ctx.barSets, Ehead, combinedHead, ctx.CoeffMatrix, ctx.vTargets) {
 			t.Fatalf("expected EvalStep2 to reject head index")
 		}
@@ -1045,9 +1045,9 @@ func TestPACSTampering(t *testing.T) {
 	t.Run("LVCS/tail-only: reject randomness-support index", func(t *testing.T) {
 		ctx, _, _, _ := buildSim(t)
 		Erand := append([]int(nil), ctx.E...)
-		Erand[0] = ctx.maskIdx[0]
+		Erand[0] = ctx.ncols
 		openRandTail := lvcs.EvalFinish(ctx.pk, Erand)
-		combinedRand := combineOpenings(ctx.maskOpen.DECSOpen, openRandTail.DECSOpen)
+		combinedRand := combineOpenings(nil, openRandTail.DECSOpen)
 		if ctx.vrf.EvalStep2(ctx.barSets, Erand, combinedRand, ctx.CoeffMatrix, ctx.vTargets) {
 			t.Fatalf("expected EvalStep2 to reject randomness-slot index in E")
 		}
@@ -1094,7 +1094,7 @@ func TestPACSTampering(t *testing.T) {
 	t.Run("Eq4: tamper Q", func(t *testing.T) {
 		ctx, _, _, _ := buildSim(t)
 		bumpConst(ctx.ringQ, ctx.Q[0], ctx.q)
-		ok := checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, ctx.maskOpen, ctx.Fpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeAgg, ctx.omega, ctx.Eprime)
+		ok := checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, nil, ctx.Fpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeAgg, ctx.omega, ctx.Eprime)
 		if ok {
 			t.Fatalf("expected Eq.(4) check to fail")
 		}
@@ -1102,7 +1102,7 @@ func TestPACSTampering(t *testing.T) {
 	t.Run("Eq4: tamper gammaPrime", func(t *testing.T) {
 		ctx, _, _, _ := buildSim(t)
 		ctx.GammaPrimeAgg[0][0] = (ctx.GammaPrimeAgg[0][0] + 1) % ctx.q
-		ok := checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, ctx.maskOpen, ctx.Fpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeAgg, ctx.omega, ctx.Eprime)
+		ok := checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, nil, ctx.Fpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeAgg, ctx.omega, ctx.Eprime)
 		if ok {
 			t.Fatalf("expected Eq.(4) check to fail")
 		}
@@ -1156,7 +1156,7 @@ func TestPACSParamGrid(t *testing.T) {
 func TestEq4TamperMaskOnly(t *testing.T) {
 	ctx, _, _, _ := buildSim(t)
 	bumpConst(ctx.ringQ, ctx.M[0], ctx.q)
-	if checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, ctx.maskOpen, ctx.Fpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeAgg, ctx.omega, ctx.Eprime) {
+	if checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, nil, ctx.Fpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeAgg, ctx.omega, ctx.Eprime) {
 		t.Fatalf("Eq.(4) should fail when M is tampered")
 	}
 }
@@ -1191,6 +1191,21 @@ func BenchmarkBuildSim(b *testing.B) {
 	}
 }
 
+// BenchmarkPACSSmall runs `ntrucli pacs-small` with its default flags at
+// each specialised extension degree.
+func BenchmarkPACSSmall(b *testing.B) {
+	for _, theta := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("theta=%d", theta), func(b *testing.B) {
+			opts := SimOpts{NCols: 4, Ell: 20, EllPrime: 2, Rho: 2, Eta: 15, Theta: theta, Lambda: 256, ChainW: 3}
+			for i := 0; i < b.N; i++ {
+				if _, err := RunOnce(opts); err != nil {
+					b.Fatalf("RunOnce: %v", err)
+				}
+			}
+		})
+	}
+}
+
 func TestSmallFieldRowLayoutAndQueries(t *testing.T) {
 	opts := defaultSimOpts()
 	opts.Theta = 3
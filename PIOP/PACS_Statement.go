@@ -455,6 +455,7 @@ func BuildQFromDisk() (Q []*ring.Poly, omega []uint64, ringQ *ring.Ring) {
 	A[0][0], A[0][1] = one, negH
 
 	//-------------------------------------------------------------------[2] B-mat
+	// PACS demo key, outside any pubparams bundle.
 	Bcoeffs, _ := ntrurio.LoadBMatrixCoeffs(resolve("Parameters/Bmatrix.json"))
 	B0Const := []*ring.Poly{toNTTwrap(ringQ, Bcoeffs[0], toNTT)}
 	B0Msg := [][]*ring.Poly{{toNTTwrap(ringQ, Bcoeffs[1], toNTT)}}
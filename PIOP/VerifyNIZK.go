@@ -2,7 +2,7 @@ package PIOP
 
 import (
 	"bytes"
-	"encoding/binary"
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -10,7 +10,6 @@ import (
 	decs "vSIS-Signature/DECS"
 	lvcs "vSIS-Signature/LVCS"
 	kf "vSIS-Signature/internal/kfield"
-	ntrurio "vSIS-Signature/ntru/io"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
@@ -20,21 +19,35 @@ import (
 // checks currently cover FS rounds 0–3, LVCS EvalStep2, DECS mask verification,
 // Eq.(4) (via tail openings), and the ΣΩ sum constraints.
 func VerifyNIZK(proof *Proof) (okLin, okEq4, okSum bool, err error) {
+	return VerifyNIZKContext(context.Background(), proof)
+}
+
+// VerifyNIZKContext is VerifyNIZK with cancellation between the transcript
+// rounds and opening checks; it returns a *CanceledError once ctx is done.
+func VerifyNIZKContext(ctx context.Context, proof *Proof) (okLin, okEq4, okSum bool, err error) {
 	if proof != nil && len(proof.LabelsDigest) > 0 {
 		return false, false, false, errors.New("VerifyNIZK: credential proofs require verifier-side constraint replay; use VerifyWithConstraints")
 	}
-	return verifyNIZK(proof, nil)
+	return verifyNIZK(ctx, proof, nil)
 }
 
 // VerifyNIZKWithReplay runs VerifyNIZK and additionally replays Eq.(4) using
 // the supplied constraint evaluators on the opened rows.
 func VerifyNIZKWithReplay(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum bool, err error) {
-	return verifyNIZK(proof, replay)
+	return verifyNIZK(context.Background(), proof, replay)
+}
+
+// VerifyNIZKWithReplayContext is VerifyNIZKWithReplay with cancellation.
+func VerifyNIZKWithReplayContext(ctx context.Context, proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum bool, err error) {
+	return verifyNIZK(ctx, proof, replay)
 }
 
-func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum bool, err error) {
+func verifyNIZK(ctx context.Context, proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum bool, err error) {
 	if proof == nil {
-		return false, false, false, errors.New("VerifyNIZK: nil proof")
+		return false, false, false, fmt.Errorf("VerifyNIZK: nil proof: %w", ErrMalformedProof)
+	}
+	if err := checkCtx(ctx, "VerifyNIZK"); err != nil {
+		return false, false, false, err
 	}
 	defer func() {
 		if proof != nil {
@@ -44,26 +57,30 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	}()
 	vTargets := proof.VTargetsMatrix()
 	if len(vTargets) == 0 || len(vTargets[0]) == 0 {
-		return false, false, false, errors.New("VerifyNIZK: missing VTargets")
+		return false, false, false, fmt.Errorf("VerifyNIZK: missing VTargets: %w", ErrMalformedProof)
 	}
 	barSets := proof.BarSetsMatrix()
 	if len(barSets) == 0 || len(barSets[0]) == 0 {
-		return false, false, false, errors.New("VerifyNIZK: missing BarSets")
+		return false, false, false, fmt.Errorf("VerifyNIZK: missing BarSets: %w", ErrMalformedProof)
 	}
 	if proof.RowOpening == nil {
-		return false, false, false, errors.New("VerifyNIZK: missing row opening")
+		return false, false, false, fmt.Errorf("VerifyNIZK: missing row opening: %w", ErrMalformedProof)
 	}
 	if len(proof.Digests[0]) == 0 || len(proof.Digests[1]) == 0 || len(proof.Digests[3]) == 0 {
-		return false, false, false, errors.New("VerifyNIZK: incomplete transcript digests")
+		return false, false, false, fmt.Errorf("VerifyNIZK: incomplete transcript digests: %w", ErrMalformedProof)
 	}
 
-	par, err := ntrurio.LoadParams(resolve("Parameters/Parameters.json"), true /* allowMismatch */)
-	if err != nil {
-		return false, false, false, fmt.Errorf("VerifyNIZK: load parameters: %w", err)
+	var ringOverride *ring.Ring
+	var xof XOF = NewShake256XOF(64)
+	if replay != nil {
+		ringOverride = replay.Ring
+		if replay.oracle != nil {
+			xof = replay.oracle
+		}
 	}
-	ringQ, err := ring.NewRing(par.N, []uint64{par.Q})
+	ringQ, err := paramsRing(ringOverride)
 	if err != nil {
-		return false, false, false, fmt.Errorf("VerifyNIZK: ring.NewRing: %w", err)
+		return false, false, false, fmt.Errorf("VerifyNIZK: %w", err)
 	}
 	q := ringQ.Modulus[0]
 	ncols := len(vTargets[0])
@@ -74,7 +91,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	// Derive Ω as in the prover.
 	px := ringQ.NewPoly()
 	if len(px.Coeffs) == 0 || len(px.Coeffs[0]) < 2 {
-		return false, false, false, errors.New("VerifyNIZK: unexpected ring dimension")
+		return false, false, false, fmt.Errorf("VerifyNIZK: unexpected ring dimension: %w", ErrMalformedProof)
 	}
 	px.Coeffs[0][1] = 1
 	pts := ringQ.NewPoly()
@@ -85,12 +102,17 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 		ncols = len(omega)
 	} else {
 		if len(pts.Coeffs[0]) < ncols {
-			return false, false, false, errors.New("VerifyNIZK: Ω exceeds ring dimension")
+			return false, false, false, fmt.Errorf("VerifyNIZK: Ω exceeds ring dimension: %w", ErrMalformedProof)
 		}
 		omega = append([]uint64(nil), pts.Coeffs[0][:ncols]...)
 	}
 	if err := checkOmega(omega, q); err != nil {
-		return false, false, false, fmt.Errorf("VerifyNIZK: invalid Ω: %w", err)
+		return false, false, false, fmt.Errorf("VerifyNIZK: invalid Ω: %w: %w", err, ErrMalformedProof)
+	}
+	for _, op := range []*decs.DECSOpening{proof.RowOpening, proof.MOpening} {
+		if err := op.CheckResidueWidth(q); err != nil {
+			return false, false, false, fmt.Errorf("VerifyNIZK: %w: %w", err, ErrMalformedProof)
+		}
 	}
 
 	ell := len(proof.Tail)
@@ -106,12 +128,15 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	if lambda <= 0 {
 		lambda = 256
 	}
-	fs := NewFS(NewShake256XOF(64), proof.Salt, FSParams{Lambda: lambda, Kappa: proof.Kappa})
+	fs := NewFS(xof, proof.Salt, FSParams{Lambda: lambda, Kappa: proof.Kappa})
 	rootBytes := append([]byte(nil), proof.Root[:]...)
 	material0 := [][]byte{rootBytes}
 	if len(proof.LabelsDigest) > 0 {
 		material0 = append(material0, proof.LabelsDigest)
 	}
+	if len(proof.ParamsID) > 0 {
+		material0 = append(material0, proof.ParamsID)
+	}
 	h1, err := verifyRoundDigest(fs, 0, proof.Ctr[0], material0, proof.Digests[0], proof.Kappa[0])
 	if err != nil {
 		return false, false, false, fmt.Errorf("VerifyNIZK: FS round 0: %w", err)
@@ -122,7 +147,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 
 	// LVCS degree check binds Γ to Root.
 	if len(proof.R) != eta {
-		return false, false, false, fmt.Errorf("VerifyNIZK: expected %d R-polynomials, got %d", eta, len(proof.R))
+		return false, false, false, fmt.Errorf("VerifyNIZK: expected %d R-polynomials, got %d: %w", eta, len(proof.R), ErrMalformedProof)
 	}
 	Rpolys := coeffsToPolys(ringQ, proof.R)
 
@@ -140,11 +165,14 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	vrf := lvcs.NewVerifierWithParams(ringQ, rRows, lvcsParams, ncols)
 	vrf.Root = proof.Root
 	vrf.AcceptGamma(Gamma)
-	if !vrf.CommitStep2(Rpolys) {
-		return false, false, false, errors.New("VerifyNIZK: LVCS CommitStep2 rejected R polynomials")
+	if err := vrf.CheckCommitStep2(Rpolys); err != nil {
+		return false, false, false, fmt.Errorf("VerifyNIZK: LVCS CommitStep2: %w", err)
 	}
 
 	// ----------------------------------------------------------------- FS round 1
+	if err := checkCtx(ctx, "VerifyNIZK"); err != nil {
+		return okLin, okEq4, false, err
+	}
 	gammaBytes := bytesFromUint64Matrix(Gamma)
 	rBytes := polysToBytes(Rpolys)
 	transcript2 := [][]byte{rootBytes, gammaBytes, rBytes}
@@ -153,7 +181,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	}
 	if proof.Theta > 1 {
 		if len(proof.Chi) == 0 || len(proof.Zeta) == 0 {
-			return false, false, false, errors.New("VerifyNIZK: missing Chi/Zeta for θ>1")
+			return false, false, false, fmt.Errorf("VerifyNIZK: missing Chi/Zeta for θ>1: %w", ErrMalformedProof)
 		}
 		transcript2 = append(transcript2, encodeUint64Slice(proof.Chi), encodeUint64Slice(proof.Zeta))
 	}
@@ -164,7 +192,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	seed2 := h2
 
 	if len(proof.FparNTT) == 0 || len(proof.QNTT) == 0 {
-		return false, false, false, errors.New("VerifyNIZK: missing Eq.(4) polynomial data")
+		return false, false, false, fmt.Errorf("VerifyNIZK: missing Eq.(4) polynomial data: %w", ErrMalformedProof)
 	}
 	FparPolys := nttMatrixToPolys(ringQ, proof.FparNTT)
 	FaggPolys := nttMatrixToPolys(ringQ, proof.FaggNTT)
@@ -178,45 +206,45 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 
 	if proof.Theta > 1 {
 		if len(proof.GammaPrimeK) == 0 {
-			return false, false, false, errors.New("VerifyNIZK: missing GammaPrimeK for θ>1")
+			return false, false, false, fmt.Errorf("VerifyNIZK: missing GammaPrimeK for θ>1: %w", ErrMalformedProof)
 		}
 		rows := len(proof.GammaPrimeK)
 		cols := len(proof.GammaPrimeK[0])
 		fsGammaPrime := sampleFSMatrixK(rows, cols, proof.Theta, q, newFSRNG("GammaPrime", seed2))
 		if !kMatrixEqual(fsGammaPrime, proof.GammaPrimeK) {
-			return false, false, false, errors.New("VerifyNIZK: GammaPrimeK mismatch")
+			return false, false, false, fmt.Errorf("VerifyNIZK: GammaPrimeK mismatch: %w", ErrFSDigest)
 		}
 		gammaPrimeBytes = bytesFromKScalarMat(fsGammaPrime)
 		if totalAgg > 0 {
 			if len(proof.GammaAggK) == 0 {
-				return false, false, false, errors.New("VerifyNIZK: missing GammaAggK for θ>1")
+				return false, false, false, fmt.Errorf("VerifyNIZK: missing GammaAggK for θ>1: %w", ErrMalformedProof)
 			}
 			fsGammaAgg := sampleFSVectorK(len(proof.GammaAggK), len(proof.GammaAggK[0]), proof.Theta, q, newFSRNG("GammaPrimeAgg", seed2, []byte{1}))
 			if !kMatrixEqual(fsGammaAgg, proof.GammaAggK) {
-				return false, false, false, errors.New("VerifyNIZK: GammaAggK mismatch")
+				return false, false, false, fmt.Errorf("VerifyNIZK: GammaAggK mismatch: %w", ErrFSDigest)
 			}
 			gammaAggBytes = bytesFromKScalarMat(fsGammaAgg)
 		}
 	} else {
 		if len(proof.GammaPrime) == 0 || len(proof.GammaPrime[0]) == 0 {
-			return false, false, false, errors.New("VerifyNIZK: missing GammaPrime")
+			return false, false, false, fmt.Errorf("VerifyNIZK: missing GammaPrime: %w", ErrMalformedProof)
 		}
 		rows := len(proof.GammaPrime)
 		cols := len(proof.GammaPrime[0])
 		fsGammaPrime := sampleFSMatrix(rows, cols, q, newFSRNG("GammaPrime", seed2))
 		if !matrixEqual(fsGammaPrime, proof.GammaPrime) {
-			return false, false, false, errors.New("VerifyNIZK: GammaPrime mismatch")
+			return false, false, false, fmt.Errorf("VerifyNIZK: GammaPrime mismatch: %w", ErrFSDigest)
 		}
 		gammaPrimeBytes = bytesFromUint64Matrix(fsGammaPrime)
 		if totalAgg > 0 {
 			if len(proof.GammaAgg) == 0 || len(proof.GammaAgg[0]) == 0 {
-				return false, false, false, errors.New("VerifyNIZK: missing GammaAgg")
+				return false, false, false, fmt.Errorf("VerifyNIZK: missing GammaAgg: %w", ErrMalformedProof)
 			}
 			rowsAgg := len(proof.GammaAgg)
 			colsAgg := len(proof.GammaAgg[0])
 			fsGammaAgg := sampleFSMatrix(rowsAgg, colsAgg, q, newFSRNG("GammaPrimeAgg", seed2, []byte{1}))
 			if !matrixEqual(fsGammaAgg, proof.GammaAgg) {
-				return false, false, false, errors.New("VerifyNIZK: GammaAgg mismatch")
+				return false, false, false, fmt.Errorf("VerifyNIZK: GammaAgg mismatch: %w", ErrFSDigest)
 			}
 			gammaAggBytes = bytesFromUint64Matrix(fsGammaAgg)
 		}
@@ -245,7 +273,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 
 	if proof.Theta > 1 {
 		if len(proof.CoeffMatrix) == 0 || len(proof.KPoint) == 0 {
-			return false, false, false, errors.New("VerifyNIZK: missing coefficient matrix or K points for θ>1")
+			return false, false, false, fmt.Errorf("VerifyNIZK: missing coefficient matrix or K points for θ>1: %w", ErrMalformedProof)
 		}
 		coeffMatrix = copyMatrix(proof.CoeffMatrix)
 		transcript4 = [][]byte{
@@ -261,7 +289,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	} else {
 		ellPrime := len(barSets)
 		if ellPrime == 0 {
-			return false, false, false, errors.New("VerifyNIZK: empty bar sets")
+			return false, false, false, fmt.Errorf("VerifyNIZK: empty bar sets: %w", ErrMalformedProof)
 		}
 		points := sampleDistinctFieldElemsAvoid(ellPrime, q, newFSRNG("EvalPoints", seed3), omega)
 		coeffMatrix = make([][]uint64, ellPrime)
@@ -274,7 +302,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 			coeffMatrix[i] = row
 		}
 		if len(proof.CoeffMatrix) > 0 && !matrixEqual(coeffMatrix, proof.CoeffMatrix) {
-			return false, false, false, errors.New("VerifyNIZK: coefficient matrix mismatch")
+			return false, false, false, fmt.Errorf("VerifyNIZK: coefficient matrix mismatch: %w", ErrFSDigest)
 		}
 		transcript4 = [][]byte{
 			rootBytes,
@@ -298,27 +326,29 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	tailStart := ncols + ell
 	tailLen := int(ringQ.N) - tailStart
 	if tailLen < ell {
-		return false, false, false, errors.New("VerifyNIZK: insufficient tail region")
+		return false, false, false, fmt.Errorf("VerifyNIZK: insufficient tail region: %w", ErrMalformedProof)
 	}
 	derivedTail := sampleDistinctIndices(tailStart, tailLen, ell, newFSRNG("TailPoints", seed4))
 	if !equalIntSlices(derivedTail, proof.Tail) {
-		return false, false, false, errors.New("VerifyNIZK: tail indices mismatch")
+		return false, false, false, fmt.Errorf("VerifyNIZK: tail indices mismatch: %w", ErrFSDigest)
 	}
 
 	// ----------------------------------------------------------------- LVCS EvalStep2
-	maskIdx := make([]int, ell)
-	for i := 0; i < ell; i++ {
-		maskIdx[i] = ncols + i
+	if err := checkCtx(ctx, "VerifyNIZK"); err != nil {
+		return okLin, okEq4, false, err
 	}
-	okLin, err = verifyLVCSConstraints(ringQ, lvcsParams, proof, Gamma, Rpolys, coeffMatrix, barSets, vTargets, maskIdx, proof.Tail, ncols)
+	okLin, err = verifyLVCSConstraints(ringQ, lvcsParams, proof, Gamma, Rpolys, coeffMatrix, barSets, vTargets, proof.Tail, ncols)
 	if err != nil {
 		return false, false, false, fmt.Errorf("VerifyNIZK: %w", err)
 	}
 
 	// ----------------------------------------------------------------- DECS mask verification
+	if err := checkCtx(ctx, "VerifyNIZK"); err != nil {
+		return okLin, okEq4, false, err
+	}
 	unpackedMask := expandPackedOpening(proof.MOpening)
 	if unpackedMask == nil || len(unpackedMask.Pvals) == 0 && len(unpackedMask.PvalsBits) == 0 {
-		return false, false, false, errors.New("VerifyNIZK: missing merged mask opening data")
+		return false, false, false, fmt.Errorf("VerifyNIZK: missing merged mask opening data: %w", ErrMalformedProof)
 	}
 
 	var smallFieldK *kf.Field
@@ -326,7 +356,7 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 	var MK []*KPoly
 	if proof.Theta > 1 {
 		if len(proof.Chi) == 0 {
-			return false, false, false, errors.New("VerifyNIZK: missing Chi for θ>1")
+			return false, false, false, fmt.Errorf("VerifyNIZK: missing Chi for θ>1: %w", ErrMalformedProof)
 		}
 		field, fieldErr := kf.New(q, proof.Theta, proof.Chi)
 		if fieldErr != nil {
@@ -334,16 +364,17 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 		}
 		smallFieldK = field
 		if len(proof.QKData) == 0 || len(proof.MKData) == 0 {
-			return false, false, false, errors.New("VerifyNIZK: missing QK/MK data for θ>1")
+			return false, false, false, fmt.Errorf("VerifyNIZK: missing QK/MK data for θ>1: %w", ErrMalformedProof)
 		}
 		QK = restoreKPolys(proof.QKData)
 		MK = restoreKPolys(proof.MKData)
 	}
 	if replay != nil && replay.Eval != nil {
+		var kErr error
 		// θ>1 replay at K-points (primary) when available.
 		if proof.Theta > 1 {
 			if replay.EvalK == nil {
-				return okLin, false, false, errors.New("VerifyNIZK: missing K evaluator for θ>1 replay")
+				return okLin, false, false, fmt.Errorf("VerifyNIZK: missing K evaluator for θ>1 replay: %w", ErrMalformedProof)
 			}
 			rowEvals := proof.PvalsKEvalMatrix()
 			vTargets := proof.VTargetsMatrix()
@@ -374,12 +405,17 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 				CarryRows:    replay.CarryRows,
 				BoundB:       replay.BoundB,
 				CarryBound:   replay.CarryBound,
+				Report:       replay.Report,
 			})
 			if err != nil || !ok {
 				if err == nil {
-					err = errors.New("VerifyNIZK: K-point constraint replay failed")
+					err = &ErrConstraint{Name: "Eq4.K", Row: -1}
+				}
+				if replay.Report == nil {
+					return okLin, false, false, err
 				}
-				return okLin, false, false, err
+				// Diagnostic mode: keep going so the tail replay is reported too.
+				kErr = err
 			}
 		}
 
@@ -397,17 +433,22 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 			GammaAgg:   proof.GammaAgg,
 			Ring:      ringQ,
 			RowCount:  rowCount,
+			Fpar:      FparPolys,
+			Report:    replay.Report,
 		})
 		if err != nil || !ok {
 			if err == nil {
-				err = errors.New("VerifyNIZK: tail constraint replay failed")
+				err = &ErrConstraint{Name: "Eq4.tail", Row: -1}
 			}
 			return okLin, false, false, err
 		}
+		if kErr != nil {
+			return okLin, false, false, kErr
+		}
 		okEq4 = true
 	} else {
 		if !checkEq4OnTailOpen(ringQ, smallFieldK, proof.Theta, proof.Tail, QPolys, QK, MK, FparPolys, FaggPolys, proof.GammaPrime, proof.GammaAgg, proof.GammaPrimeK, proof.GammaAggK, proof.MOpening) {
-			return okLin, false, false, errors.New("VerifyNIZK: Eq.(4) tail check failed")
+			return okLin, false, false, fmt.Errorf("VerifyNIZK: %w", &ErrConstraint{Name: "Eq4.tail", Row: -1})
 		}
 		okEq4 = true
 
@@ -420,22 +461,25 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 			Pvals := unpackUint64Matrix(proof.PvalsEvalBits, proof.PvalsEvalRows, proof.PvalsEvalCols)
 			maskVals := unpackUint64Matrix(proof.MaskEvalBits, proof.MaskEvalRows, proof.MaskEvalCols)
 			if len(Pvals) == 0 {
-				return okLin, false, false, errors.New("VerifyNIZK: missing eval-point Pvals")
+				return okLin, false, false, fmt.Errorf("VerifyNIZK: missing eval-point Pvals: %w", ErrMalformedProof)
 			}
 			if len(maskVals) == 0 {
 				// fabricate zero masks if absent
 				maskVals = make([][]uint64, len(idxs))
 			}
 			if !checkEq4OnEvalOpen(q, idxs, maskVals, QPolys, FparPolys, FaggPolys, proof.GammaPrime, proof.GammaAgg) {
-				return okLin, false, false, errors.New("VerifyNIZK: Eq.(4) eval-point check failed")
+				return okLin, false, false, fmt.Errorf("VerifyNIZK: %w", &ErrConstraint{Name: "Eq4.eval", Row: -1})
 			}
 		}
 	}
 
 	// ----------------------------------------------------------------- ΣΩ check (Eq.7)
+	if err := checkCtx(ctx, "VerifyNIZK"); err != nil {
+		return okLin, okEq4, false, err
+	}
 	okSum = VerifyQ(ringQ, QPolys, omega)
 	if !okSum {
-		return okLin, okEq4, false, fmt.Errorf("VerifyNIZK: ΣΩ failed")
+		return okLin, okEq4, false, fmt.Errorf("VerifyNIZK: %w", &ErrConstraint{Name: "SumOmega", Row: -1})
 	}
 
 	return okLin, okEq4, okSum, nil
@@ -443,10 +487,10 @@ func verifyNIZK(proof *Proof, replay *ConstraintReplay) (okLin, okEq4, okSum boo
 
 func verifyRoundDigest(fs *FS, round int, ctr uint64, material [][]byte, expected []byte, kappa int) ([]byte, error) {
 	if fs == nil {
-		return nil, errors.New("nil FS state")
+		return nil, fmt.Errorf("nil FS state: %w", ErrMalformedProof)
 	}
 	if round < 0 || round >= len(fs.labels) {
-		return nil, fmt.Errorf("invalid FS round %d", round)
+		return nil, fmt.Errorf("invalid FS round %d: %w", round, ErrMalformedProof)
 	}
 	input := append([]byte(nil), fs.salt...)
 	for _, m := range material {
@@ -455,10 +499,10 @@ func verifyRoundDigest(fs *FS, round int, ctr uint64, material [][]byte, expecte
 	input = append(input, u64le(ctr)...)
 	digest := fs.xof.Expand(fs.labels[round], input)
 	if !bytes.Equal(digest, expected) {
-		return nil, fmt.Errorf("digest mismatch in round %d", round)
+		return nil, fmt.Errorf("round %d: %w", round, ErrFSDigest)
 	}
 	if !hasZeroPrefix(digest, kappa) {
-		return nil, fmt.Errorf("grinding predicate failed in round %d", round)
+		return nil, fmt.Errorf("grinding predicate failed in round %d: %w", round, ErrFSDigest)
 	}
 	return digest, nil
 }
@@ -580,85 +624,57 @@ func verifyLVCSConstraints(
 	coeffMatrix [][]uint64,
 	barSets [][]uint64,
 	vTargets [][]uint64,
-	maskIdx []int,
 	tail []int,
 	ncols int,
 ) (bool, error) {
 	base := proof.RowOpening
 	if base == nil {
-		return false, errors.New("VerifyNIZK: nil row opening")
+		return false, fmt.Errorf("VerifyNIZK: nil row opening: %w", ErrMalformedProof)
 	}
 	if len(coeffMatrix) == 0 || len(coeffMatrix[0]) == 0 {
-		return false, errors.New("VerifyNIZK: empty coefficient matrix")
+		return false, fmt.Errorf("VerifyNIZK: empty coefficient matrix: %w", ErrMalformedProof)
 	}
 	rowCount := base.R
 	if rowCount <= 0 {
 		rowCount = len(coeffMatrix[0])
 	}
 	if len(coeffMatrix[0]) != rowCount {
-		return false, errors.New("VerifyNIZK: coefficient matrix row length mismatch")
+		return false, fmt.Errorf("VerifyNIZK: coefficient matrix row length mismatch: %w", ErrMalformedProof)
+	}
+	// Only the tail set E is opened; the masked positions stay hidden.
+	if base.EntryCount() != len(tail) {
+		return false, fmt.Errorf("VerifyNIZK: row opening has %d entries, want |E|=%d: %w", base.EntryCount(), len(tail), ErrMalformedProof)
 	}
 	eta := base.Eta
 	if eta <= 0 {
 		eta = len(Gamma)
 	}
-	maskOpen, err := buildSubsetOpening(base, maskIdx, rowCount, eta)
-	if err != nil {
-		return false, fmt.Errorf("VerifyNIZK: mask opening: %w", err)
-	}
 	tailOpen, err := buildSubsetOpening(base, tail, rowCount, eta)
 	if err != nil {
-		return false, fmt.Errorf("VerifyNIZK: tail opening: %w", err)
-	}
-	for i := range maskOpen.Pvals {
-		if len(maskOpen.Pvals[i]) != rowCount {
-			return false, fmt.Errorf("VerifyNIZK: mask Pvals[%d] len=%d want=%d", i, len(maskOpen.Pvals[i]), rowCount)
-		}
-		if eta > 0 && len(maskOpen.Mvals[i]) != eta {
-			return false, fmt.Errorf("VerifyNIZK: mask Mvals[%d] len=%d want=%d", i, len(maskOpen.Mvals[i]), eta)
-		}
+		return false, fmt.Errorf("VerifyNIZK: tail opening: %w: %w", err, ErrMalformedProof)
 	}
 	for i := range tailOpen.Pvals {
 		if len(tailOpen.Pvals[i]) != rowCount {
-			return false, fmt.Errorf("VerifyNIZK: tail Pvals[%d] len=%d want=%d", i, len(tailOpen.Pvals[i]), rowCount)
+			return false, fmt.Errorf("VerifyNIZK: tail Pvals[%d] len=%d want=%d: %w", i, len(tailOpen.Pvals[i]), rowCount, ErrMalformedProof)
 		}
 		if eta > 0 && len(tailOpen.Mvals[i]) != eta {
-			return false, fmt.Errorf("VerifyNIZK: tail Mvals[%d] len=%d want=%d", i, len(tailOpen.Mvals[i]), eta)
+			return false, fmt.Errorf("VerifyNIZK: tail Mvals[%d] len=%d want=%d: %w", i, len(tailOpen.Mvals[i]), eta, ErrMalformedProof)
 		}
 	}
 	subsetParams := decs.Params{Degree: params.Degree, Eta: eta, NonceBytes: params.NonceBytes}
-	if err := verifyDECSSubset(ringQ, proof.Root, subsetParams, Gamma, Rpolys, maskOpen, maskIdx); err != nil {
-		return false, fmt.Errorf("VerifyNIZK: mask subset: %w", err)
-	}
 	if err := verifyDECSSubset(ringQ, proof.Root, subsetParams, Gamma, Rpolys, tailOpen, tail); err != nil {
 		return false, fmt.Errorf("VerifyNIZK: tail subset: %w", err)
 	}
 	if len(coeffMatrix) != len(barSets) || len(coeffMatrix) != len(vTargets) {
-		return false, errors.New("VerifyNIZK: coefficient matrix dimension mismatch")
+		return false, fmt.Errorf("VerifyNIZK: coefficient matrix dimension mismatch: %w", ErrMalformedProof)
 	}
 	mod := ringQ.Modulus[0]
-	for t, idx := range maskIdx {
-		maskedPos := idx - ncols
-		row := maskOpen.Pvals[t]
-		for k := 0; k < len(barSets); k++ {
-			if len(coeffMatrix[k]) != len(row) {
-				return false, errors.New("VerifyNIZK: coeff row length mismatch")
-			}
-			sum := uint64(0)
-			for j := 0; j < len(row); j++ {
-				sum = lvcs.MulAddMod64(sum, coeffMatrix[k][j], row[j], mod)
-			}
-			if sum != barSets[k][maskedPos]%mod {
-				return false, fmt.Errorf("VerifyNIZK: masked linear relation mismatch k=%d pos=%d sum=%d target=%d", k, maskedPos, sum, barSets[k][maskedPos]%mod)
-			}
-		}
-	}
 	ell := len(barSets[0])
 	Qvals := make([]*ring.Poly, len(barSets))
 	for k := 0; k < len(barSets); k++ {
 		poly, interpErr := interpolateRowLocal(ringQ, vTargets[k], barSets[k], ncols, ell)
 		if interpErr != nil {
-			return false, fmt.Errorf("VerifyNIZK: interpolateRow(%d): %w", k, interpErr)
+			return false, fmt.Errorf("VerifyNIZK: interpolateRow(%d): %w: %w", k, interpErr, ErrMalformedProof)
 		}
 		Qvals[k] = ringQ.NewPoly()
 		ringQ.NTT(poly, Qvals[k])
@@ -672,7 +688,7 @@ func verifyLVCSConstraints(
 				sum = lvcs.MulAddMod64(sum, coeffMatrix[k][j], row[j], mod)
 			}
 			if lhs != sum {
-				return false, fmt.Errorf("VerifyNIZK: tail linear relation mismatch k=%d idx=%d lhs=%d rhs=%d", k, idx, lhs, sum)
+				return false, fmt.Errorf("VerifyNIZK: tail linear relation idx=%d: %w", idx, &ErrConstraint{Name: "LVCS.tail", Row: k})
 			}
 		}
 	}
@@ -681,7 +697,7 @@ func verifyLVCSConstraints(
 
 func buildSubsetOpening(base *decs.DECSOpening, indices []int, rowCount, eta int) (*decs.DECSOpening, error) {
 	if base == nil {
-		return nil, errors.New("nil base opening")
+		return nil, fmt.Errorf("nil base opening: %w", ErrMalformedProof)
 	}
 	if err := decs.EnsureMerkleDecoded(base); err != nil {
 		return nil, err
@@ -716,7 +732,7 @@ func buildSubsetOpening(base *decs.DECSOpening, indices []int, rowCount, eta int
 	for i, idx := range indices {
 		pos, ok := posByIdx[idx]
 		if !ok {
-			return nil, fmt.Errorf("opening missing index %d", idx)
+			return nil, fmt.Errorf("opening missing index %d: %w", idx, ErrMalformedProof)
 		}
 		sub.Indices[i] = idx
 		if len(base.Pvals) > 0 {
@@ -769,16 +785,16 @@ func interpolateRowLocal(ringQ *ring.Ring, row []uint64, mask []uint64, ncols, e
 	T[0] = 1
 	for _, xj := range xs {
 		for k := m; k >= 1; k-- {
-			T[k] = (T[k-1] + mod - (xj * T[k] % mod)) % mod
+			T[k] = (T[k-1] + mod - lvcs.MulModReduced(xj, T[k], mod)) % mod
 		}
-		T[0] = (mod - (xj * T[0] % mod)) % mod
+		T[0] = (mod - lvcs.MulModReduced(xj, T[0], mod)) % mod
 	}
 	Pcoefs := make([]uint64, m)
 	tmp := make([]uint64, m)
 	for i, xi := range xs {
 		tmp[m-1] = T[m]
 		for k := m - 2; k >= 0; k-- {
-			tmp[k] = (T[k+1] + xi*tmp[k+1]) % mod
+			tmp[k] = (T[k+1] + lvcs.MulModReduced(xi, tmp[k+1], mod)) % mod
 		}
 		denom := uint64(1)
 		for j, xj := range xs {
@@ -786,15 +802,15 @@ func interpolateRowLocal(ringQ *ring.Ring, row []uint64, mask []uint64, ncols, e
 				continue
 			}
 			diff := (xi + mod - xj) % mod
-			denom = (denom * diff) % mod
+			denom = lvcs.MulModReduced(denom, diff, mod)
 		}
 		inv := new(big.Int).ModInverse(new(big.Int).SetUint64(denom), new(big.Int).SetUint64(mod))
 		if inv == nil {
 			return nil, errors.New("interpolateRow: denom not invertible")
 		}
-		scale := (ys[i] * inv.Uint64()) % mod
+		scale := lvcs.MulModReduced(ys[i]%mod, inv.Uint64(), mod)
 		for k := 0; k < m; k++ {
-			Pcoefs[k] = (Pcoefs[k] + tmp[k]*scale) % mod
+			Pcoefs[k] = (Pcoefs[k] + lvcs.MulModReduced(tmp[k], scale, mod)) % mod
 		}
 	}
 	P := ringQ.NewPoly()
@@ -808,14 +824,14 @@ func interpolateRowLocal(ringQ *ring.Ring, row []uint64, mask []uint64, ncols, e
 func verifyDECSSubset(ringQ *ring.Ring, root [16]byte, params decs.Params, Gamma [][]uint64, R []*ring.Poly, open *decs.DECSOpening, indices []int) error {
 	entryCount := open.EntryCount()
 	if len(indices) != entryCount {
-		return fmt.Errorf("DECS subset: index length mismatch")
+		return fmt.Errorf("DECS subset: index length mismatch: %w", ErrMalformedProof)
 	}
 	rowCount := len(Gamma[0])
 	if rowCount <= 0 {
-		return fmt.Errorf("DECS subset: empty Gamma rows")
+		return fmt.Errorf("DECS subset: empty Gamma rows: %w", ErrMalformedProof)
 	}
 	if len(R) != params.Eta {
-		return fmt.Errorf("DECS subset: R count mismatch")
+		return fmt.Errorf("DECS subset: R count mismatch: %w", ErrMalformedProof)
 	}
 	Re := make([]*ring.Poly, params.Eta)
 	for k := 0; k < params.Eta; k++ {
@@ -826,26 +842,16 @@ func verifyDECSSubset(ringQ *ring.Ring, root [16]byte, params decs.Params, Gamma
 	mod := ringQ.Modulus[0]
 	for t, idx := range indices {
 		if idx < 0 || idx >= int(ringQ.N) {
-			return fmt.Errorf("DECS subset: index %d out of range", idx)
+			return fmt.Errorf("DECS subset: index %d out of range: %w", idx, ErrMalformedProof)
 		}
-		buf := make([]byte, 4*(rowCount+params.Eta)+2+params.NonceBytes)
-		off := 0
 		pvals := make([]uint64, rowCount)
 		for j := 0; j < rowCount; j++ {
-			pv := decs.GetOpeningPval(open, t, j) % mod
-			pvals[j] = pv
-			binary.LittleEndian.PutUint32(buf[off:], uint32(pv))
-			off += 4
+			pvals[j] = decs.GetOpeningPval(open, t, j) % mod
 		}
 		mvals := make([]uint64, params.Eta)
 		for k := 0; k < params.Eta; k++ {
-			mv := decs.GetOpeningMval(open, t, k) % mod
-			mvals[k] = mv
-			binary.LittleEndian.PutUint32(buf[off:], uint32(mv))
-			off += 4
+			mvals[k] = decs.GetOpeningMval(open, t, k) % mod
 		}
-		binary.LittleEndian.PutUint16(buf[off:], uint16(idx))
-		off += 2
 		var nonce []byte
 		if len(open.Nonces) > t && len(open.Nonces[t]) > 0 {
 			nonce = open.Nonces[t]
@@ -853,15 +859,15 @@ func verifyDECSSubset(ringQ *ring.Ring, root [16]byte, params decs.Params, Gamma
 			nonce = decs.DeriveNonce(open.NonceSeed, idx, open.NonceBytes)
 		}
 		if len(nonce) != params.NonceBytes {
-			return fmt.Errorf("DECS subset: nonce length mismatch at t=%d", t)
+			return fmt.Errorf("DECS subset: nonce length mismatch at t=%d: %w", t, ErrMalformedProof)
 		}
-		copy(buf[off:], nonce[:params.NonceBytes])
+		buf := decs.EncodeLeaf(pvals, mvals, idx, nonce[:params.NonceBytes], decs.LeafResidueBytes(mod))
 		path, err := extractPathNodes(open, t)
 		if err != nil {
 			return fmt.Errorf("DECS subset: %w", err)
 		}
 		if !decs.VerifyPath(buf, path, root, idx) {
-			return fmt.Errorf("DECS subset: Merkle verification failed at idx=%d", idx)
+			return fmt.Errorf("DECS subset: idx=%d: %w", idx, ErrMerklePath)
 		}
 		for k := 0; k < params.Eta; k++ {
 			lhs := Re[k].Coeffs[0][idx] % mod
@@ -870,7 +876,7 @@ func verifyDECSSubset(ringQ *ring.Ring, root [16]byte, params decs.Params, Gamma
 				rhs = lvcs.MulAddMod64(rhs, Gamma[k][j], pvals[j], mod)
 			}
 			if lhs != rhs%mod {
-				return fmt.Errorf("DECS subset: relation mismatch k=%d idx=%d lhs=%d rhs=%d", k, idx, lhs, rhs%mod)
+				return fmt.Errorf("DECS subset: idx=%d: %w", idx, &ErrConstraint{Name: "DECS.eval", Row: k})
 			}
 		}
 	}
@@ -882,12 +888,12 @@ func extractPathNodes(open *decs.DECSOpening, t int) ([][]byte, error) {
 		return nil, err
 	}
 	if len(open.PathIndex) == 0 || t < 0 || t >= len(open.PathIndex) {
-		return nil, errors.New("missing path indices")
+		return nil, fmt.Errorf("missing path indices: %w", ErrMalformedProof)
 	}
 	path := make([][]byte, len(open.PathIndex[t]))
 	for lvl, id := range open.PathIndex[t] {
 		if id < 0 || id >= len(open.Nodes) {
-			return nil, fmt.Errorf("path node index out of range at t=%d lvl=%d", t, lvl)
+			return nil, fmt.Errorf("path node index out of range at t=%d lvl=%d: %w", t, lvl, ErrMalformedProof)
 		}
 		path[lvl] = open.Nodes[id]
 	}
@@ -1,6 +1,10 @@
 package PIOP
 
-import "fmt"
+import (
+	"fmt"
+
+	lvcs "vSIS-Signature/LVCS"
+)
 
 // LinfSpec holds parameters for the membership-chain ℓ∞ bound proof.
 type LinfSpec struct {
@@ -113,7 +117,7 @@ func NewLinfChainSpec(q uint64, W, L, ell int, beta uint64) LinfSpec {
 	RPows := make([]uint64, L)
 	RPows[0] = 1 % q
 	for i := 1; i < L; i++ {
-		RPows[i] = (RPows[i-1] * (R % q)) % q
+		RPows[i] = lvcs.MulModReduced(RPows[i-1], R%q, q)
 	}
 	return LinfSpec{
 		Q: q, R: R, W: W, L: L, Ell: ell,
@@ -149,7 +153,7 @@ func NewRangeMembershipSpec(q uint64, B int) RangeMembershipSpec {
 		next := make([]uint64, len(coeffs)+1)
 		for d := range coeffs {
 			next[d+1] = (next[d+1] + coeffs[d]) % q
-			next[d] = (next[d] + q - (coeffs[d]*r)%q) % q
+			next[d] = (next[d] + q - lvcs.MulModReduced(coeffs[d], r, q)) % q
 		}
 		coeffs = next
 	}
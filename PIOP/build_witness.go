@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"os"
 	"time"
+	lvcs "vSIS-Signature/LVCS"
 	ntru "vSIS-Signature/ntru"
 	ntrurio "vSIS-Signature/ntru/io"
 	ntrukeys "vSIS-Signature/ntru/keys"
@@ -48,7 +49,7 @@ func mulScalarNTT(r *ring.Ring, p *ring.Poly, c uint64, dst *ring.Poly) {
 
 	// Montgomery representation: coefficient-wise multiplication
 	for i := range p.Coeffs[0] {
-		dst.Coeffs[0][i] = (p.Coeffs[0][i] * c) % q
+		dst.Coeffs[0][i] = lvcs.MulModReduced(p.Coeffs[0][i]%q, c, q)
 	}
 }
 
@@ -258,6 +259,8 @@ func BuildWitnessFromDisk() (w1 []*ring.Poly, w2 *ring.Poly, w3 []*ring.Poly, er
 	A[0][0], A[0][1] = one, negHCoeff
 
 	// ‣ 2. B-matrix columns  (stored in coefficient domain → lift) -------------
+	//    The standalone file, as ntru/signverify signs under it; credential
+	//    statements take B from their bundle instead (credential.LoadHashKey).
 	Bcoeffs, err := ntrurio.LoadBMatrixCoeffs(resolve("Parameters/Bmatrix.json"))
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("load Bmatrix: %w", err)
@@ -1,6 +1,10 @@
 package PIOP
 
-import "github.com/tuneinsight/lattigo/v4/ring"
+import (
+	"context"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
 
 // PublicInputs collects public values for a statement build; unused fields can stay nil.
 type PublicInputs struct {
@@ -15,7 +19,32 @@ type PublicInputs struct {
 	Nonce  [][]int64
 	U      []*ring.Poly
 	BoundB int64
-	Extras map[string]interface{}
+	// Blocks gives the credential block lengths; the zero value means one
+	// polynomial per block.
+	Blocks CredentialBlocks
+	// ParamsDigest is the digest of the public-parameter bundle B, Ac and A
+	// come from (pubparams.Bundle.Digest); nil for statements whose
+	// matrices are not loaded from a bundle.
+	ParamsDigest []byte
+	// Policy adds disclosure and predicate constraints on M1 to a showing
+	// (BuildShowingCombined only); nil for none.
+	Policy *ShowingPolicy
+	// Scope makes a showing a pseudonym showing for that scope (a verifier
+	// identifier, see showing_nym.go): Nonce must be prf.ScopeNonce(Scope)
+	// and the PRF key is bound to M2, so Tag is the holder's pseudonym for
+	// the scope. BuildShowingCombined only; empty for a one-time tag.
+	Scope string
+	// BindKey binds the PRF key to M2 as a pseudonym showing does, but under
+	// the public Nonce: a holder then has one tag per nonce, which is what a
+	// rate limit over a set of nonces counts on. Scope and NonceDomain imply
+	// it.
+	BindKey bool
+	// NonceDomain hides the nonce of a showing (see showing_nonce.go): Nonce
+	// stays empty, the nonce lanes are proven to lie in the domain and the
+	// PRF key is bound to M2.
+	// BuildShowingCombined only; nil for a public nonce.
+	NonceDomain *NonceDomain
+	Extras      map[string]interface{}
 }
 
 // WitnessInputs collects witness vectors.
@@ -31,7 +60,10 @@ type WitnessInputs struct {
 	// RU* + RI* = R* + (2B+1)·K* with K* ∈ {-1,0,1}.
 	K0 []*ring.Poly
 	K1 []*ring.Poly
-	U  []*ring.Poly
+	// Quot holds the quotient digit rows of a lifted statement (see lift.go),
+	// committed right after K1.
+	Quot []*ring.Poly
+	U    []*ring.Poly
 	// T can be kept internal (hash output) when not exposed as public.
 	T      []int64
 	Extras map[string]interface{}
@@ -48,6 +80,18 @@ type ConstraintSet struct {
 
 	// Optional PRF layout for showing-mode verification from openings.
 	PRFLayout *PRFLayout
+
+	// Optional batch layout: several showings merged side by side into one
+	// showing statement (see BuildShowingBatch). It only changes the labels
+	// the transcript binds.
+	Batch *BatchLayout
+}
+
+// BatchLayout lists the showings of a batch proof. Showing s occupies its
+// own ncols points of Ω in every row (see batchSlot), and the transcript
+// binds Publics in order.
+type BatchLayout struct {
+	Publics []PublicInputs
 }
 
 // PRFLayout carries enough metadata to locate and verify the PRF trace in the
@@ -67,3 +111,12 @@ type StatementBuilder interface {
 	Build(pub PublicInputs, wit WitnessInputs, cfg MaskConfig) (*Proof, error)
 	Verify(pub PublicInputs, proof *Proof) (bool, error)
 }
+
+// ContextStatementBuilder is a StatementBuilder whose build and verify steps
+// can be cancelled. Both built-in builders implement it; a cancelled run
+// returns a *CanceledError.
+type ContextStatementBuilder interface {
+	StatementBuilder
+	BuildContext(ctx context.Context, pub PublicInputs, wit WitnessInputs, cfg MaskConfig) (*Proof, error)
+	VerifyContext(ctx context.Context, pub PublicInputs, proof *Proof) (bool, error)
+}
@@ -0,0 +1,17 @@
+package PIOP
+
+import (
+	"context"
+
+	ntru "vSIS-Signature/ntru"
+)
+
+// CanceledError is returned by the context-aware prover and verifier entry
+// points (the *Context variants) when ctx is done before they finish. It is
+// shared with the NTRU signer so callers can match a single type.
+type CanceledError = ntru.CanceledError
+
+// checkCtx reports a *CanceledError for op once ctx is done.
+func checkCtx(ctx context.Context, op string) error {
+	return ntru.CheckContext(ctx, op)
+}
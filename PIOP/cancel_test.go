@@ -0,0 +1,43 @@
+package PIOP
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunOnceContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := RunOnceContext(ctx, defaultSimOpts())
+	var ce *CanceledError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *CanceledError, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled in chain, got %v", err)
+	}
+}
+
+func TestRunOnceContextDeadlineDuringGrinding(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	opts := defaultSimOpts()
+	// 64-bit grinding never terminates on its own.
+	opts.Kappa = [4]int{64, 0, 0, 0}
+	_, err := RunOnceContext(ctx, opts)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestVerifyNIZKContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, _, err := VerifyNIZKContext(ctx, &Proof{})
+	var ce *CanceledError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *CanceledError, got %v", err)
+	}
+}
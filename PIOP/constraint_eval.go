@@ -46,6 +46,9 @@ type EvalKInput struct {
 	CarryRows    []int
 	BoundB       int64
 	CarryBound   int64
+	// Report, when non-nil, switches to diagnostic mode: every K-point is
+	// replayed and mismatches are recorded instead of returned on first hit.
+	Report *VerificationReport
 }
 
 // EvalTailInput bundles the tail-opening material needed to replay Eq.(4)
@@ -59,6 +62,10 @@ type EvalTailInput struct {
 	GammaAgg   [][]uint64
 	Ring       *ring.Ring
 	RowCount   int
+	// Fpar (NTT) and Report enable diagnostic mode as in EvalKInput; Fpar is
+	// only read to compare replayed residuals with the committed ones.
+	Fpar   []*ring.Poly
+	Report *VerificationReport
 }
 
 // ConstraintEvaluator evaluates all constraint residuals at the provided
@@ -74,13 +81,21 @@ type KConstraintEvaluator func(e kf.Elem, rowVals []kf.Elem) (fpar []kf.Elem, fa
 // When provided to the verifier, precomputed F-polys are ignored and residuals
 // are recomputed directly from row openings.
 type ConstraintReplay struct {
+	// Ring is the ring the proof was built over; nil means the ring of
+	// Parameters/Parameters.json.
+	Ring     *ring.Ring
 	Eval     ConstraintEvaluator
 	EvalK    KConstraintEvaluator
 	RowCount int
+	// Report collects per-family diagnostics when set (see VerificationReport).
+	Report     *VerificationReport
 	BoundRows  []int
 	CarryRows  []int
 	BoundB     int64
 	CarryBound int64
+	// oracle replaces SHAKE-256 when replaying the FS rounds; it is the
+	// SimOpts oracle the proof was built with (nil = SHAKE-256).
+	oracle XOF
 }
 
 func composeEvaluators(a, b ConstraintEvaluator) ConstraintEvaluator {
@@ -192,6 +207,23 @@ func EvaluateConstraintsOnEvals(eval ConstraintEvaluator, in EvalInput) (bool, e
 	return true, nil
 }
 
+// kPolyTableLen is the MultiEval length covering the QK/MK degrees and, when
+// a ring is given, the F-polynomials re-evaluated from it.
+func kPolyTableLen(in EvalKInput) int {
+	n := 0
+	if in.Ring != nil {
+		n = in.Ring.N
+	}
+	for _, kps := range [][]*KPoly{in.QK, in.MK} {
+		for _, kp := range kps {
+			if kp != nil {
+				n = max(n, kp.Degree+1)
+			}
+		}
+	}
+	return n
+}
+
 // EvaluateConstraintsOnKPoints replays Eq.(4) at K-points using row values
 // reconstructed from VTargets and the provided constraint evaluator.
 func EvaluateConstraintsOnKPoints(eval KConstraintEvaluator, in EvalKInput) (bool, error) {
@@ -211,8 +243,25 @@ func EvaluateConstraintsOnKPoints(eval KConstraintEvaluator, in EvalKInput) (boo
 		return false, fmt.Errorf("missing QK/MK")
 	}
 	rho := len(in.QK)
+	var firstErr error
+	var fparCoeff [][]uint64
+	if in.Report != nil && in.Ring != nil {
+		fparCoeff = make([][]uint64, len(in.Fpar))
+		tmp := in.Ring.NewPoly()
+		for j, p := range in.Fpar {
+			if p == nil {
+				continue
+			}
+			in.Ring.InvNTT(p, tmp)
+			fparCoeff[j] = append([]uint64(nil), tmp.Coeffs[0]...)
+		}
+	}
+	points := make([]kf.Elem, len(in.KPoints))
 	for kpIdx, limbs := range in.KPoints {
-		e := in.K.Phi(limbs)
+		points[kpIdx] = in.K.Phi(limbs)
+	}
+	me := in.K.NewMultiEval(points, kPolyTableLen(in))
+	for kpIdx, e := range points {
 		var rowVals []kf.Elem
 		var err error
 		if len(in.RowEvals) > 0 {
@@ -240,15 +289,24 @@ func EvaluateConstraintsOnKPoints(eval KConstraintEvaluator, in EvalKInput) (boo
 					continue
 				}
 				in.Ring.InvNTT(in.Fpar[idx], tmp)
-				fpar[idx] = in.K.EvalFPolyAtK(tmp.Coeffs[0], e)
+				fpar[idx] = me.EvalFAt(kpIdx, tmp.Coeffs[0])
+			}
+		}
+		if in.Report != nil {
+			for j, val := range fpar {
+				if j >= len(fparCoeff) || fparCoeff[j] == nil {
+					continue
+				}
+				committed := me.EvalFAt(kpIdx, fparCoeff[j])
+				in.Report.recordResidual(uint64(kpIdx), true, j, kElemLimbs(in.K, val), kElemLimbs(in.K, committed))
 			}
 		}
 		for i := 0; i < rho; i++ {
 			if i >= len(in.MK) || in.QK[i] == nil || in.MK[i] == nil {
 				return false, fmt.Errorf("missing K polys at row %d", i)
 			}
-			lhs := evalKPolyAtK(in.K, in.QK[i], e)
-			rhs := evalKPolyAtK(in.K, in.MK[i], e)
+			lhs := evalKPolyMulti(me, kpIdx, in.QK[i])
+			rhs := evalKPolyMulti(me, kpIdx, in.MK[i])
 			if i < len(in.GammaPrimeK) {
 				rowGamma := in.GammaPrimeK[i]
 				for j, val := range fpar {
@@ -270,10 +328,20 @@ func EvaluateConstraintsOnKPoints(eval KConstraintEvaluator, in EvalKInput) (boo
 				}
 			}
 			if !elemEqual(in.K, lhs, rhs) {
-				return false, fmt.Errorf("eq4 K-point mismatch at kp=%d row=%d", kpIdx, i)
+				err := fmt.Errorf("eq4 K-point mismatch at kp=%d row=%d", kpIdx, i)
+				if in.Report == nil {
+					return false, err
+				}
+				in.Report.recordEq4(uint64(kpIdx), true, i, kElemLimbs(in.K, lhs), kElemLimbs(in.K, rhs))
+				if firstErr == nil {
+					firstErr = err
+				}
 			}
 		}
 	}
+	if firstErr != nil {
+		return false, firstErr
+	}
 	return true, nil
 }
 
@@ -320,6 +388,7 @@ func EvaluateConstraintsOnTailOpen(eval ConstraintEvaluator, in EvalTailInput) (
 		posByIdxMask[in.MaskOpen.IndexAt(pos)] = pos
 	}
 	rho := len(in.Q)
+	var firstErr error
 	for _, idx := range in.Tail {
 		posRow, ok := posByIdxRow[idx]
 		if !ok {
@@ -341,6 +410,15 @@ func EvaluateConstraintsOnTailOpen(eval ConstraintEvaluator, in EvalTailInput) (
 		if coeffPos < 0 {
 			coeffPos += N
 		}
+		if in.Report != nil {
+			for j, val := range fpar {
+				if j >= len(in.Fpar) || in.Fpar[j] == nil || coeffPos >= len(in.Fpar[j].Coeffs[0]) {
+					continue
+				}
+				committed := in.Fpar[j].Coeffs[0][coeffPos] % q
+				in.Report.recordResidual(uint64(idx), false, j, []uint64{val % q}, []uint64{committed})
+			}
+		}
 		for i := 0; i < rho; i++ {
 			if i >= len(in.Q) || in.Q[i] == nil || coeffPos >= len(in.Q[i].Coeffs[0]) {
 				return false, fmt.Errorf("invalid Q at row %d idx %d", i, idx)
@@ -366,10 +444,20 @@ func EvaluateConstraintsOnTailOpen(eval ConstraintEvaluator, in EvalTailInput) (
 				}
 			}
 			if lhs != rhs {
-				return false, fmt.Errorf("eq4 tail replay mismatch idx=%d row=%d lhs=%d rhs=%d", idx, i, lhs, rhs)
+				err := fmt.Errorf("eq4 tail replay mismatch idx=%d row=%d lhs=%d rhs=%d", idx, i, lhs, rhs)
+				if in.Report == nil {
+					return false, err
+				}
+				in.Report.recordEq4(uint64(idx), false, i, []uint64{lhs}, []uint64{rhs})
+				if firstErr == nil {
+					firstErr = err
+				}
 			}
 		}
 	}
+	if firstErr != nil {
+		return false, firstErr
+	}
 	return true, nil
 }
 
@@ -392,6 +480,8 @@ type CredentialConstraintConfig struct {
 	PackingSelNTT []uint64
 	PackingNCols  int
 
+	// Idx* give the first row of each block; Blocks gives the block lengths
+	// (zero value: one row per block).
 	IdxM1  int
 	IdxM2  int
 	IdxRU0 int
@@ -402,10 +492,15 @@ type CredentialConstraintConfig struct {
 	IdxK0  int
 	IdxK1  int
 	IdxT   int // optional: T as witness row
+	Blocks CredentialBlocks
 
 	BoundRows []int
 	CarryRows []int
 
+	// Lift subtracts q·k from the commit, center and hash residuals of a
+	// lifted statement; its quotient rows must also be listed in BoundRows.
+	Lift *LiftLayout
+
 	Omega []uint64
 }
 
@@ -421,6 +516,8 @@ type PostSignConstraintConfig struct {
 	PackingSelNTT []uint64
 	PackingNCols  int
 
+	// Idx* give the first row of each block; Blocks gives the block lengths
+	// (zero value: one row per block).
 	IdxM1    int
 	IdxM2    int
 	IdxR0    int
@@ -428,6 +525,7 @@ type PostSignConstraintConfig struct {
 	IdxT     int
 	IdxUBase int
 	UCount   int
+	Blocks   CredentialBlocks
 
 	BoundRows []int
 
@@ -454,6 +552,8 @@ type PRFConstraintConfig struct {
 // CredentialEvaluator builds a ConstraintEvaluator for the credential
 // pre-sign constraints (commit, center, hash, bounds).
 func (cfg CredentialConstraintConfig) CredentialEvaluator() ConstraintEvaluator {
+	commitRows := cfg.commitRows()
+	n := cfg.Blocks.norm()
 	return func(evalIdx uint64, rows []uint64) ([]uint64, []uint64, error) {
 		if cfg.Ring == nil {
 			return nil, nil, fmt.Errorf("nil ring")
@@ -487,65 +587,47 @@ func (cfg CredentialConstraintConfig) CredentialEvaluator() ConstraintEvaluator
 					if ptIdx >= len(cfg.Ac[i][j].Coeffs[0]) {
 						continue
 					}
-					vecIdx := []int{cfg.IdxM1, cfg.IdxM2, cfg.IdxRU0, cfg.IdxRU1, cfg.IdxR}
-					if j < len(vecIdx) {
-						sum = lvcs.MulAddMod64(sum, cfg.Ac[i][j].Coeffs[0][ptIdx]%q, getRow(vecIdx[j]), q)
+					if j < len(commitRows) {
+						sum = lvcs.MulAddMod64(sum, cfg.Ac[i][j].Coeffs[0][ptIdx]%q, getRow(commitRows[j]), q)
 					}
 				}
 				fpar[i] = sum % q
 			}
 		}
 
-		// Center constraints (two residuals, paper-faithful wrap form).
+		// Center constraints (one residual per RU0 then RU1 index,
+		// paper-faithful wrap form).
 		if cfg.Bound > 0 {
 			delta := uint64(2*cfg.Bound + 1)
-			ru0 := getRow(cfg.IdxRU0)
-			ru1 := getRow(cfg.IdxRU1)
-			r0 := getRow(cfg.IdxR0)
-			r1 := getRow(cfg.IdxR1)
-			k0 := getRow(cfg.IdxK0)
-			k1 := getRow(cfg.IdxK1)
-			ri0 := cfg.getRI(cfg.RI0, ptIdx)
-			ri1 := cfg.getRI(cfg.RI1, ptIdx)
-			res0 := (ru0 + ri0 + q - r0) % q
-			res0 = (res0 + q - (delta*k0)%q) % q
-			res1 := (ru1 + ri1 + q - r1) % q
-			res1 = (res1 + q - (delta*k1)%q) % q
-			fpar = append(fpar, res0%q, res1%q)
+			center := func(ru, ri, r, k uint64) uint64 {
+				res := (ru + ri + q - r) % q
+				return (res + q - lvcs.MulModReduced(delta%q, k, q)) % q
+			}
+			for i := 0; i < n.RU0; i++ {
+				fpar = append(fpar, center(getRow(cfg.IdxRU0+i), cfg.getRI(cfg.RI0, i, ptIdx), getRow(cfg.IdxR0+i), getRow(cfg.IdxK0+i)))
+			}
+			for i := 0; i < n.RU1; i++ {
+				fpar = append(fpar, center(getRow(cfg.IdxRU1+i), cfg.getRI(cfg.RI1, i, ptIdx), getRow(cfg.IdxR1+i), getRow(cfg.IdxK1+i)))
+			}
 		}
 
 		// Hash residual (cleared denominator form).
-		if len(cfg.B) >= 4 && ptIdx >= 0 {
-			b0 := cfg.B[0].Coeffs[0][ptIdx] % q
-			b1 := cfg.B[1].Coeffs[0][ptIdx] % q
-			b2 := cfg.B[2].Coeffs[0][ptIdx] % q
-			b3 := cfg.B[3].Coeffs[0][ptIdx] % q
-			m1 := getRow(cfg.IdxM1)
-			m2 := getRow(cfg.IdxM2)
-			r0v := getRow(cfg.IdxR0)
-			r1v := getRow(cfg.IdxR1)
-			t := cfg.getT(ptIdx, rows)
-			// (B3 - R1)*T - (B0 + B1*(M1+M2) + B2*R0)
-			res := (q + b3 - r1v) % q
-			res = (res * t) % q
-			mCombined := (m1 + m2) % q
-			lin := b0
-			lin = lvcs.MulAddMod64(lin, b1, mCombined, q)
-			lin = lvcs.MulAddMod64(lin, b2, r0v, q)
-			if res >= lin {
-				res = (res - lin) % q
-			} else {
-				res = (res + q - lin) % q
+		if len(cfg.B) >= cfg.Blocks.HashLayout().KeyLen() && ptIdx >= 0 {
+			key := func(k int) uint64 { return cfg.B[k].Coeffs[0][ptIdx] % q }
+			res := hashResidualAt(q, cfg.Blocks, key, getRow, cfg.IdxM1, cfg.IdxM2, cfg.IdxR0, cfg.IdxR1, cfg.getT(ptIdx, rows))
+			fpar = append(fpar, res)
+		}
+
+		// Lifted statements: subtract q·k from commit, center and hash.
+		if cfg.Lift != nil && len(fpar) == cfg.Lift.Relations() {
+			for rel := range fpar {
+				fpar[rel] = (fpar[rel] + q - cfg.Lift.quotient(rel, getRow, q)) % q
 			}
-			fpar = append(fpar, res%q)
 		}
 
 		// Packing residuals: enforce lower/upper-half zeroing (evaluation-domain proxy).
 		if len(cfg.PackingSelNTT) > 0 && ptIdx >= 0 && ptIdx < len(cfg.PackingSelNTT) {
-			sel := cfg.PackingSelNTT[ptIdx] % q
-			oneMinus := (1 + q - sel) % q
-			fpar = append(fpar, lvcs.MulMod64(sel, getRow(cfg.IdxM1), q))
-			fpar = append(fpar, lvcs.MulMod64(oneMinus, getRow(cfg.IdxM2), q))
+			fpar = append(fpar, packingAt(q, cfg.PackingSelNTT[ptIdx]%q, cfg.Blocks, getRow, cfg.IdxM1, cfg.IdxM2)...)
 		}
 
 		// Bounds: P_B(row) for configured rows (evaluation-domain).
@@ -571,6 +653,7 @@ func (cfg CredentialConstraintConfig) CredentialEvaluator() ConstraintEvaluator
 // PostSignEvaluator builds a ConstraintEvaluator for post-sign constraints:
 // A·U = T, hash cleared-denominator, packing, and bounds.
 func (cfg PostSignConstraintConfig) PostSignEvaluator() ConstraintEvaluator {
+	keyLen := cfg.Blocks.HashLayout().KeyLen()
 	return func(evalIdx uint64, rows []uint64) ([]uint64, []uint64, error) {
 		if cfg.Ring == nil {
 			return nil, nil, fmt.Errorf("nil ring")
@@ -609,36 +692,14 @@ func (cfg PostSignConstraintConfig) PostSignEvaluator() ConstraintEvaluator {
 		}
 
 		// Hash residual (cleared denominator).
-		if len(cfg.B) >= 4 && ptIdx >= 0 {
-			b0 := cfg.B[0].Coeffs[0][ptIdx] % q
-			b1 := cfg.B[1].Coeffs[0][ptIdx] % q
-			b2 := cfg.B[2].Coeffs[0][ptIdx] % q
-			b3 := cfg.B[3].Coeffs[0][ptIdx] % q
-			m1 := getRow(cfg.IdxM1)
-			m2 := getRow(cfg.IdxM2)
-			r0v := getRow(cfg.IdxR0)
-			r1v := getRow(cfg.IdxR1)
-			t := getRow(cfg.IdxT)
-			res := (q + b3 - r1v) % q
-			res = (res * t) % q
-			mCombined := (m1 + m2) % q
-			lin := b0
-			lin = lvcs.MulAddMod64(lin, b1, mCombined, q)
-			lin = lvcs.MulAddMod64(lin, b2, r0v, q)
-			if res >= lin {
-				res = (res - lin) % q
-			} else {
-				res = (res + q - lin) % q
-			}
-			fpar = append(fpar, res%q)
+		if len(cfg.B) >= keyLen && ptIdx >= 0 {
+			key := func(k int) uint64 { return cfg.B[k].Coeffs[0][ptIdx] % q }
+			fpar = append(fpar, hashResidualAt(q, cfg.Blocks, key, getRow, cfg.IdxM1, cfg.IdxM2, cfg.IdxR0, cfg.IdxR1, getRow(cfg.IdxT)))
 		}
 
 		// Packing residuals.
 		if len(cfg.PackingSelNTT) > 0 && ptIdx >= 0 && ptIdx < len(cfg.PackingSelNTT) {
-			sel := cfg.PackingSelNTT[ptIdx] % q
-			oneMinus := (1 + q - sel) % q
-			fpar = append(fpar, lvcs.MulMod64(sel, getRow(cfg.IdxM1), q))
-			fpar = append(fpar, lvcs.MulMod64(oneMinus, getRow(cfg.IdxM2), q))
+			fpar = append(fpar, packingAt(q, cfg.PackingSelNTT[ptIdx]%q, cfg.Blocks, getRow, cfg.IdxM1, cfg.IdxM2)...)
 		}
 
 		// Bounds on configured rows.
@@ -746,31 +807,15 @@ func (cfg PostSignConstraintConfig) PostSignKEvaluator(K *kf.Field) (KConstraint
 		}
 
 		// Hash residual.
-		if len(cache.BCoeff) >= 4 {
-			b0 := K.EvalFPolyAtK(cache.BCoeff[0], e)
-			b1 := K.EvalFPolyAtK(cache.BCoeff[1], e)
-			b2 := K.EvalFPolyAtK(cache.BCoeff[2], e)
-			b3 := K.EvalFPolyAtK(cache.BCoeff[3], e)
-			m1 := getRow(cfg.IdxM1)
-			m2 := getRow(cfg.IdxM2)
-			r0v := getRow(cfg.IdxR0)
-			r1v := getRow(cfg.IdxR1)
-			t := getRow(cfg.IdxT)
-			res := K.Sub(b3, r1v)
-			res = K.Mul(res, t)
-			lin := b0
-			lin = K.Add(lin, K.Mul(b1, K.Add(m1, m2)))
-			lin = K.Add(lin, K.Mul(b2, r0v))
-			res = K.Sub(res, lin)
-			fpar = append(fpar, res)
+		if len(cache.BCoeff) >= cfg.Blocks.HashLayout().KeyLen() {
+			key := func(k int) kf.Elem { return K.EvalFPolyAtK(cache.BCoeff[k], e) }
+			fpar = append(fpar, hashResidualAtK(K, cfg.Blocks, key, getRow, cfg.IdxM1, cfg.IdxM2, cfg.IdxR0, cfg.IdxR1, getRow(cfg.IdxT)))
 		}
 
 		// Packing residuals.
 		if len(cache.PackingSelCoeff) > 0 {
 			sel := K.EvalFPolyAtK(cache.PackingSelCoeff, e)
-			oneMinus := K.Sub(K.One(), sel)
-			fpar = append(fpar, K.Mul(sel, getRow(cfg.IdxM1)))
-			fpar = append(fpar, K.Mul(oneMinus, getRow(cfg.IdxM2)))
+			fpar = append(fpar, packingAtK(K, sel, cfg.Blocks, getRow, cfg.IdxM1, cfg.IdxM2)...)
 		}
 
 		// Bounds.
@@ -814,34 +859,13 @@ func (cfg PostSignConstraintConfig) PostSignEvaluatorCore() ConstraintEvaluator
 			}
 		}
 		// Hash residual.
-		if len(cfg.B) >= 4 {
-			b0 := cfg.B[0].Coeffs[0][ptIdx] % q
-			b1 := cfg.B[1].Coeffs[0][ptIdx] % q
-			b2 := cfg.B[2].Coeffs[0][ptIdx] % q
-			b3 := cfg.B[3].Coeffs[0][ptIdx] % q
-			m1 := getRow(cfg.IdxM1)
-			m2 := getRow(cfg.IdxM2)
-			r0v := getRow(cfg.IdxR0)
-			r1v := getRow(cfg.IdxR1)
-			t := getRow(cfg.IdxT)
-			res := (b3 + q - r1v) % q
-			res = lvcs.MulAddMod64(0, res, t, q)
-			lin := b0
-			lin = lvcs.MulAddMod64(lin, b1, (m1+m2)%q, q)
-			lin = lvcs.MulAddMod64(lin, b2, r0v, q)
-			if res >= lin {
-				res -= lin
-			} else {
-				res += q - lin
-			}
-			fpar = append(fpar, res%q)
+		if len(cfg.B) >= cfg.Blocks.HashLayout().KeyLen() {
+			key := func(k int) uint64 { return cfg.B[k].Coeffs[0][ptIdx] % q }
+			fpar = append(fpar, hashResidualAt(q, cfg.Blocks, key, getRow, cfg.IdxM1, cfg.IdxM2, cfg.IdxR0, cfg.IdxR1, getRow(cfg.IdxT)))
 		}
 		// Packing residuals.
 		if len(cfg.PackingSelNTT) > 0 {
-			sel := cfg.PackingSelNTT[ptIdx] % q
-			oneMinus := (1 + q - sel) % q
-			fpar = append(fpar, lvcs.MulMod64(sel, getRow(cfg.IdxM1), q))
-			fpar = append(fpar, lvcs.MulMod64(oneMinus, getRow(cfg.IdxM2), q))
+			fpar = append(fpar, packingAt(q, cfg.PackingSelNTT[ptIdx]%q, cfg.Blocks, getRow, cfg.IdxM1, cfg.IdxM2)...)
 		}
 		return fpar, nil, nil
 	}
@@ -901,29 +925,13 @@ func (cfg PostSignConstraintConfig) PostSignKEvaluatorCore(K *kf.Field) (KConstr
 				fpar = append(fpar, K.Sub(sum, tVal))
 			}
 		}
-		if len(cache.BCoeff) >= 4 {
-			b0 := K.EvalFPolyAtK(cache.BCoeff[0], e)
-			b1 := K.EvalFPolyAtK(cache.BCoeff[1], e)
-			b2 := K.EvalFPolyAtK(cache.BCoeff[2], e)
-			b3 := K.EvalFPolyAtK(cache.BCoeff[3], e)
-			m1 := getRow(cfg.IdxM1)
-			m2 := getRow(cfg.IdxM2)
-			r0v := getRow(cfg.IdxR0)
-			r1v := getRow(cfg.IdxR1)
-			t := getRow(cfg.IdxT)
-			res := K.Sub(b3, r1v)
-			res = K.Mul(res, t)
-			lin := b0
-			lin = K.Add(lin, K.Mul(b1, K.Add(m1, m2)))
-			lin = K.Add(lin, K.Mul(b2, r0v))
-			res = K.Sub(res, lin)
-			fpar = append(fpar, res)
+		if len(cache.BCoeff) >= cfg.Blocks.HashLayout().KeyLen() {
+			key := func(k int) kf.Elem { return K.EvalFPolyAtK(cache.BCoeff[k], e) }
+			fpar = append(fpar, hashResidualAtK(K, cfg.Blocks, key, getRow, cfg.IdxM1, cfg.IdxM2, cfg.IdxR0, cfg.IdxR1, getRow(cfg.IdxT)))
 		}
 		if len(cache.PackingSelCoeff) > 0 {
 			sel := K.EvalFPolyAtK(cache.PackingSelCoeff, e)
-			oneMinus := K.Sub(K.One(), sel)
-			fpar = append(fpar, K.Mul(sel, getRow(cfg.IdxM1)))
-			fpar = append(fpar, K.Mul(oneMinus, getRow(cfg.IdxM2)))
+			fpar = append(fpar, packingAtK(K, sel, cfg.Blocks, getRow, cfg.IdxM1, cfg.IdxM2)...)
 		}
 		return fpar, nil, nil
 	}, nil
@@ -966,6 +974,8 @@ func (cfg CredentialConstraintConfig) CredentialKEvaluator(K *kf.Field) (KConstr
 	if err != nil {
 		return nil, err
 	}
+	commitRows := cfg.commitRows()
+	n := cfg.Blocks.norm()
 	return func(e kf.Elem, rows []kf.Elem) ([]kf.Elem, []kf.Elem, error) {
 		q := cfg.Ring.Modulus[0]
 		getRow := func(idx int) kf.Elem {
@@ -979,16 +989,15 @@ func (cfg CredentialConstraintConfig) CredentialKEvaluator(K *kf.Field) (KConstr
 		var fpar []kf.Elem
 		if len(cache.AcCoeff) > 0 {
 			fpar = make([]kf.Elem, len(cache.AcCoeff))
-			vecIdx := []int{cfg.IdxM1, cfg.IdxM2, cfg.IdxRU0, cfg.IdxRU1, cfg.IdxR}
 			for i := range cache.AcCoeff {
 				sum := K.Zero()
 				if i < len(cache.ComCoeff) {
 					comVal := K.EvalFPolyAtK(cache.ComCoeff[i], e)
 					sum = K.Sub(sum, comVal)
 				}
-				for j := 0; j < len(cache.AcCoeff[i]) && j < len(vecIdx); j++ {
+				for j := 0; j < len(cache.AcCoeff[i]) && j < len(commitRows); j++ {
 					aVal := K.EvalFPolyAtK(cache.AcCoeff[i][j], e)
-					sum = K.Add(sum, K.Mul(aVal, getRow(vecIdx[j])))
+					sum = K.Add(sum, K.Mul(aVal, getRow(commitRows[j])))
 				}
 				fpar[i] = sum
 			}
@@ -998,29 +1007,24 @@ func (cfg CredentialConstraintConfig) CredentialKEvaluator(K *kf.Field) (KConstr
 		if cfg.Bound > 0 {
 			delta := uint64(2*cfg.Bound + 1)
 			deltaK := K.EmbedF(delta % q)
-			ru0 := getRow(cfg.IdxRU0)
-			ru1 := getRow(cfg.IdxRU1)
-			r0 := getRow(cfg.IdxR0)
-			r1 := getRow(cfg.IdxR1)
-			k0 := getRow(cfg.IdxK0)
-			k1 := getRow(cfg.IdxK1)
-			ri0 := K.EvalFPolyAtK(cache.RI0Coeff, e)
-			ri1 := K.EvalFPolyAtK(cache.RI1Coeff, e)
-			res0 := K.Sub(K.Sub(K.Add(ru0, ri0), r0), K.Mul(deltaK, k0))
-			res1 := K.Sub(K.Sub(K.Add(ru1, ri1), r1), K.Mul(deltaK, k1))
-			fpar = append(fpar, res0, res1)
+			riAt := func(coeffs [][]uint64, i int) kf.Elem {
+				if i >= len(coeffs) {
+					return K.Zero()
+				}
+				return K.EvalFPolyAtK(coeffs[i], e)
+			}
+			for i := 0; i < n.RU0; i++ {
+				res := K.Sub(K.Add(getRow(cfg.IdxRU0+i), riAt(cache.RI0Coeff, i)), getRow(cfg.IdxR0+i))
+				fpar = append(fpar, K.Sub(res, K.Mul(deltaK, getRow(cfg.IdxK0+i))))
+			}
+			for i := 0; i < n.RU1; i++ {
+				res := K.Sub(K.Add(getRow(cfg.IdxRU1+i), riAt(cache.RI1Coeff, i)), getRow(cfg.IdxR1+i))
+				fpar = append(fpar, K.Sub(res, K.Mul(deltaK, getRow(cfg.IdxK1+i))))
+			}
 		}
 
 		// Hash residual.
-		if len(cache.BCoeff) >= 4 {
-			b0 := K.EvalFPolyAtK(cache.BCoeff[0], e)
-			b1 := K.EvalFPolyAtK(cache.BCoeff[1], e)
-			b2 := K.EvalFPolyAtK(cache.BCoeff[2], e)
-			b3 := K.EvalFPolyAtK(cache.BCoeff[3], e)
-			m1 := getRow(cfg.IdxM1)
-			m2 := getRow(cfg.IdxM2)
-			r0v := getRow(cfg.IdxR0)
-			r1v := getRow(cfg.IdxR1)
+		if len(cache.BCoeff) >= cfg.Blocks.HashLayout().KeyLen() {
 			var t kf.Elem
 			if cfg.IdxT >= 0 && cfg.IdxT < len(rows) {
 				t = getRow(cfg.IdxT)
@@ -1029,21 +1033,21 @@ func (cfg CredentialConstraintConfig) CredentialKEvaluator(K *kf.Field) (KConstr
 			} else {
 				t = K.Zero()
 			}
-			res := K.Sub(b3, r1v)
-			res = K.Mul(res, t)
-			lin := b0
-			lin = K.Add(lin, K.Mul(b1, K.Add(m1, m2)))
-			lin = K.Add(lin, K.Mul(b2, r0v))
-			res = K.Sub(res, lin)
-			fpar = append(fpar, res)
+			key := func(k int) kf.Elem { return K.EvalFPolyAtK(cache.BCoeff[k], e) }
+			fpar = append(fpar, hashResidualAtK(K, cfg.Blocks, key, getRow, cfg.IdxM1, cfg.IdxM2, cfg.IdxR0, cfg.IdxR1, t))
+		}
+
+		// Lifted statements: subtract q·k from commit, center and hash.
+		if cfg.Lift != nil && len(fpar) == cfg.Lift.Relations() {
+			for rel := range fpar {
+				fpar[rel] = K.Sub(fpar[rel], cfg.Lift.quotientK(K, rel, getRow, q))
+			}
 		}
 
 		// Packing residuals via selector polynomial on Ω.
 		if len(cache.PackingSelCoeff) > 0 {
 			sel := K.EvalFPolyAtK(cache.PackingSelCoeff, e)
-			oneMinus := K.Sub(K.One(), sel)
-			fpar = append(fpar, K.Mul(sel, getRow(cfg.IdxM1)))
-			fpar = append(fpar, K.Mul(oneMinus, getRow(cfg.IdxM2)))
+			fpar = append(fpar, packingAtK(K, sel, cfg.Blocks, getRow, cfg.IdxM1, cfg.IdxM2)...)
 		}
 
 		// Bounds: P_B(row) over K.
@@ -1066,17 +1070,100 @@ func (cfg CredentialConstraintConfig) CredentialKEvaluator(K *kf.Field) (KConstr
 	}, nil
 }
 
-func (cfg CredentialConstraintConfig) getRI(ri []*ring.Poly, pt int) uint64 {
-	if pt < 0 || ri == nil {
+func (cfg CredentialConstraintConfig) getRI(ri []*ring.Poly, i, pt int) uint64 {
+	if pt < 0 || i >= len(ri) || ri[i] == nil || pt >= len(ri[i].Coeffs[0]) {
 		return 0
 	}
-	for _, p := range ri {
-		if p == nil || pt >= len(p.Coeffs[0]) {
-			continue
+	return ri[i].Coeffs[0][pt]
+}
+
+// commitRows maps the commitment columns M1||M2||RU0||RU1||R to rows.
+func (cfg CredentialConstraintConfig) commitRows() []int {
+	n := cfg.Blocks.norm()
+	out := span(cfg.IdxM1, n.M1)
+	out = append(out, span(cfg.IdxM2, n.M2)...)
+	out = append(out, span(cfg.IdxRU0, n.RU0)...)
+	out = append(out, span(cfg.IdxRU1, n.RU1)...)
+	return append(out, span(cfg.IdxR, n.R)...)
+}
+
+// hashResidualAt evaluates the cleared-denominator hash of the blocks at
+// one point (see buildHashResidualBlocksNTT); key(k) is B_k at the point.
+func hashResidualAt(q uint64, blocks CredentialBlocks, key func(int) uint64, getRow func(int) uint64, idxM1, idxM2, idxR0, idxR1 int, t uint64) uint64 {
+	hl, n := blocks.HashLayout(), blocks.norm()
+	lin := key(0)
+	for i := 0; i < hl.Msg; i++ {
+		var m uint64
+		if i < n.M1 {
+			m += getRow(idxM1 + i)
 		}
-		return p.Coeffs[0][pt]
+		if i < n.M2 {
+			m += getRow(idxM2 + i)
+		}
+		lin = lvcs.MulAddMod64(lin, key(hl.MsgKey(i)), m%q, q)
 	}
-	return 0
+	for j := 0; j < n.RU0; j++ {
+		lin = lvcs.MulAddMod64(lin, key(hl.X0Key(j)), getRow(idxR0+j), q)
+	}
+	den := (key(3) + q - getRow(idxR1)) % q
+	for k := 1; k < n.RU1; k++ {
+		den = (den + q - lvcs.MulMod64(key(hl.X1Key(k)), getRow(idxR1+k), q)) % q
+	}
+	res := lvcs.MulModReduced(den, t%q, q)
+	return (res + q - lin) % q
+}
+
+// hashResidualAtK is hashResidualAt over K.
+func hashResidualAtK(K *kf.Field, blocks CredentialBlocks, key func(int) kf.Elem, getRow func(int) kf.Elem, idxM1, idxM2, idxR0, idxR1 int, t kf.Elem) kf.Elem {
+	hl, n := blocks.HashLayout(), blocks.norm()
+	lin := key(0)
+	for i := 0; i < hl.Msg; i++ {
+		m := K.Zero()
+		if i < n.M1 {
+			m = K.Add(m, getRow(idxM1+i))
+		}
+		if i < n.M2 {
+			m = K.Add(m, getRow(idxM2+i))
+		}
+		lin = K.Add(lin, K.Mul(key(hl.MsgKey(i)), m))
+	}
+	for j := 0; j < n.RU0; j++ {
+		lin = K.Add(lin, K.Mul(key(hl.X0Key(j)), getRow(idxR0+j)))
+	}
+	den := K.Sub(key(3), getRow(idxR1))
+	for k := 1; k < n.RU1; k++ {
+		den = K.Sub(den, K.Mul(key(hl.X1Key(k)), getRow(idxR1+k)))
+	}
+	return K.Sub(K.Mul(den, t), lin)
+}
+
+// packingAt returns sel·M1_i for every M1 row, then (1−sel)·M2_i for every
+// M2 row, at one point.
+func packingAt(q, sel uint64, blocks CredentialBlocks, getRow func(int) uint64, idxM1, idxM2 int) []uint64 {
+	n := blocks.norm()
+	oneMinus := (1 + q - sel) % q
+	out := make([]uint64, 0, n.M1+n.M2)
+	for i := 0; i < n.M1; i++ {
+		out = append(out, lvcs.MulMod64(sel, getRow(idxM1+i), q))
+	}
+	for i := 0; i < n.M2; i++ {
+		out = append(out, lvcs.MulMod64(oneMinus, getRow(idxM2+i), q))
+	}
+	return out
+}
+
+// packingAtK is packingAt over K.
+func packingAtK(K *kf.Field, sel kf.Elem, blocks CredentialBlocks, getRow func(int) kf.Elem, idxM1, idxM2 int) []kf.Elem {
+	n := blocks.norm()
+	oneMinus := K.Sub(K.One(), sel)
+	out := make([]kf.Elem, 0, n.M1+n.M2)
+	for i := 0; i < n.M1; i++ {
+		out = append(out, K.Mul(sel, getRow(idxM1+i)))
+	}
+	for i := 0; i < n.M2; i++ {
+		out = append(out, K.Mul(oneMinus, getRow(idxM2+i)))
+	}
+	return out
 }
 
 func (cfg CredentialConstraintConfig) getT(ptIdx int, rows []uint64) uint64 {
@@ -1117,14 +1204,15 @@ func boundPoly(x, B, q int64) uint64 {
 	if B <= 0 {
 		return 0
 	}
-	res := int64(1 % q)
+	res := uint64(1 % q)
 	for i := -B; i <= B; i++ {
-		res = (res * ((x - i) % q)) % q
-		if res < 0 {
-			res += q
+		d := (x - i) % q
+		if d < 0 {
+			d += q
 		}
+		res = lvcs.MulModReduced(res, uint64(d), uint64(q))
 	}
-	return uint64(res % q)
+	return res
 }
 
 // buildRowValsFromVTargets reconstructs row evaluations at the K-point from VTargets.
@@ -1187,8 +1275,8 @@ type credentialKEvalCache struct {
 	AcCoeff         [][][]uint64
 	ComCoeff        [][]uint64
 	BCoeff          [][]uint64
-	RI0Coeff        []uint64
-	RI1Coeff        []uint64
+	RI0Coeff        [][]uint64
+	RI1Coeff        [][]uint64
 	TPublicCoeff    []uint64
 	PackingSelCoeff []uint64
 }
@@ -1248,19 +1336,19 @@ func buildCredentialKEvalCache(cfg CredentialConstraintConfig, K *kf.Field) (*cr
 			cache.BCoeff[i] = coeff
 		}
 	}
-	if len(cfg.RI0) > 0 {
-		coeff, err := toCoeffTheta(cfg.RI0[0])
+	for _, p := range cfg.RI0 {
+		coeff, err := toCoeffTheta(p)
 		if err != nil {
 			return nil, err
 		}
-		cache.RI0Coeff = coeff
+		cache.RI0Coeff = append(cache.RI0Coeff, coeff)
 	}
-	if len(cfg.RI1) > 0 {
-		coeff, err := toCoeffTheta(cfg.RI1[0])
+	for _, p := range cfg.RI1 {
+		coeff, err := toCoeffTheta(p)
 		if err != nil {
 			return nil, err
 		}
-		cache.RI1Coeff = coeff
+		cache.RI1Coeff = append(cache.RI1Coeff, coeff)
 	}
 	if cfg.TPublicNTT != nil {
 		coeff, err := toCoeffTheta(cfg.TPublicNTT)
@@ -1269,8 +1357,9 @@ func buildCredentialKEvalCache(cfg CredentialConstraintConfig, K *kf.Field) (*cr
 		}
 		cache.TPublicCoeff = coeff
 	}
-	// Packing selector: interpolate over Ω of length ncols.
-	if ncols%2 == 0 {
+	// Packing selector: interpolate over Ω of length ncols (only when the
+	// config packs, as in Families).
+	if ncols%2 == 0 && (len(cfg.PackingSelNTT) > 0 || cfg.PackingNCols > 0) {
 		selCoeff, err := buildPackingSelectorCoeff(cfg.Ring, ncols)
 		if err != nil {
 			return nil, err
@@ -1397,9 +1486,9 @@ func (cfg PRFConstraintConfig) PRFEvaluator() ConstraintEvaluator {
 			base := v % q
 			for exp > 0 {
 				if exp&1 == 1 {
-					res = (res * base) % q
+					res = lvcs.MulModReduced(res, base, q)
 				}
-				base = (base * base) % q
+				base = lvcs.MulModReduced(base, base, q)
 				exp >>= 1
 			}
 			return res
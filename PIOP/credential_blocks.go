@@ -0,0 +1,229 @@
+package PIOP
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	vsishash "vSIS-Signature/vSIS-HASH"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// CredentialBlocks gives the number of ring elements in each block of a
+// credential witness. Rows are committed block by block in the order
+// M1, M2, RU0, RU1, R, R0, R1, K0, K1, where R0/K0 have RU0 elements and
+// R1/K1 have RU1. The zero value is the single-polynomial layout.
+//
+// Every block index gets its own relations: the commitment covers all
+// M1..R rows, centering and carries are per RU0/RU1 index, the hash takes
+// message block i = M1[i]+M2[i] (see vsishash.VecLayout), and packing and
+// bounds apply row by row.
+type CredentialBlocks struct {
+	M1  int
+	M2  int
+	RU0 int
+	RU1 int
+	R   int
+}
+
+// singleBlocks is the layout the zero CredentialBlocks stands for.
+var singleBlocks = CredentialBlocks{M1: 1, M2: 1, RU0: 1, RU1: 1, R: 1}
+
+func (b CredentialBlocks) norm() CredentialBlocks {
+	if b == (CredentialBlocks{}) {
+		return singleBlocks
+	}
+	return b
+}
+
+// Single reports whether every block holds one polynomial.
+func (b CredentialBlocks) Single() bool { return b.norm() == singleBlocks }
+
+// Validate checks that every block is non-empty.
+func (b CredentialBlocks) Validate() error {
+	n := b.norm()
+	if n.M1 < 1 || n.M2 < 1 || n.RU0 < 1 || n.RU1 < 1 || n.R < 1 {
+		return fmt.Errorf("credential blocks %+v: every block needs at least one polynomial", b)
+	}
+	return nil
+}
+
+// CommitCols returns the number of committed columns M1||M2||RU0||RU1||R.
+func (b CredentialBlocks) CommitCols() int {
+	n := b.norm()
+	return n.M1 + n.M2 + n.RU0 + n.RU1 + n.R
+}
+
+// Witness returns the number of rows M1..K1.
+func (b CredentialBlocks) Witness() int {
+	n := b.norm()
+	return n.CommitCols() + 2*(n.RU0+n.RU1)
+}
+
+// HashLayout returns the vector-hash layout of the blocks.
+func (b CredentialBlocks) HashLayout() vsishash.VecLayout {
+	n := b.norm()
+	return vsishash.VecLayout{Msg: max(n.M1, n.M2), X0: n.RU0, X1: n.RU1}
+}
+
+// credentialRowIdx holds the first row of every block.
+type credentialRowIdx struct {
+	M1, M2, RU0, RU1, R, R0, R1, K0, K1 int
+}
+
+func (b CredentialBlocks) rowIdx() credentialRowIdx {
+	n := b.norm()
+	var idx credentialRowIdx
+	idx.M2 = n.M1
+	idx.RU0 = idx.M2 + n.M2
+	idx.RU1 = idx.RU0 + n.RU0
+	idx.R = idx.RU1 + n.RU1
+	idx.R0 = idx.R + n.R
+	idx.R1 = idx.R0 + n.RU0
+	idx.K0 = idx.R1 + n.RU1
+	idx.K1 = idx.K0 + n.RU0
+	return idx
+}
+
+// span returns the rows start..start+n−1.
+func span(start, n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = start + i
+	}
+	return out
+}
+
+// preBoundRows lists the rows bounded by B in the pre-sign statement
+// (M1..R1); preCarryRows the carry rows K0, K1.
+func (b CredentialBlocks) preBoundRows() []int { return span(0, b.rowIdx().K0) }
+
+func (b CredentialBlocks) preCarryRows() []int {
+	idx := b.rowIdx()
+	return span(idx.K0, b.Witness()-idx.K0)
+}
+
+// postBoundRows lists the rows bounded by B in the post-sign statement:
+// M1, M2, R0 and R1. A re-bound showing (see showing_commit.go) also opens
+// Com with RU0, RU1 and R, so rebound bounds every row before K0 instead.
+func (b CredentialBlocks) postBoundRows(rebound bool) []int {
+	if rebound {
+		return b.preBoundRows()
+	}
+	n, idx := b.norm(), b.rowIdx()
+	return append(append(span(idx.M1, n.M1+n.M2), span(idx.R0, n.RU0)...), span(idx.R1, n.RU1)...)
+}
+
+// label encodes the blocks for the FS transcript; the single layout has no
+// label so that existing statements keep their digest.
+func (b CredentialBlocks) label() []byte {
+	if b.Single() {
+		return nil
+	}
+	n := b.norm()
+	out := make([]byte, 0, 40)
+	for _, v := range []int{n.M1, n.M2, n.RU0, n.RU1, n.R} {
+		out = binary.LittleEndian.AppendUint64(out, uint64(v))
+	}
+	return out
+}
+
+// BlocksOf returns the block lengths of wit.
+func BlocksOf(wit WitnessInputs) CredentialBlocks {
+	return CredentialBlocks{M1: len(wit.M1), M2: len(wit.M2), RU0: len(wit.RU0), RU1: len(wit.RU1), R: len(wit.R)}
+}
+
+// checkPublics checks that the public inputs present in pub have the shapes
+// the blocks imply.
+func (b CredentialBlocks) checkPublics(pub PublicInputs) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	n := b.norm()
+	if len(pub.RI0) > 0 && len(pub.RI0) != n.RU0 {
+		return fmt.Errorf("RI0: expected %d polys, got %d", n.RU0, len(pub.RI0))
+	}
+	if len(pub.RI1) > 0 && len(pub.RI1) != n.RU1 {
+		return fmt.Errorf("RI1: expected %d polys, got %d", n.RU1, len(pub.RI1))
+	}
+	if len(pub.Ac) > 0 && len(pub.Ac[0]) != n.CommitCols() {
+		return fmt.Errorf("ac: %d columns, blocks need %d", len(pub.Ac[0]), n.CommitCols())
+	}
+	if len(pub.B) > 1 && len(pub.B) != n.HashLayout().KeyLen() {
+		return fmt.Errorf("b: expected %d polys, got %d", n.HashLayout().KeyLen(), len(pub.B))
+	}
+	return nil
+}
+
+// checkWitness checks that every block of wit has the length b gives it.
+// R0..K1 are optional as a group.
+func (b CredentialBlocks) checkWitness(wit WitnessInputs) error {
+	n := b.norm()
+	check := func(name string, v []*ring.Poly, want int) error {
+		if len(v) != want {
+			return fmt.Errorf("%s: expected %d polys, got %d", name, want, len(v))
+		}
+		for i, p := range v {
+			if p == nil {
+				return fmt.Errorf("%s[%d]: nil poly", name, i)
+			}
+		}
+		return nil
+	}
+	blocks := []struct {
+		name string
+		v    []*ring.Poly
+		want int
+	}{
+		{"M1", wit.M1, n.M1}, {"M2", wit.M2, n.M2}, {"RU0", wit.RU0, n.RU0}, {"RU1", wit.RU1, n.RU1}, {"R", wit.R, n.R},
+	}
+	if len(wit.R0) > 0 || len(wit.R1) > 0 {
+		blocks = append(blocks, []struct {
+			name string
+			v    []*ring.Poly
+			want int
+		}{
+			{"R0", wit.R0, n.RU0}, {"R1", wit.R1, n.RU1}, {"K0", wit.K0, n.RU0}, {"K1", wit.K1, n.RU1},
+		}...)
+	}
+	for _, blk := range blocks {
+		if err := check(blk.name, blk.v, blk.want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// credentialRowsOf returns the rows M1..K1 of wit in commitment order.
+func credentialRowsOf(wit WitnessInputs) []*ring.Poly {
+	var rows []*ring.Poly
+	for _, blk := range [][]*ring.Poly{wit.M1, wit.M2, wit.RU0, wit.RU1, wit.R, wit.R0, wit.R1, wit.K0, wit.K1} {
+		rows = append(rows, blk...)
+	}
+	return rows
+}
+
+// newPostSignConfig lays out the post-sign evaluator for the rows of
+// BuildCredentialRowsShowing: the blocks of pub.Blocks, then T, then U.
+func newPostSignConfig(ringQ *ring.Ring, pub PublicInputs, thetaA [][]*ring.Poly, thetaB []*ring.Poly, packSelNTT []uint64, ncols int, omega []uint64) PostSignConstraintConfig {
+	blocks := pub.Blocks
+	idx := blocks.rowIdx()
+	return PostSignConstraintConfig{
+		Ring:          ringQ,
+		A:             thetaA,
+		B:             thetaB,
+		Bound:         pub.BoundB,
+		PackingNCols:  ncols,
+		PackingSelNTT: packSelNTT,
+		IdxM1:         idx.M1,
+		IdxM2:         idx.M2,
+		IdxR0:         idx.R0,
+		IdxR1:         idx.R1,
+		IdxT:          blocks.Witness(),
+		IdxUBase:      blocks.Witness() + 1,
+		UCount:        len(pub.A[0]),
+		Blocks:        blocks,
+		BoundRows:     blocks.postBoundRows(pub.rebound()),
+		Omega:         omega,
+	}
+}
@@ -1,22 +1,29 @@
 package PIOP
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // credentialBuilder hosts the credential statement using BuildWithConstraints.
 type credentialBuilder struct {
 	opts SimOpts
 }
 
-func NewCredentialBuilder(opts SimOpts) StatementBuilder {
+func NewCredentialBuilder(opts SimOpts) ContextStatementBuilder {
 	opts.applyDefaults()
 	return &credentialBuilder{opts: opts}
 }
 
-func (b *credentialBuilder) Build(pub PublicInputs, wit WitnessInputs, _ MaskConfig) (*Proof, error) {
+func (b *credentialBuilder) Build(pub PublicInputs, wit WitnessInputs, cfg MaskConfig) (*Proof, error) {
+	return b.BuildContext(context.Background(), pub, wit, cfg)
+}
+
+func (b *credentialBuilder) BuildContext(ctx context.Context, pub PublicInputs, wit WitnessInputs, _ MaskConfig) (*Proof, error) {
 	if err := validatePublics(pub); err != nil {
 		return nil, err
 	}
-	if err := validateWitnesses(wit); err != nil {
+	if err := validateWitnesses(wit, pub.Blocks); err != nil {
 		return nil, err
 	}
 	if pub.BoundB <= 0 {
@@ -30,7 +37,7 @@ func (b *credentialBuilder) Build(pub PublicInputs, wit WitnessInputs, _ MaskCon
 	if err != nil {
 		return nil, fmt.Errorf("build credential constraint set: %w", err)
 	}
-	proof, err := BuildWithConstraints(pub, wit, cs, b.opts, FSModeCredential)
+	proof, err := BuildWithConstraintsContext(ctx, pub, wit, cs, b.opts, FSModeCredential)
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +45,10 @@ func (b *credentialBuilder) Build(pub PublicInputs, wit WitnessInputs, _ MaskCon
 }
 
 func (b *credentialBuilder) Verify(pub PublicInputs, proof *Proof) (bool, error) {
+	return b.VerifyContext(context.Background(), pub, proof)
+}
+
+func (b *credentialBuilder) VerifyContext(ctx context.Context, pub PublicInputs, proof *Proof) (bool, error) {
 	if proof == nil {
 		return false, fmt.Errorf("nil proof")
 	}
@@ -46,7 +57,7 @@ func (b *credentialBuilder) Verify(pub PublicInputs, proof *Proof) (bool, error)
 	}
 	// Constraint set can be left empty for credential verify; the verifier replays FS using proof metadata.
 	cs := ConstraintSet{}
-	return VerifyWithConstraints(proof, cs, pub, b.opts, FSModeCredential)
+	return VerifyWithConstraintsContext(ctx, proof, cs, pub, b.opts, FSModeCredential)
 }
 
-var _ StatementBuilder = (*credentialBuilder)(nil)
+var _ ContextStatementBuilder = (*credentialBuilder)(nil)
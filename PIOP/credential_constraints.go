@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
+	lvcs "vSIS-Signature/LVCS"
 	"vSIS-Signature/prf"
 )
 
@@ -160,10 +161,82 @@ func BuildSignatureConstraintNTT(ringQ *ring.Ring, A [][]*ring.Poly, U []*ring.P
 	return residuals, nil
 }
 
+// buildHashResidualBlocksNTT is BuildHashConstraintsNTT for vector-valued
+// blocks: B is the key of blocks.HashLayout() and the witness is read from
+// the committed rows (NTT) at the block offsets:
+//
+//	(B3 − R1_0 − Σ D_k·R1_k) ⊙ T − (B0 + Σ G_i·(M1_i+M2_i) + Σ H_j·R0_j)
+//
+// With single-polynomial blocks this is BuildHashConstraintsNTT.
+func buildHashResidualBlocksNTT(ringQ *ring.Ring, blocks CredentialBlocks, B []*ring.Poly, rowsNTT []*ring.Poly, tNTT *ring.Poly) (*ring.Poly, error) {
+	hl := blocks.HashLayout()
+	if len(B) != hl.KeyLen() {
+		return nil, fmt.Errorf("b must have %d polys, got %d", hl.KeyLen(), len(B))
+	}
+	if len(rowsNTT) < blocks.Witness() {
+		return nil, fmt.Errorf("rows length %d < %d for hash inputs", len(rowsNTT), blocks.Witness())
+	}
+	n, idx := blocks.norm(), blocks.rowIdx()
+	tmp := ringQ.NewPoly()
+	num := ringQ.NewPoly()
+	ring.Copy(B[0], num)
+	for i := 0; i < hl.Msg; i++ {
+		msg := ringQ.NewPoly()
+		if i < n.M1 {
+			ringQ.Add(msg, rowsNTT[idx.M1+i], msg)
+		}
+		if i < n.M2 {
+			ringQ.Add(msg, rowsNTT[idx.M2+i], msg)
+		}
+		ringQ.MulCoeffs(B[hl.MsgKey(i)], msg, tmp)
+		ringQ.Add(num, tmp, num)
+	}
+	for j := 0; j < n.RU0; j++ {
+		ringQ.MulCoeffs(B[hl.X0Key(j)], rowsNTT[idx.R0+j], tmp)
+		ringQ.Add(num, tmp, num)
+	}
+	den := ringQ.NewPoly()
+	ringQ.Sub(B[3], rowsNTT[idx.R1], den)
+	for k := 1; k < n.RU1; k++ {
+		ringQ.MulCoeffs(B[hl.X1Key(k)], rowsNTT[idx.R1+k], tmp)
+		ringQ.Sub(den, tmp, den)
+	}
+	res := ringQ.NewPoly()
+	ringQ.MulCoeffs(den, tNTT, res)
+	ringQ.Sub(res, num, res)
+	return res, nil
+}
+
+// buildPackingResiduals returns sel⊙M1_i for every M1 row, then (1−sel)⊙M2_i
+// for every M2 row: M1 occupies the lower half of Ω and M2 the upper half.
+func buildPackingResiduals(ringQ *ring.Ring, blocks CredentialBlocks, rowsNTT []*ring.Poly, ncols int) ([]*ring.Poly, error) {
+	if ncols%2 != 0 {
+		return nil, fmt.Errorf("ncols %d is not even for packing", ncols)
+	}
+	selNTT, oneMinusSel, err := buildPackingSelectorNTT(ringQ, ncols)
+	if err != nil {
+		return nil, fmt.Errorf("packing selector: %w", err)
+	}
+	n, idx := blocks.norm(), blocks.rowIdx()
+	out := make([]*ring.Poly, 0, n.M1+n.M2)
+	for i := 0; i < n.M1; i++ {
+		p := ringQ.NewPoly()
+		ringQ.MulCoeffs(selNTT, rowsNTT[idx.M1+i], p)
+		out = append(out, p)
+	}
+	for i := 0; i < n.M2; i++ {
+		p := ringQ.NewPoly()
+		ringQ.MulCoeffs(oneMinusSel, rowsNTT[idx.M2+i], p)
+		out = append(out, p)
+	}
+	return out, nil
+}
+
 // buildCredentialConstraintSetPreFromRows builds the pre-sign constraint set
 // directly from the committed row polynomials (NTT domain). This ensures the
 // constraint polynomials include the LVCS tails, matching the paper definition
-// F_j(X) = f_j(P(X), Theta(X)) on the full polynomial P.
+// F_j(X) = f_j(P(X), Theta(X)) on the full polynomial P. Rows are laid out as
+// described by pub.Blocks.
 func buildCredentialConstraintSetPreFromRows(ringQ *ring.Ring, bound int64, pub PublicInputs, rowsNTT []*ring.Poly, ncols int) (ConstraintSet, error) {
 	if ringQ == nil {
 		return ConstraintSet{}, fmt.Errorf("nil ring")
@@ -186,20 +259,21 @@ func buildCredentialConstraintSetPreFromRows(ringQ *ring.Ring, bound int64, pub
 	if len(pub.T) == 0 {
 		return ConstraintSet{}, fmt.Errorf("missing public T coeffs for hash constraint")
 	}
-	if len(rowsNTT) < 9 {
-		return ConstraintSet{}, fmt.Errorf("rows length %d < 9 (missing K0/K1)", len(rowsNTT))
+	blocks := pub.Blocks
+	if err := blocks.checkPublics(pub); err != nil {
+		return ConstraintSet{}, err
+	}
+	if len(rowsNTT) < blocks.Witness() {
+		return ConstraintSet{}, fmt.Errorf("rows length %d < %d (missing K0/K1)", len(rowsNTT), blocks.Witness())
 	}
-
-	// Row order: M1,M2,RU0,RU1,R,R0,R1,K0,K1,(optional T/U...)
-	m1NTT := rowsNTT[0]
-	m2NTT := rowsNTT[1]
-	ru0NTT := rowsNTT[2]
-	ru1NTT := rowsNTT[3]
-	rNTT := rowsNTT[4]
-	r0NTT := rowsNTT[5]
-	r1NTT := rowsNTT[6]
-	k0NTT := rowsNTT[7]
-	k1NTT := rowsNTT[8]
+	lift, err := liftLayoutFromPublics(pub, ringQ)
+	if err != nil {
+		return ConstraintSet{}, err
+	}
+	if lift != nil && len(rowsNTT) < lift.Base+lift.Rows() {
+		return ConstraintSet{}, fmt.Errorf("rows length %d < %d (missing quotient rows)", len(rowsNTT), lift.Base+lift.Rows())
+	}
+	n, idx := blocks.norm(), blocks.rowIdx()
 
 	// Interpolate public polynomials over Ω so constraint evaluation at K-points
 	// uses Θ(X) (degree < ncols), not full ring polynomials.
@@ -222,13 +296,24 @@ func buildCredentialConstraintSetPreFromRows(ringQ *ring.Ring, bound int64, pub
 		}
 		thetaCom[i] = theta
 	}
-	thetaRI0, err := thetaPolyFromNTT(ringQ, pub.RI0[0], ncols)
+	thetaRI := func(name string, ri []*ring.Poly) ([]*ring.Poly, error) {
+		out := make([]*ring.Poly, len(ri))
+		for i := range ri {
+			theta, terr := thetaPolyFromNTT(ringQ, ri[i], ncols)
+			if terr != nil {
+				return nil, fmt.Errorf("theta %s[%d]: %w", name, i, terr)
+			}
+			out[i] = theta
+		}
+		return out, nil
+	}
+	thetaRI0, err := thetaRI("RI0", pub.RI0)
 	if err != nil {
-		return ConstraintSet{}, fmt.Errorf("theta RI0: %w", err)
+		return ConstraintSet{}, err
 	}
-	thetaRI1, err := thetaPolyFromNTT(ringQ, pub.RI1[0], ncols)
+	thetaRI1, err := thetaRI("RI1", pub.RI1)
 	if err != nil {
-		return ConstraintSet{}, fmt.Errorf("theta RI1: %w", err)
+		return ConstraintSet{}, err
 	}
 	thetaB := make([]*ring.Poly, len(pub.B))
 	for i := range pub.B {
@@ -240,21 +325,17 @@ func buildCredentialConstraintSetPreFromRows(ringQ *ring.Ring, bound int64, pub
 	}
 
 	// Commit residuals: Ac·[M1||M2||RU0||RU1||R] - Com.
-	vec := []*ring.Poly{m1NTT, m2NTT, ru0NTT, ru1NTT, rNTT}
-	comRes, err := BuildCommitConstraints(ringQ, thetaAc, vec, thetaCom)
+	comRes, err := BuildCommitConstraints(ringQ, thetaAc, rowsNTT[:blocks.CommitCols()], thetaCom)
 	if err != nil {
 		return ConstraintSet{}, fmt.Errorf("commit residuals: %w", err)
 	}
 
-	centerWrapResidual := func(ru, ri, rVal, kVal *ring.Poly) (*ring.Poly, error) {
-		if ru == nil || ri == nil || rVal == nil || kVal == nil {
-			return nil, fmt.Errorf("nil center-wrap input poly")
-		}
-		if bound <= 0 {
-			return nil, fmt.Errorf("invalid bound %d", bound)
-		}
-		q := ringQ.Modulus[0]
-		delta := uint64((2*bound + 1) % int64(q))
+	if bound <= 0 {
+		return ConstraintSet{}, fmt.Errorf("invalid bound %d", bound)
+	}
+	q := ringQ.Modulus[0]
+	delta := uint64((2*bound + 1) % int64(q))
+	centerWrapResidual := func(ru, ri, rVal, kVal *ring.Poly) *ring.Poly {
 		// res = RU + RI - R - delta*K   (all in NTT / evaluation domain)
 		res := ringQ.NewPoly()
 		ringQ.Add(ru, ri, res)
@@ -262,45 +343,37 @@ func buildCredentialConstraintSetPreFromRows(ringQ *ring.Ring, bound int64, pub
 		tmp := ringQ.NewPoly()
 		scalePolyNTT(ringQ, kVal, delta, tmp)
 		ringQ.Sub(res, tmp, res)
-		return res, nil
+		return res
 	}
 
-	// Center residuals.
-	centerRes0, err := centerWrapResidual(ru0NTT, thetaRI0, r0NTT, k0NTT)
-	if err != nil {
-		return ConstraintSet{}, fmt.Errorf("center wrap residual 0: %w", err)
+	// Center residuals, one per RU0 index then one per RU1 index.
+	centerRes := make([]*ring.Poly, 0, n.RU0+n.RU1)
+	for i := 0; i < n.RU0; i++ {
+		centerRes = append(centerRes, centerWrapResidual(rowsNTT[idx.RU0+i], thetaRI0[i], rowsNTT[idx.R0+i], rowsNTT[idx.K0+i]))
 	}
-	centerRes1, err := centerWrapResidual(ru1NTT, thetaRI1, r1NTT, k1NTT)
-	if err != nil {
-		return ConstraintSet{}, fmt.Errorf("center wrap residual 1: %w", err)
+	for i := 0; i < n.RU1; i++ {
+		centerRes = append(centerRes, centerWrapResidual(rowsNTT[idx.RU1+i], thetaRI1[i], rowsNTT[idx.R1+i], rowsNTT[idx.K1+i]))
+	}
+	if lift != nil {
+		for i := range comRes {
+			ringQ.Sub(comRes[i], lift.quotientNTT(ringQ, i, rowsNTT), comRes[i])
+		}
+		for i := range centerRes {
+			ringQ.Sub(centerRes[i], lift.quotientNTT(ringQ, lift.Commit+i, rowsNTT), centerRes[i])
+		}
 	}
-	centerRes := []*ring.Poly{centerRes0, centerRes1}
 
 	// Packing constraints (evaluation-domain): enforce m1 occupies lower half,
 	// m2 upper half over Ω of length ncols.
-	if ncols%2 != 0 {
-		return ConstraintSet{}, fmt.Errorf("ncols %d is not even for packing", ncols)
-	}
-	selNTT, oneMinusSel, err := buildPackingSelectorNTT(ringQ, ncols)
+	packRes, err := buildPackingResiduals(ringQ, blocks, rowsNTT, ncols)
 	if err != nil {
-		return ConstraintSet{}, fmt.Errorf("packing selector: %w", err)
+		return ConstraintSet{}, err
 	}
-	m1Pack := ringQ.NewPoly()
-	m2Pack := ringQ.NewPoly()
-	ringQ.MulCoeffs(selNTT, m1NTT, m1Pack)
-	ringQ.MulCoeffs(oneMinusSel, m2NTT, m2Pack)
 
-	// Hash constraint: T = HashMessage(B, M1, M2, R0, R1).
-	toCoeff := func(p *ring.Poly) *ring.Poly {
-		cp := ringQ.NewPoly()
-		ring.Copy(p, cp)
-		ringQ.InvNTT(cp, cp)
-		return cp
-	}
-	// Interpolate public T over Ω to get Θ_T (degree < ncols), then pass
-	// its coefficients to the hash gadget.
+	// Hash constraint: T = HashMessage(B, M1, M2, R0, R1) with public T
+	// interpolated over Ω (Θ_T, degree < ncols).
 	tNTT := ringQ.NewPoly()
-	q64 := int64(ringQ.Modulus[0])
+	q64 := int64(q)
 	for i := 0; i < ringQ.N && i < len(pub.T); i++ {
 		v := pub.T[i]
 		if v < 0 {
@@ -309,50 +382,46 @@ func buildCredentialConstraintSetPreFromRows(ringQ *ring.Ring, bound int64, pub
 		tNTT.Coeffs[0][i] = uint64(v % q64)
 	}
 	ringQ.NTT(tNTT, tNTT)
-	tThetaCoeff, err := thetaCoeffFromNTT(ringQ, tNTT, ncols)
+	thetaT, err := thetaPolyFromNTT(ringQ, tNTT, ncols)
 	if err != nil {
 		return ConstraintSet{}, fmt.Errorf("theta T: %w", err)
 	}
-	tThetaInt := make([]int64, len(tThetaCoeff))
-	for i := range tThetaCoeff {
-		tThetaInt[i] = int64(tThetaCoeff[i])
-	}
-	hashRes, err := BuildHashConstraints(
-		ringQ,
-		thetaB,
-		toCoeff(m1NTT),
-		toCoeff(m2NTT),
-		toCoeff(r0NTT),
-		toCoeff(r1NTT),
-		tThetaInt,
-	)
+	hashRes, err := buildHashResidualBlocksNTT(ringQ, blocks, thetaB, rowsNTT, thetaT)
 	if err != nil {
 		return ConstraintSet{}, fmt.Errorf("hash residuals: %w", err)
 	}
+	if lift != nil {
+		ringQ.Sub(hashRes, lift.quotientNTT(ringQ, lift.Commit+len(centerRes), rowsNTT), hashRes)
+	}
 
 	// Bounds (evaluation-domain composition): enforce membership in [-B,B] for
-	// witness rows, and [-1,1] for K0/K1.
-	q := ringQ.Modulus[0]
+	// witness rows M1..R1, and [-1,1] for the carries.
 	if bound > int64(^uint(0)>>1) {
 		return ConstraintSet{}, fmt.Errorf("bound too large for membership spec: %d", bound)
 	}
 	specVal := NewRangeMembershipSpec(q, int(bound))
-	boundedRows := []*ring.Poly{m1NTT, m2NTT, ru0NTT, ru1NTT, rNTT, r0NTT, r1NTT}
+	boundedRows := append([]*ring.Poly(nil), rowsNTT[:idx.K0]...)
+	if lift != nil {
+		for _, i := range lift.RowIndices() {
+			boundedRows = append(boundedRows, rowsNTT[i])
+		}
+	}
 	fparBounds := buildFparRangeMembershipCompose(ringQ, boundedRows, specVal)
 	specCarry := NewRangeMembershipSpec(q, 1)
-	fparCarry := buildFparRangeMembershipCompose(ringQ, []*ring.Poly{k0NTT, k1NTT}, specCarry)
+	fparCarry := buildFparRangeMembershipCompose(ringQ, rowsNTT[idx.K0:blocks.Witness()], specCarry)
 	fparBounds = append(fparBounds, fparCarry...)
 
+	fparInt := append(append(comRes, centerRes...), hashRes)
 	return ConstraintSet{
-		FparInt:  append(append(append(comRes, centerRes...), hashRes...), m1Pack, m2Pack),
+		FparInt:  append(fparInt, packRes...),
 		FparNorm: fparBounds,
 	}, nil
 }
 
 // buildCredentialConstraintSetPostFromRows builds the post-sign constraint set
 // (signature, hash, packing, bounds) directly from committed row polynomials
-// in NTT form. Row order is assumed to be:
-// M1,M2,RU0,RU1,R,R0,R1,K0,K1,T,U...
+// in NTT form. Row order is assumed to be the blocks of pub.Blocks
+// (M1,M2,RU0,RU1,R,R0,R1,K0,K1) followed by T and U.
 func buildCredentialConstraintSetPostFromRows(ringQ *ring.Ring, bound int64, pub PublicInputs, rowsNTT []*ring.Poly, ncols int) (ConstraintSet, error) {
 	if ringQ == nil {
 		return ConstraintSet{}, fmt.Errorf("nil ring")
@@ -366,23 +435,23 @@ func buildCredentialConstraintSetPostFromRows(ringQ *ring.Ring, bound int64, pub
 	if len(pub.B) == 0 {
 		return ConstraintSet{}, fmt.Errorf("missing B for hash constraint")
 	}
-	if len(rowsNTT) < 10 {
-		return ConstraintSet{}, fmt.Errorf("rows length %d < 10 (missing T/U)", len(rowsNTT))
+	blocks := pub.Blocks
+	if err := blocks.checkPublics(pub); err != nil {
+		return ConstraintSet{}, err
+	}
+	tIdx := blocks.Witness()
+	if len(rowsNTT) <= tIdx {
+		return ConstraintSet{}, fmt.Errorf("rows length %d < %d (missing T/U)", len(rowsNTT), tIdx+1)
 	}
 	uCount := len(pub.A[0])
 	if uCount == 0 {
 		return ConstraintSet{}, fmt.Errorf("empty A columns")
 	}
-	tIdx := 9
 	uStart := tIdx + 1
 	if len(rowsNTT) < uStart+uCount {
 		return ConstraintSet{}, fmt.Errorf("rows length %d < %d for U rows", len(rowsNTT), uStart+uCount)
 	}
 
-	m1NTT := rowsNTT[0]
-	m2NTT := rowsNTT[1]
-	r0NTT := rowsNTT[5]
-	r1NTT := rowsNTT[6]
 	tNTT := rowsNTT[tIdx]
 	uRows := rowsNTT[uStart : uStart+uCount]
 
@@ -410,30 +479,25 @@ func buildCredentialConstraintSetPostFromRows(ringQ *ring.Ring, bound int64, pub
 	if err != nil {
 		return ConstraintSet{}, fmt.Errorf("signature residuals: %w", err)
 	}
-	hashRes, err := BuildHashConstraintsNTT(ringQ, thetaB, m1NTT, m2NTT, r0NTT, r1NTT, tNTT)
+	hashRes, err := buildHashResidualBlocksNTT(ringQ, blocks, thetaB, rowsNTT, tNTT)
 	if err != nil {
 		return ConstraintSet{}, fmt.Errorf("hash residuals: %w", err)
 	}
-
-	if ncols%2 != 0 {
-		return ConstraintSet{}, fmt.Errorf("ncols %d is not even for packing", ncols)
-	}
-	selNTT, oneMinusSel, err := buildPackingSelectorNTT(ringQ, ncols)
+	packRes, err := buildPackingResiduals(ringQ, blocks, rowsNTT, ncols)
 	if err != nil {
-		return ConstraintSet{}, fmt.Errorf("packing selector: %w", err)
+		return ConstraintSet{}, err
 	}
-	m1Pack := ringQ.NewPoly()
-	m2Pack := ringQ.NewPoly()
-	ringQ.MulCoeffs(selNTT, m1NTT, m1Pack)
-	ringQ.MulCoeffs(oneMinusSel, m2NTT, m2Pack)
 
 	q := ringQ.Modulus[0]
 	specVal := NewRangeMembershipSpec(q, int(bound))
-	boundedRows := []*ring.Poly{m1NTT, m2NTT, r0NTT, r1NTT}
+	var boundedRows []*ring.Poly
+	for _, i := range blocks.postBoundRows(pub.rebound()) {
+		boundedRows = append(boundedRows, rowsNTT[i])
+	}
 	fparBounds := buildFparRangeMembershipCompose(ringQ, boundedRows, specVal)
 
-	fparInt := append(sigRes, hashRes...)
-	fparInt = append(fparInt, m1Pack, m2Pack)
+	fparInt := append(sigRes, hashRes)
+	fparInt = append(fparInt, packRes...)
 	return ConstraintSet{
 		FparInt:  fparInt,
 		FparNorm: fparBounds,
@@ -502,26 +566,29 @@ func BuildCredentialConstraintSetPre(ringQ *ring.Ring, bound int64, pub PublicIn
 		return cp
 	}
 
+	if err := pub.Blocks.checkWitness(wit); err != nil {
+		return ConstraintSet{}, err
+	}
+
 	// Basic bound sanity on witness polys (evaluation domain).
-	allWits := []*ring.Poly{wit.M1[0], wit.M2[0], wit.RU0[0], wit.RU1[0], wit.R[0], wit.R0[0], wit.R1[0], wit.K0[0], wit.K1[0]}
-	nttWits := make([]*ring.Poly, len(allWits))
+	allWits := credentialRowsOf(wit)
+	rowsNTT := make([]*ring.Poly, len(allWits))
 	for i := range allWits {
-		nttWits[i] = ensureNTT(allWits[i])
+		rowsNTT[i] = ensureNTT(allWits[i])
 	}
-	if err := BuildBoundConstraintsEvalDomain(ringQ, nttWits, bound); err != nil {
+	if err := BuildBoundConstraintsEvalDomain(ringQ, rowsNTT, bound); err != nil {
 		return ConstraintSet{}, fmt.Errorf("bound check failed: %w", err)
 	}
 
-	rowsNTT := []*ring.Poly{
-		ensureNTT(wit.M1[0]),
-		ensureNTT(wit.M2[0]),
-		ensureNTT(wit.RU0[0]),
-		ensureNTT(wit.RU1[0]),
-		ensureNTT(wit.R[0]),
-		ensureNTT(wit.R0[0]),
-		ensureNTT(wit.R1[0]),
-		ensureNTT(wit.K0[0]),
-		ensureNTT(wit.K1[0]),
+	if len(wit.Quot) > 0 {
+		quotNTT := make([]*ring.Poly, len(wit.Quot))
+		for i := range wit.Quot {
+			quotNTT[i] = ensureNTT(wit.Quot[i])
+		}
+		if err := BuildBoundConstraintsEvalDomain(ringQ, quotNTT, bound); err != nil {
+			return ConstraintSet{}, fmt.Errorf("quotient bound check failed: %w", err)
+		}
+		rowsNTT = append(rowsNTT, quotNTT...)
 	}
 	// Use the same row-based builder (without LVCS tails).
 	return buildCredentialConstraintSetPreFromRows(ringQ, bound, pub, rowsNTT, ncols)
@@ -600,9 +667,9 @@ func BuildPRFConstraintSet(ringQ *ring.Ring, prfParams *prf.Params, rows []*ring
 			exp := d
 			for exp > 0 {
 				if exp&1 == 1 {
-					res = (res * base) % uint64(q)
+					res = lvcs.MulModReduced(res, base, uint64(q))
 				}
-				base = (base * base) % uint64(q)
+				base = lvcs.MulModReduced(base, base, uint64(q))
 				exp >>= 1
 			}
 			out.Coeffs[0][i] = res
@@ -613,7 +680,7 @@ func BuildPRFConstraintSet(ringQ *ring.Ring, prfParams *prf.Params, rows []*ring
 		cp := ringQ.NewPoly()
 		ring.Copy(p, cp)
 		for i := 0; i < ringQ.N; i++ {
-			cp.Coeffs[0][i] = (cp.Coeffs[0][i] * scalar) % uint64(q)
+			cp.Coeffs[0][i] = lvcs.MulModReduced(cp.Coeffs[0][i], scalar%uint64(q), uint64(q))
 		}
 		return cp
 	}
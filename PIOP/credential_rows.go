@@ -10,8 +10,9 @@ import (
 )
 
 // buildCredentialRows maps WitnessInputs into an ordered row list for credential mode.
-// Pre-sign uses 7 witness rows (M1,M2,RU0,RU1,R,R0,R1); post-sign may add U (and
-// legacy callers may still provide an internal T row via wit.T).
+// Pre-sign commits the blocks M1,M2,RU0,RU1,R,R0,R1,K0,K1 (one row per
+// polynomial); post-sign may add U (and legacy callers may still provide an
+// internal T row via wit.T).
 // It returns the row polynomials,
 // LVCS row inputs (heads), a basic RowLayout, decs params, and mask layout offsets.
 func buildCredentialRows(ringQ *ring.Ring, wit WitnessInputs, opts SimOpts) (rows []*ring.Poly, rowInputs []lvcs.RowInput, layout RowLayout, decsParams decs.Params, maskRowOffset, maskRowCount, witnessCount, ncols int, err error) {
@@ -59,17 +60,10 @@ func buildCredentialRows(ringQ *ring.Ring, wit WitnessInputs, opts SimOpts) (row
 		return
 	}
 
-	rows = []*ring.Poly{
-		wit.M1[0],
-		wit.M2[0],
-		wit.RU0[0],
-		wit.RU1[0],
-		wit.R[0],
-		wit.R0[0],
-		wit.R1[0],
-		wit.K0[0],
-		wit.K1[0],
-	}
+	// Every block is committed in full, block after block (see CredentialBlocks).
+	rows = credentialRowsOf(wit)
+	// Lifted statements commit their quotient digits right after the carries.
+	rows = append(rows, wit.Quot...)
 	// Legacy: some callers still provide T as an internal witness (hash output).
 	// Paper-faithful pre-sign issuance treats T/t as public, so new callers should
 	// omit wit.T and provide pub.T instead.
@@ -109,14 +103,8 @@ func buildCredentialRows(ringQ *ring.Ring, wit WitnessInputs, opts SimOpts) (row
 
 	// Masks start after witness rows.
 	maskRowOffset = len(rows)
-	maskRowCount = opts.Rho
-	if maskRowCount > 0 {
-		zeroHead := make([]uint64, ncols)
-		for i := 0; i < maskRowCount; i++ {
-			rows = append(rows, ringQ.NewPoly())
-			rowInputs = append(rowInputs, lvcs.RowInput{Head: zeroHead})
-		}
-	}
+	rows, rowInputs = appendCredentialMaskRows(ringQ, rows, rowInputs, opts, ncols)
+	maskRowCount = len(rows) - maskRowOffset
 
 	// DECS params: degree bound based on ncols+ell-1 (clipped by ring size), eta from opts.
 	maxDegree := opts.DQOverride
@@ -126,6 +114,23 @@ func buildCredentialRows(ringQ *ring.Ring, wit WitnessInputs, opts SimOpts) (row
 			maxDegree = int(ringQ.N) - 1
 		}
 	}
-	decsParams = decs.Params{Degree: maxDegree, Eta: opts.Eta, NonceBytes: 16}
+	decsParams = decs.Params{Degree: maxDegree, Eta: opts.Eta, NonceBytes: 16, Rand: opts.coins}
 	return
 }
+
+// appendCredentialMaskRows appends the θ·ℓ′ credential mask rows, one per
+// LVCS query (see buildKPointCoeffMatrixRows). Their coefficients are
+// uniform, so their heads on Ω are too, and each VTargets row is a witness
+// combination plus a fresh uniform vector.
+func appendCredentialMaskRows(ringQ *ring.Ring, rows []*ring.Poly, rowInputs []lvcs.RowInput, opts SimOpts, ncols int) ([]*ring.Poly, []lvcs.RowInput) {
+	q := ringQ.Modulus[0]
+	coins := opts.coinSource()
+	masks := make([]*ring.Poly, opts.Theta*opts.EllPrime)
+	for i := range masks {
+		masks[i] = ringQ.NewPoly()
+		for j := range masks[i].Coeffs[0] {
+			masks[i].Coeffs[0][j] = randUint64Mod(coins, q)
+		}
+	}
+	return append(rows, masks...), append(rowInputs, buildRowInputs(ringQ, masks, ncols)...)
+}
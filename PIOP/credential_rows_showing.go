@@ -10,7 +10,8 @@ import (
 )
 
 // BuildCredentialRowsShowing maps witness inputs into rows for the showing (post-sign) proof.
-// It reuses the pre-sign rows (M1,M2,RU0,RU1,R,R0,R1,K0,K1) and appends the full PRF trace:
+// It reuses the pre-sign rows (every polynomial of M1,M2,RU0,RU1,R,R0,R1,K0,K1)
+// and appends the full PRF trace:
 // x^(r)_j for r=0..R (R=RF+RP), j=0..t-1 in row-major order. startIdx is the index
 // where x^(0)_0 begins in the returned rows.
 func BuildCredentialRowsShowing(ringQ *ring.Ring, wit WitnessInputs, prfParamsLenKey, prfParamsLenNonce, prfRF, prfRP int, opts SimOpts) (rows []*ring.Poly, rowInputs []lvcs.RowInput, layout RowLayout, decsParams decs.Params, maskRowOffset, maskRowCount, witnessCount, startIdx, ncols int, err error) {
@@ -23,35 +24,9 @@ func BuildCredentialRowsShowing(ringQ *ring.Ring, wit WitnessInputs, prfParamsLe
 		opts.NCols = int(ringQ.N)
 	}
 	ncols = opts.NCols
-	// Pre-sign base rows.
-	// Pre-sign rows: optional in PRF-only demos; include only if provided.
-	if len(wit.M1) > 0 {
-		rows = append(rows, wit.M1[0])
-	}
-	if len(wit.M2) > 0 {
-		rows = append(rows, wit.M2[0])
-	}
-	if len(wit.RU0) > 0 {
-		rows = append(rows, wit.RU0[0])
-	}
-	if len(wit.RU1) > 0 {
-		rows = append(rows, wit.RU1[0])
-	}
-	if len(wit.R) > 0 {
-		rows = append(rows, wit.R[0])
-	}
-	if len(wit.R0) > 0 {
-		rows = append(rows, wit.R0[0])
-	}
-	if len(wit.R1) > 0 {
-		rows = append(rows, wit.R1[0])
-	}
-	if len(wit.K0) > 0 {
-		rows = append(rows, wit.K0[0])
-	}
-	if len(wit.K1) > 0 {
-		rows = append(rows, wit.K1[0])
-	}
+	// Pre-sign base rows, block after block (see CredentialBlocks). They are
+	// optional in PRF-only demos; blocks are included only if provided.
+	rows = append(rows, credentialRowsOf(wit)...)
 	// Optional internal T row (hash output) for post-signature proofs.
 	if len(wit.T) > 0 {
 		tPoly := ringQ.NewPoly()
@@ -115,14 +90,8 @@ func BuildCredentialRowsShowing(ringQ *ring.Ring, wit WitnessInputs, prfParamsLe
 
 	// Masks start after witness rows.
 	maskRowOffset = len(rows)
-	maskRowCount = opts.Rho
-	if maskRowCount > 0 {
-		zeroHead := make([]uint64, ncols)
-		for i := 0; i < maskRowCount; i++ {
-			rows = append(rows, ringQ.NewPoly())
-			rowInputs = append(rowInputs, lvcs.RowInput{Head: zeroHead})
-		}
-	}
+	rows, rowInputs = appendCredentialMaskRows(ringQ, rows, rowInputs, opts, ncols)
+	maskRowCount = len(rows) - maskRowOffset
 
 	// DECS params: degree bound based on ncols+ell-1 (clipped by ring size), eta from opts.
 	maxDegree := opts.DQOverride
@@ -132,6 +101,6 @@ func BuildCredentialRowsShowing(ringQ *ring.Ring, wit WitnessInputs, prfParamsLe
 			maxDegree = int(ringQ.N) - 1
 		}
 	}
-	decsParams = decs.Params{Degree: maxDegree, Eta: opts.Eta, NonceBytes: 16}
+	decsParams = decs.Params{Degree: maxDegree, Eta: opts.Eta, NonceBytes: 16, Rand: opts.coins}
 	return
 }
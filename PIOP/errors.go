@@ -0,0 +1,73 @@
+package PIOP
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	decs "vSIS-Signature/DECS"
+)
+
+// Verification errors. They are the DECS/LVCS sentinels, so a rejection
+// raised deep in the commitment layer matches the same value here.
+var (
+	ErrMalformedProof = decs.ErrMalformedProof
+	ErrMerklePath     = decs.ErrMerklePath
+	ErrFSDigest       = decs.ErrFSDigest
+	ErrDegreeBound    = decs.ErrDegreeBound
+)
+
+// ErrParamsID is returned when a proof was built under a different ring,
+// public matrices, PRF parameter set or SimOpts than the verifier's (see
+// ComputeParamsID).
+var ErrParamsID = errors.New("proof params id does not match the verifier configuration")
+
+// ErrConstraint reports a failed algebraic check (LVCS relation, Eq.(4) or
+// ΣΩ); match it with errors.As.
+type ErrConstraint = decs.ErrConstraint
+
+// Verify checks proof and returns nil on acceptance. Credential proofs are
+// replayed against set and pub (see VerifyWithConstraints); PACS proofs are
+// verified from the transcript alone once their ParamsID matches opts.
+// Rejections wrap ErrMalformedProof, ErrMerklePath, ErrFSDigest,
+// ErrDegreeBound, ErrParamsID or *ErrConstraint.
+func Verify(proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts) error {
+	return VerifyContext(context.Background(), proof, set, pub, opts)
+}
+
+// VerifyContext is Verify with cancellation; it returns a *CanceledError once
+// ctx is done.
+func VerifyContext(ctx context.Context, proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts) error {
+	if proof == nil {
+		return fmt.Errorf("Verify: nil proof: %w", ErrMalformedProof)
+	}
+	// Credential proofs bind a labels digest; route them to the replay even
+	// when the caller passed default options.
+	if opts.Credential || set.PRFLayout != nil || len(proof.LabelsDigest) > 0 {
+		opts.Credential = true
+		ok, err := VerifyWithConstraintsContext(ctx, proof, set, pub, opts, "")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &ErrConstraint{Name: "replay", Row: -1}
+		}
+		return nil
+	}
+	if err := checkPACSParamsID(proof, opts); err != nil {
+		return err
+	}
+	okLin, okEq4, okSum, err := VerifyNIZKContext(ctx, proof)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !okLin:
+		return &ErrConstraint{Name: "LVCS", Row: -1}
+	case !okEq4:
+		return &ErrConstraint{Name: "Eq4", Row: -1}
+	case !okSum:
+		return &ErrConstraint{Name: "SumOmega", Row: -1}
+	}
+	return nil
+}
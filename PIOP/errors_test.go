@@ -0,0 +1,37 @@
+package PIOP
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyClassifiesRejections(t *testing.T) {
+	sim, _, _, _ := buildSim(t)
+	opts := defaultSimOpts()
+	if err := Verify(sim.proof, ConstraintSet{}, PublicInputs{}, opts); err != nil {
+		t.Fatalf("Verify rejected honest proof: %v", err)
+	}
+
+	other := opts
+	other.Eta++
+	if err := Verify(sim.proof, ConstraintSet{}, PublicInputs{}, other); !errors.Is(err, ErrParamsID) {
+		t.Fatalf("expected ErrParamsID for other SimOpts, got %v", err)
+	}
+
+	tamperedDigest := sim.proof.Snapshot().Restore()
+	tamperedDigest.Digests[0] = append([]byte(nil), tamperedDigest.Digests[0]...)
+	tamperedDigest.Digests[0][len(tamperedDigest.Digests[0])-1] ^= 1
+	if err := Verify(tamperedDigest, ConstraintSet{}, PublicInputs{}, opts); !errors.Is(err, ErrFSDigest) {
+		t.Fatalf("expected ErrFSDigest, got %v", err)
+	}
+
+	truncated := sim.proof.Snapshot().Restore()
+	truncated.R = truncated.R[:len(truncated.R)-1]
+	if err := Verify(truncated, ConstraintSet{}, PublicInputs{}, opts); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected ErrMalformedProof, got %v", err)
+	}
+
+	if err := Verify(nil, ConstraintSet{}, PublicInputs{}, SimOpts{}); !errors.Is(err, ErrMalformedProof) {
+		t.Fatalf("expected ErrMalformedProof for nil proof, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package PIOP
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"sort"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
@@ -90,6 +91,24 @@ func BuildPublicLabels(pub PublicInputs) []PublicLabel {
 	if len(pub.U) > 0 {
 		appendPoly("U", pub.U)
 	}
+	if b := pub.Blocks.label(); b != nil {
+		labels = append(labels, PublicLabel{Name: "Blocks", Data: b})
+	}
+	if len(pub.ParamsDigest) > 0 {
+		labels = append(labels, PublicLabel{Name: "Params", Data: pub.ParamsDigest})
+	}
+	if b := pub.Policy.label(); b != nil {
+		labels = append(labels, PublicLabel{Name: "Policy", Data: b})
+	}
+	if pub.Scope != "" {
+		labels = append(labels, PublicLabel{Name: "Scope", Data: []byte(pub.Scope)})
+	}
+	if pub.BindKey {
+		labels = append(labels, PublicLabel{Name: "BindKey", Data: []byte{1}})
+	}
+	if b := pub.NonceDomain.label(); b != nil {
+		labels = append(labels, PublicLabel{Name: "NonceDomain", Data: b})
+	}
 	if len(pub.Extras) > 0 {
 		keys := make([]string, 0, len(pub.Extras))
 		for k := range pub.Extras {
@@ -104,3 +123,18 @@ func BuildPublicLabels(pub PublicInputs) []PublicLabel {
 	}
 	return labels
 }
+
+// BuildBatchPublicLabels binds the publics of every showing in a batch, in
+// order, together with the batch size. Labels of showing s are prefixed with
+// "showing[s].".
+func BuildBatchPublicLabels(pubs []PublicInputs) []PublicLabel {
+	count := make([]byte, 8)
+	binary.LittleEndian.PutUint64(count, uint64(len(pubs)))
+	labels := []PublicLabel{{Name: "BatchCount", Data: count}}
+	for s, pub := range pubs {
+		for _, l := range BuildPublicLabels(pub) {
+			labels = append(labels, PublicLabel{Name: fmt.Sprintf("showing[%d].%s", s, l.Name), Data: l.Data})
+		}
+	}
+	return labels
+}
@@ -1,8 +1,13 @@
 package PIOP
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"golang.org/x/crypto/sha3"
 )
@@ -43,22 +48,77 @@ func (s Shake256XOF) Expand(label string, parts ...[]byte) []byte {
 	return out
 }
 
+// fsRoundLabels are the XOF labels of the four grinding rounds.
+var fsRoundLabels = [4]string{"fs-gamma", "fs-gammap", "fs-eprime", "fs-tail"}
+
+// ProgrammedXOF is a test-mode XOF modelling a programmable random oracle:
+// queries under a programmed label return the programmed digest whatever the
+// input, other labels go to Base. Programming the four FS round labels fixes
+// every verifier challenge before the prover speaks, which is what the
+// zero-knowledge leak report (zk_leaks.go) and an extractor rewinding the
+// transcript need. Proofs built with it only verify under the same oracle.
+type ProgrammedXOF struct {
+	Base XOF
+
+	mu  sync.RWMutex
+	out map[string][]byte
+}
+
+// NewProgrammedXOF returns a ProgrammedXOF falling back to base.
+func NewProgrammedXOF(base XOF) *ProgrammedXOF {
+	return &ProgrammedXOF{Base: base, out: make(map[string][]byte)}
+}
+
+// ProgramRound fixes the digest answered in FS round (0..3). The digest
+// must have the κ leading zero bits of the round, or grinding never ends.
+func (x *ProgrammedXOF) ProgramRound(round int, digest []byte) {
+	if round < 0 || round >= len(fsRoundLabels) {
+		panic("ProgrammedXOF.ProgramRound: round out of range")
+	}
+	x.mu.Lock()
+	x.out[fsRoundLabels[round]] = append([]byte(nil), digest...)
+	x.mu.Unlock()
+}
+
+// Expand implements XOF.
+func (x *ProgrammedXOF) Expand(label string, parts ...[]byte) []byte {
+	x.mu.RLock()
+	d, ok := x.out[label]
+	x.mu.RUnlock()
+	if ok {
+		return append([]byte(nil), d...)
+	}
+	return x.Base.Expand(label, parts...)
+}
+
 // FSParams bundles the Fiat–Shamir security parameters.
 type FSParams struct {
 	Lambda int // random oracle security parameter (bits)
 	Kappa  [4]int
+	// Workers bounds the number of goroutines used while grinding
+	// (0 = runtime.GOMAXPROCS). The accepted counter does not depend on it.
+	Workers int
 }
 
 // FS tracks the four grinding rounds in the SmallWood–ARK transcript.
 type FS struct {
-	xof    XOF
-	params FSParams
-	salt   []byte
-	ctr    [4]uint64
-	h      [4][]byte
-	labels [4]string
+	xof      XOF
+	params   FSParams
+	salt     []byte
+	ctr      [4]uint64
+	h        [4][]byte
+	labels   [4]string
+	attempts [4]uint64
 }
 
+// grindParallelMinBits is the smallest κ for which grinding is spread across
+// goroutines; below it the expected 2^κ hashes are cheaper than the fan-out.
+const grindParallelMinBits = 8
+
+// grindCheckEvery is the number of hashes a grinding worker evaluates between
+// two context cancellation checks.
+const grindCheckEvery = 1024
+
 // NewFS prepares the Fiat–Shamir state with the provided XOF and salt.
 func NewFS(x XOF, salt []byte, params FSParams) *FS {
 	if params.Lambda <= 0 {
@@ -68,7 +128,7 @@ func NewFS(x XOF, salt []byte, params FSParams) *FS {
 		xof:    x,
 		params: params,
 		salt:   append([]byte(nil), salt...),
-		labels: [4]string{"fs-gamma", "fs-gammap", "fs-eprime", "fs-tail"},
+		labels: fsRoundLabels,
 	}
 	return fs
 }
@@ -86,33 +146,136 @@ func (fs *FS) Ctr(round int) uint64 {
 	return fs.ctr[round]
 }
 
+// Attempts reports how many digests were evaluated while grinding the given
+// round, summed over all workers.
+func (fs *FS) Attempts(round int) uint64 {
+	if round < 0 || round >= len(fs.attempts) {
+		panic("FS.Attempts: round out of range")
+	}
+	return fs.attempts[round]
+}
+
+// GrindAttempts returns the per-round digest counts recorded so far.
+func (fs *FS) GrindAttempts() [4]uint64 {
+	return fs.attempts
+}
+
 // GrindAndDerive performs the κ-bit grinding loop for the selected round and
 // returns the accepted hash material along with the derived challenge bytes.
 func (fs *FS) GrindAndDerive(round int, material [][]byte, derive func([]byte) []byte) (h []byte, ctr uint64, chal []byte) {
+	h, ctr, chal, err := fs.GrindAndDeriveContext(context.Background(), round, material, derive)
+	if err != nil {
+		panic(fmt.Errorf("FS.GrindAndDerive: %w", err))
+	}
+	return h, ctr, chal
+}
+
+// GrindAndDeriveContext is GrindAndDerive with cancellation; once ctx is done
+// it returns a *CanceledError. The counter space
+// is partitioned across FSParams.Workers goroutines and the smallest counter
+// whose digest has κ leading zero bits is accepted, so the transcript is the
+// same as the one produced by a sequential search.
+func (fs *FS) GrindAndDeriveContext(ctx context.Context, round int, material [][]byte, derive func([]byte) []byte) (h []byte, ctr uint64, chal []byte, err error) {
 	if round < 0 || round >= len(fs.ctr) {
 		panic("FS.GrindAndDerive: round out of range")
 	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	kappa := fs.params.Kappa[round]
-	counter := fs.ctr[round]
-	for {
-		input := make([]byte, len(fs.salt))
-		copy(input, fs.salt)
-		for _, m := range material {
-			input = append(input, m...)
+	prefix := make([]byte, 0, len(fs.salt)+8)
+	prefix = append(prefix, fs.salt...)
+	for _, m := range material {
+		prefix = append(prefix, m...)
+	}
+	workers := fs.params.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if kappa < grindParallelMinBits {
+		workers = 1
+	}
+	counter, digest, attempts, err := grind(ctx, fs.xof, fs.labels[round], prefix, fs.ctr[round], kappa, workers)
+	fs.attempts[round] += attempts
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	fs.h[round] = append([]byte(nil), digest...)
+	fs.ctr[round] = counter
+	chal = derive(digest)
+	return fs.h[round], counter, chal, nil
+}
+
+// grind searches counters start, start+1, ... for the first digest of
+// label‖prefix‖ctr with κ leading zero bits. Worker w scans the residue class
+// start+w (mod workers) in increasing order and stops once it passes the best
+// counter found so far, which makes the result independent of scheduling.
+func grind(ctx context.Context, x XOF, label string, prefix []byte, start uint64, kappa, workers int) (uint64, []byte, uint64, error) {
+	var (
+		mu         sync.Mutex
+		best       atomic.Uint64
+		bestDigest []byte
+		total      atomic.Uint64
+		aborted    atomic.Bool
+		wrapped    atomic.Bool
+	)
+	best.Store(math.MaxUint64)
+	scan := func(offset, stride uint64) {
+		input := make([]byte, len(prefix)+8)
+		copy(input, prefix)
+		var n uint64
+		defer func() { total.Add(n) }()
+		for c := start + offset; ; {
+			if c > best.Load() {
+				return
+			}
+			if n%grindCheckEvery == 0 && ctx.Err() != nil {
+				aborted.Store(true)
+				return
+			}
+			binary.LittleEndian.PutUint64(input[len(prefix):], c)
+			digest := x.Expand(label, input)
+			n++
+			if hasZeroPrefix(digest, kappa) {
+				mu.Lock()
+				if c < best.Load() {
+					best.Store(c)
+					bestDigest = digest
+				}
+				mu.Unlock()
+				return
+			}
+			next := c + stride
+			if next < c {
+				wrapped.Store(true)
+				return
+			}
+			c = next
 		}
-		input = append(input, u64le(counter)...)
-		digest := fs.xof.Expand(fs.labels[round], input)
-		if hasZeroPrefix(digest, kappa) {
-			fs.h[round] = append([]byte(nil), digest...)
-			fs.ctr[round] = counter
-			chal = derive(digest)
-			return fs.h[round], counter, chal
+	}
+	if workers <= 1 {
+		scan(0, 1)
+	} else {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(offset uint64) {
+				defer wg.Done()
+				scan(offset, uint64(workers))
+			}(uint64(w))
 		}
-		counter++
-		if counter == 0 {
+		wg.Wait()
+	}
+	if aborted.Load() {
+		return 0, nil, total.Load(), checkCtx(ctx, "FS.GrindAndDerive")
+	}
+	if bestDigest == nil {
+		if wrapped.Load() {
 			panic("FS.GrindAndDerive: counter wrapped")
 		}
+		return 0, nil, total.Load(), fmt.Errorf("grinding found no valid counter")
 	}
+	return best.Load(), bestDigest, total.Load(), nil
 }
 
 // hasZeroPrefix checks whether the first kappa bits of buf are zero.
@@ -0,0 +1,71 @@
+package PIOP
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestGrindParallelMatchesSequential(t *testing.T) {
+	salt := []byte("grind-salt")
+	material := [][]byte{[]byte("root"), []byte("labels")}
+	kappa := [4]int{10, 11, 12, 9}
+	seq := NewFS(NewShake256XOF(64), salt, FSParams{Kappa: kappa, Workers: 1})
+	par := NewFS(NewShake256XOF(64), salt, FSParams{Kappa: kappa, Workers: 7})
+	id := func(h []byte) []byte { return h }
+	for round := 0; round < 4; round++ {
+		hs, cs, _ := seq.GrindAndDerive(round, material, id)
+		hp, cp, _ := par.GrindAndDerive(round, material, id)
+		if cs != cp {
+			t.Fatalf("round %d: sequential ctr=%d parallel ctr=%d", round, cs, cp)
+		}
+		if !bytes.Equal(hs, hp) {
+			t.Fatalf("round %d: digest mismatch", round)
+		}
+		if !hasZeroPrefix(hp, kappa[round]) {
+			t.Fatalf("round %d: digest lacks %d zero bits", round, kappa[round])
+		}
+		if got, want := seq.Attempts(round), cs+1; got != want {
+			t.Fatalf("round %d: sequential attempts=%d want %d", round, got, want)
+		}
+		if par.Attempts(round) < cp+1 {
+			t.Fatalf("round %d: parallel attempts=%d below ctr+1=%d", round, par.Attempts(round), cp+1)
+		}
+	}
+}
+
+func TestGrindContextCancelled(t *testing.T) {
+	fs := NewFS(NewShake256XOF(64), []byte("salt"), FSParams{Kappa: [4]int{64, 0, 0, 0}, Workers: 4})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, _, err := fs.GrindAndDeriveContext(ctx, 0, [][]byte{[]byte("m")}, func(h []byte) []byte { return h })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	var ce *CanceledError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *CanceledError, got %T", err)
+	}
+}
+
+func TestProgrammedXOFAnswersProgrammedRounds(t *testing.T) {
+	ch, err := SampleFSChallenges([4]int{0, 3, 9, 16}, rand.Reader)
+	if err != nil {
+		t.Fatalf("SampleFSChallenges: %v", err)
+	}
+	x := ch.Oracle()
+	fs := NewFS(x, []byte("salt"), FSParams{Kappa: [4]int{0, 3, 9, 16}})
+	id := func(h []byte) []byte { return h }
+	for round := 0; round < 4; round++ {
+		h, ctr, _ := fs.GrindAndDerive(round, [][]byte{[]byte("m")}, id)
+		if ctr != 0 || !bytes.Equal(h, ch[round]) {
+			t.Fatalf("round %d: got ctr=%d, digest not the programmed one", round, ctr)
+		}
+	}
+	want := NewShake256XOF(64).Expand("other", []byte("m"))
+	if got := x.Expand("other", []byte("m")); !bytes.Equal(got, want) {
+		t.Fatalf("unprogrammed label did not fall back to the base XOF")
+	}
+}
@@ -0,0 +1,85 @@
+//go:build testonly
+
+package PIOP
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// SetFSOracle makes the prover and verifier run the Fiat–Shamir rounds
+// through x instead of SHAKE-256 (nil restores it). A ProgrammedXOF fixes
+// the challenges before the prover commits, which voids soundness; the hook
+// exists for the leak report and the rewinding extractor only.
+func (o *SimOpts) SetFSOracle(x XOF) { o.fsOracle = x }
+
+// SetCoins makes the prover draw its salt, masks, row tails and K-field
+// samples from r instead of crypto/rand (nil restores it). Replaying r
+// under new challenges is how the extractor rewinds a prover.
+func (o *SimOpts) SetCoins(r io.Reader) { o.coins = r }
+
+// SimulateProof is the zero-knowledge simulator of the credential proof. It
+// sees only the publics and the challenges ch, which it programs into the
+// oracle before committing: the rows are uniform polynomials in place of the
+// witness (plus uniform quotient rows for lifted publics), and since the
+// simulator knows Γ′ ahead of the commitment it balances ΣΩ Q through the
+// masks (balanceMasks). The transcript verifies under ch.Oracle().
+func SimulateProof(ctx context.Context, pub PublicInputs, opts SimOpts, ch FSChallenges) (*Proof, error) {
+	if err := validatePublics(pub); err != nil {
+		return nil, err
+	}
+	opts.Credential = true
+	opts.fsOracle = ch.Oracle()
+	opts.simulate = true
+	opts.applyDefaults()
+	ringQ, _, _, err := loadParamsAndOmega(opts)
+	if err != nil {
+		return nil, fmt.Errorf("simulate: load params/omega: %w", err)
+	}
+	q := ringQ.Modulus[0]
+	coins := opts.coinSource()
+	uniform := func(n int) []*ring.Poly {
+		out := make([]*ring.Poly, n)
+		for i := range out {
+			out[i] = ringQ.NewPoly()
+			for j := range out[i].Coeffs[0] {
+				out[i].Coeffs[0][j] = randUint64Mod(coins, q)
+			}
+		}
+		return out
+	}
+	n := pub.Blocks.norm()
+	wit := WitnessInputs{
+		M1: uniform(n.M1), M2: uniform(n.M2), RU0: uniform(n.RU0), RU1: uniform(n.RU1), R: uniform(n.R),
+		R0: uniform(n.RU0), R1: uniform(n.RU1), K0: uniform(n.RU0), K1: uniform(n.RU1),
+	}
+	lift, err := liftLayoutFromPublics(pub, ringQ)
+	if err != nil {
+		return nil, fmt.Errorf("simulate: %w", err)
+	}
+	if lift != nil {
+		wit.Quot = uniform(lift.Rows())
+	}
+	// The builder rebuilds the constraints from the committed rows; the set
+	// passed in only fixes their layout, which a zero witness (inside every
+	// bound) gives as well.
+	zero := func(n int) []*ring.Poly {
+		out := make([]*ring.Poly, n)
+		for i := range out {
+			out[i] = ringQ.NewPoly()
+		}
+		return out
+	}
+	shape := WitnessInputs{
+		M1: zero(n.M1), M2: zero(n.M2), RU0: zero(n.RU0), RU1: zero(n.RU1), R: zero(n.R),
+		R0: zero(n.RU0), R1: zero(n.RU1), K0: zero(n.RU0), K1: zero(n.RU1), Quot: zero(len(wit.Quot)),
+	}
+	cs, err := BuildCredentialConstraintSetPre(ringQ, pub.BoundB, pub, shape, opts.NCols)
+	if err != nil {
+		return nil, fmt.Errorf("simulate: build constraint set: %w", err)
+	}
+	return BuildWithConstraintsContext(ctx, pub, wit, cs, opts, FSModeCredential)
+}
@@ -1,11 +1,11 @@
 package PIOP
 
 import (
+	"context"
 	"fmt"
 
 	lvcs "vSIS-Signature/LVCS"
 	kf "vSIS-Signature/internal/kfield"
-	ntrurio "vSIS-Signature/ntru/io"
 	"vSIS-Signature/prf"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
@@ -20,6 +20,15 @@ const FSModeCredential = "PACS-Credential"
 // prover when Credential is false. The caller can provide a personalization string; if empty,
 // FSModeCredential is used.
 func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet, opts SimOpts, personalization string) (*Proof, error) {
+	return BuildWithConstraintsContext(context.Background(), pub, wit, set, opts, personalization)
+}
+
+// BuildWithConstraintsContext is BuildWithConstraints with cancellation; it
+// returns a *CanceledError once ctx is done.
+func BuildWithConstraintsContext(ctx context.Context, pub PublicInputs, wit WitnessInputs, set ConstraintSet, opts SimOpts, personalization string) (*Proof, error) {
+	if err := checkCtx(ctx, "BuildWithConstraints"); err != nil {
+		return nil, err
+	}
 	opts.applyDefaults()
 	if personalization == "" {
 		personalization = FSModeCredential
@@ -35,8 +44,8 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 		}
 		// Map witness inputs to rows/layout/decs params.
 		rows, rowInputs, rowLayout, decsParams, maskRowOffset, maskRowCount, witnessCount, _, err := buildCredentialRows(ringQ, wit, opts)
-		// If PRF trace is present, switch to showing row builder.
 		if err == nil && wit.Extras != nil {
+			// If PRF trace is present, switch to showing row builder.
 			if _, ok := wit.Extras["prf_trace"]; ok {
 				params, perr := prf.LoadDefaultParams()
 				if perr != nil {
@@ -55,7 +64,17 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 		var pk *lvcs.ProverKey
 		var oracleLayout lvcs.OracleLayout
 		labels := BuildPublicLabels(pub)
+		if set.Batch != nil {
+			labels = BuildBatchPublicLabels(set.Batch.Publics)
+		}
 		labelsDigest := computeLabelsDigest(labels)
+		var prfParams *prf.Params
+		if set.PRFLayout != nil {
+			if prfParams, err = prf.LoadDefaultParams(); err != nil {
+				return nil, fmt.Errorf("load prf params: %w", err)
+			}
+		}
+		paramsID := ComputeParamsID(ringQ, pub, prfParams, opts, personalization)
 
 		// Small-field params (theta>1) if needed.
 		var sfRows [][]uint64
@@ -68,7 +87,7 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 		origWitnessCount := witnessCount
 		witnessPolys := rows[:origWitnessCount]
 		if opts.Theta > 1 {
-			sf, sfErr := deriveSmallFieldParamsNoRows(ringQ, omega, opts.Theta)
+			sf, sfErr := deriveSmallFieldParamsNoRows(ringQ, omega, opts.Theta, opts.coins)
 			if sfErr != nil {
 				return nil, fmt.Errorf("small-field params: %w", sfErr)
 			}
@@ -91,6 +110,9 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 			sfMuInv = append([]uint64(nil), sf.MuInv.Limb...)
 			sfNCols = len(omega)
 		}
+		if err := checkCtx(ctx, "BuildWithConstraints"); err != nil {
+			return nil, err
+		}
 		// Commit rows to get root/pk/layout using possibly updated rowInputs/layout.
 		root, pk, oracleLayout, err = commitRows(ringQ, rowInputs, opts.Ell, decsParams, witnessCount, maskRowOffset, maskRowCount)
 		if err != nil {
@@ -126,6 +148,68 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 				set.FparNorm = postRows.FparNorm
 				set.FaggInt = postRows.FaggInt
 				set.FaggNorm = postRows.FaggNorm
+				// Policy, key-binding, commit and nonce-domain residuals follow
+				// the post-sign prefix.
+				off := len(postRows.FparInt)
+				if !pub.Policy.empty() {
+					policy, perr := NewPolicyConstraintConfig(ringQ, pub.Policy, pub.Blocks, pub.BoundB, sfNCols)
+					if perr != nil {
+						return nil, perr
+					}
+					polRows, perr := policy.residuals(pk.RowPolys)
+					if perr != nil {
+						return nil, fmt.Errorf("rebuild policy constraints from rows: %w", perr)
+					}
+					if len(set.FparInt) < off+len(polRows) {
+						return nil, fmt.Errorf("constraint set too small for policy: have %d want >=%d", len(set.FparInt), off+len(polRows))
+					}
+					copy(set.FparInt[off:off+len(polRows)], polRows)
+					off += len(polRows)
+				}
+				if pub.bindsKey() && set.PRFLayout != nil {
+					keys, perr := NewKeyBindingConfig(ringQ, pub.Blocks, set.PRFLayout.StartIdx, set.PRFLayout.LenKey, sfNCols)
+					if perr != nil {
+						return nil, perr
+					}
+					keyRows, perr := keys.residuals(pk.RowPolys)
+					if perr != nil {
+						return nil, fmt.Errorf("rebuild key binding from rows: %w", perr)
+					}
+					if len(set.FparInt) < off+len(keyRows) {
+						return nil, fmt.Errorf("constraint set too small for key binding: have %d want >=%d", len(set.FparInt), off+len(keyRows))
+					}
+					copy(set.FparInt[off:off+len(keyRows)], keyRows)
+					off += len(keyRows)
+				}
+				if pub.rebound() {
+					comRows, perr := showingCommitResiduals(ringQ, pub, pk.RowPolys, sfNCols)
+					if perr != nil {
+						return nil, fmt.Errorf("rebuild commit constraints from rows: %w", perr)
+					}
+					if len(set.FparInt) < off+len(comRows) {
+						return nil, fmt.Errorf("constraint set too small for commitment: have %d want >=%d", len(set.FparInt), off+len(comRows))
+					}
+					copy(set.FparInt[off:off+len(comRows)], comRows)
+					off += len(comRows)
+				}
+				if !pub.NonceDomain.empty() && set.PRFLayout != nil {
+					params, perr := prf.LoadDefaultParams()
+					if perr != nil {
+						return nil, fmt.Errorf("load prf params: %w", perr)
+					}
+					dom, perr := NewNonceDomainConfig(ringQ, pub.NonceDomain, set.PRFLayout.StartIdx, params, pub.BoundB)
+					if perr != nil {
+						return nil, perr
+					}
+					domRows, perr := dom.residuals(pk.RowPolys)
+					if perr != nil {
+						return nil, fmt.Errorf("rebuild nonce domain from rows: %w", perr)
+					}
+					if len(set.FparInt) < off+len(domRows) {
+						return nil, fmt.Errorf("constraint set too small for nonce domain: have %d want >=%d", len(set.FparInt), off+len(domRows))
+					}
+					copy(set.FparInt[off:off+len(domRows)], domRows)
+				}
 			}
 
 			// Rebuild PRF constraints when layout + tag are present.
@@ -146,6 +230,9 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 			}
 		}
 
+		if err := checkCtx(ctx, "BuildWithConstraints"); err != nil {
+			return nil, err
+		}
 		// Flatten constraint set for masking config derivation.
 		FparAll := append([]*ring.Poly{}, set.FparInt...)
 		FparAll = append(FparAll, set.FparNorm...)
@@ -182,13 +269,14 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 			NCols:             sfNCols,
 			DecsParams:        decsParams,
 			LabelsDigest:      labelsDigest,
+			ParamsID:          paramsID,
 			SmallFieldChi:     sfChi,
 			SmallFieldOmegaS1: sfOmegaS1,
 			SmallFieldMuInv:   sfMuInv,
 			SmallFieldK:       sfK,
 			SmallFieldRows:    sfRows,
 		}
-		proof, err := RunMaskingFS(mfsIn)
+		proof, err := RunMaskingFSContext(ctx, mfsIn)
 		if err != nil {
 			return nil, fmt.Errorf("RunMaskingFS: %w", err)
 		}
@@ -201,17 +289,55 @@ func BuildWithConstraints(pub PublicInputs, wit WitnessInputs, set ConstraintSet
 	}
 	// Bridge to existing PACS flow; constraint set/publics are ignored because
 	// PACS builds its own witness/constraints internally.
-	b := NewPACSBuilder(opts)
-	return b.Build(pub, wit, MaskConfig{})
+	b := &pacsBuilder{opts: opts}
+	return b.BuildContext(ctx, pub, wit, MaskConfig{})
 }
 
 // VerifyWithConstraints replays the FS transcript for a proof built with
 // BuildWithConstraints, using the supplied constraint set, personalization,
 // and public inputs. For now, PACS still bridges to VerifyNIZK.
 func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts, personalization string) (bool, error) {
+	return VerifyWithConstraintsContext(context.Background(), proof, set, pub, opts, personalization)
+}
+
+// VerifyWithConstraintsContext is VerifyWithConstraints with cancellation; it
+// returns a *CanceledError once ctx is done.
+func VerifyWithConstraintsContext(ctx context.Context, proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts, personalization string) (bool, error) {
+	return verifyWithConstraints(ctx, proof, set, pub, opts, personalization, nil)
+}
+
+// VerifyWithConstraintsReport is VerifyWithConstraints in diagnostic mode. The
+// Eq.(4) replay runs over every tail point and K-point instead of stopping at
+// the first mismatch, and each replayed residual is compared with the
+// prover's committed F-polynomials per constraint family (commit, center,
+// hash, packing, bounds, signature, PRF round i, ...). The report is returned
+// even when verification fails; err is the verdict of the plain verifier.
+func VerifyWithConstraintsReport(proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts, personalization string) (*VerificationReport, error) {
+	return VerifyWithConstraintsReportContext(context.Background(), proof, set, pub, opts, personalization)
+}
+
+// VerifyWithConstraintsReportContext is VerifyWithConstraintsReport with
+// cancellation.
+func VerifyWithConstraintsReportContext(ctx context.Context, proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts, personalization string) (*VerificationReport, error) {
+	var report *VerificationReport
+	ok, err := verifyWithConstraints(ctx, proof, set, pub, opts, personalization, &report)
+	if report == nil {
+		report = NewVerificationReport(nil)
+		report.finish(ok, ok, ok, err)
+	}
+	if err == nil && !ok {
+		err = &ErrConstraint{Name: "replay", Row: -1}
+	}
+	return report, err
+}
+
+func verifyWithConstraints(ctx context.Context, proof *Proof, set ConstraintSet, pub PublicInputs, opts SimOpts, personalization string, report **VerificationReport) (bool, error) {
+	if err := checkCtx(ctx, "VerifyWithConstraints"); err != nil {
+		return false, err
+	}
 	opts.applyDefaults()
 	if proof == nil {
-		return false, fmt.Errorf("nil proof")
+		return false, fmt.Errorf("nil proof: %w", ErrMalformedProof)
 	}
 	if len(set.FparInt)+len(set.FparNorm)+len(set.FaggInt)+len(set.FaggNorm) == 0 && !opts.Credential {
 		// For PACS, constraint set is ignored; for credential we enforce non-empty above.
@@ -221,29 +347,40 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 	}
 	if opts.Credential {
 		// For credential mode, constraint polys are already snapshotted into the proof; we only
-		// bind publics via labels digest and replay the transcript.
+		// check the configuration via ParamsID, bind publics via labels digest and replay the transcript.
+		// If the prover recorded a truncated domain, respect it; otherwise allow opts.NCols as a hint.
+		if proof.NColsUsed == 0 && opts.NCols > 0 {
+			proof.NColsUsed = opts.NCols
+		}
+		ringQ, omega, _, err := loadParamsAndOmega(opts)
+		if err != nil {
+			return false, fmt.Errorf("load params for replay: %w", err)
+		}
+		var prfParams *prf.Params
+		if set.PRFLayout != nil {
+			if prfParams, err = prf.LoadDefaultParams(); err != nil {
+				return false, fmt.Errorf("load prf params: %w", err)
+			}
+		}
+		if err := checkParamsID(proof, ComputeParamsID(ringQ, pub, prfParams, opts, personalization)); err != nil {
+			return false, err
+		}
 		labels := BuildPublicLabels(pub)
+		if set.Batch != nil {
+			labels = BuildBatchPublicLabels(set.Batch.Publics)
+		}
 		digest := computeLabelsDigest(labels)
 		if len(proof.LabelsDigest) == 0 {
 			// Backfill for proofs that predate label hashing.
 			proof.LabelsDigest = digest
 		} else if !equalByteSlices(digest, proof.LabelsDigest) {
-			return false, fmt.Errorf("labels digest mismatch")
-		}
-		// If the prover recorded a truncated domain, respect it; otherwise allow opts.NCols as a hint.
-		if proof.NColsUsed == 0 && opts.NCols > 0 {
-			proof.NColsUsed = opts.NCols
+			return false, fmt.Errorf("labels digest mismatch: %w", ErrFSDigest)
 		}
 		if len(proof.OmegaTrunc) == 0 && opts.NCols > 0 {
-			omega, err := deriveOmegaWithNCols(opts.NCols)
-			if err == nil {
-				proof.OmegaTrunc = omega
+			if trunc, err := deriveOmegaWithNCols(ringQ, opts.NCols); err == nil {
+				proof.OmegaTrunc = trunc
 			}
 		}
-		ringQ, omega, _, err := loadParamsAndOmega(opts)
-		if err != nil {
-			return false, fmt.Errorf("load params for replay: %w", err)
-		}
 		if len(proof.OmegaTrunc) > 0 {
 			omega = append([]uint64(nil), proof.OmegaTrunc...)
 		} else if opts.NCols > 0 && len(omega) > opts.NCols {
@@ -315,19 +452,19 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			}
 		}
 		var thetaRI0, thetaRI1 []*ring.Poly
-		if len(pub.RI0) > 0 {
-			theta, err := thetaPolyFromNTT(ringQ, pub.RI0[0], ncols)
+		for i := range pub.RI0 {
+			theta, err := thetaPolyFromNTT(ringQ, pub.RI0[i], ncols)
 			if err != nil {
-				return false, fmt.Errorf("theta RI0: %w", err)
+				return false, fmt.Errorf("theta RI0[%d]: %w", i, err)
 			}
-			thetaRI0 = []*ring.Poly{theta}
+			thetaRI0 = append(thetaRI0, theta)
 		}
-		if len(pub.RI1) > 0 {
-			theta, err := thetaPolyFromNTT(ringQ, pub.RI1[0], ncols)
+		for i := range pub.RI1 {
+			theta, err := thetaPolyFromNTT(ringQ, pub.RI1[i], ncols)
 			if err != nil {
-				return false, fmt.Errorf("theta RI1: %w", err)
+				return false, fmt.Errorf("theta RI1[%d]: %w", i, err)
 			}
-			thetaRI1 = []*ring.Poly{theta}
+			thetaRI1 = append(thetaRI1, theta)
 		}
 		thetaB := make([]*ring.Poly, len(pub.B))
 		for i := range pub.B {
@@ -340,6 +477,8 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 
 		var (
 			eval       ConstraintEvaluator
+			families   []ConstraintFamily
+			postBoundsFamilies []ConstraintFamily
 			evalK      KConstraintEvaluator
 			rowCount   int
 			haveCred   bool
@@ -363,29 +502,22 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			}
 			K = k
 		}
-		// Build post-sign evaluator when A is present.
+		// Policies, scopes, commitments and nonce domains only extend single
+		// showings (see BuildShowingCombined).
+		if (!pub.Policy.empty() || pub.bindsKey() || pub.rebound() || !pub.NonceDomain.empty()) && (set.Batch != nil || len(pub.A) == 0 || set.PRFLayout == nil || len(pub.Tag) == 0) {
+			return false, fmt.Errorf("showing policy outside a single showing: %w", ErrMalformedProof)
+		}
 		if len(pub.A) > 0 {
-			uCount := len(pub.A[0])
-			cfgPost := PostSignConstraintConfig{
-				Ring:          ringQ,
-				A:             thetaA,
-				B:             thetaB,
-				Bound:         pub.BoundB,
-				PackingNCols:  ncols,
-				PackingSelNTT: packSelNTT,
-				IdxM1:         0,
-				IdxM2:         1,
-				IdxR0:         5,
-				IdxR1:         6,
-				IdxT:          9,
-				IdxUBase:      10,
-				UCount:        uCount,
-				BoundRows:     []int{0, 1, 5, 6},
-				Omega:         omega,
+			// Build post-sign evaluator when A is present.
+			if err := pub.Blocks.checkPublics(pub); err != nil {
+				return false, fmt.Errorf("%w: %w", err, ErrMalformedProof)
 			}
+			cfgPost := newPostSignConfig(ringQ, pub, thetaA, thetaB, packSelNTT, ncols, omega)
 			splitPostBounds = set.PRFLayout != nil && len(pub.Tag) > 0
 			if splitPostBounds {
 				eval = cfgPost.PostSignEvaluatorCore()
+				families = cfgPost.Families(true, false)
+				postBoundsFamilies = cfgPost.Families(false, true)
 				postBoundsEval = cfgPost.PostSignEvaluatorBounds()
 				if proof.Theta > 1 && K != nil {
 					ek, err := cfgPost.PostSignKEvaluatorCore(K)
@@ -401,6 +533,7 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 				}
 			} else {
 				eval = cfgPost.PostSignEvaluator()
+				families = cfgPost.Families(true, true)
 				if proof.Theta > 1 && K != nil {
 					ek, err := cfgPost.PostSignKEvaluator(K)
 					if err != nil {
@@ -409,11 +542,108 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 					evalK = ek
 				}
 			}
+			// Policy residuals sit between the post-sign core and the PRF.
+			if !pub.Policy.empty() {
+				cfgPol, err := NewPolicyConstraintConfig(ringQ, pub.Policy, pub.Blocks, pub.BoundB, ncols)
+				if err != nil {
+					return false, fmt.Errorf("%w: %w", err, ErrMalformedProof)
+				}
+				eval = composeEvaluators(eval, cfgPol.PolicyEvaluator())
+				families = appendFamilies(families, cfgPol.Families()...)
+				if proof.Theta > 1 && K != nil {
+					ek, err := cfgPol.PolicyKEvaluator(K)
+					if err != nil {
+						return false, err
+					}
+					evalK = composeKEvaluators(evalK, ek)
+				}
+			}
+			// Key-binding residuals follow the policy.
+			if pub.bindsKey() {
+				params, err := prf.LoadDefaultParams()
+				if err != nil {
+					return false, fmt.Errorf("load prf params: %w", err)
+				}
+				if pub.Scope != "" {
+					if err := checkScopeNonce(pub, params, ncols); err != nil {
+						return false, fmt.Errorf("%w: %w", err, ErrMalformedProof)
+					}
+				}
+				if set.PRFLayout.LenKey != params.LenKey {
+					return false, fmt.Errorf("key binding: layout has %d key lanes, want %d: %w", set.PRFLayout.LenKey, params.LenKey, ErrMalformedProof)
+				}
+				cfgKey, err := NewKeyBindingConfig(ringQ, pub.Blocks, set.PRFLayout.StartIdx, params.LenKey, ncols)
+				if err != nil {
+					return false, fmt.Errorf("%w: %w", err, ErrMalformedProof)
+				}
+				eval = composeEvaluators(eval, cfgKey.KeyBindingEvaluator())
+				families = appendFamilies(families, cfgKey.Families()...)
+				if proof.Theta > 1 && K != nil {
+					ek, err := cfgKey.KeyBindingKEvaluator(K)
+					if err != nil {
+						return false, err
+					}
+					evalK = composeKEvaluators(evalK, ek)
+				}
+			}
+			// Commit residuals of a re-bound showing follow the key binding.
+			if pub.rebound() {
+				if len(pub.Ac) == 0 || len(pub.Com) != len(pub.Ac) {
+					return false, fmt.Errorf("re-bound showing: Com has %d rows, Ac has %d: %w", len(pub.Com), len(pub.Ac), ErrMalformedProof)
+				}
+				cfgCom := newShowingCommitConfig(ringQ, pub, thetaAc, thetaCom, omega)
+				eval = composeEvaluators(eval, cfgCom.CredentialEvaluator())
+				families = appendFamilies(families, cfgCom.Families()...)
+				if proof.Theta > 1 && K != nil {
+					ek, err := cfgCom.CredentialKEvaluator(K)
+					if err != nil {
+						return false, err
+					}
+					evalK = composeKEvaluators(evalK, ek)
+				}
+			}
+			// Nonce-domain residuals of a hidden nonce come last.
+			if !pub.NonceDomain.empty() {
+				if len(pub.Nonce) > 0 || pub.Scope != "" {
+					return false, fmt.Errorf("hidden-nonce showing with a public nonce: %w", ErrMalformedProof)
+				}
+				params, err := prf.LoadDefaultParams()
+				if err != nil {
+					return false, fmt.Errorf("load prf params: %w", err)
+				}
+				cfgDom, err := NewNonceDomainConfig(ringQ, pub.NonceDomain, set.PRFLayout.StartIdx, params, pub.BoundB)
+				if err != nil {
+					return false, fmt.Errorf("%w: %w", err, ErrMalformedProof)
+				}
+				eval = composeEvaluators(eval, cfgDom.NonceDomainEvaluator())
+				families = appendFamilies(families, cfgDom.Families()...)
+				if proof.Theta > 1 && K != nil {
+					ek, err := cfgDom.NonceDomainKEvaluator(K)
+					if err != nil {
+						return false, err
+					}
+					evalK = composeKEvaluators(evalK, ek)
+				}
+			}
 			boundRows = append([]int(nil), cfgPost.BoundRows...)
 			boundB = cfgPost.Bound
 			rowCount = cfgPost.IdxUBase + cfgPost.UCount
 			haveCred = true
 		} else if len(pub.Ac) > 0 || len(pub.Com) > 0 || len(pub.B) > 0 || len(pub.RI0) > 0 || len(pub.RI1) > 0 {
+			if err := pub.Blocks.checkPublics(pub); err != nil {
+				return false, fmt.Errorf("%w: %w", err, ErrMalformedProof)
+			}
+			lift, err := liftLayoutFromPublics(pub, ringQ)
+			if err != nil {
+				return false, err
+			}
+			blocks, idx := pub.Blocks, pub.Blocks.rowIdx()
+			credBoundRows := blocks.preBoundRows()
+			credRowCount := blocks.Witness()
+			if lift != nil {
+				credBoundRows = append(credBoundRows, lift.RowIndices()...)
+				credRowCount += lift.Rows()
+			}
 			cfgEval := CredentialConstraintConfig{
 				Ring:          ringQ,
 				Ac:            thetaAc,
@@ -426,18 +656,20 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 				TPublicNTT:    tThetaNTT,
 				PackingNCols:  ncols,
 				PackingSelNTT: packSelNTT,
-				IdxM1:         0,
-				IdxM2:         1,
-				IdxRU0:        2,
-				IdxRU1:        3,
-				IdxR:          4,
-				IdxR0:         5,
-				IdxR1:         6,
-				IdxK0:         7,
-				IdxK1:         8,
+				IdxM1:         idx.M1,
+				IdxM2:         idx.M2,
+				IdxRU0:        idx.RU0,
+				IdxRU1:        idx.RU1,
+				IdxR:          idx.R,
+				IdxR0:         idx.R0,
+				IdxR1:         idx.R1,
+				IdxK0:         idx.K0,
+				IdxK1:         idx.K1,
 				IdxT:          -1,
-				BoundRows:     []int{0, 1, 2, 3, 4, 5, 6},
-				CarryRows:     []int{7, 8},
+				Blocks:        blocks,
+				BoundRows:     credBoundRows,
+				CarryRows:     blocks.preCarryRows(),
+				Lift:          lift,
 				Omega:         omega,
 			}
 			cfgK := CredentialConstraintConfig{
@@ -451,21 +683,24 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 				CarryBound:   1,
 				TPublicNTT:   tThetaNTT,
 				PackingNCols: ncols,
-				IdxM1:        0,
-				IdxM2:        1,
-				IdxRU0:       2,
-				IdxRU1:       3,
-				IdxR:         4,
-				IdxR0:        5,
-				IdxR1:        6,
-				IdxK0:        7,
-				IdxK1:        8,
+				IdxM1:        idx.M1,
+				IdxM2:        idx.M2,
+				IdxRU0:       idx.RU0,
+				IdxRU1:       idx.RU1,
+				IdxR:         idx.R,
+				IdxR0:        idx.R0,
+				IdxR1:        idx.R1,
+				IdxK0:        idx.K0,
+				IdxK1:        idx.K1,
 				IdxT:         -1,
-				BoundRows:    []int{0, 1, 2, 3, 4, 5, 6},
-				CarryRows:    []int{7, 8},
+				Blocks:       blocks,
+				BoundRows:    credBoundRows,
+				CarryRows:    blocks.preCarryRows(),
+				Lift:         lift,
 				Omega:        omega,
 			}
 			eval = cfgEval.CredentialEvaluator()
+			families = cfgEval.Families()
 			if proof.Theta > 1 && K != nil {
 				ek, err := cfgK.CredentialKEvaluator(K)
 				if err != nil {
@@ -477,7 +712,7 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			carryRows = append([]int(nil), cfgEval.CarryRows...)
 			boundB = cfgEval.Bound
 			carryBound = cfgEval.CarryBound
-			rowCount = cfgEval.IdxK1 + 1
+			rowCount = credRowCount
 			haveCred = true
 		}
 		// Build PRF evaluator when layout is present.
@@ -492,6 +727,7 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			}
 			evalPRF := cfgPRF.PRFEvaluator()
 			eval = composeEvaluators(eval, evalPRF)
+			families = appendFamilies(families, cfgPRF.Families()...)
 			if proof.Theta > 1 && K != nil {
 				ek, err := cfgPRF.PRFKEvaluator(K)
 				if err != nil {
@@ -501,6 +737,7 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			}
 			if splitPostBounds && postBoundsEval != nil {
 				eval = composeEvaluators(eval, postBoundsEval)
+				families = appendFamilies(families, postBoundsFamilies...)
 				if proof.Theta > 1 && K != nil && postBoundsEvalK != nil {
 					evalK = composeKEvaluators(evalK, postBoundsEvalK)
 				}
@@ -515,6 +752,7 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			return false, fmt.Errorf("no evaluators available for replay")
 		}
 		replay := &ConstraintReplay{
+			Ring:       ringQ,
 			Eval:       eval,
 			EvalK:      evalK,
 			RowCount:   rowCount,
@@ -522,33 +760,32 @@ func VerifyWithConstraints(proof *Proof, set ConstraintSet, pub PublicInputs, op
 			CarryRows:  carryRows,
 			BoundB:     boundB,
 			CarryBound: carryBound,
+			oracle:     opts.fsOracle,
+		}
+		if report != nil {
+			replay.Report = NewVerificationReport(families)
+			*report = replay.Report
 		}
 
-		okLin, okEq4, okSum, err := VerifyNIZKWithReplay(proof, replay)
+		okLin, okEq4, okSum, err := VerifyNIZKWithReplayContext(ctx, proof, replay)
+		if report != nil {
+			replay.Report.finish(okLin, okEq4, okSum, err)
+		}
 		return okLin && okEq4 && okSum, err
 	}
-	okLin, okEq4, okSum, err := VerifyNIZK(proof)
+	okLin, okEq4, okSum, err := VerifyNIZKContext(ctx, proof)
+	if report != nil {
+		*report = NewVerificationReport(nil)
+		(*report).finish(okLin, okEq4, okSum, err)
+	}
 	return okLin && okEq4 && okSum, err
 }
 
 // deriveOmegaWithNCols mirrors the prover's omega derivation but limits the
 // domain to ncols. Used as a fallback when the proof does not carry OmegaTrunc.
-func deriveOmegaWithNCols(ncols int) ([]uint64, error) {
-	par, err := ntrurio.LoadParams(resolve("Parameters/Parameters.json"), true /* allowMismatch */)
-	if err != nil {
-		return nil, fmt.Errorf("load params: %w", err)
-	}
-	ringQ, err := ring.NewRing(par.N, []uint64{par.Q})
-	if err != nil {
-		return nil, fmt.Errorf("ring: %w", err)
-	}
+func deriveOmegaWithNCols(ringQ *ring.Ring, ncols int) ([]uint64, error) {
 	if ncols <= 0 || ncols > ringQ.N {
 		return nil, fmt.Errorf("invalid ncols %d", ncols)
 	}
-	px := ringQ.NewPoly()
-	px.Coeffs[0][1] = 1
-	pts := ringQ.NewPoly()
-	ringQ.NTT(px, pts)
-	omega := append([]uint64(nil), pts.Coeffs[0][:ncols]...)
-	return omega, nil
+	return append([]uint64(nil), ringOmega(ringQ, ncols)...), nil
 }
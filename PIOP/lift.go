@@ -0,0 +1,545 @@
+package PIOP
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+	lvcs "vSIS-Signature/LVCS"
+	kf "vSIS-Signature/internal/kfield"
+)
+
+// Lifted statements prove the pre-sign credential relations, which hold mod
+// the NTRU modulus q, with a PIOP running over a larger NTT prime p. Every
+// value is embedded as an integer (witness rows centered in [−B,B], public
+// values in [0,q)), so each relation R ≡ 0 (mod q) becomes the exact integer
+// identity R − q·k = 0 for a quotient witness k. The quotient is committed as
+// balanced base-(2B+1) digit rows bounded in [−B,B] like the other witness
+// rows, and p is chosen large enough that R − q·k cannot wrap mod p: a zero
+// residual mod p is then a zero over Z, hence mod q. The bounds proven mod p
+// are integer bounds, and each query carries about log p bits, so far smaller
+// η and ℓ′ reach the soundness target; the K-field replay still needs θ > 1.
+
+// Public extras that mark a statement lifted from a smaller modulus.
+const (
+	liftExtraQ         = "Lift.Q"
+	liftExtraStatement = "Lift.Statement"
+)
+
+// LiftLayout locates the quotient rows of a lifted pre-sign statement. The
+// relations are ordered as the residuals of BuildCredentialConstraintSetPre:
+// commit rows 0..Commit−1, one center relation per RU0 then RU1 index, and
+// the hash. Relation i owns Digits[i] consecutive rows, least significant
+// digit first, and the rows of relation i+1 follow those of relation i.
+type LiftLayout struct {
+	Q      uint64 // modulus of the original statement
+	Radix  uint64 // digit radix 2B+1
+	Digits []int  // balanced digits of each relation's quotient
+	Base   int    // row index of the first quotient digit
+	Commit int    // commitment rows of the original statement
+}
+
+// NewLiftLayout sizes the quotients of a pre-sign statement mod q with bound
+// B, credential blocks blocks and commitRows commitment rows, proven over the
+// prime p. It fails when p is too small for the lifted relations to be exact.
+func NewLiftLayout(q, p uint64, bound int64, blocks CredentialBlocks, commitRows int) (*LiftLayout, error) {
+	if q < 2 || p <= q {
+		return nil, fmt.Errorf("lift: need 2 <= q < p, got q=%d p=%d", q, p)
+	}
+	if bound <= 0 {
+		return nil, fmt.Errorf("lift: invalid bound %d", bound)
+	}
+	if err := blocks.Validate(); err != nil {
+		return nil, fmt.Errorf("lift: %w", err)
+	}
+	if commitRows <= 0 {
+		return nil, fmt.Errorf("lift: invalid commitment rows %d", commitRows)
+	}
+	n := blocks.norm()
+	B := big.NewInt(bound)
+	// |commit| <= q·(cols·B+1) and |center| < q. In the hash, R1_0·T, the
+	// message and x0 terms and B0' contribute at most q·(B+(M1+M2)·B+X0·B+1),
+	// while every D_k·R1_k·T with k >= 1 is a product of two values below q
+	// and one bounded by B, so it adds q²·B.
+	commitMax := new(big.Int).Mul(big.NewInt(int64(blocks.CommitCols())), B)
+	commitMax.Add(commitMax, big.NewInt(1))
+	hashMax := new(big.Int).Mul(big.NewInt(int64(1+n.M1+n.M2+n.RU0)), B)
+	hashMax.Add(hashMax, big.NewInt(1))
+	wide := new(big.Int).Mul(new(big.Int).SetUint64(q), B)
+	wide.Mul(wide, big.NewInt(int64(n.RU1-1)))
+	hashMax.Add(hashMax, wide)
+
+	kmax := make([]*big.Int, 0, commitRows+n.RU0+n.RU1+1)
+	for i := 0; i < commitRows; i++ {
+		kmax = append(kmax, commitMax)
+	}
+	for i := 0; i < n.RU0+n.RU1; i++ {
+		kmax = append(kmax, big.NewInt(1))
+	}
+	kmax = append(kmax, hashMax)
+
+	radix := big.NewInt(2*bound + 1)
+	digits := make([]int, len(kmax))
+	for rel, k := range kmax {
+		capacity := big.NewInt(0) // (radix^digits − 1)/2
+		pow := big.NewInt(1)
+		for capacity.Cmp(k) < 0 {
+			pow.Mul(pow, radix)
+			capacity.Sub(pow, big.NewInt(1))
+			capacity.Rsh(capacity, 1)
+			digits[rel]++
+		}
+		// Any residual R − q·k with R and k in range stays below p/2 in
+		// magnitude: 2·q·(kmax + capacity + 1) < p.
+		lim := new(big.Int).Add(capacity, k)
+		lim.Add(lim, big.NewInt(1))
+		lim.Mul(lim, new(big.Int).SetUint64(q))
+		lim.Lsh(lim, 1)
+		if lim.Cmp(new(big.Int).SetUint64(p)) >= 0 {
+			return nil, fmt.Errorf("lift: prime %d too small for relation %d with q=%d B=%d (need > %s)", p, rel, q, bound, lim)
+		}
+	}
+	return &LiftLayout{
+		Q:      q,
+		Radix:  uint64(2*bound + 1),
+		Digits: digits,
+		Base:   blocks.Witness(), // after M1..K1
+		Commit: commitRows,
+	}, nil
+}
+
+// Relations returns the number of lifted relations.
+func (l *LiftLayout) Relations() int { return len(l.Digits) }
+
+// Rows returns the number of quotient digit rows.
+func (l *LiftLayout) Rows() int {
+	rows := 0
+	for _, d := range l.Digits {
+		rows += d
+	}
+	return rows
+}
+
+// Row returns the row index of digit d of relation rel.
+func (l *LiftLayout) Row(rel, d int) int {
+	row := l.Base + d
+	for _, n := range l.Digits[:rel] {
+		row += n
+	}
+	return row
+}
+
+// RowIndices lists every quotient digit row in order.
+func (l *LiftLayout) RowIndices() []int {
+	out := make([]int, l.Rows())
+	for i := range out {
+		out[i] = l.Base + i
+	}
+	return out
+}
+
+// weights returns q·radix^d mod p for every digit of relation rel.
+func (l *LiftLayout) weights(rel int, p uint64) []uint64 {
+	w := make([]uint64, l.Digits[rel])
+	cur := l.Q % p
+	for d := range w {
+		w[d] = cur
+		cur = lvcs.MulModReduced(cur, l.Radix%p, p)
+	}
+	return w
+}
+
+// quotient returns q·k mod p for relation rel from base-field row values.
+func (l *LiftLayout) quotient(rel int, getRow func(int) uint64, p uint64) uint64 {
+	var sum uint64
+	for d, w := range l.weights(rel, p) {
+		sum = lvcs.MulAddMod64(sum, w, getRow(l.Row(rel, d)), p)
+	}
+	return sum
+}
+
+// quotientK returns q·k for relation rel from K-point row evaluations.
+func (l *LiftLayout) quotientK(K *kf.Field, rel int, getRow func(int) kf.Elem, p uint64) kf.Elem {
+	sum := K.Zero()
+	for d, w := range l.weights(rel, p) {
+		sum = K.Add(sum, K.Mul(K.EmbedF(w), getRow(l.Row(rel, d))))
+	}
+	return sum
+}
+
+// quotientNTT returns q·k for relation rel from row polynomials in NTT form.
+func (l *LiftLayout) quotientNTT(ringP *ring.Ring, rel int, rowsNTT []*ring.Poly) *ring.Poly {
+	sum := ringP.NewPoly()
+	tmp := ringP.NewPoly()
+	for d, w := range l.weights(rel, ringP.Modulus[0]) {
+		scalePolyNTT(ringP, rowsNTT[l.Row(rel, d)], w, tmp)
+		ringP.Add(sum, tmp, sum)
+	}
+	return sum
+}
+
+// liftLayoutFromPublics returns the layout of a lifted statement over ringP,
+// or nil when pub carries no lift marker.
+func liftLayoutFromPublics(pub PublicInputs, ringP *ring.Ring) (*LiftLayout, error) {
+	raw, ok := pub.Extras[liftExtraQ]
+	if !ok {
+		return nil, nil
+	}
+	b, ok := raw.([]byte)
+	if !ok || len(b) != 8 {
+		return nil, fmt.Errorf("lift: malformed %s extra", liftExtraQ)
+	}
+	if len(pub.Ac) == 0 || len(pub.Ac[0]) == 0 {
+		return nil, fmt.Errorf("lift: missing Ac")
+	}
+	return NewLiftLayout(binary.LittleEndian.Uint64(b), ringP.Modulus[0], pub.BoundB, pub.Blocks, len(pub.Ac))
+}
+
+// liftRings checks that ringP can host a lifted statement of ringQ.
+func liftRings(ringQ, ringP *ring.Ring) error {
+	if ringQ == nil || ringP == nil {
+		return fmt.Errorf("lift: nil ring")
+	}
+	if len(ringQ.Modulus) != 1 || len(ringP.Modulus) != 1 {
+		return fmt.Errorf("lift: rings must have a single modulus")
+	}
+	if ringQ.N != ringP.N {
+		return fmt.Errorf("lift: ring dimension mismatch %d vs %d", ringQ.N, ringP.N)
+	}
+	return nil
+}
+
+// LiftCredentialPublics rewrites the public inputs of a pre-sign credential
+// statement over ringQ as the lifted statement over ringP (same dimension,
+// larger prime). NTT values of Ac, Com and the hash key other than B0 and B3
+// are copied as integers in [0,q), RI0/RI1 are centered, and the hash is
+// rewritten with its public part folded into B0: the lifted hash relation is
+//
+//	−(R1_0 + Σ D_k·R1_k)·T − (B0' + Σ G_i·(M1_i+M2_i) + Σ H_j·R0_j) − q·k = 0,
+//	B0' = −((B3·T − B0) mod q),
+//
+// with B3' = 0 and T carried as its NTT values mod q (see vsishash.VecLayout
+// for the key). Extras record q and the labels digest of the original
+// statement so the lifted transcript is bound to it.
+func LiftCredentialPublics(ringQ, ringP *ring.Ring, pub PublicInputs) (PublicInputs, error) {
+	if err := liftRings(ringQ, ringP); err != nil {
+		return PublicInputs{}, err
+	}
+	if _, ok := pub.Extras[liftExtraQ]; ok {
+		return PublicInputs{}, fmt.Errorf("lift: statement is already lifted")
+	}
+	if len(pub.A) > 0 {
+		return PublicInputs{}, fmt.Errorf("lift: only the pre-sign statement can be lifted")
+	}
+	if len(pub.Ac) == 0 || len(pub.Com) != len(pub.Ac) {
+		return PublicInputs{}, fmt.Errorf("lift: Ac/Com shape mismatch")
+	}
+	if len(pub.RI0) == 0 || len(pub.RI1) == 0 || len(pub.B) < 4 || len(pub.T) == 0 {
+		return PublicInputs{}, fmt.Errorf("lift: missing RI0/RI1/B/T")
+	}
+	if err := pub.Blocks.checkPublics(pub); err != nil {
+		return PublicInputs{}, fmt.Errorf("lift: %w", err)
+	}
+	q, p := ringQ.Modulus[0], ringP.Modulus[0]
+	if _, err := NewLiftLayout(q, p, pub.BoundB, pub.Blocks, len(pub.Ac)); err != nil {
+		return PublicInputs{}, err
+	}
+	N := ringQ.N
+
+	// copyNTT embeds the NTT values of a ringQ poly as a ringP NTT poly.
+	copyNTT := func(src *ring.Poly, centered bool) *ring.Poly {
+		dst := ringP.NewPoly()
+		for i := 0; i < N; i++ {
+			v := src.Coeffs[0][i] % q
+			if centered && v > q/2 {
+				v = p - (q - v)
+			}
+			dst.Coeffs[0][i] = v
+		}
+		return dst
+	}
+	copyAll := func(src []*ring.Poly, centered bool) []*ring.Poly {
+		dst := make([]*ring.Poly, len(src))
+		for i := range src {
+			dst[i] = copyNTT(src[i], centered)
+		}
+		return dst
+	}
+	out := pub
+	out.Ac = make([][]*ring.Poly, len(pub.Ac))
+	for i := range pub.Ac {
+		out.Ac[i] = copyAll(pub.Ac[i], false)
+	}
+	out.Com = copyAll(pub.Com, false)
+	out.RI0 = copyAll(pub.RI0, true)
+	out.RI1 = copyAll(pub.RI1, true)
+
+	tq := liftTNTT(ringQ, pub.T)
+	b0 := ringP.NewPoly()
+	for i := 0; i < N; i++ {
+		c := lvcs.MulModReduced(pub.B[3].Coeffs[0][i]%q, tq.Coeffs[0][i], q)
+		c = (c + q - pub.B[0].Coeffs[0][i]%q) % q
+		b0.Coeffs[0][i] = (p - c) % p
+	}
+	out.B = copyAll(pub.B, false)
+	out.B[0], out.B[3] = b0, ringP.NewPoly()
+
+	tp := copyNTT(tq, false)
+	ringP.InvNTT(tp, tp)
+	out.T = make([]int64, N)
+	for i := range out.T {
+		out.T[i] = int64(tp.Coeffs[0][i])
+	}
+
+	out.Extras = make(map[string]interface{}, len(pub.Extras)+2)
+	for k, v := range pub.Extras {
+		out.Extras[k] = v
+	}
+	qb := make([]byte, 8)
+	binary.LittleEndian.PutUint64(qb, q)
+	out.Extras[liftExtraQ] = qb
+	out.Extras[liftExtraStatement] = computeLabelsDigest(BuildPublicLabels(pub))
+	return out, nil
+}
+
+// liftTNTT returns the public T coefficients as a ringQ poly in NTT form.
+func liftTNTT(ringQ *ring.Ring, t []int64) *ring.Poly {
+	q := int64(ringQ.Modulus[0])
+	tp := ringQ.NewPoly()
+	for i := 0; i < ringQ.N && i < len(t); i++ {
+		v := t[i] % q
+		if v < 0 {
+			v += q
+		}
+		tp.Coeffs[0][i] = uint64(v)
+	}
+	ringQ.NTT(tp, tp)
+	return tp
+}
+
+// LiftCredentialWitness lifts a pre-sign witness over ringQ to ringP and
+// computes the quotient digit rows of every relation over the first ncols
+// evaluation points (Ω). pub is the original, unlifted statement.
+func LiftCredentialWitness(ringQ, ringP *ring.Ring, pub PublicInputs, wit WitnessInputs, ncols int) (WitnessInputs, error) {
+	if err := liftRings(ringQ, ringP); err != nil {
+		return WitnessInputs{}, err
+	}
+	if len(wit.U) > 0 || len(wit.T) > 0 {
+		return WitnessInputs{}, fmt.Errorf("lift: only the pre-sign witness can be lifted")
+	}
+	if ncols <= 0 || ncols > ringQ.N {
+		ncols = ringQ.N
+	}
+	if len(pub.Ac) == 0 || len(pub.Com) != len(pub.Ac) || len(pub.B) < 4 || len(pub.RI0) == 0 || len(pub.RI1) == 0 {
+		return WitnessInputs{}, fmt.Errorf("lift: incomplete pre-sign publics")
+	}
+	blocks := pub.Blocks
+	if err := blocks.checkPublics(pub); err != nil {
+		return WitnessInputs{}, fmt.Errorf("lift: %w", err)
+	}
+	if len(wit.R0) == 0 {
+		return WitnessInputs{}, fmt.Errorf("lift: missing witness R0/R1/K0/K1")
+	}
+	if err := blocks.checkWitness(wit); err != nil {
+		return WitnessInputs{}, fmt.Errorf("lift: witness: %w", err)
+	}
+	q, p := ringQ.Modulus[0], ringP.Modulus[0]
+	layout, err := NewLiftLayout(q, p, pub.BoundB, blocks, len(pub.Ac))
+	if err != nil {
+		return WitnessInputs{}, err
+	}
+	N := ringQ.N
+	center := func(v uint64) int64 {
+		v %= q
+		if v > q/2 {
+			return int64(v) - int64(q)
+		}
+		return int64(v)
+	}
+	// Evaluation-domain values of every witness row M1..K1, centered mod q.
+	src := credentialRowsOf(wit)
+	vals := make([][]int64, len(src))
+	tmp := ringQ.NewPoly()
+	for i, row := range src {
+		ring.Copy(row, tmp)
+		ringQ.NTT(tmp, tmp)
+		vals[i] = make([]int64, N)
+		for j := 0; j < N; j++ {
+			vals[i][j] = center(tmp.Coeffs[0][j])
+		}
+	}
+	n, idx, hl := blocks.norm(), blocks.rowIdx(), blocks.HashLayout()
+
+	quot := make([][]int64, layout.Relations())
+	for i := range quot {
+		quot[i] = make([]int64, N)
+	}
+	divide := func(rel, slot int, s int64) error {
+		if s%int64(q) != 0 {
+			return fmt.Errorf("lift: relation %d does not hold mod q at slot %d", rel, slot)
+		}
+		quot[rel][slot] = s / int64(q)
+		return nil
+	}
+	pubVal := func(p *ring.Poly, j int) int64 { return int64(p.Coeffs[0][j] % q) }
+	tq := liftTNTT(ringQ, pub.T)
+	delta := 2*pub.BoundB + 1
+	// NewLiftLayout bounds every relation by p/2 < 2^63, so the integer sums
+	// below cannot overflow.
+	for j := 0; j < ncols; j++ {
+		for i := range pub.Ac {
+			s := -pubVal(pub.Com[i], j)
+			for c := range pub.Ac[i] {
+				s += pubVal(pub.Ac[i][c], j) * vals[c][j]
+			}
+			if err := divide(i, j, s); err != nil {
+				return WitnessInputs{}, err
+			}
+		}
+		rel := layout.Commit
+		for i := 0; i < n.RU0; i++ {
+			s := vals[idx.RU0+i][j] + center(pub.RI0[i].Coeffs[0][j]) - vals[idx.R0+i][j] - delta*vals[idx.K0+i][j]
+			if err := divide(rel, j, s); err != nil {
+				return WitnessInputs{}, err
+			}
+			rel++
+		}
+		for i := 0; i < n.RU1; i++ {
+			s := vals[idx.RU1+i][j] + center(pub.RI1[i].Coeffs[0][j]) - vals[idx.R1+i][j] - delta*vals[idx.K1+i][j]
+			if err := divide(rel, j, s); err != nil {
+				return WitnessInputs{}, err
+			}
+			rel++
+		}
+		t := int64(tq.Coeffs[0][j])
+		c := lvcs.MulModReduced(pub.B[3].Coeffs[0][j]%q, tq.Coeffs[0][j], q)
+		c = (c + q - pub.B[0].Coeffs[0][j]%q) % q
+		sh := int64(c) - vals[idx.R1][j]*t
+		for k := 1; k < n.RU1; k++ {
+			sh -= pubVal(pub.B[hl.X1Key(k)], j) * t * vals[idx.R1+k][j]
+		}
+		for i := 0; i < hl.Msg; i++ {
+			var m int64
+			if i < n.M1 {
+				m += vals[idx.M1+i][j]
+			}
+			if i < n.M2 {
+				m += vals[idx.M2+i][j]
+			}
+			sh -= pubVal(pub.B[hl.MsgKey(i)], j) * m
+		}
+		for k := 0; k < n.RU0; k++ {
+			sh -= pubVal(pub.B[hl.X0Key(k)], j) * vals[idx.R0+k][j]
+		}
+		if err := divide(rel, j, sh); err != nil {
+			return WitnessInputs{}, err
+		}
+	}
+
+	// toRow places centered evaluation-domain values as a ringP coefficient poly.
+	toRow := func(v []int64) *ring.Poly {
+		out := ringP.NewPoly()
+		for j := 0; j < N; j++ {
+			x := v[j] % int64(p)
+			if x < 0 {
+				x += int64(p)
+			}
+			out.Coeffs[0][j] = uint64(x)
+		}
+		ringP.InvNTT(out, out)
+		return out
+	}
+	rows := make([]*ring.Poly, len(vals))
+	for i := range vals {
+		rows[i] = toRow(vals[i])
+	}
+	out := WitnessInputs{
+		M1: rows[idx.M1:idx.M2:idx.M2], M2: rows[idx.M2:idx.RU0:idx.RU0],
+		RU0: rows[idx.RU0:idx.RU1:idx.RU1], RU1: rows[idx.RU1:idx.R:idx.R],
+		R: rows[idx.R:idx.R0:idx.R0], R0: rows[idx.R0:idx.R1:idx.R1], R1: rows[idx.R1:idx.K0:idx.K0],
+		K0: rows[idx.K0:idx.K1:idx.K1], K1: rows[idx.K1:],
+		Extras: wit.Extras,
+	}
+	radix := int64(layout.Radix)
+	half := (radix - 1) / 2
+	for rel := range quot {
+		digits := make([][]int64, layout.Digits[rel])
+		for d := range digits {
+			digits[d] = make([]int64, N)
+		}
+		for j := 0; j < N; j++ {
+			k := quot[rel][j]
+			for d := range digits {
+				rem := ((k % radix) + radix) % radix
+				if rem > half {
+					rem -= radix
+				}
+				digits[d][j] = rem
+				k = (k - rem) / radix
+			}
+			if k != 0 {
+				return WitnessInputs{}, fmt.Errorf("lift: quotient of relation %d exceeds %d digits", rel, layout.Digits[rel])
+			}
+		}
+		for d := range digits {
+			out.Quot = append(out.Quot, toRow(digits[d]))
+		}
+	}
+	return out, nil
+}
+
+// liftedCredentialBuilder proves a pre-sign credential statement mod q with
+// the PIOP running over opts.Ring (a larger NTT prime) via LiftCredential*.
+type liftedCredentialBuilder struct {
+	ringQ *ring.Ring
+	inner credentialBuilder
+}
+
+// NewLiftedCredentialBuilder returns a builder whose statements are given over
+// ringQ (nil selects Parameters/Parameters.json) and proven over opts.Ring.
+func NewLiftedCredentialBuilder(ringQ *ring.Ring, opts SimOpts) (ContextStatementBuilder, error) {
+	if opts.Ring == nil {
+		return nil, fmt.Errorf("lift: SimOpts.Ring must select the proof field")
+	}
+	ringQ, err := paramsRing(ringQ)
+	if err != nil {
+		return nil, err
+	}
+	if err := liftRings(ringQ, opts.Ring); err != nil {
+		return nil, err
+	}
+	opts.applyDefaults()
+	return &liftedCredentialBuilder{ringQ: ringQ, inner: credentialBuilder{opts: opts}}, nil
+}
+
+func (b *liftedCredentialBuilder) Build(pub PublicInputs, wit WitnessInputs, cfg MaskConfig) (*Proof, error) {
+	return b.BuildContext(context.Background(), pub, wit, cfg)
+}
+
+func (b *liftedCredentialBuilder) BuildContext(ctx context.Context, pub PublicInputs, wit WitnessInputs, cfg MaskConfig) (*Proof, error) {
+	lpub, err := LiftCredentialPublics(b.ringQ, b.inner.opts.Ring, pub)
+	if err != nil {
+		return nil, err
+	}
+	lwit, err := LiftCredentialWitness(b.ringQ, b.inner.opts.Ring, pub, wit, b.inner.opts.NCols)
+	if err != nil {
+		return nil, err
+	}
+	return b.inner.BuildContext(ctx, lpub, lwit, cfg)
+}
+
+func (b *liftedCredentialBuilder) Verify(pub PublicInputs, proof *Proof) (bool, error) {
+	return b.VerifyContext(context.Background(), pub, proof)
+}
+
+func (b *liftedCredentialBuilder) VerifyContext(ctx context.Context, pub PublicInputs, proof *Proof) (bool, error) {
+	lpub, err := LiftCredentialPublics(b.ringQ, b.inner.opts.Ring, pub)
+	if err != nil {
+		return false, err
+	}
+	return b.inner.VerifyContext(ctx, lpub, proof)
+}
+
+var _ ContextStatementBuilder = (*liftedCredentialBuilder)(nil)
@@ -1,6 +1,7 @@
 package PIOP
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -39,6 +40,7 @@ type MaskingFSInput struct {
 	NCols            int    // number of columns in rows (for verifier)
 	DecsParams       decs.Params
 	LabelsDigest     []byte // hash of public labels included in FS binding
+	ParamsID         []byte // configuration digest absorbed in FS round 0 (ComputeParamsID)
 	// Small-field (theta>1) parameters
 	SmallFieldChi     []uint64
 	SmallFieldOmegaS1 []uint64
@@ -50,10 +52,17 @@ type MaskingFSInput struct {
 // RunMaskingFS is a placeholder for a reusable masking/Merkle/FS driver.
 // It mirrors the masking/FS portion of buildSimWith but takes explicit inputs.
 func RunMaskingFS(in MaskingFSInput) (*Proof, error) {
+	return RunMaskingFSContext(context.Background(), in)
+}
+
+// RunMaskingFSContext is RunMaskingFS with cancellation of the grinding loops
+// and per-row work; it returns a *CanceledError once ctx is done.
+func RunMaskingFSContext(ctx context.Context, in MaskingFSInput) (*Proof, error) {
 	defer prof.Track(time.Now(), "RunMaskingFS")
 	o := in.Opts
 	o.applyDefaults()
 	args := maskFSArgs{
+		ctx:              ctx,
 		ringQ:            in.RingQ,
 		omega:            in.Omega,
 		q:                in.RingQ.Modulus[0],
@@ -82,6 +91,7 @@ func RunMaskingFS(in MaskingFSInput) (*Proof, error) {
 		decsParams:       in.DecsParams,
 		ncolsOverride:    in.NCols,
 		labelsDigest:     append([]byte(nil), in.LabelsDigest...),
+		paramsID:         append([]byte(nil), in.ParamsID...),
 	}
 	if o.Theta > 1 {
 		args.smallFieldChi = append([]uint64(nil), in.SmallFieldChi...)
@@ -1,9 +1,10 @@
 package PIOP
 
 import (
-	cryptoRand "crypto/rand"
+	"context"
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	decs "vSIS-Signature/DECS"
 	lvcs "vSIS-Signature/LVCS"
@@ -58,6 +59,7 @@ func evalRowsAt(r *ring.Ring, polys []*ring.Poly, points []uint64) [][]uint64 {
 // maskFSArgs carries all inputs needed to run the masking/Merkle/FS loop.
 // It mirrors the locals present in buildSimWith.
 type maskFSArgs struct {
+	ctx      context.Context
 	ringQ    *ring.Ring
 	omega    []uint64
 	q        uint64
@@ -123,6 +125,7 @@ type maskFSArgs struct {
 	decsParams      decs.Params
 
 	labelsDigest []byte
+	paramsID     []byte
 
 	// Optional ncols override (head length) for theta>1
 	ncolsOverride int
@@ -159,7 +162,6 @@ type maskFSOutput struct {
 	tailIndices    []int
 
 	// Openings/placeholders as needed
-	openMask        *lvcs.Opening
 	openTail        *lvcs.Opening
 	combinedOpen    *decs.DECSOpening
 	rowLayout       RowLayout
@@ -169,6 +171,7 @@ type maskFSOutput struct {
 	Root            [16]byte
 	evalReqs        []lvcs.EvalRequest
 	Tail            []int
+	grindAttempts   [4]uint64
 }
 
 // runMaskFS executes the masking/Merkle/FS round 1 scaffold and prepares the proof header.
@@ -181,6 +184,13 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 	if args.PK == nil {
 		return out, fmt.Errorf("nil prover key")
 	}
+	ctx := args.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := checkCtx(ctx, "runMaskFS"); err != nil {
+		return out, err
+	}
 	o := args.opts
 	o.applyDefaults()
 	ringQ := args.ringQ
@@ -189,12 +199,15 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 		q = ringQ.Modulus[0]
 	}
 	// FS initialization
-	baseXOF := NewShake256XOF(64)
+	var baseXOF XOF = NewShake256XOF(64)
+	if o.fsOracle != nil {
+		baseXOF = o.fsOracle
+	}
 	salt := make([]byte, 32)
-	if _, err := cryptoRand.Read(salt); err != nil {
+	if _, err := io.ReadFull(o.coinSource(), salt); err != nil {
 		return out, fmt.Errorf("rand salt: %w", err)
 	}
-	fs := NewFS(baseXOF, salt, FSParams{Lambda: o.Lambda, Kappa: o.Kappa})
+	fs := NewFS(baseXOF, salt, FSParams{Lambda: o.Lambda, Kappa: o.Kappa, Workers: o.GrindWorkers})
 	proof := &Proof{
 		Root:            args.root,
 		Salt:            append([]byte(nil), salt...),
@@ -208,6 +221,7 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 		NColsUsed:       args.ncols,
 		OmegaTrunc:      append([]uint64(nil), args.omega...),
 		LabelsDigest:    append([]byte(nil), args.labelsDigest...),
+		ParamsID:        append([]byte(nil), args.paramsID...),
 	}
 	if o.Theta > 1 {
 		proof.Chi = append([]uint64(nil), args.smallFieldChi...)
@@ -221,7 +235,13 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 	if len(args.labelsDigest) > 0 {
 		material0 = append(material0, args.labelsDigest)
 	}
-	round1 := fsRound(fs, proof, 0, "Gamma", material0...)
+	if len(args.paramsID) > 0 {
+		material0 = append(material0, args.paramsID)
+	}
+	round1, err := fsRound(ctx, fs, proof, 0, "Gamma", material0...)
+	if err != nil {
+		return out, err
+	}
 	gammaRNG := round1.RNG
 	Gamma := sampleFSMatrix(o.Eta, len(args.rowInputs), q, gammaRNG)
 	gammaBytes := bytesFromUint64Matrix(Gamma)
@@ -241,7 +261,10 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 	if proof.Theta > 1 {
 		transcript2 = append(transcript2, encodeUint64Slice(proof.Chi), encodeUint64Slice(proof.Zeta))
 	}
-	round2 := fsRound(fs, proof, 1, "GammaPrime", transcript2...)
+	round2, err := fsRound(ctx, fs, proof, 1, "GammaPrime", transcript2...)
+	if err != nil {
+		return out, err
+	}
 	seed2 := round2.Seed
 	gammaPrimeRNG := round2.RNG
 	gammaAggRNG := newFSRNG("GammaPrimeAgg", seed2, []byte{1})
@@ -277,7 +300,8 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 	if proof.Theta > 1 {
 		// Small-field branch: sample independent masks with ΣΩ M_i = 0.
 		// Do not compensate for ΣΩ Fpar/Fagg; ΣΩ will detect violations.
-		MK := SampleIndependentMaskPolynomialsK(ringQ, args.smallFieldK, args.rho, args.maskDegreeTarget, args.omega)
+		maskParams := maskSamplerParams{omega: args.omega, maxDeg: args.maskDegreeTarget, rng: o.coins}
+		MK := sampleMaskPolynomialsK(ringQ, args.smallFieldK, maskParams, args.rho, nil)
 		M := make([]*ring.Poly, args.rho)
 		for i := range MK {
 			poly := ringQ.NewPoly()
@@ -291,13 +315,16 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 		}
 		out.M = M
 		out.MK = MK
-		proof.MKData = snapshotKPolys(MK)
 		// Q and QK
 		qLayout := BuildQLayout{
 			WitnessPolys: args.w1[:args.origW1Len],
 			MaskPolys:    M,
 		}
 		out.Q = BuildQ(ringQ, qLayout, args.FparInt, args.FparNorm, args.FaggInt, args.FaggNorm, GammaPrime, GammaAgg)
+		if o.simulate {
+			balanceMasks(ringQ, args.omega, M, MK, out.Q)
+		}
+		proof.MKData = snapshotKPolys(MK)
 		proof.QNTT = polysToNTTMatrix(out.Q)
 		out.QK = BuildQK(ringQ, args.smallFieldK, MK, args.FparAll, args.FaggAll, GammaPrimeK, GammaAggK)
 		proof.QKData = snapshotKPolys(out.QK)
@@ -307,6 +334,9 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 			if checkMismatch {
 				break
 			}
+			if err := checkCtx(ctx, "runMaskFS"); err != nil {
+				return out, err
+			}
 			kcoeff := kpolyToCoeffPolys(ringQ, out.QK[i])
 			for idx := 0; idx < len(kcoeff[0].Coeffs[0]); idx++ {
 				lhsLimbs := make([]uint64, len(kcoeff))
@@ -384,12 +414,15 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 	if len(args.labelsDigest) > 0 {
 		round3Material = append(round3Material, args.labelsDigest)
 	}
-	round3 := fsRound(fs, proof, 2, func() string {
+	round3, err := fsRound(ctx, fs, proof, 2, func() string {
 		if proof.Theta > 1 {
 			return "EvalKPoint"
 		}
 		return "EvalPoints"
 	}(), round3Material...)
+	if err != nil {
+		return out, err
+	}
 	seed3 := round3.Seed
 	var coeffMatrix [][]uint64
 	var kPointLimbs [][]uint64
@@ -435,7 +468,7 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 			}
 			var coeffBlock [][]uint64
 			if args.opts.Credential {
-				coeffBlock = buildKPointCoeffMatrixRows(ringQ, args.smallFieldK, len(args.rows), candidate)
+				coeffBlock = buildKPointCoeffMatrixRows(ringQ, args.smallFieldK, len(args.rows), args.maskRowOffset, len(smallFieldEvals), candidate)
 			} else {
 				coeffBlock = buildKPointCoeffMatrix(ringQ, args.smallFieldK, args.omega, args.rows, candidate, args.smallFieldMuInv, args.maskRowOffset, args.maskRowCount)
 			}
@@ -509,28 +542,27 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 		bytesFromUint64Matrix(vTargets),
 	}
 	proof.TailTranscript = flattenBytes(transcript4)
-	round4 := fsRound(fs, proof, 3, "TailPoints", transcript4...)
+	round4, err := fsRound(ctx, fs, proof, 3, "TailPoints", transcript4...)
+	if err != nil {
+		return out, err
+	}
 	tailRNG := round4.RNG
 	E := sampleDistinctIndices(tailStart, tailLen, args.ell, tailRNG)
 	proof.Tail = append([]int(nil), E...)
 
-	maskIdx := make([]int, args.ell)
-	for i := 0; i < args.ell; i++ {
-		maskIdx[i] = args.ncols + i
-	}
-	openMask := lvcs.EvalFinish(args.PK, maskIdx)
+	// Only E is opened: the masked positions ncols..ncols+ℓ−1 carry the row
+	// tails, which keep the openings at E independent of the heads.
 	openTail := lvcs.EvalFinish(args.PK, E)
-	combinedOpen := combineOpenings(openMask.DECSOpen, openTail.DECSOpen)
+	combinedOpen := combineOpenings(nil, openTail.DECSOpen)
 	proof.RowOpening = cloneDECSOpening(combinedOpen)
 	proof.RowOpening.R = len(args.rowInputs)
 	proof.RowOpening.Eta = args.decsParams.Eta
 	decs.PackOpening(proof.RowOpening)
 
 	maskEval := evalPolySetAtIndices(ringQ, out.M, E)
-	maskOpen := makeMaskTailOpening(E, maskEval)
+	maskOpen := makeMaskTailOpening(E, maskEval, ringQ.Modulus[0])
 	proof.MOpening = cloneDECSOpening(maskOpen)
 
-	out.openMask = openMask
 	out.openTail = openTail
 	out.combinedOpen = combinedOpen
 	out.tailIndices = append([]int(nil), E...)
@@ -538,6 +570,34 @@ func runMaskFS(args maskFSArgs) (maskFSOutput, error) {
 	out.maskRowOffset = args.maskRowOffset
 	out.maskRowCount = args.maskRowCount
 	out.maskPolyCount = len(out.M)
+	out.grindAttempts = fs.GrindAttempts()
 
 	return out, nil
 }
+
+// balanceMasks adds to each mask M_i, to the first limb of MK_i and to Q_i
+// the constant that makes ΣΩ Q_i vanish. A witness makes every F_j vanish on
+// Ω, so the honest prover never needs it; the simulator, which commits
+// random rows, answers the ΣΩ check with it instead.
+func balanceMasks(ringQ *ring.Ring, omega []uint64, M []*ring.Poly, MK []*KPoly, Q []*ring.Poly) {
+	q := ringQ.Modulus[0]
+	nInv := modInv(uint64(len(omega))%q, q)
+	coeff := ringQ.NewPoly()
+	for i := range Q {
+		ringQ.InvNTT(Q[i], coeff)
+		sum := uint64(0)
+		for _, w := range omega {
+			sum = modAdd(sum, EvalPoly(coeff.Coeffs[0], w%q, q), q)
+		}
+		c := modMul(modSub(0, sum, q), nInv, q)
+		// A constant is the same in every NTT slot.
+		for _, p := range []*ring.Poly{M[i], Q[i]} {
+			for j := range p.Coeffs[0] {
+				p.Coeffs[0][j] = modAdd(p.Coeffs[0][j], c, q)
+			}
+		}
+		if len(MK[i].Limbs) > 0 && len(MK[i].Limbs[0]) > 0 {
+			MK[i].Limbs[0][0] = modAdd(MK[i].Limbs[0][0], c, q)
+		}
+	}
+}
@@ -201,7 +201,7 @@ func TestEq4RejectsMessageRangeTamper(t *testing.T) {
 	tamperedBlock := buildFparRangeMembership(ctx.ringQ, tamperSource, spec)
 	tamperedFpar := append([]*ring.Poly(nil), ctx.Fpar...)
 	copy(tamperedFpar[base:base+len(tamperedBlock)], tamperedBlock)
-	if checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, ctx.maskOpen, tamperedFpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeScalars, ctx.omega, ctx.Eprime) {
+	if checkEq4OnOpening(ctx.ringQ, ctx.Q, ctx.M, nil, tamperedFpar, ctx.Fagg, ctx.GammaPrimePoly, ctx.GammaPrimeScalars, ctx.omega, ctx.Eprime) {
 		t.Fatalf("Eq.(4) verifier accepted tampered message membership block")
 	}
 }
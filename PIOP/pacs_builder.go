@@ -1,6 +1,9 @@
 package PIOP
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // pacsBuilder is a thin wrapper around the existing PACS prover/verifier,
 // exposed through the StatementBuilder interface. It ignores explicit publics
@@ -11,28 +14,44 @@ type pacsBuilder struct {
 }
 
 // NewPACSBuilder returns a StatementBuilder backed by the existing PACS flow.
-func NewPACSBuilder(opts SimOpts) StatementBuilder {
+func NewPACSBuilder(opts SimOpts) ContextStatementBuilder {
 	opts.applyDefaults()
 	return &pacsBuilder{opts: opts}
 }
 
 // Build runs the existing prover and returns the transcript proof.
-func (b *pacsBuilder) Build(_ PublicInputs, _ WitnessInputs, _ MaskConfig) (*Proof, error) {
-	ctx, _, _, _ := buildSimWith(nil, b.opts)
-	if ctx == nil || ctx.proof == nil {
+func (b *pacsBuilder) Build(pub PublicInputs, wit WitnessInputs, cfg MaskConfig) (*Proof, error) {
+	return b.BuildContext(context.Background(), pub, wit, cfg)
+}
+
+// BuildContext is Build with cancellation.
+func (b *pacsBuilder) BuildContext(ctx context.Context, _ PublicInputs, _ WitnessInputs, _ MaskConfig) (*Proof, error) {
+	sim, _, _, _ := buildSimWithContext(ctx, nil, b.opts)
+	if sim == nil || sim.proof == nil {
+		if err := checkCtx(ctx, "pacs builder"); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("pacs builder: nil proof")
 	}
-	return ctx.proof, nil
+	return sim.proof, nil
 }
 
 // Verify reuses VerifyNIZK on the provided proof; publics are already embedded.
-func (b *pacsBuilder) Verify(_ PublicInputs, proof *Proof) (bool, error) {
+func (b *pacsBuilder) Verify(pub PublicInputs, proof *Proof) (bool, error) {
+	return b.VerifyContext(context.Background(), pub, proof)
+}
+
+// VerifyContext is Verify with cancellation.
+func (b *pacsBuilder) VerifyContext(ctx context.Context, _ PublicInputs, proof *Proof) (bool, error) {
 	if proof == nil {
 		return false, fmt.Errorf("nil proof")
 	}
-	okLin, okEq4, okSum, err := VerifyNIZK(proof)
+	if err := checkPACSParamsID(proof, b.opts); err != nil {
+		return false, err
+	}
+	okLin, okEq4, okSum, err := VerifyNIZKContext(ctx, proof)
 	return okLin && okEq4 && okSum, err
 }
 
 // Compile-time guard.
-var _ StatementBuilder = (*pacsBuilder)(nil)
+var _ ContextStatementBuilder = (*pacsBuilder)(nil)
@@ -0,0 +1,379 @@
+package PIOP
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	ntrurio "vSIS-Signature/ntru/io"
+)
+
+// StatementShape is what SelectParams needs to know about a statement: how
+// many rows are committed and how many constraints of which degree are
+// proven over them. Q and RingN default to Parameters/Parameters.json.
+type StatementShape struct {
+	Rows           int    // committed witness rows (mask rows excluded)
+	Constraints    int    // parallel constraints |F_par|
+	AggConstraints int    // aggregated constraints |F_agg|
+	Degree         int    // max degree d of a parallel constraint in the rows
+	AggDegree      int    // max degree d′ of an aggregated constraint
+	Q              uint64 // base field modulus
+	RingN          int    // ring dimension; also the default Merkle leaf count
+}
+
+// ParamObjective orders the Pareto set returned by SelectParams.
+type ParamObjective int
+
+const (
+	// ObjectiveSize prefers the smallest estimated proof.
+	ObjectiveSize ParamObjective = iota
+	// ObjectiveProverTime prefers the cheapest estimated prover.
+	ObjectiveProverTime
+	// ObjectiveBalanced minimises the product of normalised size and prover cost.
+	ObjectiveBalanced
+)
+
+// ParseParamObjective maps "size", "time" and "balanced" to an objective.
+func ParseParamObjective(s string) (ParamObjective, error) {
+	switch s {
+	case "size":
+		return ObjectiveSize, nil
+	case "time":
+		return ObjectiveProverTime, nil
+	case "balanced":
+		return ObjectiveBalanced, nil
+	}
+	return 0, fmt.Errorf("unknown objective %q (want size|time|balanced)", s)
+}
+
+// ParamSpace bounds the search. Ell, EllPrime, Rho and Eta are scanned in
+// ascending order up to their maximum; NCols, Theta and Kappa are explicit
+// candidate lists.
+type ParamSpace struct {
+	NCols       []int
+	Theta       []int
+	Kappa       []int // grinding bits tried for every FS round
+	MaxEll      int
+	MaxEllPrime int
+	MaxRho      int
+	MaxEta      int
+	Lambda      int // FS hash security λ (grinding slack is λ−κ_i)
+}
+
+// DefaultParamSpace is the space searched by SelectParams.
+func DefaultParamSpace() ParamSpace {
+	return ParamSpace{
+		NCols:       []int{4, 8, 12, 16, 24, 32},
+		Theta:       []int{1, 2, 3, 4},
+		Kappa:       []int{0, 4, 8, 12, 16},
+		MaxEll:      64,
+		MaxEllPrime: 32,
+		MaxRho:      16,
+		MaxEta:      32,
+		Lambda:      256,
+	}
+}
+
+// ParamCandidate is one point of the Pareto set.
+type ParamCandidate struct {
+	Opts SimOpts
+	// Bits is the union bound over ε₁..ε₄ (each lowered by its round's κ_i)
+	// and the four grinding slack terms.
+	Bits      float64
+	Budget    SoundnessBudget
+	SizeBytes int
+	// ProverCost is a relative work estimate in field-operation units; it is
+	// only meaningful for comparing candidates of the same shape.
+	ProverCost float64
+}
+
+// SelectParams searches DefaultParamSpace for SimOpts reaching targetBits on
+// the given statement and returns the Pareto set over (size, prover cost,
+// soundness), ordered by objective.
+func SelectParams(shape StatementShape, targetBits int, objective ParamObjective) ([]ParamCandidate, error) {
+	return SelectParamsIn(shape, targetBits, objective, DefaultParamSpace())
+}
+
+// SelectParamsIn is SelectParams over an explicit search space.
+//
+// Every ε_i is monotone in its own knob (ε₁ in η, ε₂ in ρ, ε₃ in ℓ′, ε₄ in
+// ℓ) while size and prover cost grow with all of them, so for a fixed
+// (θ, NCols, κ) the smallest value meeting the per-round target dominates
+// every larger one. The search therefore only branches over θ, NCols and the
+// per-round κ_i and takes the minimal feasible value of each knob.
+func SelectParamsIn(shape StatementShape, targetBits int, objective ParamObjective, space ParamSpace) ([]ParamCandidate, error) {
+	if targetBits <= 0 {
+		return nil, fmt.Errorf("target bits must be > 0")
+	}
+	if shape.Rows <= 0 {
+		return nil, fmt.Errorf("statement shape needs Rows > 0")
+	}
+	shape, err := shape.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	if space.Lambda <= 0 {
+		space.Lambda = DefaultParamSpace().Lambda
+	}
+	space.Kappa = append([]int(nil), space.Kappa...)
+	if len(space.Kappa) == 0 {
+		space.Kappa = []int{0}
+	}
+	sort.Ints(space.Kappa)
+	// Eight terms enter the union bound, so each needs 3 bits of margin.
+	need := float64(targetBits) + 3
+	var all []ParamCandidate
+	for _, theta := range space.Theta {
+		for _, ncols := range space.NCols {
+			if theta <= 0 || ncols <= 0 || ncols >= shape.RingN {
+				continue
+			}
+			all = append(all, searchRounds(shape, space, theta, ncols, need, float64(targetBits))...)
+		}
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no parameters in the search space reach %d bits", targetBits)
+	}
+	front := paretoFront(all)
+	sortCandidates(front, objective)
+	return front, nil
+}
+
+// searchRounds picks, for every per-round κ_i, the smallest ρ, ℓ, η, ℓ′
+// meeting need and combines the choices into candidates.
+func searchRounds(shape StatementShape, space ParamSpace, theta, ncols int, need, target float64) []ParamCandidate {
+	q := shape.Q
+	nLeaves := shape.RingN
+	qBits := math.Log2(float64(q))
+	fieldSize := math.Pow(float64(q), float64(theta))
+	type choice struct{ kappa, value int }
+	var rounds [4][]choice
+	// keep records a κ only when it lowers the knob below what less grinding
+	// already reached; extra grinding that buys nothing is never useful.
+	keep := func(cs []choice, c choice) []choice {
+		if n := len(cs); n > 0 && cs[n-1].value <= c.value {
+			return cs
+		}
+		return append(cs, c)
+	}
+	for _, kappa := range space.Kappa {
+		if kappa < 0 || float64(space.Lambda-kappa) < need {
+			continue
+		}
+		k := float64(kappa)
+		// ε₂: ρ·θ·log q bits.
+		for rho := 1; rho <= space.MaxRho; rho++ {
+			if float64(rho*theta)*qBits+k >= need {
+				rounds[1] = keep(rounds[1], choice{kappa, rho})
+				break
+			}
+		}
+		// ε₄: log C(N, ℓ) − log C(s+ℓ−1, ℓ).
+		for ell := 1; ell <= space.MaxEll && ncols+ell-1 < nLeaves; ell++ {
+			if logComb2(float64(nLeaves), ell)-logComb2(float64(ncols+ell-1), ell)+k >= need {
+				rounds[3] = keep(rounds[3], choice{kappa, ell})
+				break
+			}
+		}
+		// ε₃: log C(|F|−s, ℓ′) − log C(d_Q, ℓ′).
+		for ellPrime := 1; ellPrime <= space.MaxEllPrime; ellPrime++ {
+			dQ := computeDQFromConstraintDegrees(shape.Degree, shape.AggDegree, ncols, ellPrime)
+			if dQ < ellPrime {
+				continue
+			}
+			if logComb2(fieldSize-float64(ncols), ellPrime)-logComb2(float64(dQ), ellPrime)+k >= need {
+				rounds[2] = keep(rounds[2], choice{kappa, ellPrime})
+				break
+			}
+		}
+	}
+	var out []ParamCandidate
+	for _, c4 := range rounds[3] {
+		ell := c4.value
+		// ε₁ depends on ℓ through d_DECS = s+ℓ−1, so it is chosen per ℓ.
+		var etas []choice
+		for _, kappa := range space.Kappa {
+			if kappa < 0 || float64(space.Lambda-kappa) < need {
+				continue
+			}
+			for eta := 1; eta <= space.MaxEta; eta++ {
+				if float64(eta)*qBits-logComb2(float64(nLeaves), ncols+ell+1)+float64(kappa) >= need {
+					etas = keep(etas, choice{kappa, eta})
+					break
+				}
+			}
+		}
+		for _, c1 := range etas {
+			for _, c2 := range rounds[1] {
+				for _, c3 := range rounds[2] {
+					opts := SimOpts{
+						NCols:    ncols,
+						Ell:      ell,
+						EllPrime: c3.value,
+						Rho:      c2.value,
+						Eta:      c1.value,
+						Theta:    theta,
+						Kappa:    [4]int{c1.kappa, c2.kappa, c3.kappa, c4.kappa},
+						Lambda:   space.Lambda,
+					}
+					cand := evaluateCandidate(shape, opts)
+					if cand.Bits >= target {
+						out = append(out, cand)
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// evaluateCandidate scores opts with computeSoundnessBudget and the analytic
+// size (EstimateProofSize) and cost models.
+func evaluateCandidate(shape StatementShape, opts SimOpts) ParamCandidate {
+	q := shape.Q
+	fieldSize := math.Pow(float64(q), float64(opts.Theta))
+	dQ := computeDQFromConstraintDegrees(shape.Degree, shape.AggDegree, opts.NCols, opts.EllPrime)
+	sb := computeSoundnessBudget(opts, q, fieldSize, dQ, opts.NCols, opts.Ell, opts.EllPrime, opts.Eta, shape.RingN, shape.Rows)
+	return ParamCandidate{
+		Opts:       opts,
+		Bits:       grindAdjustedBits(sb, opts.Kappa),
+		Budget:     sb,
+		SizeBytes:  candidateProofBytes(shape, opts),
+		ProverCost: estimateProverCost(shape, opts, dQ),
+	}
+}
+
+// grindAdjustedBits is the union bound of sb with round i's ε_i lowered by
+// 2^{-κ_i}: grinding κ_i bits in FS round i multiplies the cost of every
+// attempt to re-sample that round's challenge by 2^{κ_i}.
+func grindAdjustedBits(sb SoundnessBudget, kappa [4]int) float64 {
+	total := 0.0
+	for i := 0; i < 4; i++ {
+		total += math.Pow(2, -(sb.Bits[i] + float64(kappa[i])))
+		total += sb.Grinding[i]
+	}
+	if total <= 0 {
+		return math.Inf(1)
+	}
+	return -math.Log2(total)
+}
+
+// candidateProofBytes is EstimateProofSize for the RunOnce layout of opts and
+// falls back to estimateProofBytes for shapes that model rejects.
+func candidateProofBytes(shape StatementShape, opts SimOpts) int {
+	if rep, err := estimateShapeSize(opts, shape); err == nil {
+		return rep.Total
+	}
+	return estimateProofBytes(shape, opts)
+}
+
+// estimateProofBytes is a coarse analytic model of MeasureProofSize: the
+// DECS row and mask openings at the ℓ tail indices with their Merkle
+// siblings, the ℓ′ evaluation targets, and the fixed FS material.
+func estimateProofBytes(shape StatementShape, opts SimOpts) int {
+	const packedBits = 20 // width of the packed opening streams
+	rows := shape.Rows + opts.Rho
+	depth := int(math.Ceil(math.Log2(float64(shape.RingN))))
+	siblings := opts.Ell * (depth - int(math.Floor(math.Log2(float64(opts.Ell)))))
+	if siblings < 0 {
+		siblings = 0
+	}
+	bits := opts.Ell * (rows + opts.Eta) * packedBits // RowOpening values
+	bits += opts.EllPrime * rows * packedBits         // Pvals at E′ (or K-points)
+	bits += opts.Rho * opts.Theta * opts.EllPrime * packedBits
+	bytes := (bits + 7) / 8
+	bytes += siblings * 16                 // Merkle siblings
+	bytes += 32 + 16 + 4*8 + 4*64          // salt, root, counters, digests
+	bytes += 2 * opts.Theta * 8            // χ, ζ
+	bytes += opts.Ell * (opts.Eta + 1) * 3 // mask opening and tail indices
+	return bytes
+}
+
+// estimateProverCost counts the dominant prover work: interpolating and
+// committing every row over the N leaves, building the ρ batched Q
+// polynomials of degree d_Q over all constraints, and 2^{κ_i} grinding
+// attempts per round.
+func estimateProverCost(shape StatementShape, opts SimOpts, dQ int) float64 {
+	n := float64(shape.RingN)
+	rows := float64(shape.Rows + opts.Rho + opts.Eta)
+	cost := rows * n * math.Log2(n)
+	constraints := float64(shape.Constraints + shape.AggConstraints)
+	cost += float64(opts.Rho*opts.Theta*opts.Theta) * float64(dQ+1) * constraints
+	for _, k := range opts.Kappa {
+		cost += math.Pow(2, float64(k))
+	}
+	return cost
+}
+
+func (shape StatementShape) withDefaults() (StatementShape, error) {
+	if shape.Q == 0 || shape.RingN == 0 {
+		par, err := ntrurio.LoadParams(resolve("Parameters/Parameters.json"), true /* allowMismatch */)
+		if err != nil {
+			return shape, fmt.Errorf("load params: %w", err)
+		}
+		if shape.Q == 0 {
+			shape.Q = par.Q
+		}
+		if shape.RingN == 0 {
+			shape.RingN = par.N
+		}
+	}
+	if shape.Degree <= 0 {
+		shape.Degree = 1
+	}
+	if shape.AggDegree <= 0 {
+		shape.AggDegree = 1
+	}
+	return shape, nil
+}
+
+// dominates reports whether a is no worse than b in size, cost and bits and
+// strictly better in at least one.
+func (a ParamCandidate) dominates(b ParamCandidate) bool {
+	if a.SizeBytes > b.SizeBytes || a.ProverCost > b.ProverCost || a.Bits < b.Bits {
+		return false
+	}
+	return a.SizeBytes < b.SizeBytes || a.ProverCost < b.ProverCost || a.Bits > b.Bits
+}
+
+func paretoFront(all []ParamCandidate) []ParamCandidate {
+	var front []ParamCandidate
+	for i, c := range all {
+		dominated := false
+		for j, o := range all {
+			if i != j && o.dominates(c) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, c)
+		}
+	}
+	return front
+}
+
+func sortCandidates(cs []ParamCandidate, objective ParamObjective) {
+	minSize, minCost := math.Inf(1), math.Inf(1)
+	for _, c := range cs {
+		minSize = math.Min(minSize, float64(c.SizeBytes))
+		minCost = math.Min(minCost, c.ProverCost)
+	}
+	score := func(c ParamCandidate) float64 {
+		switch objective {
+		case ObjectiveProverTime:
+			return c.ProverCost
+		case ObjectiveBalanced:
+			return float64(c.SizeBytes) / minSize * c.ProverCost / minCost
+		default:
+			return float64(c.SizeBytes)
+		}
+	}
+	sort.SliceStable(cs, func(i, j int) bool {
+		si, sj := score(cs[i]), score(cs[j])
+		if si != sj {
+			return si < sj
+		}
+		return cs[i].Bits > cs[j].Bits
+	})
+}
@@ -0,0 +1,68 @@
+package PIOP
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSelectParamsParetoFront(t *testing.T) {
+	shape := StatementShape{Rows: 100, Constraints: 50, Degree: 5, AggDegree: 1}
+	for _, target := range []int{80, 128} {
+		front, err := SelectParams(shape, target, ObjectiveSize)
+		if err != nil {
+			t.Fatalf("target %d: %v", target, err)
+		}
+		if len(front) == 0 {
+			t.Fatalf("target %d: empty Pareto set", target)
+		}
+		for i, c := range front {
+			if c.Bits < float64(target) {
+				t.Fatalf("target %d: candidate %d has %.2f bits", target, i, c.Bits)
+			}
+			// The budget must agree with computeSoundnessBudget on the same opts.
+			if got := evaluateCandidate(mustShape(t, shape), c.Opts); math.Abs(got.Bits-c.Bits) > 1e-9 {
+				t.Fatalf("candidate %d: bits %.4f, recomputed %.4f", i, c.Bits, got.Bits)
+			}
+			for j, o := range front {
+				if i != j && o.dominates(c) {
+					t.Fatalf("candidate %d is dominated by %d", i, j)
+				}
+			}
+			if i > 0 && front[i-1].SizeBytes > c.SizeBytes {
+				t.Fatalf("size objective not sorted at %d", i)
+			}
+		}
+	}
+}
+
+func TestSelectParamsObjectiveOrder(t *testing.T) {
+	shape := StatementShape{Rows: 100, Constraints: 50, Degree: 5, AggDegree: 1}
+	front, err := SelectParams(shape, 128, ObjectiveProverTime)
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	for i := 1; i < len(front); i++ {
+		if front[i-1].ProverCost > front[i].ProverCost {
+			t.Fatalf("time objective not sorted at %d", i)
+		}
+	}
+}
+
+func TestSelectParamsUnreachable(t *testing.T) {
+	space := DefaultParamSpace()
+	space.MaxRho = 1
+	space.Theta = []int{1}
+	space.Kappa = []int{0}
+	if _, err := SelectParamsIn(StatementShape{Rows: 10}, 200, ObjectiveSize, space); err == nil {
+		t.Fatalf("expected error for a target the space cannot reach")
+	}
+}
+
+func mustShape(t *testing.T, shape StatementShape) StatementShape {
+	t.Helper()
+	s, err := shape.withDefaults()
+	if err != nil {
+		t.Fatalf("shape defaults: %v", err)
+	}
+	return s
+}
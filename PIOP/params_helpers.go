@@ -10,16 +10,13 @@ import (
 
 // loadParamsAndOmega loads Parameters.json, constructs the ring, and derives
 // the evaluation set Ω exactly as buildSimWith currently does. It returns the
-// ring, omega, and ncols (ring dimension).
+// ring, omega, and ncols (ring dimension). SimOpts.Ring, when set, replaces
+// the ring from Parameters.json.
 func loadParamsAndOmega(opts SimOpts) (*ring.Ring, []uint64, int, error) {
 	opts.applyDefaults()
-	par, err := ntrurio.LoadParams(resolve("Parameters/Parameters.json"), true /* allowMismatch */)
-	if err != nil {
-		return nil, nil, 0, fmt.Errorf("load params: %w", err)
-	}
-	ringQ, err := ring.NewRing(par.N, []uint64{par.Q})
+	ringQ, err := paramsRing(opts.Ring)
 	if err != nil {
-		return nil, nil, 0, fmt.Errorf("ring.NewRing: %w", err)
+		return nil, nil, 0, err
 	}
 	q := ringQ.Modulus[0]
 	ncols := opts.NCols
@@ -30,13 +27,40 @@ func loadParamsAndOmega(opts SimOpts) (*ring.Ring, []uint64, int, error) {
 		return nil, nil, 0, fmt.Errorf("SimOpts.NLeaves=%d mismatch ring dimension %d", opts.NLeaves, ncols)
 	}
 	// Derive omega exactly as buildSimWith: take NTT of X and slice first ncols.
-	px := ringQ.NewPoly()
-	px.Coeffs[0][1] = 1
-	pts := ringQ.NewPoly()
-	ringQ.NTT(px, pts)
-	omega := pts.Coeffs[0][:ncols]
+	omega := ringOmega(ringQ, ncols)
 	if err := checkOmega(omega, q); err != nil {
 		return nil, nil, 0, fmt.Errorf("invalid omega: %w", err)
 	}
 	return ringQ, omega, ncols, nil
 }
+
+// paramsRing returns override when it is set and otherwise the ring of
+// Parameters/Parameters.json. The PIOP works over one word-size prime at a
+// time; rings with several RNS limbs are proven limb by limb (see rns.go).
+func paramsRing(override *ring.Ring) (*ring.Ring, error) {
+	if override != nil {
+		if len(override.Modulus) != 1 {
+			return nil, fmt.Errorf("ring has %d moduli; prove RNS rings per limb with BuildRNS", len(override.Modulus))
+		}
+		return override, nil
+	}
+	par, err := ntrurio.LoadParams(resolve("Parameters/Parameters.json"), true /* allowMismatch */)
+	if err != nil {
+		return nil, fmt.Errorf("load params: %w", err)
+	}
+	ringQ, err := ring.NewRing(par.N, []uint64{par.Q})
+	if err != nil {
+		return nil, fmt.Errorf("ring.NewRing: %w", err)
+	}
+	return ringQ, nil
+}
+
+// ringOmega returns the first ncols NTT evaluation points of ringQ, i.e. the
+// NTT of X truncated to ncols.
+func ringOmega(ringQ *ring.Ring, ncols int) []uint64 {
+	px := ringQ.NewPoly()
+	px.Coeffs[0][1] = 1
+	pts := ringQ.NewPoly()
+	ringQ.NTT(px, pts)
+	return pts.Coeffs[0][:ncols]
+}
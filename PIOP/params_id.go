@@ -0,0 +1,107 @@
+package PIOP
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"vSIS-Signature/prf"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// ComputeParamsID digests the configuration a proof is built under: the ring,
+// the public matrices B, Ac and A together with the parameter-bundle digest
+// and bound, the PRF parameter set (nil for statements without a PRF trace),
+// the FS personalization and the SimOpts knobs that shape the transcript.
+// Provers store it in Proof.ParamsID and absorb it in FS round 0; verifiers
+// recompute it from their own configuration and reject a proof whose ID
+// differs before replaying anything. Test hooks (Mutate, the FS oracle) and
+// scheduling knobs (GrindWorkers, NLeaves) are not part of the ID.
+func ComputeParamsID(ringQ *ring.Ring, pub PublicInputs, prfParams *prf.Params, opts SimOpts, personalization string) []byte {
+	opts.applyDefaults()
+	h := sha256.New()
+	h.Write([]byte("vSIS-PIOP/ParamsID/v1"))
+	var buf [8]byte
+	writeInt := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	writeBytes := func(b []byte) {
+		writeInt(uint64(len(b)))
+		h.Write(b)
+	}
+	writePolys := func(polys []*ring.Poly) {
+		writeInt(uint64(len(polys)))
+		for _, p := range polys {
+			for _, c := range p.Coeffs[0] {
+				writeInt(c)
+			}
+		}
+	}
+	writeMatrix := func(m [][]*ring.Poly) {
+		writeInt(uint64(len(m)))
+		for _, row := range m {
+			writePolys(row)
+		}
+	}
+	writeBool := func(b bool) {
+		if b {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+	}
+
+	writeInt(uint64(ringQ.N))
+	writeInt(uint64(len(ringQ.Modulus)))
+	for _, q := range ringQ.Modulus {
+		writeInt(q)
+	}
+	writePolys(pub.B)
+	writeMatrix(pub.Ac)
+	writeMatrix(pub.A)
+	writeBytes(pub.ParamsDigest)
+	writeInt(uint64(pub.BoundB))
+	if prfParams != nil {
+		d := prfParams.Digest()
+		writeBytes(d[:])
+	} else {
+		writeBytes(nil)
+	}
+	writeBytes([]byte(personalization))
+
+	writeInt(uint64(opts.Rho))
+	writeInt(uint64(opts.EllPrime))
+	writeInt(uint64(opts.Ell))
+	writeInt(uint64(opts.Eta))
+	writeInt(uint64(opts.Theta))
+	for _, k := range opts.Kappa {
+		writeInt(uint64(k))
+	}
+	writeInt(uint64(opts.NCols))
+	writeInt(uint64(opts.DQOverride))
+	writeInt(uint64(opts.Lambda))
+	writeInt(uint64(opts.ChainW))
+	writeInt(uint64(opts.ChainL))
+	writeBool(opts.CoeffPacking)
+	writeBool(opts.Credential)
+	return h.Sum(nil)
+}
+
+// checkParamsID compares a proof's ParamsID with the verifier's.
+func checkParamsID(proof *Proof, want []byte) error {
+	if !equalByteSlices(proof.ParamsID, want) {
+		return ErrParamsID
+	}
+	return nil
+}
+
+// checkPACSParamsID checks a PACS proof, which has no public inputs or
+// personalization, against the ring and knobs of opts.
+func checkPACSParamsID(proof *Proof, opts SimOpts) error {
+	ringQ, err := paramsRing(opts.Ring)
+	if err != nil {
+		return err
+	}
+	return checkParamsID(proof, ComputeParamsID(ringQ, PublicInputs{}, nil, opts, ""))
+}
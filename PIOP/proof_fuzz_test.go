@@ -0,0 +1,317 @@
+package PIOP
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	decs "vSIS-Signature/DECS"
+)
+
+// fuzzProof is one honest PACS proof shared by the fuzz targets below; the
+// seed corpora are generated from it.
+var (
+	fuzzProofOnce sync.Once
+	fuzzProofSnap *ProofSnapshot
+	fuzzProofQ    uint64
+	fuzzProofN    int
+)
+
+func loadFuzzProof(tb testing.TB) (ProofSnapshot, uint64, int) {
+	tb.Helper()
+	fuzzProofOnce.Do(func() {
+		ctx, okLin, okEq4, okSum := buildSimWith(nil, secureSimOpts())
+		if ctx == nil || ctx.proof == nil || !(okLin && okEq4 && okSum) {
+			return
+		}
+		snap := ctx.proof.Snapshot()
+		fuzzProofSnap, fuzzProofQ, fuzzProofN = &snap, ctx.q, int(ctx.ringQ.N)
+	})
+	if fuzzProofSnap == nil {
+		tb.Fatal("fuzz fixture: honest proof did not verify")
+	}
+	return fuzzProofSnap.Restore().Snapshot(), fuzzProofQ, fuzzProofN
+}
+
+func verifiesNIZK(proof *Proof) bool {
+	okLin, okEq4, okSum, err := VerifyNIZK(proof)
+	return err == nil && okLin && okEq4 && okSum
+}
+
+// snapshotSlots lists every integer field reachable from v (slice lengths
+// are exercised by FuzzProofSnapshotVerify instead).
+func snapshotSlots(v reflect.Value, out []reflect.Value) []reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			out = snapshotSlots(v.Elem(), out)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out = snapshotSlots(v.Field(i), out)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			out = snapshotSlots(v.Index(i), out)
+		}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		out = append(out, v)
+	case reflect.Uint8, reflect.Uint32, reflect.Uint64:
+		out = append(out, v)
+	}
+	return out
+}
+
+// residueWidths are the packed widths the seeds write into ResidueBits:
+// legacy zero, too narrow, one past a 64-bit word and far past it.
+var residueWidths = []int{0, 1, 19, 21, 64, 65, 1 << 62}
+
+// slotIndex returns the position of field in slots, or -1.
+func slotIndex(slots []reflect.Value, field *int) int {
+	for i, s := range slots {
+		if s.Kind() == reflect.Int && s.Addr().Interface() == any(field) {
+			return i
+		}
+	}
+	return -1
+}
+
+// FuzzProofSnapshotVerify decodes arbitrary JSON as a ProofSnapshot (the
+// form SimReport carries proofs in) and verifies it. Neither Restore nor
+// VerifyNIZK may panic on hostile lengths.
+func FuzzProofSnapshotVerify(f *testing.F) {
+	snap, _, _ := loadFuzzProof(f)
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(raw)
+	snap.Digests = append(snap.Digests, []byte{1}, []byte{2})
+	snap.RowOpening.MaskCount = 1 << 40
+	raw, _ = json.Marshal(snap)
+	f.Add(raw)
+	f.Add([]byte(`{"Root":"AA==","VTargetsBits":"/////////////w==","Digests":[null,null,null,null,null,null]}`))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		var ps ProofSnapshot
+		if err := json.Unmarshal(raw, &ps); err != nil {
+			return
+		}
+		VerifyNIZK(ps.Restore())
+	})
+}
+
+// FuzzProofSnapshotFields overwrites one integer field of an honest snapshot
+// with an arbitrary value. Counts, widths and packed indices are all read from
+// the proof, so this reaches the length checks much faster than JSON mutation.
+func FuzzProofSnapshotFields(f *testing.F) {
+	snap, _, _ := loadFuzzProof(f)
+	n := len(snapshotSlots(reflect.ValueOf(&snap), nil))
+	for _, slot := range []uint32{0, 1, 7, uint32(n / 3), uint32(n / 2), uint32(n - 1)} {
+		for _, val := range []uint64{0, 1, 1 << 31, 1<<63 - 1, ^uint64(0)} {
+			f.Add(slot, val)
+		}
+	}
+	// Regression: an oversized ResidueBits used to panic the unpacking.
+	slots := snapshotSlots(reflect.ValueOf(&snap), nil)
+	for _, op := range []*decs.DECSOpening{snap.RowOpening, snap.MOpening} {
+		if op == nil {
+			continue
+		}
+		slot := slotIndex(slots, &op.ResidueBits)
+		if slot < 0 {
+			f.Fatal("ResidueBits not among the snapshot slots")
+		}
+		for _, w := range residueWidths {
+			f.Add(uint32(slot), uint64(w))
+		}
+	}
+	f.Fuzz(func(t *testing.T, slot uint32, val uint64) {
+		ps, _, _ := loadFuzzProof(t)
+		slots := snapshotSlots(reflect.ValueOf(&ps), nil)
+		s := slots[int(slot)%len(slots)]
+		if s.CanInt() {
+			s.SetInt(int64(val))
+		} else {
+			s.SetUint(val)
+		}
+		VerifyNIZK(ps.Restore())
+	})
+}
+
+// proofTamper mutates one value the verifier binds, either through the
+// Fiat–Shamir transcript or through a DECS/LVCS opening check. It reports
+// false when the selected value does not exist in this proof.
+type proofTamper func(p *Proof, pos int, delta, q uint64, n int) bool
+
+// boundTampers covers the proof content that must be bound. Metadata the
+// verifier re-derives (packed dimensions and widths, RowLayout,
+// RoundCounters, the θ=1 eval helpers) and FparNTT/QNTT outside the opened
+// tail slots are deliberately absent: changing them leaves the proof valid.
+var boundTampers = []proofTamper{
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		p.Root[pos%len(p.Root)] ^= byte(delta | 1)
+		return true
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		return flipByte(p.Salt, pos, delta)
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		p.Ctr[pos%len(p.Ctr)] += delta | 1
+		return true
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		return flipByte(p.Digests[pos%len(p.Digests)], pos/len(p.Digests), delta)
+	},
+	func(p *Proof, pos int, delta, _ uint64, n int) bool {
+		if len(p.Tail) == 0 {
+			return false
+		}
+		i := pos % len(p.Tail)
+		p.Tail[i] = (p.Tail[i] + 1 + int(delta%uint64(n-1))) % n
+		return true
+	},
+	func(p *Proof, pos int, delta, q uint64, _ int) bool {
+		mat := copyMatrix(p.VTargetsMatrix())
+		if !bumpEntry(mat, pos, delta, q) {
+			return false
+		}
+		p.setVTargets(mat)
+		return true
+	},
+	func(p *Proof, pos int, delta, q uint64, _ int) bool {
+		mat := copyMatrix(p.BarSetsMatrix())
+		if !bumpEntry(mat, pos, delta, q) {
+			return false
+		}
+		p.setBarSets(mat)
+		return true
+	},
+	func(p *Proof, pos int, delta, q uint64, _ int) bool {
+		return bumpEntry(p.R, pos, delta, q)
+	},
+	func(p *Proof, pos int, delta, q uint64, _ int) bool {
+		return bumpEntry(p.CoeffMatrix, pos, delta, q)
+	},
+	func(p *Proof, pos int, delta, q uint64, _ int) bool {
+		return bumpEntry(p.GammaPrime, pos, delta, q)
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		return flipOpeningBits(p.RowOpening.PvalsBits, p.RowOpening, p.RowOpening.R, pos, delta)
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		return flipOpeningBits(p.RowOpening.MvalsBits, p.RowOpening, p.RowOpening.Eta, pos, delta)
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		if len(p.RowOpening.FrontierNodes) == 0 {
+			return false
+		}
+		node := p.RowOpening.FrontierNodes[pos%len(p.RowOpening.FrontierNodes)]
+		return flipByte(node, pos/len(p.RowOpening.FrontierNodes), delta)
+	},
+	func(p *Proof, pos int, delta, _ uint64, _ int) bool {
+		return flipByte(p.RowOpening.NonceSeed, pos, delta)
+	},
+	func(p *Proof, _ int, delta, _ uint64, _ int) bool {
+		return setResidueBits(p.RowOpening, delta)
+	},
+	func(p *Proof, _ int, delta, _ uint64, _ int) bool {
+		return setResidueBits(p.MOpening, delta)
+	},
+}
+
+// residueTampers are the boundTampers indices of setResidueBits.
+var residueTampers = []int{len(boundTampers) - 2, len(boundTampers) - 1}
+
+// setResidueBits rewrites the packed width of op to residueWidths[delta],
+// skipping widths that decode like the current one.
+func setResidueBits(op *decs.DECSOpening, delta uint64) bool {
+	if op == nil {
+		return false
+	}
+	w := residueWidths[delta%uint64(len(residueWidths))]
+	effective := func(w int) int {
+		if w == 0 {
+			return 20
+		}
+		return w
+	}
+	if effective(w) == effective(op.ResidueBits) {
+		return false
+	}
+	op.ResidueBits = w
+	return true
+}
+
+func flipByte(b []byte, pos int, delta uint64) bool {
+	if len(b) == 0 {
+		return false
+	}
+	b[pos%len(b)] ^= byte(delta | 1)
+	return true
+}
+
+func bumpEntry(mat [][]uint64, pos int, delta, q uint64) bool {
+	var total int
+	for _, row := range mat {
+		total += len(row)
+	}
+	if total == 0 {
+		return false
+	}
+	pos %= total
+	for _, row := range mat {
+		if pos < len(row) {
+			row[pos] = (row[pos] + 1 + delta%(q-1)) % q
+			return true
+		}
+		pos -= len(row)
+	}
+	return false
+}
+
+// flipOpeningBits flips one bit inside the packed residues of op; bits past
+// the last residue are padding and not covered.
+func flipOpeningBits(bits []byte, op *decs.DECSOpening, cols, pos int, delta uint64) bool {
+	width := op.ResidueBits
+	if width == 0 {
+		width = 20
+	}
+	used := op.EntryCount() * cols * width
+	if used <= 0 || len(bits)*8 < used {
+		return false
+	}
+	bit := (pos + int(delta%uint64(used))) % used
+	bits[bit/8] ^= 1 << (bit % 8)
+	return true
+}
+
+// FuzzVerifyNIZKRejectsTampering changes one bound value of an honest proof
+// and requires VerifyNIZK to reject it.
+func FuzzVerifyNIZKRejectsTampering(f *testing.F) {
+	snap, _, _ := loadFuzzProof(f)
+	if !verifiesNIZK(snap.Restore()) {
+		f.Fatal("honest proof rejected")
+	}
+	for field := range boundTampers {
+		f.Add(uint8(field), uint32(0), uint64(0))
+		f.Add(uint8(field), uint32(field*977), uint64(1)<<uint(field))
+	}
+	for _, field := range residueTampers {
+		for w := range residueWidths {
+			f.Add(uint8(field), uint32(0), uint64(w))
+		}
+	}
+	f.Fuzz(func(t *testing.T, field uint8, pos uint32, delta uint64) {
+		ps, q, n := loadFuzzProof(t)
+		proof := ps.Restore()
+		if !boundTampers[int(field)%len(boundTampers)](proof, int(pos), delta, q, n) {
+			return
+		}
+		if verifiesNIZK(proof) {
+			t.Fatalf("tampered proof verified (field %d, pos %d, delta %d)", int(field)%len(boundTampers), pos, delta)
+		}
+	})
+}
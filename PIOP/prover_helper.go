@@ -19,9 +19,11 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"time"
 
+	lvcs "vSIS-Signature/LVCS"
 	kf "vSIS-Signature/internal/kfield"
 	measure "vSIS-Signature/measure"
 	prof "vSIS-Signature/prof"
@@ -37,11 +39,14 @@ var (
 //  field helpers (mod q)
 // -----------------------------------------------------------------------------
 
-func randUint64Mod(q uint64) uint64 {
+func randUint64Mod(rng io.Reader, q uint64) uint64 {
+	if rng == nil {
+		rng = rand.Reader
+	}
 	var bound uint64 = ^uint64(0) - (^uint64(0) % q)
 	for {
 		var buf [8]byte
-		if _, err := rand.Read(buf[:]); err != nil {
+		if _, err := io.ReadFull(rng, buf[:]); err != nil {
 			panic("randUint64Mod: entropy read failed: " + err.Error())
 		}
 		v := binary.LittleEndian.Uint64(buf[:])
@@ -341,39 +346,35 @@ func kpolyToCoeffPolys(r *ring.Ring, kp *KPoly) []*ring.Poly {
 	return out
 }
 
-// evalKPolyAtF evaluates kp at an F_q point w (embedded in K).
+// evalKPolyAtF evaluates kp at an F_q point w (embedded in K). Scaling by
+// w ∈ F_q acts on every limb alike, so each limb is an F_q Horner evaluation.
 func evalKPolyAtF(K *kf.Field, kp *KPoly, w uint64) kf.Elem {
-	acc := K.Zero()
-	x := K.EmbedF(w % K.Q)
-	for k := kp.Degree; k >= 0; k-- {
-		acc = K.Mul(acc, x)
-		coeff := K.Phi(kp.coeffLimbs(k))
-		acc = K.Add(acc, coeff)
-		if k == 0 {
-			break
-		}
+	out := K.Zero()
+	for l, limb := range kp.Limbs {
+		out.Limb[l] = EvalPoly(limb[:kPolyLen(kp, limb)], w%K.Q, K.Q)
 	}
-	return acc
+	return out
 }
 
-// evalKPolyAtK evaluates kp at a K-point e.
-func evalKPolyAtK(K *kf.Field, kp *KPoly, e kf.Elem) kf.Elem {
-	acc := K.Zero()
-	for k := kp.Degree; k >= 0; k-- {
-		acc = K.Mul(acc, e)
-		coeff := K.Phi(kp.coeffLimbs(k))
-		acc = K.Add(acc, coeff)
-		if k == 0 {
-			break
-		}
+// evalKPolyMulti evaluates kp at the p-th point of me.
+func evalKPolyMulti(me *kf.MultiEval, p int, kp *KPoly) kf.Elem {
+	limbs := make([][]uint64, len(kp.Limbs))
+	for l, limb := range kp.Limbs {
+		limbs[l] = limb[:kPolyLen(kp, limb)]
 	}
-	return acc
+	return me.EvalKAt(p, limbs)
+}
+
+// kPolyLen is the number of coefficients of limb up to kp.Degree.
+func kPolyLen(kp *KPoly, limb []uint64) int {
+	return max(0, min(kp.Degree+1, len(limb)))
 }
 
 // BuildMaskPolynomialsK builds M_i ∈ K[X] of degree ≤ dQ such that ΣΩ Q_i(ω)=0 in K.
 type maskSamplerParams struct {
 	omega  []uint64
 	maxDeg int
+	rng    io.Reader // nil = crypto/rand
 }
 
 func validateMaskSamplerParams(q uint64, params maskSamplerParams) {
@@ -407,7 +408,7 @@ func maskSamplerS(omega []uint64, dQ int, q uint64) []uint64 {
 			if k == 1 {
 				powers[j] = w % q
 			} else {
-				powers[j] = (powers[j] * w) % q
+				powers[j] = lvcs.MulModReduced(powers[j], w%q, q)
 			}
 			sum = modAdd(sum, powers[j], q)
 		}
@@ -432,7 +433,7 @@ func sampleMaskPolynomialsF(
 		sum := uint64(0)
 		coeffs := make([]uint64, ringQ.N)
 		for k := 1; k <= params.maxDeg; k++ {
-			randomCoeff := randUint64Mod(q)
+			randomCoeff := randUint64Mod(params.rng, q)
 			if k < len(S) {
 				sum = modAdd(sum, modMul(randomCoeff, S[k], q), q)
 			}
@@ -479,7 +480,7 @@ func sampleMaskPolynomialsK(
 		for k := 1; k <= params.maxDeg; k++ {
 			limbs := make([]uint64, K.Theta)
 			for t := 0; t < K.Theta; t++ {
-				limbs[t] = randUint64Mod(q)
+				limbs[t] = randUint64Mod(params.rng, q)
 			}
 			coeff := K.Phi(limbs)
 			kp.setCoeffK(k, limbs)
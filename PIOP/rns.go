@@ -0,0 +1,267 @@
+package PIOP
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// RNSProof proves a statement over a ring whose modulus Q = q_0·…·q_{L−1}
+// has several RNS limbs. Limbs[i] is an ordinary proof of the statement
+// reduced mod q_i, built over the single-modulus ring of that limb, so every
+// limb reuses the word-size arithmetic, DECS leaves and packing codecs of
+// the single-prime PIOP.
+//
+// The limbs share one Fiat–Shamir transcript: the public labels of limb i
+// bind the full modulus chain, the limb index, and the transcript of limb
+// i−1 (root, salt and round digests), so limb challenges are derived after
+// every earlier limb is fixed and limbs cannot be mixed across proofs or
+// reordered. Ring relations (commitment, hash, signature, packing) hold mod Q
+// exactly when they hold mod every q_i.
+//
+// Nothing ties the limb witnesses to one another: each limb may open other
+// residues, so the CRT of the limb witnesses is an arbitrary element of Z_Q
+// even when every limb proves its residues short. Statements with a norm
+// bound (PublicInputs.BoundB > 0) are therefore refused with ErrRNSBound.
+type RNSProof struct {
+	N      int
+	Moduli []uint64
+	Limbs  []*Proof
+}
+
+// ErrRNSBound is returned by BuildRNS and VerifyRNS for statements with a
+// norm bound, which the limb-by-limb composition cannot prove over Z.
+var ErrRNSBound = errors.New("RNS proofs do not bind the limbs to one short witness; bounded statements are not supported")
+
+// Public label names binding a limb proof to its RNS context.
+const (
+	rnsLabelModuli = "RNS.Moduli"
+	rnsLabelLimb   = "RNS.Limb"
+	rnsLabelPrev   = "RNS.Prev"
+)
+
+// SplitRNS returns the single-modulus ring of every limb of ringQ. The limb
+// rings use the same primitive roots as ringQ, so limb i of a polynomial in
+// either domain (coefficient or NTT) is the same polynomial of limb ring i.
+func SplitRNS(ringQ *ring.Ring) ([]*ring.Ring, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
+	}
+	limbs := make([]*ring.Ring, len(ringQ.Modulus))
+	for i, q := range ringQ.Modulus {
+		r, err := ring.NewRing(ringQ.N, []uint64{q})
+		if err != nil {
+			return nil, fmt.Errorf("limb %d: ring.NewRing: %w", i, err)
+		}
+		limbs[i] = r
+	}
+	return limbs, nil
+}
+
+// LimbPolys returns limb of every polynomial in polys as a polynomial of
+// limbRing. Nil entries stay nil.
+func LimbPolys(limbRing *ring.Ring, polys []*ring.Poly, limb int) []*ring.Poly {
+	if polys == nil {
+		return nil
+	}
+	out := make([]*ring.Poly, len(polys))
+	for i, p := range polys {
+		if p == nil {
+			continue
+		}
+		out[i] = limbRing.NewPoly()
+		copy(out[i].Coeffs[0], p.Coeffs[limb])
+	}
+	return out
+}
+
+func limbMatrix(limbRing *ring.Ring, mat [][]*ring.Poly, limb int) [][]*ring.Poly {
+	if mat == nil {
+		return nil
+	}
+	out := make([][]*ring.Poly, len(mat))
+	for i := range mat {
+		out[i] = LimbPolys(limbRing, mat[i], limb)
+	}
+	return out
+}
+
+// LimbPublicInputs reduces pub to limb: ring elements keep their limb
+// residues and the public hash output T is reduced mod q_limb. PRF tags and
+// nonces live in the PRF field and are passed through, as are Extras.
+func LimbPublicInputs(limbRing *ring.Ring, pub PublicInputs, limb int) PublicInputs {
+	out := pub
+	out.Com = LimbPolys(limbRing, pub.Com, limb)
+	out.RI0 = LimbPolys(limbRing, pub.RI0, limb)
+	out.RI1 = LimbPolys(limbRing, pub.RI1, limb)
+	out.Ac = limbMatrix(limbRing, pub.Ac, limb)
+	out.A = limbMatrix(limbRing, pub.A, limb)
+	out.B = LimbPolys(limbRing, pub.B, limb)
+	out.U = LimbPolys(limbRing, pub.U, limb)
+	if pub.T != nil {
+		q := int64(limbRing.Modulus[0])
+		out.T = make([]int64, len(pub.T))
+		for i, v := range pub.T {
+			if v %= q; v < 0 {
+				v += q
+			}
+			out.T[i] = v
+		}
+	}
+	if pub.Extras != nil {
+		out.Extras = make(map[string]interface{}, len(pub.Extras))
+		for k, v := range pub.Extras {
+			out.Extras[k] = v
+		}
+	}
+	return out
+}
+
+// LimbWitnessInputs reduces wit to limb. Extras are passed through unchanged.
+func LimbWitnessInputs(limbRing *ring.Ring, wit WitnessInputs, limb int) WitnessInputs {
+	out := wit
+	out.M1 = LimbPolys(limbRing, wit.M1, limb)
+	out.M2 = LimbPolys(limbRing, wit.M2, limb)
+	out.RU0 = LimbPolys(limbRing, wit.RU0, limb)
+	out.RU1 = LimbPolys(limbRing, wit.RU1, limb)
+	out.R = LimbPolys(limbRing, wit.R, limb)
+	out.R0 = LimbPolys(limbRing, wit.R0, limb)
+	out.R1 = LimbPolys(limbRing, wit.R1, limb)
+	out.K0 = LimbPolys(limbRing, wit.K0, limb)
+	out.K1 = LimbPolys(limbRing, wit.K1, limb)
+	out.U = LimbPolys(limbRing, wit.U, limb)
+	return out
+}
+
+// BuildRNS proves pub/wit over the multi-limb ring ringQ, one limb at a time,
+// with the statement builder returned by newBuilder (e.g.
+// NewCredentialBuilder). The builder must bind PublicInputs into the
+// transcript. Statements with a norm bound are refused (ErrRNSBound).
+func BuildRNS(ringQ *ring.Ring, newBuilder func(SimOpts) ContextStatementBuilder, pub PublicInputs, wit WitnessInputs, opts SimOpts) (*RNSProof, error) {
+	return BuildRNSContext(context.Background(), ringQ, newBuilder, pub, wit, opts)
+}
+
+// BuildRNSContext is BuildRNS with cancellation; it returns a *CanceledError
+// once ctx is done.
+func BuildRNSContext(ctx context.Context, ringQ *ring.Ring, newBuilder func(SimOpts) ContextStatementBuilder, pub PublicInputs, wit WitnessInputs, opts SimOpts) (*RNSProof, error) {
+	if pub.BoundB > 0 {
+		return nil, fmt.Errorf("BuildRNS: bound %d: %w", pub.BoundB, ErrRNSBound)
+	}
+	limbs, err := SplitRNS(ringQ)
+	if err != nil {
+		return nil, err
+	}
+	out := &RNSProof{N: ringQ.N, Moduli: append([]uint64(nil), ringQ.Modulus...)}
+	var prev *Proof
+	for i, limbRing := range limbs {
+		if err := checkCtx(ctx, "BuildRNS"); err != nil {
+			return nil, err
+		}
+		limbOpts := opts
+		limbOpts.Ring = limbRing
+		limbPub := bindRNSLimb(LimbPublicInputs(limbRing, pub, i), ringQ, i, prev)
+		proof, err := newBuilder(limbOpts).BuildContext(ctx, limbPub, LimbWitnessInputs(limbRing, wit, i), MaskConfig{})
+		if err != nil {
+			return nil, fmt.Errorf("limb %d (q=%d): %w", i, limbRing.Modulus[0], err)
+		}
+		if len(proof.LabelsDigest) == 0 {
+			return nil, fmt.Errorf("limb %d: builder does not bind public labels; RNS limbs cannot be chained", i)
+		}
+		out.Limbs = append(out.Limbs, proof)
+		prev = proof
+	}
+	return out, nil
+}
+
+// VerifyRNS checks every limb of proof against pub reduced to that limb,
+// rebuilding the transcript chain from the preceding limbs. Like BuildRNS it
+// refuses statements with a norm bound.
+func VerifyRNS(ringQ *ring.Ring, newBuilder func(SimOpts) ContextStatementBuilder, pub PublicInputs, proof *RNSProof, opts SimOpts) (bool, error) {
+	return VerifyRNSContext(context.Background(), ringQ, newBuilder, pub, proof, opts)
+}
+
+// VerifyRNSContext is VerifyRNS with cancellation.
+func VerifyRNSContext(ctx context.Context, ringQ *ring.Ring, newBuilder func(SimOpts) ContextStatementBuilder, pub PublicInputs, proof *RNSProof, opts SimOpts) (bool, error) {
+	if pub.BoundB > 0 {
+		return false, fmt.Errorf("VerifyRNS: bound %d: %w", pub.BoundB, ErrRNSBound)
+	}
+	if proof == nil {
+		return false, fmt.Errorf("nil RNS proof: %w", ErrMalformedProof)
+	}
+	limbs, err := SplitRNS(ringQ)
+	if err != nil {
+		return false, err
+	}
+	if proof.N != ringQ.N || len(proof.Moduli) != len(limbs) || len(proof.Limbs) != len(limbs) {
+		return false, fmt.Errorf("RNS proof shape N=%d limbs=%d/%d, want N=%d limbs=%d: %w",
+			proof.N, len(proof.Moduli), len(proof.Limbs), ringQ.N, len(limbs), ErrMalformedProof)
+	}
+	for i, q := range ringQ.Modulus {
+		if proof.Moduli[i] != q {
+			return false, fmt.Errorf("RNS limb %d modulus %d, want %d: %w", i, proof.Moduli[i], q, ErrMalformedProof)
+		}
+	}
+	var prev *Proof
+	for i, limbRing := range limbs {
+		if err := checkCtx(ctx, "VerifyRNS"); err != nil {
+			return false, err
+		}
+		if proof.Limbs[i] == nil {
+			return false, fmt.Errorf("RNS limb %d missing: %w", i, ErrMalformedProof)
+		}
+		limbOpts := opts
+		limbOpts.Ring = limbRing
+		limbPub := bindRNSLimb(LimbPublicInputs(limbRing, pub, i), ringQ, i, prev)
+		ok, err := newBuilder(limbOpts).VerifyContext(ctx, limbPub, proof.Limbs[i])
+		if err != nil {
+			return false, fmt.Errorf("limb %d (q=%d): %w", i, limbRing.Modulus[0], err)
+		}
+		if !ok {
+			return false, nil
+		}
+		prev = proof.Limbs[i]
+	}
+	return true, nil
+}
+
+// bindRNSLimb adds the RNS labels of limb to pub.Extras: the modulus chain,
+// the limb index, and the transcript digest of the previous limb.
+func bindRNSLimb(pub PublicInputs, ringQ *ring.Ring, limb int, prev *Proof) PublicInputs {
+	if pub.Extras == nil {
+		pub.Extras = map[string]interface{}{}
+	}
+	moduli := make([]byte, 8*(1+len(ringQ.Modulus)))
+	binary.LittleEndian.PutUint64(moduli, uint64(ringQ.N))
+	for i, q := range ringQ.Modulus {
+		binary.LittleEndian.PutUint64(moduli[8*(i+1):], q)
+	}
+	pub.Extras[rnsLabelModuli] = moduli
+	idx := make([]byte, 8)
+	binary.LittleEndian.PutUint64(idx, uint64(limb))
+	pub.Extras[rnsLabelLimb] = idx
+	pub.Extras[rnsLabelPrev] = rnsTranscriptDigest(prev)
+	return pub
+}
+
+// rnsTranscriptDigest hashes the Fiat–Shamir transcript of a limb proof; the
+// first limb chains from the empty digest.
+func rnsTranscriptDigest(p *Proof) []byte {
+	h := sha256.New()
+	if p == nil {
+		return h.Sum(nil)
+	}
+	h.Write(p.Root[:])
+	h.Write(p.Salt)
+	h.Write(p.LabelsDigest)
+	var ctr [8]byte
+	for round := range p.Digests {
+		binary.LittleEndian.PutUint64(ctr[:], p.Ctr[round])
+		h.Write(ctr[:])
+		h.Write(p.Digests[round])
+	}
+	return h.Sum(nil)
+}
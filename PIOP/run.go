@@ -2,9 +2,11 @@ package PIOP
 
 import (
 	"bytes"
+	"context"
 	cryptoRand "crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	"runtime"
 	"sort"
@@ -191,8 +193,6 @@ type simCtx struct {
 	vrf               *lvcs.VerifierState
 	pk                *lvcs.ProverKey
 	vTargets          [][]uint64
-	maskIdx           []int
-	maskOpen          *lvcs.Opening
 	tailOpen          *lvcs.Opening
 	combinedOpen      *decs.DECSOpening
 	proof             *Proof
@@ -216,6 +216,7 @@ type simCtx struct {
 	rangeSpec         RangeMembershipSpec
 	msgSource         []*ring.Poly
 	rndSource         []*ring.Poly
+	grindAttempts     [4]uint64
 }
 
 // SimOpts controls the behaviour of the PACS simulation and exposes all
@@ -234,6 +235,8 @@ type SimOpts struct {
 	ChainW     int
 	ChainL     int
 	CoeffPacking bool
+	// GrindWorkers bounds the goroutines used for FS grinding (0 = GOMAXPROCS).
+	GrindWorkers int
 
 	// Mutate allows tests to tweak the witness (w1,w2,w3) before constraints.
 	Mutate func(r *ring.Ring, omega []uint64, ell int, w1 []*ring.Poly, w2 *ring.Poly, w3 []*ring.Poly) `json:"-"`
@@ -241,6 +244,26 @@ type SimOpts struct {
 	// Credential switches on the augmented credential statement (commit/center/sig)
 	// once it is wired. Currently not implemented; kept for future integration.
 	Credential bool
+
+	// Ring replaces the ring of Parameters/Parameters.json for the credential
+	// builders and their verifiers. It must have a single modulus (NTT
+	// primes up to 61 bits); BuildRNS proves bound-free statements over
+	// multi-limb rings one limb at a time, and NewLiftedCredentialBuilder
+	// proves statements mod a smaller q over it.
+	Ring *ring.Ring `json:"-"`
+
+	// fsOracle replaces SHAKE-256 in the Fiat–Shamir rounds of both prover
+	// and verifier. Programmed challenges void soundness, so it can only be
+	// set through SetFSOracle in testonly builds (fs_oracle_testonly.go).
+	fsOracle XOF
+	// coins replaces crypto/rand as the prover's randomness (salt, masks,
+	// row tails, K-field sampling). Replaying coins under new challenges
+	// extracts the witness, so it is set only through SetCoins in testonly
+	// builds.
+	coins io.Reader
+	// simulate makes runMaskFS balance ΣΩ Q through the masks instead of
+	// through the witness (balanceMasks). Only SimulateProof sets it.
+	simulate bool
 }
 
 func defaultSimOpts() SimOpts {
@@ -301,12 +324,23 @@ func (o *SimOpts) applyDefaults() {
 	if o.ChainL < 0 {
 		o.ChainL = 0
 	}
+	if o.GrindWorkers < 0 {
+		o.GrindWorkers = 0
+	}
 	// Credential flag defaults to false; leave as provided.
 }
 
 // ApplyDefaultsExported exposes applyDefaults to external callers (tests).
 func (o *SimOpts) ApplyDefaultsExported() { o.applyDefaults() }
 
+// coinSource returns the prover's randomness: coins, or crypto/rand.
+func (o *SimOpts) coinSource() io.Reader {
+	if o.coins == nil {
+		return cryptoRand.Reader
+	}
+	return o.coins
+}
+
 // RowLayout captures the witness row partition so verifiers can recover per-row values.
 type RowLayout struct {
 	SigCount        int
@@ -336,6 +370,7 @@ type Proof struct {
 	Ctr              [4]uint64
 	Digests          [4][]byte
 	LabelsDigest     []byte
+	ParamsID         []byte // see ComputeParamsID; absorbed in FS round 0
 	Lambda           int
 	Kappa            [4]int
 	Theta            int
@@ -403,21 +438,24 @@ type fsRoundResult struct {
 	RNG  *fsRNG
 }
 
-func fsRound(fs *FS, proof *Proof, round int, label string, material ...[]byte) fsRoundResult {
+func fsRound(ctx context.Context, fs *FS, proof *Proof, round int, label string, material ...[]byte) (fsRoundResult, error) {
 	if fs == nil {
 		panic("fsRound: nil FS state")
 	}
 	if proof == nil {
 		panic("fsRound: nil proof")
 	}
-	h, ctr, seed := fs.GrindAndDerive(round, material, func(h []byte) []byte { return h })
+	h, ctr, seed, err := fs.GrindAndDeriveContext(ctx, round, material, func(h []byte) []byte { return h })
+	if err != nil {
+		return fsRoundResult{}, err
+	}
 	proof.Ctr[round] = ctr
 	proof.RoundCounters[round] = ctr
 	proof.Digests[round] = append([]byte(nil), h...)
 	return fsRoundResult{
 		Seed: append([]byte(nil), seed...),
 		RNG:  newFSRNG(label, seed),
-	}
+	}, nil
 }
 
 func (p *Proof) setVTargets(mat [][]uint64) {
@@ -565,9 +603,10 @@ type SimReport struct {
 	ParallelRows    int
 	AggregatedRows  int
 	WitnessCols     int
-	MaskLeaves      int
 	TailLeaves      int
 	MerkleOpens     int
+	// GrindAttempts counts the digests evaluated in each FS grinding round.
+	GrindAttempts [4]uint64
 }
 
 // ChainSpecSummary captures the effective ℓ∞-chain configuration used in a run.
@@ -626,6 +665,7 @@ type ProofSnapshot struct {
 	Ctr          [4]uint64
 	Digests      [][]byte
 	LabelsDigest []byte
+	ParamsID     []byte
 	NColsUsed    int
 	OmegaTrunc   []uint64
 	// Eval-point consistency (optional)
@@ -683,21 +723,33 @@ type ProofSnapshot struct {
 
 // RunOnce executes a single serialized PACS simulation and captures metrics.
 func RunOnce(o SimOpts) (SimReport, error) {
+	return RunOnceContext(context.Background(), o)
+}
+
+// RunOnceContext is RunOnce with cancellation; it returns a *CanceledError
+// once ctx is done.
+func RunOnceContext(ctx context.Context, o SimOpts) (SimReport, error) {
 	o.applyDefaults()
 	if o.Credential {
 		return SimReport{}, fmt.Errorf("credential mode is not supported via RunOnce; use the credential builder path instead")
 	}
-	return runPACS(o)
+	return runPACS(ctx, o)
 }
 
 // runPACS preserves the existing behaviour for non-credential simulations.
-func runPACS(o SimOpts) (SimReport, error) {
+func runPACS(ctx context.Context, o SimOpts) (SimReport, error) {
 	runtime.GC()
 	var ms0, ms1 runtime.MemStats
 	runtime.ReadMemStats(&ms0)
 	start := time.Now()
-	ctx, okLin, okEq4, okSum := buildSimWith(nil, o)
+	sim, okLin, okEq4, okSum := buildSimWithContext(ctx, nil, o)
 	total := time.Since(start)
+	if sim == nil {
+		if err := checkCtx(ctx, "RunOnce"); err != nil {
+			prof.SnapshotAndReset()
+			return SimReport{Opts: o}, err
+		}
+	}
 	runtime.ReadMemStats(&ms1)
 	entries := prof.SnapshotAndReset()
 	totalUS := total.Microseconds()
@@ -754,8 +806,8 @@ func runPACS(o SimOpts) (SimReport, error) {
 	counts["__total__"] = len(entries)
 	sizes := map[string]int64{}
 	layerSizes := map[string]int64{}
-	if ctx != nil && ctx.proof != nil {
-		if sizeParts, totalBytes := proofSizeBreakdown(ctx.proof); len(sizeParts) > 0 {
+	if sim != nil && sim.proof != nil {
+		if sizeParts, totalBytes := proofSizeBreakdown(sim.proof); len(sizeParts) > 0 {
 			for k, v := range sizeParts {
 				sizes[k] = int64(v)
 				if k == "TOTAL" {
@@ -778,8 +830,8 @@ func runPACS(o SimOpts) (SimReport, error) {
 			}
 		}
 	}
-	if ctx != nil && ctx.ringQ != nil {
-		o.NLeaves = int(ctx.ringQ.N)
+	if sim != nil && sim.ringQ != nil {
+		o.NLeaves = int(sim.ringQ.N)
 	}
 	rep := SimReport{
 		Opts:            o,
@@ -798,14 +850,14 @@ func runPACS(o SimOpts) (SimReport, error) {
 		ProofSizeLayers: layerSizes,
 		PeakHeapB:       ms1.TotalAlloc - ms0.TotalAlloc,
 	}
-	if ctx != nil {
-		rep.Soundness = ctx.soundness
-		rep.ProofBytes = ctx.proofBytes
-		if ctx.ringQ != nil {
-			rep.QMod = ctx.ringQ.Modulus[0]
+	if sim != nil {
+		rep.Soundness = sim.soundness
+		rep.ProofBytes = sim.proofBytes
+		if sim.ringQ != nil {
+			rep.QMod = sim.ringQ.Modulus[0]
 		}
-		rep.Proof = ctx.proof.Snapshot()
-		spec := ctx.linfAux.Spec
+		rep.Proof = sim.proof.Snapshot()
+		spec := sim.linfAux.Spec
 		rep.Chain = ChainSpecSummary{
 			W:     spec.W,
 			Base:  int(spec.R),
@@ -813,14 +865,14 @@ func runPACS(o SimOpts) (SimReport, error) {
 			LSDLo: spec.LSDLo,
 			LSDHi: spec.LSDHi,
 		}
-		rep.ParallelDeg = ctx.parallelDeg
-		rep.AggregatedDeg = ctx.aggregatedDeg
-		rep.ParallelRows = ctx.parallelRows
-		rep.AggregatedRows = ctx.aggregatedRows
-		rep.WitnessCols = ctx.witnessCols
-		rep.MaskLeaves = len(ctx.maskIdx)
-		rep.TailLeaves = len(ctx.E)
-		rep.MerkleOpens = rep.MaskLeaves + rep.TailLeaves
+		rep.ParallelDeg = sim.parallelDeg
+		rep.AggregatedDeg = sim.aggregatedDeg
+		rep.ParallelRows = sim.parallelRows
+		rep.AggregatedRows = sim.aggregatedRows
+		rep.WitnessCols = sim.witnessCols
+		rep.TailLeaves = len(sim.E)
+		rep.MerkleOpens = rep.TailLeaves
+		rep.GrindAttempts = sim.grindAttempts
 	} else {
 		return rep, fmt.Errorf("simulation aborted: missing fixtures or parameters")
 	}
@@ -1120,6 +1172,7 @@ func cloneDECSOpening(op *decs.DECSOpening) *decs.DECSOpening {
 	// copy metadata and packed buffers if present
 	clone.R = op.R
 	clone.Eta = op.Eta
+	clone.ResidueBits = op.ResidueBits
 	clone.NonceBytes = op.NonceBytes
 	if len(op.NonceSeed) > 0 {
 		clone.NonceSeed = append([]byte(nil), op.NonceSeed...)
@@ -1223,8 +1276,7 @@ func unpackUint64Matrix(data []byte, rows, cols int) [][]uint64 {
 	if rows <= 0 || cols <= 0 {
 		return nil
 	}
-	need := rows * cols * 8
-	if len(data) != need {
+	if cols > len(data)/8 || rows != len(data)/8/cols || len(data) != rows*cols*8 {
 		return nil
 	}
 	out := make([][]uint64, rows)
@@ -1560,9 +1612,12 @@ func proofSizeBreakdown(proof *Proof) (map[string]int, int) {
 }
 
 // ProofSizeReport summarises the byte footprint of a proof as consumed by the verifier.
+// StdDev is set by EstimateProofSize only: the standard deviation of Total
+// over the FS-sampled tail.
 type ProofSizeReport struct {
-	Total int
-	Parts map[string]int
+	Total  int
+	Parts  map[string]int
+	StdDev float64
 }
 
 // MeasureProofSize returns a copy of the breakdown used by VerifyNIZK to reconstruct the proof.
@@ -1623,6 +1678,7 @@ func (p *Proof) Snapshot() ProofSnapshot {
 		Ctr:                p.Ctr,
 		Digests:            digests,
 		LabelsDigest:       append([]byte(nil), p.LabelsDigest...),
+		ParamsID:           append([]byte(nil), p.ParamsID...),
 		NColsUsed:          p.NColsUsed,
 		OmegaTrunc:         append([]uint64(nil), p.OmegaTrunc...),
 		EvalPoints:         append([]uint64(nil), p.EvalPoints...),
@@ -1706,6 +1762,7 @@ func (ps ProofSnapshot) Restore() *Proof {
 		Kappa:              ps.Kappa,
 		Theta:              ps.Theta,
 		LabelsDigest:       append([]byte(nil), ps.LabelsDigest...),
+		ParamsID:           append([]byte(nil), ps.ParamsID...),
 		Chi:                append([]uint64(nil), ps.Chi...),
 		Zeta:               append([]uint64(nil), ps.Zeta...),
 		Tail:               append([]int(nil), ps.Tail...),
@@ -1740,7 +1797,9 @@ func (ps ProofSnapshot) Restore() *Proof {
 	proof.BarSetsRows = ps.BarSetsRows
 	proof.BarSetsCols = ps.BarSetsCols
 	proof.BarSetsBitWidth = ps.BarSetsBitWidth
-	for i := range ps.Digests {
+	// Snapshots may come from untrusted JSON: extra digests are dropped and
+	// the verifier rejects the proof for the missing ones.
+	for i := 0; i < len(ps.Digests) && i < len(proof.Digests); i++ {
 		proof.Digests[i] = append([]byte(nil), ps.Digests[i]...)
 	}
 	return proof
@@ -1806,6 +1865,7 @@ func combineOpenings(mask, tail *decs.DECSOpening) *decs.DECSOpening {
 		}
 		combined.R = mask.R
 		combined.Eta = mask.Eta
+		combined.ResidueBits = mask.ResidueBits
 		if len(combined.NonceSeed) == 0 && len(mask.NonceSeed) > 0 {
 			combined.NonceSeed = append([]byte(nil), mask.NonceSeed...)
 			combined.NonceBytes = mask.NonceBytes
@@ -1819,6 +1879,9 @@ func combineOpenings(mask, tail *decs.DECSOpening) *decs.DECSOpening {
 		if combined.Eta == 0 {
 			combined.Eta = tail.Eta
 		}
+		if combined.ResidueBits == 0 {
+			combined.ResidueBits = tail.ResidueBits
+		}
 		if len(tail.NonceSeed) > 0 {
 			if len(combined.NonceSeed) == 0 {
 				combined.NonceSeed = append([]byte(nil), tail.NonceSeed...)
@@ -1849,6 +1912,12 @@ func RunPACSSimulation() bool {
 }
 
 func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
+	return buildSimWithContext(context.Background(), t, o)
+}
+
+// buildSimWithContext is buildSimWith with cancellation; when ctx is done it
+// returns a nil context so callers can report checkCtx(ctx, ...).
+func buildSimWithContext(ctx context.Context, t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	defer prof.Track(time.Now(), "buildSimWith")
 	o.applyDefaults()
 	maskRowOffset := 0
@@ -2070,7 +2139,7 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 			panic(fmt.Sprintf("invalid Eta: %d", o.Eta))
 		}
 	}
-	decsParams := decs.Params{Degree: maxDegree, Eta: o.Eta, NonceBytes: 16}
+	decsParams := decs.Params{Degree: maxDegree, Eta: o.Eta, NonceBytes: 16, Rand: o.coins}
 	var rows [][]uint64
 	var smallFieldK *kf.Field
 	var smallFieldChi []uint64
@@ -2143,8 +2212,6 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	// locals for theta>1 FS reuse
 	var (
 		E                 []int
-		maskIdx           []int
-		openMaskSaved     *lvcs.Opening
 		openTailSaved     *lvcs.Opening
 		combinedOpenSaved *decs.DECSOpening
 		maskEval          [][]uint64
@@ -2159,6 +2226,12 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	for i := range rows {
 		rowInputs[i] = lvcs.RowInput{Head: rows[i]}
 	}
+	if err := checkCtx(ctx, "buildSimWith"); err != nil {
+		if t != nil {
+			t.Fatalf("%v", err)
+		}
+		return nil, false, false, false
+	}
 	commitInitStart := time.Now()
 	root, pk, oracleLayout, err := commitRows(ringQ, rowInputs, ell, decsParams, witnessRowCount, maskRowOffset, maskRowCount)
 	prof.Track(commitInitStart, "LVCS.CommitInit")
@@ -2172,7 +2245,8 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	oracleLayout.Witness = lvcs.LayoutSegment{Offset: 0, Count: witnessRowCount}
 	oracleLayout.Mask = lvcs.LayoutSegment{Offset: maskRowOffset, Count: maskRowCount}
 
-	proof := &Proof{Root: root, Lambda: o.Lambda, Theta: o.Theta, Kappa: o.Kappa, RowLayout: rowLayout}
+	paramsID := ComputeParamsID(ringQ, PublicInputs{}, nil, o, "")
+	proof := &Proof{Root: root, Lambda: o.Lambda, Theta: o.Theta, Kappa: o.Kappa, RowLayout: rowLayout, ParamsID: paramsID}
 	proof.MaskRowOffset = maskRowOffset
 	proof.MaskRowCount = maskRowCount
 	proof.MaskDegreeBound = maskDegreeBound
@@ -2185,7 +2259,7 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	// choose path based on Theta; Theta>1 delegates to runMaskFS
 	baseXOF := NewShake256XOF(64)
 	salt := make([]byte, 32)
-	if _, err := cryptoRand.Read(salt); err != nil {
+	if _, err := io.ReadFull(o.coinSource(), salt); err != nil {
 		if t != nil {
 			t.Fatalf("rand salt: %v", err)
 		} else {
@@ -2193,9 +2267,10 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		}
 	}
 	proof.Salt = append([]byte(nil), salt...)
-	fs := NewFS(baseXOF, salt, FSParams{Lambda: o.Lambda, Kappa: o.Kappa})
+	fs := NewFS(baseXOF, salt, FSParams{Lambda: o.Lambda, Kappa: o.Kappa, Workers: o.GrindWorkers})
 
 	var (
+		grindAttempts   [4]uint64
 		Gamma           [][]uint64
 		gammaBytes      []byte
 		GammaPrime      [][]uint64
@@ -2221,7 +2296,6 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		maskDegreeMax   int
 		evalReqs        []lvcs.EvalRequest
 		Rpolys          []*ring.Poly
-		openMask        *lvcs.Opening
 		openTail        *lvcs.Opening
 		combinedOpen    *decs.DECSOpening
 	)
@@ -2242,6 +2316,7 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 
 	if o.Theta > 1 {
 		argsFS := maskFSArgs{
+			ctx:               ctx,
 			ringQ:             ringQ,
 			omega:             omega,
 			q:                 q,
@@ -2291,16 +2366,20 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 			rowLayout:         rowLayout,
 			oracleLayout:      oracleLayout,
 			decsParams:        decsParams,
+			paramsID:          paramsID,
 		}
 		fsOut, err := runMaskFS(argsFS)
 		if err != nil {
 			if t != nil {
 				t.Fatalf("runMaskFS: %v", err)
+			} else if ctx.Err() != nil {
+				return nil, false, false, false
 			} else {
 				panic(fmt.Sprintf("runMaskFS: %v", err))
 			}
 		}
 		proof = fsOut.proof
+		grindAttempts = fsOut.grindAttempts
 		Gamma = fsOut.Gamma
 		gammaBytes = bytesFromUint64Matrix(Gamma)
 		GammaPrime = fsOut.GammaPrime
@@ -2329,11 +2408,9 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		smallFieldEvals = fsOut.smallFieldEvals
 		vTargets = fsOut.vTargets
 		E = append([]int(nil), fsOut.tailIndices...)
-		openMaskSaved = fsOut.openMask
 		openTailSaved = fsOut.openTail
 		combinedOpenSaved = fsOut.combinedOpen
 		evalReqs = fsOut.evalReqs
-		openMask = openMaskSaved
 		openTail = openTailSaved
 		combinedOpen = combinedOpenSaved
 		proof.FparNTT = polysToNTTMatrix(FparAll)
@@ -2352,7 +2429,13 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		}
 	} else {
 		// Original Theta==1 path unchanged
-		round1 := fsRound(fs, proof, 0, "Gamma", root[:])
+		round1, err := fsRound(ctx, fs, proof, 0, "Gamma", root[:], paramsID)
+		if err != nil {
+			if t != nil {
+				t.Fatalf("FS round 0: %v", err)
+			}
+			return nil, false, false, false
+		}
 		gammaRNG := round1.RNG
 		Gamma = sampleFSMatrix(o.Eta, len(rows), q, gammaRNG)
 		gammaBytes = bytesFromUint64Matrix(Gamma)
@@ -2378,7 +2461,13 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		totalParallel := len(FparAll)
 		totalAgg := len(FaggAll)
 		transcript2 := [][]byte{root[:], gammaBytes, polysToBytes(Rpolys)}
-		round2 := fsRound(fs, proof, 1, "GammaPrime", transcript2...)
+		round2, err := fsRound(ctx, fs, proof, 1, "GammaPrime", transcript2...)
+		if err != nil {
+			if t != nil {
+				t.Fatalf("FS round 1: %v", err)
+			}
+			return nil, false, false, false
+		}
 		seed2 := round2.Seed
 		gammaPrimeRNG := round2.RNG
 		gammaAggRNG := newFSRNG("GammaPrimeAgg", seed2, []byte{1})
@@ -2430,7 +2519,13 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 			polysToBytes(Q),
 		}
 		round3Label := "EvalPoints"
-		round3 := fsRound(fs, proof, 2, round3Label, transcript3...)
+		round3, err := fsRound(ctx, fs, proof, 2, round3Label, transcript3...)
+		if err != nil {
+			if t != nil {
+				t.Fatalf("FS round 2: %v", err)
+			}
+			return nil, false, false, false
+		}
 		seed3 := round3.Seed
 		evalPointRNG := round3.RNG
 		points = sampleDistinctFieldElemsAvoid(ellPrime, q, evalPointRNG, omega)
@@ -2463,11 +2558,6 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 
 	var verifyMaskOpen *decs.DECSOpening
 	if o.Theta > 1 {
-		maskIdx = make([]int, len(E))
-		for i := 0; i < len(E); i++ {
-			maskIdx[i] = ncols + i
-		}
-		openMask = openMaskSaved
 		openTail = openTailSaved
 		combinedOpen = combinedOpenSaved
 		verifyMaskOpen = cloneDECSOpening(proof.MOpening)
@@ -2487,7 +2577,14 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 			bytesFromUint64Matrix(barSets),
 			bytesFromUint64Matrix(vTargets),
 		}
-		round4 := fsRound(fs, proof, 3, "TailPoints", transcript4...)
+		round4, err := fsRound(ctx, fs, proof, 3, "TailPoints", transcript4...)
+		if err != nil {
+			if t != nil {
+				t.Fatalf("FS round 3: %v", err)
+			}
+			return nil, false, false, false
+		}
+		grindAttempts = fs.GrindAttempts()
 		tailStart := ncols + ell
 		tailLen := int(ringQ.N) - tailStart
 		if tailLen < ell {
@@ -2501,27 +2598,19 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		E = sampleDistinctIndices(tailStart, tailLen, ell, tailRNG)
 		proof.Tail = append([]int(nil), E...)
 
-		maskIdx = make([]int, ell)
-		for i := 0; i < ell; i++ {
-			maskIdx[i] = ncols + i
-		}
-		evalFinishStart := time.Now()
-		openMask = lvcs.EvalFinish(pk, maskIdx)
-		prof.Track(evalFinishStart, "LVCS.EvalFinish")
 		evalTailStart := time.Now()
 		openTail = lvcs.EvalFinish(pk, E)
 		prof.Track(evalTailStart, "LVCS.EvalFinish")
-		combinedOpen = combineOpenings(openMask.DECSOpen, openTail.DECSOpen)
+		combinedOpen = combineOpenings(nil, openTail.DECSOpen)
 		proof.RowOpening = cloneDECSOpening(combinedOpen)
 		// Pack row opening for compact serialization
 		decs.PackOpening(proof.RowOpening)
 
 		maskEval = evalPolySetAtIndices(ringQ, layoutMasks, E)
-		maskOpen := makeMaskTailOpening(E, maskEval)
+		maskOpen := makeMaskTailOpening(E, maskEval, q)
 		verifyMaskOpen = cloneDECSOpening(maskOpen)
 		proof.MOpening = cloneDECSOpening(maskOpen)
 		decs.PackOpening(proof.MOpening)
-		openMaskSaved = openMask
 		openTailSaved = openTail
 		combinedOpenSaved = combinedOpen
 
@@ -2541,9 +2630,8 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	}
 	// Persist eval-point openings for verifier-side recomputation if needed.
 	if combinedOpen != nil {
-		// Use the explicit mask indices and tail indices we sampled (maskIdx, E).
-		evalIdx := append([]int(nil), maskIdx...)
-		evalIdx = append(evalIdx, E...)
+		// Use the tail indices we sampled (E).
+		evalIdx := append([]int(nil), E...)
 		proof.EvalPoints = make([]uint64, len(evalIdx))
 		for i, v := range evalIdx {
 			proof.EvalPoints[i] = uint64(v)
@@ -2578,7 +2666,7 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	for i := range rndSource {
 		rndSourceCopy[i] = rndSource[i].CopyNew()
 	}
-	ctx := &simCtx{
+	sim := &simCtx{
 		ringQ:             ringQ,
 		q:                 q,
 		omega:             omega,
@@ -2627,8 +2715,6 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		vrf:               vrf,
 		pk:                pk,
 		vTargets:          vTargets,
-		maskIdx:           maskIdx,
-		maskOpen:          openMaskSaved,
 		tailOpen:          openTailSaved,
 		combinedOpen:      combinedOpenSaved,
 		proof:             proof,
@@ -2644,11 +2730,12 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 		msgSource:         msgSourceCopy,
 		rndSource:         rndSourceCopy,
 		rangeSpec:         rangeSpecVal,
+		grindAttempts:     grindAttempts,
 	}
 	if o.Theta > 1 {
-		ctx.chi = append([]uint64(nil), smallFieldChi...)
-		ctx.zeta = append([]uint64(nil), smallFieldOmegaS1.Limb...)
-		ctx.KField = smallFieldK
+		sim.chi = append([]uint64(nil), smallFieldChi...)
+		sim.zeta = append([]uint64(nil), smallFieldOmegaS1.Limb...)
+		sim.KField = smallFieldK
 	}
 
 	coeffMatch := true
@@ -2669,7 +2756,7 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	if o.Theta > 1 {
 		okEq4Omega = checkEq4OnOmegaK_QK(ringQ, smallFieldK, omega, QK, MK, FparAll, FaggAll, GammaPrimeK, GammaAggK)
 	} else {
-		okEq4Omega = checkEq4OnOpening(ringQ, Q, M, openMask, FparAll, FaggAll, GammaPrimePoly, GammaAgg, omega, points)
+		okEq4Omega = checkEq4OnOpening(ringQ, Q, M, nil, FparAll, FaggAll, GammaPrimePoly, GammaAgg, omega, points)
 	}
 	okEq4K := true
 	if o.Theta > 1 {
@@ -2696,9 +2783,9 @@ func buildSimWith(t *testing.T, o SimOpts) (*simCtx, bool, bool, bool) {
 	if smallFieldK != nil {
 		fieldSize = math.Pow(float64(q), float64(smallFieldK.Theta))
 	}
-	ctx.soundness = logSoundnessBudget(o, q, fieldSize, dQ, ncols, ell, ellPrime, o.Eta, leafCount, origW1Len)
+	sim.soundness = logSoundnessBudget(o, q, fieldSize, dQ, ncols, ell, ellPrime, o.Eta, leafCount, origW1Len)
 
-	return ctx, okLin, okEq4, okSum
+	return sim, okLin, okEq4, okSum
 }
 
 func columnsToRowsSmallField(r *ring.Ring,
@@ -2843,19 +2930,12 @@ func evalRowsAtKPoints(r *ring.Ring, K *kf.Field, polys []*ring.Poly, evals []kf
 			coeffs[i][j] %= q
 		}
 	}
+	vals := K.NewMultiEval(evals, r.N).EvalFMany(coeffs)
 	out := make([][]uint64, len(evals))
-	for idx, e := range evals {
+	for idx := range evals {
 		row := make([]uint64, len(polys)*theta)
-		offset := 0
 		for i := range polys {
-			val := K.Zero()
-			if coeffs[i] != nil {
-				val = K.EvalFPolyAtK(coeffs[i], e)
-			}
-			for limb := 0; limb < theta; limb++ {
-				row[offset+limb] = val.Limb[limb] % q
-			}
-			offset += theta
+			copy(row[i*theta:(i+1)*theta], vals[i][idx].Limb)
 		}
 		out[idx] = row
 	}
@@ -2946,8 +3026,11 @@ func buildKPointCoeffMatrix(
 // single K-point when the rows already correspond to witness polynomials.
 // This is a row-oriented alternative to buildKPointCoeffMatrix; it produces
 // deterministic coefficients derived from the K-point limbs so the verifier
-// can replay the same transcript.
-func buildKPointCoeffMatrixRows(r *ring.Ring, K *kf.Field, totalRows int, e kf.Elem) [][]uint64 {
+// can replay the same transcript. The rows from maskRowOffset on are the
+// credential mask rows, one per LVCS query: coordinate coord of query
+// `query` adds mask row maskRowOffset+query·θ+coord with coefficient 1, so
+// every target on Ω is masked by its own uniform head.
+func buildKPointCoeffMatrixRows(r *ring.Ring, K *kf.Field, totalRows, maskRowOffset, query int, e kf.Elem) [][]uint64 {
 	if r == nil || K == nil || totalRows <= 0 {
 		return nil
 	}
@@ -2958,9 +3041,12 @@ func buildKPointCoeffMatrixRows(r *ring.Ring, K *kf.Field, totalRows int, e kf.E
 		row := make([]uint64, totalRows)
 		base := e.Limb[coord] % q
 		step := uint64(coord + 1)
-		for j := 0; j < totalRows; j++ {
+		for j := 0; j < maskRowOffset && j < totalRows; j++ {
 			row[j] = (base + step*uint64(j+1)) % q
 		}
+		if mask := maskRowOffset + query*theta + coord; mask < totalRows {
+			row[mask] = 1
+		}
 		coeffs[coord] = row
 	}
 	return coeffs
@@ -3052,8 +3138,11 @@ func equalByteSlices(a, b []byte) bool {
 	return bytes.Equal(a, b)
 }
 
-func makeMaskTailOpening(indices []int, values [][]uint64) *decs.DECSOpening {
+func makeMaskTailOpening(indices []int, values [][]uint64, q uint64) *decs.DECSOpening {
 	open := &decs.DECSOpening{}
+	if w := decs.ResidueWidth(q); w != 20 {
+		open.ResidueBits = w
+	}
 	if len(indices) == 0 {
 		return open
 	}
@@ -3116,20 +3205,26 @@ func checkEq4AtK_K_QK(
 	if K == nil {
 		return false
 	}
+	me := K.NewMultiEval([]kf.Elem{e}, r.N)
 	coeff := r.NewPoly()
-	evalAtK := func(p *ring.Poly) kf.Elem {
-		if p == nil {
-			return K.Zero()
+	evalAll := func(polys []*ring.Poly) []kf.Elem {
+		out := make([]kf.Elem, len(polys))
+		for j, p := range polys {
+			if p == nil {
+				continue
+			}
+			r.InvNTT(p, coeff)
+			out[j] = me.EvalFAt(0, coeff.Coeffs[0])
 		}
-		r.InvNTT(p, coeff)
-		return K.EvalFPolyAtK(coeff.Coeffs[0], e)
+		return out
 	}
+	fparVals, faggVals := evalAll(Fpar), evalAll(Fagg)
 	for i := range QK {
 		if i >= len(MK) || QK[i] == nil || MK[i] == nil {
 			return false
 		}
-		lhs := evalKPolyAtK(K, QK[i], e)
-		rhs := evalKPolyAtK(K, MK[i], e)
+		lhs := evalKPolyMulti(me, 0, QK[i])
+		rhs := evalKPolyMulti(me, 0, MK[i])
 		if i < len(gammaK) {
 			row := gammaK[i]
 			for j := range Fpar {
@@ -3137,7 +3232,7 @@ func checkEq4AtK_K_QK(
 					continue
 				}
 				g := K.Phi(row[j])
-				rhs = K.Add(rhs, K.Mul(g, evalAtK(Fpar[j])))
+				rhs = K.Add(rhs, K.Mul(g, fparVals[j]))
 			}
 		}
 		if i < len(gammaAggK) {
@@ -3147,7 +3242,7 @@ func checkEq4AtK_K_QK(
 					continue
 				}
 				g := K.Phi(row[j])
-				rhs = K.Add(rhs, K.Mul(g, evalAtK(Fagg[j])))
+				rhs = K.Add(rhs, K.Mul(g, faggVals[j]))
 			}
 		}
 		if !elemEqual(K, lhs, rhs) {
@@ -3458,6 +3553,8 @@ func loadPublicTables(ringQ *ring.Ring) (A [][]*ring.Poly, b1, B0Const []*ring.P
 	ringQ.NTT(negHCoeff, negHCoeff)
 	A[0][0], A[0][1] = one, negHCoeff
 
+	// The plain signature statement hashes under the standalone
+	// Parameters/Bmatrix.json, not a pubparams bundle: no digest binds it.
 	rawB, err := ntrurio.LoadBMatrixCoeffs(resolve("Parameters/Bmatrix.json"))
 	if err != nil {
 		return nil, nil, nil, nil, nil, fmt.Errorf("load Bmatrix.json: %w", err)
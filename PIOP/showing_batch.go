@@ -0,0 +1,341 @@
+package PIOP
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// BuildShowingBatch proves k showings at once. The showings are merged side
+// by side on Ω into one showing statement over k·ncols points: showing s
+// keeps its own ncols points of every row (see batchSlot), and the
+// signature, hash, bound and PRF constraints are pointwise on Ω, so the
+// merged statement holds exactly when every showing does. The batch
+// therefore commits as many rows as a single showing and shares the row
+// openings, masks and Q polynomials, while the FS transcript binds all k
+// sets of publics (see BatchLayout). opts.NCols is the per-showing ncols;
+// k·opts.NCols must fit in the ring.
+func BuildShowingBatch(pubs []PublicInputs, wits []WitnessInputs, opts SimOpts) (*Proof, error) {
+	return BuildShowingBatchContext(context.Background(), pubs, wits, opts)
+}
+
+// BuildShowingBatchContext is BuildShowingBatch with cancellation; it returns
+// a *CanceledError once ctx is done.
+func BuildShowingBatchContext(ctx context.Context, pubs []PublicInputs, wits []WitnessInputs, opts SimOpts) (*Proof, error) {
+	if err := checkCtx(ctx, "BuildShowingBatch"); err != nil {
+		return nil, err
+	}
+	if len(pubs) == 0 {
+		return nil, fmt.Errorf("empty batch")
+	}
+	if len(pubs) != len(wits) {
+		return nil, fmt.Errorf("batch has %d publics but %d witnesses", len(pubs), len(wits))
+	}
+	for s := range pubs {
+		if len(pubs[s].A) == 0 || len(pubs[s].B) == 0 {
+			return nil, fmt.Errorf("showing %d: missing A/B for post-sign constraints", s)
+		}
+		if len(pubs[s].Tag) == 0 || len(pubs[s].Nonce) == 0 {
+			return nil, fmt.Errorf("showing %d: missing tag/nonce publics", s)
+		}
+		if err := checkBatchPublics(pubs[s]); err != nil {
+			return nil, fmt.Errorf("showing %d: %w", s, err)
+		}
+		if len(wits[s].T) == 0 || len(wits[s].U) == 0 {
+			return nil, fmt.Errorf("showing %d: missing T/U witness for post-sign constraints", s)
+		}
+		if err := pubs[s].Blocks.checkWitness(wits[s]); err != nil {
+			return nil, fmt.Errorf("showing %d: witness blocks: %w", s, err)
+		}
+	}
+	opts.applyDefaults()
+	ringQ, _, _, err := loadParamsAndOmega(opts)
+	if err != nil {
+		return nil, fmt.Errorf("load params: %w", err)
+	}
+	batchOpts, err := showingBatchOpts(ringQ, opts, len(pubs))
+	if err != nil {
+		return nil, err
+	}
+	pub, err := mergeBatchPublics(ringQ, pubs, opts.NCols)
+	if err != nil {
+		return nil, err
+	}
+	wit, err := mergeBatchWitnesses(ringQ, wits, opts.NCols)
+	if err != nil {
+		return nil, err
+	}
+	return buildShowingContext(ctx, pub, wit, batchOpts, &BatchLayout{Publics: pubs})
+}
+
+// VerifyShowingBatch verifies a proof produced by BuildShowingBatch against
+// the publics of every showing, in the order they were proven. opts is the
+// per-showing opts the batch was built with.
+func VerifyShowingBatch(proof *Proof, pubs []PublicInputs, opts SimOpts) (bool, error) {
+	return VerifyShowingBatchContext(context.Background(), proof, pubs, opts)
+}
+
+// VerifyShowingBatchContext is VerifyShowingBatch with cancellation. The
+// merged statement and its PRF layout are rebuilt from pubs; nothing about
+// the layout is taken from the proof.
+func VerifyShowingBatchContext(ctx context.Context, proof *Proof, pubs []PublicInputs, opts SimOpts) (bool, error) {
+	if proof == nil {
+		return false, fmt.Errorf("nil proof: %w", ErrMalformedProof)
+	}
+	if len(pubs) == 0 {
+		return false, fmt.Errorf("empty batch")
+	}
+	for s := range pubs {
+		if len(pubs[s].A) == 0 || len(pubs[s].Tag) == 0 {
+			return false, fmt.Errorf("showing %d: missing A/tag publics", s)
+		}
+		if err := checkBatchPublics(pubs[s]); err != nil {
+			return false, fmt.Errorf("showing %d: %w: %w", s, err, ErrMalformedProof)
+		}
+		if err := pubs[s].Blocks.checkPublics(pubs[s]); err != nil {
+			return false, fmt.Errorf("showing %d: %w: %w", s, err, ErrMalformedProof)
+		}
+	}
+	opts.applyDefaults()
+	ringQ, _, _, err := loadParamsAndOmega(opts)
+	if err != nil {
+		return false, fmt.Errorf("load params: %w", err)
+	}
+	batchOpts, err := showingBatchOpts(ringQ, opts, len(pubs))
+	if err != nil {
+		return false, err
+	}
+	pub, err := mergeBatchPublics(ringQ, pubs, opts.NCols)
+	if err != nil {
+		return false, err
+	}
+	layout, err := ShowingLayout(pub)
+	if err != nil {
+		return false, err
+	}
+	set := ConstraintSet{
+		PRFLayout: layout,
+		Batch:     &BatchLayout{Publics: pubs},
+	}
+	batchOpts.Credential = true
+	return VerifyWithConstraintsContext(ctx, proof, set, pub, batchOpts, FSModeCredential)
+}
+
+// checkBatchPublics rejects the showing options a batch does not carry.
+func checkBatchPublics(pub PublicInputs) error {
+	if !pub.Policy.empty() || pub.bindsKey() || len(pub.Com) > 0 || !pub.NonceDomain.empty() {
+		return fmt.Errorf("batch showings take no policy, key binding, commitment or nonce domain")
+	}
+	return nil
+}
+
+// showingBatchOpts returns opts for the merged statement of k showings:
+// NCols grows to k·opts.NCols and everything else is unchanged.
+func showingBatchOpts(ringQ *ring.Ring, opts SimOpts, k int) (SimOpts, error) {
+	if opts.NCols%2 != 0 {
+		return opts, fmt.Errorf("ncols %d is not even for packing", opts.NCols)
+	}
+	if k*opts.NCols > ringQ.N {
+		return opts, fmt.Errorf("batch of %d showings needs %d points of Ω, ring has %d", k, k*opts.NCols, ringQ.N)
+	}
+	opts.NCols *= k
+	return opts, nil
+}
+
+// batchSlot is the point of Ω that carries point j of showing s in a batch
+// of k showings over ncols points each. The lower halves of every showing
+// come first, so the M1/M2 packing of the merged rows is again the split of
+// the merged Ω into halves.
+func batchSlot(s, j, k, ncols int) int {
+	h := ncols / 2
+	if j < h {
+		return s*h + j
+	}
+	return k*h + s*h + j - h
+}
+
+// mergeBatchNTT merges the values on Ω of one NTT poly per showing; points
+// past the merged Ω are zero.
+func mergeBatchNTT(ringQ *ring.Ring, polys []*ring.Poly, ncols int) *ring.Poly {
+	out := ringQ.NewPoly()
+	for s, p := range polys {
+		for j := 0; j < ncols; j++ {
+			out.Coeffs[0][batchSlot(s, j, len(polys), ncols)] = p.Coeffs[0][j]
+		}
+	}
+	return out
+}
+
+// mergeBatchCoeffs is mergeBatchNTT for coefficient-domain polys.
+func mergeBatchCoeffs(ringQ *ring.Ring, polys []*ring.Poly, ncols int) *ring.Poly {
+	ntt := make([]*ring.Poly, len(polys))
+	for s, p := range polys {
+		ntt[s] = ringQ.NewPoly()
+		ringQ.NTT(p, ntt[s])
+	}
+	out := mergeBatchNTT(ringQ, ntt, ncols)
+	ringQ.InvNTT(out, out)
+	return out
+}
+
+// mergeBatchLanes merges per-showing lanes on Ω (tags and nonces).
+func mergeBatchLanes(lanes [][][]int64, ncols int) ([][]int64, error) {
+	k := len(lanes)
+	out := make([][]int64, len(lanes[0]))
+	for i := range out {
+		out[i] = make([]int64, k*ncols)
+		for s := range lanes {
+			if len(lanes[s]) != len(out) {
+				return nil, fmt.Errorf("showing %d has %d lanes, want %d", s, len(lanes[s]), len(out))
+			}
+			if len(lanes[s][i]) < ncols {
+				return nil, fmt.Errorf("showing %d: lane %d len=%d < ncols=%d", s, i, len(lanes[s][i]), ncols)
+			}
+			for j := 0; j < ncols; j++ {
+				out[i][batchSlot(s, j, k, ncols)] = lanes[s][i][j]
+			}
+		}
+	}
+	return out, nil
+}
+
+// mergeBatchRows merges row r of every showing, for every r. merge is
+// mergeBatchNTT or mergeBatchCoeffs.
+func mergeBatchRows(ringQ *ring.Ring, rows [][]*ring.Poly, ncols int, name string, merge func(*ring.Ring, []*ring.Poly, int) *ring.Poly) ([]*ring.Poly, error) {
+	out := make([]*ring.Poly, len(rows[0]))
+	col := make([]*ring.Poly, len(rows))
+	for r := range out {
+		for s := range rows {
+			if len(rows[s]) != len(out) {
+				return nil, fmt.Errorf("showing %d has %d %s rows, want %d", s, len(rows[s]), name, len(out))
+			}
+			col[s] = rows[s][r]
+		}
+		out[r] = merge(ringQ, col, ncols)
+	}
+	return out, nil
+}
+
+// mergeBatchPublics merges the publics of a batch into the publics of one
+// showing over k·ncols points. Every showing must share BoundB, Blocks and
+// ParamsDigest.
+func mergeBatchPublics(ringQ *ring.Ring, pubs []PublicInputs, ncols int) (PublicInputs, error) {
+	first := pubs[0]
+	var (
+		as     = make([][]*ring.Poly, len(pubs))
+		bs     = make([][]*ring.Poly, len(pubs))
+		tags   = make([][][]int64, len(pubs))
+		nonces = make([][][]int64, len(pubs))
+	)
+	for s, pub := range pubs {
+		if pub.BoundB != first.BoundB || pub.Blocks != first.Blocks || !bytes.Equal(pub.ParamsDigest, first.ParamsDigest) {
+			return PublicInputs{}, fmt.Errorf("showing %d: bound, blocks or parameters differ from showing 0", s)
+		}
+		if len(pub.A) != len(first.A) {
+			return PublicInputs{}, fmt.Errorf("showing %d has %d rows of A, want %d", s, len(pub.A), len(first.A))
+		}
+		for _, row := range pub.A {
+			as[s] = append(as[s], row...)
+		}
+		bs[s], tags[s], nonces[s] = pub.B, pub.Tag, pub.Nonce
+	}
+	merged := PublicInputs{
+		BoundB:       first.BoundB,
+		Blocks:       first.Blocks,
+		ParamsDigest: first.ParamsDigest,
+	}
+	flatA, err := mergeBatchRows(ringQ, as, ncols, "A", mergeBatchNTT)
+	if err != nil {
+		return PublicInputs{}, err
+	}
+	cols := len(first.A[0])
+	for i := range first.A {
+		if len(first.A[i]) != cols {
+			return PublicInputs{}, fmt.Errorf("ragged A")
+		}
+		merged.A = append(merged.A, flatA[i*cols:(i+1)*cols])
+	}
+	if merged.B, err = mergeBatchRows(ringQ, bs, ncols, "B", mergeBatchNTT); err != nil {
+		return PublicInputs{}, err
+	}
+	if merged.Tag, err = mergeBatchLanes(tags, ncols); err != nil {
+		return PublicInputs{}, fmt.Errorf("tag: %w", err)
+	}
+	if merged.Nonce, err = mergeBatchLanes(nonces, ncols); err != nil {
+		return PublicInputs{}, fmt.Errorf("nonce: %w", err)
+	}
+	return merged, nil
+}
+
+// mergeBatchWitnesses merges the witnesses of a batch into the witness of
+// one showing over k·ncols points, matching mergeBatchPublics.
+func mergeBatchWitnesses(ringQ *ring.Ring, wits []WitnessInputs, ncols int) (WitnessInputs, error) {
+	k := len(wits)
+	blocks := func(get func(WitnessInputs) []*ring.Poly) [][]*ring.Poly {
+		out := make([][]*ring.Poly, k)
+		for s := range wits {
+			out[s] = get(wits[s])
+		}
+		return out
+	}
+	var merged WitnessInputs
+	for _, f := range []struct {
+		name string
+		dst  *[]*ring.Poly
+		get  func(WitnessInputs) []*ring.Poly
+	}{
+		{"M1", &merged.M1, func(w WitnessInputs) []*ring.Poly { return w.M1 }},
+		{"M2", &merged.M2, func(w WitnessInputs) []*ring.Poly { return w.M2 }},
+		{"RU0", &merged.RU0, func(w WitnessInputs) []*ring.Poly { return w.RU0 }},
+		{"RU1", &merged.RU1, func(w WitnessInputs) []*ring.Poly { return w.RU1 }},
+		{"R", &merged.R, func(w WitnessInputs) []*ring.Poly { return w.R }},
+		{"R0", &merged.R0, func(w WitnessInputs) []*ring.Poly { return w.R0 }},
+		{"R1", &merged.R1, func(w WitnessInputs) []*ring.Poly { return w.R1 }},
+		{"K0", &merged.K0, func(w WitnessInputs) []*ring.Poly { return w.K0 }},
+		{"K1", &merged.K1, func(w WitnessInputs) []*ring.Poly { return w.K1 }},
+		{"U", &merged.U, func(w WitnessInputs) []*ring.Poly { return w.U }},
+	} {
+		rows, err := mergeBatchRows(ringQ, blocks(f.get), ncols, f.name, mergeBatchCoeffs)
+		if err != nil {
+			return WitnessInputs{}, err
+		}
+		*f.dst = rows
+	}
+	// T is a coefficient vector: lift it, merge and read it back mod q.
+	q := int64(ringQ.Modulus[0])
+	tPolys := make([]*ring.Poly, k)
+	for s, w := range wits {
+		if len(w.T) > ringQ.N {
+			return WitnessInputs{}, fmt.Errorf("showing %d: t length %d exceeds ring dimension %d", s, len(w.T), ringQ.N)
+		}
+		tPolys[s] = ringQ.NewPoly()
+		for i, v := range w.T {
+			v %= q
+			if v < 0 {
+				v += q
+			}
+			tPolys[s].Coeffs[0][i] = uint64(v)
+		}
+	}
+	tPoly := mergeBatchCoeffs(ringQ, tPolys, ncols)
+	merged.T = make([]int64, ringQ.N)
+	for i, v := range tPoly.Coeffs[0] {
+		merged.T[i] = int64(v)
+	}
+	traces := make([][]*ring.Poly, k)
+	for s, w := range wits {
+		trace, ok := w.Extras["prf_trace"].([]*ring.Poly)
+		if !ok {
+			return WitnessInputs{}, fmt.Errorf("showing %d: missing prf_trace in witness Extras", s)
+		}
+		traces[s] = trace
+	}
+	trace, err := mergeBatchRows(ringQ, traces, ncols, "prf_trace", mergeBatchCoeffs)
+	if err != nil {
+		return WitnessInputs{}, err
+	}
+	merged.Extras = map[string]interface{}{"prf_trace": trace}
+	return merged, nil
+}
@@ -1,6 +1,7 @@
 package PIOP
 
 import (
+	"context"
 	"fmt"
 
 	"vSIS-Signature/prf"
@@ -10,9 +11,32 @@ import (
 
 // BuildShowingCombined constructs a showing statement with post-sign credential
 // constraints (signature/hash/bounds) and PRF constraints. It expects base rows
-// (M1,M2,RU0,RU1,R,R0,R1,K0,K1), a T row (wit.T), signature rows (wit.U), and
-// PRF trace rows in wit.Extras["prf_trace"]. Tag/Nonce must be provided in pub.
+// (M1,M2,RU0,RU1,R,R0,R1,K0,K1, with the block lengths of pub.Blocks), a T row
+// (wit.T), signature rows (wit.U), and PRF trace rows in
+// wit.Extras["prf_trace"]. pub must provide Tag and either Nonce or
+// NonceDomain. If pub.Policy is set, its disclosure and predicate residuals
+// follow the post-sign ones; if pub.Scope, pub.BindKey or pub.NonceDomain is
+// set, the key-binding residuals follow those; if pub.Com is set (with pub.Ac), the
+// commit residuals of a re-bound showing follow, and RU0, RU1 and R must be
+// the issuance openings; if pub.NonceDomain is set, the nonce-domain
+// residuals of a hidden nonce come last.
 func BuildShowingCombined(pub PublicInputs, wit WitnessInputs, opts SimOpts) (*Proof, error) {
+	return BuildShowingCombinedContext(context.Background(), pub, wit, opts)
+}
+
+// BuildShowingCombinedContext is BuildShowingCombined with cancellation; it
+// returns a *CanceledError once ctx is done.
+func BuildShowingCombinedContext(ctx context.Context, pub PublicInputs, wit WitnessInputs, opts SimOpts) (*Proof, error) {
+	if err := checkCtx(ctx, "BuildShowingCombined"); err != nil {
+		return nil, err
+	}
+	return buildShowingContext(ctx, pub, wit, opts, nil)
+}
+
+// buildShowingContext proves the showing statement of pub. A non-nil batch
+// binds the publics of every showing merged into pub (see BuildShowingBatch)
+// in place of pub's own labels.
+func buildShowingContext(ctx context.Context, pub PublicInputs, wit WitnessInputs, opts SimOpts, batch *BatchLayout) (*Proof, error) {
 	opts.applyDefaults()
 	ringQ, _, _, err := loadParamsAndOmega(opts)
 	if err != nil {
@@ -34,14 +58,28 @@ func BuildShowingCombined(pub PublicInputs, wit WitnessInputs, opts SimOpts) (*P
 	if len(wit.U) == 0 {
 		return nil, fmt.Errorf("missing U witness for post-sign constraints")
 	}
-	if len(pub.Tag) == 0 || len(pub.Nonce) == 0 {
-		return nil, fmt.Errorf("missing tag/nonce publics")
+	if len(pub.Tag) == 0 {
+		return nil, fmt.Errorf("missing tag publics")
+	}
+	if hidden := !pub.NonceDomain.empty(); hidden == (len(pub.Nonce) > 0) {
+		return nil, fmt.Errorf("showing needs exactly one of a public nonce and a nonce domain")
+	}
+	if !pub.NonceDomain.empty() && pub.Scope != "" {
+		return nil, fmt.Errorf("pseudonym showing: the scope nonce is public, not a nonce domain")
+	}
+	if err := pub.Blocks.checkWitness(wit); err != nil {
+		return nil, fmt.Errorf("witness blocks: %w", err)
 	}
 	// Build rows/layout with showing builder.
 	rows, _, _, _, _, _, _, startIdx, ncols, err := BuildCredentialRowsShowing(ringQ, wit, params.LenKey, params.LenNonce, params.RF, params.RP, opts)
 	if err != nil {
 		return nil, fmt.Errorf("build showing rows: %w", err)
 	}
+	if pub.Scope != "" {
+		if err := checkScopeNonce(pub, params, ncols); err != nil {
+			return nil, err
+		}
+	}
 	// Build NTT rows for constraint construction.
 	rowsNTT := make([]*ring.Poly, len(rows))
 	for i := range rows {
@@ -54,13 +92,58 @@ func BuildShowingCombined(pub PublicInputs, wit WitnessInputs, opts SimOpts) (*P
 	if err != nil {
 		return nil, fmt.Errorf("build post-sign constraint set: %w", err)
 	}
+	// Policy constraints (disclosures, predicates).
+	fparInt := append([]*ring.Poly{}, postSet.FparInt...)
+	if !pub.Policy.empty() {
+		policy, err := NewPolicyConstraintConfig(ringQ, pub.Policy, pub.Blocks, pub.BoundB, ncols)
+		if err != nil {
+			return nil, err
+		}
+		polRes, err := policy.residuals(rowsNTT)
+		if err != nil {
+			return nil, err
+		}
+		fparInt = append(fparInt, polRes...)
+	}
+	// Key binding (pseudonym, rate-limited and hidden-nonce showings).
+	if pub.bindsKey() {
+		keys, err := NewKeyBindingConfig(ringQ, pub.Blocks, startIdx, params.LenKey, ncols)
+		if err != nil {
+			return nil, err
+		}
+		keyRes, err := keys.residuals(rowsNTT)
+		if err != nil {
+			return nil, err
+		}
+		fparInt = append(fparInt, keyRes...)
+	}
+	// Commitment (re-bound showings).
+	if pub.rebound() {
+		comRes, err := showingCommitResiduals(ringQ, pub, rowsNTT, ncols)
+		if err != nil {
+			return nil, err
+		}
+		fparInt = append(fparInt, comRes...)
+	}
+	// Nonce domain (hidden nonces).
+	if !pub.NonceDomain.empty() {
+		dom, err := NewNonceDomainConfig(ringQ, pub.NonceDomain, startIdx, params, pub.BoundB)
+		if err != nil {
+			return nil, err
+		}
+		domRes, err := dom.residuals(rowsNTT)
+		if err != nil {
+			return nil, err
+		}
+		fparInt = append(fparInt, domRes...)
+	}
 	// PRF constraints.
 	prfSet, err := BuildPRFConstraintSet(ringQ, params, rowsNTT, startIdx, pub.Tag, pub.Nonce, ncols)
 	if err != nil {
 		return nil, fmt.Errorf("build prf constraint set: %w", err)
 	}
 	set := ConstraintSet{
-		FparInt:  append(append([]*ring.Poly{}, postSet.FparInt...), prfSet.FparInt...),
+		FparInt:  append(fparInt, prfSet.FparInt...),
 		FparNorm: postSet.FparNorm,
 		FaggInt:  postSet.FaggInt,
 		FaggNorm: postSet.FaggNorm,
@@ -72,7 +155,29 @@ func BuildShowingCombined(pub PublicInputs, wit WitnessInputs, opts SimOpts) (*P
 			RP:       params.RP,
 			LenTag:   params.LenTag,
 		},
+		Batch: batch,
 	}
 	opts.Credential = true
-	return BuildWithConstraints(pub, wit, set, opts, FSModeCredential)
+	return BuildWithConstraintsContext(ctx, pub, wit, set, opts, FSModeCredential)
+}
+
+// ShowingLayout returns the PRF layout of a BuildShowingCombined proof for
+// pub: the trace starts after the rows M1..K1, T and U. Verifiers that know
+// the statement use it instead of the layout the proof carries.
+func ShowingLayout(pub PublicInputs) (*PRFLayout, error) {
+	if len(pub.A) == 0 || len(pub.A[0]) == 0 {
+		return nil, fmt.Errorf("missing A for showing layout")
+	}
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		return nil, fmt.Errorf("load prf params: %w", err)
+	}
+	return &PRFLayout{
+		StartIdx: pub.Blocks.Witness() + 1 + len(pub.A[0]),
+		LenKey:   params.LenKey,
+		LenNonce: params.LenNonce,
+		RF:       params.RF,
+		RP:       params.RP,
+		LenTag:   params.LenTag,
+	}, nil
 }
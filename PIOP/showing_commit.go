@@ -0,0 +1,98 @@
+package PIOP
+
+import (
+	"fmt"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// A re-bound showing (PublicInputs.Com set next to A) also proves the
+// issuance commitment
+//
+//	Com = Ac·[M1||M2||RU0||RU1||R]
+//
+// over its committed rows, so an issuer keeping a registry of the Com values
+// it signed can match a showing to its issuance. The commit residuals are
+// those of the pre-sign statement (BuildCommitConstraints, one per row of Ac)
+// and are replayed by a CredentialConstraintConfig that carries only Ac and
+// Com. RU0, RU1 and R join the bounded rows (postBoundRows) so the opening is
+// as short as at issuance. Com is public: a re-bound showing is linkable to
+// its issuance, and to every other showing re-bound to the same Com.
+
+// rebound reports whether pub is a re-bound showing.
+func (pub PublicInputs) rebound() bool {
+	return len(pub.Com) > 0 && len(pub.A) > 0
+}
+
+// thetaCommitPublics interpolates Ac and Com over Ω.
+func thetaCommitPublics(ringQ *ring.Ring, pub PublicInputs, ncols int) ([][]*ring.Poly, []*ring.Poly, error) {
+	if len(pub.Ac) == 0 {
+		return nil, nil, fmt.Errorf("re-bound showing: missing Ac")
+	}
+	if len(pub.Com) != len(pub.Ac) {
+		return nil, nil, fmt.Errorf("re-bound showing: Com has %d rows, Ac has %d", len(pub.Com), len(pub.Ac))
+	}
+	thetaAc := make([][]*ring.Poly, len(pub.Ac))
+	for i := range pub.Ac {
+		thetaAc[i] = make([]*ring.Poly, len(pub.Ac[i]))
+		for j := range pub.Ac[i] {
+			theta, err := thetaPolyFromNTT(ringQ, pub.Ac[i][j], ncols)
+			if err != nil {
+				return nil, nil, fmt.Errorf("theta Ac[%d][%d]: %w", i, j, err)
+			}
+			thetaAc[i][j] = theta
+		}
+	}
+	thetaCom := make([]*ring.Poly, len(pub.Com))
+	for i := range pub.Com {
+		theta, err := thetaPolyFromNTT(ringQ, pub.Com[i], ncols)
+		if err != nil {
+			return nil, nil, fmt.Errorf("theta Com[%d]: %w", i, err)
+		}
+		thetaCom[i] = theta
+	}
+	return thetaAc, thetaCom, nil
+}
+
+// showingCommitResiduals builds the commit residuals of a re-bound showing
+// from the committed rows (NTT).
+func showingCommitResiduals(ringQ *ring.Ring, pub PublicInputs, rowsNTT []*ring.Poly, ncols int) ([]*ring.Poly, error) {
+	thetaAc, thetaCom, err := thetaCommitPublics(ringQ, pub, ncols)
+	if err != nil {
+		return nil, err
+	}
+	cols := pub.Blocks.CommitCols()
+	if len(rowsNTT) < cols {
+		return nil, fmt.Errorf("re-bound showing: rows length %d < %d", len(rowsNTT), cols)
+	}
+	res, err := BuildCommitConstraints(ringQ, thetaAc, rowsNTT[:cols], thetaCom)
+	if err != nil {
+		return nil, fmt.Errorf("commit residuals: %w", err)
+	}
+	return res, nil
+}
+
+// newShowingCommitConfig returns the credential config whose evaluators
+// replay only the commit residuals of a re-bound showing: it has no bound,
+// hash key or packing selector, so CredentialEvaluator, CredentialKEvaluator
+// and Families reduce to the commit family.
+func newShowingCommitConfig(ringQ *ring.Ring, pub PublicInputs, thetaAc [][]*ring.Poly, thetaCom []*ring.Poly, omega []uint64) CredentialConstraintConfig {
+	idx := pub.Blocks.rowIdx()
+	return CredentialConstraintConfig{
+		Ring:   ringQ,
+		Ac:     thetaAc,
+		Com:    thetaCom,
+		IdxM1:  idx.M1,
+		IdxM2:  idx.M2,
+		IdxRU0: idx.RU0,
+		IdxRU1: idx.RU1,
+		IdxR:   idx.R,
+		IdxR0:  idx.R0,
+		IdxR1:  idx.R1,
+		IdxK0:  idx.K0,
+		IdxK1:  idx.K1,
+		IdxT:   -1,
+		Blocks: pub.Blocks,
+		Omega:  omega,
+	}
+}
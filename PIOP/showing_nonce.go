@@ -0,0 +1,186 @@
+package PIOP
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	kf "vSIS-Signature/internal/kfield"
+	"vSIS-Signature/prf"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// NonceDomain hides the nonce of a showing (PublicInputs.NonceDomain set,
+// Nonce empty): the nonce lanes x^(0)_{lenkey+j} stay witness rows and only
+// the tag is revealed. In place of the public nonce binding every lane j is
+// constrained to the window the verifier specifies, such as the current epoch
+// plus or minus the clock skew it tolerates:
+//
+//	P_{Radius_j}(x^(0)_{lenkey+j} − Center_j),
+//
+// with P_B the RangeMembershipSpec polynomial ∏_{i=-B}^{B}(X − i). The
+// showing also binds the PRF key to M2 (see showing_nym.go), without which a
+// holder could show under any key; with it a credential has at most
+// ∏_j (2·Radius_j+1) tags per domain. A lane with radius 0 is pinned to its
+// center.
+type NonceDomain struct {
+	Lanes []NonceWindow
+}
+
+// NonceWindow is the range [Center−Radius, Center+Radius] of one nonce lane.
+type NonceWindow struct {
+	Center int64
+	Radius int
+}
+
+func (d *NonceDomain) empty() bool {
+	return d == nil || len(d.Lanes) == 0
+}
+
+// Check validates the domain for lenNonce nonce lanes. Radii are at most the
+// bound B, which keeps the residual degree within that of the bounds.
+func (d *NonceDomain) Check(lenNonce int, bound int64) error {
+	if len(d.Lanes) != lenNonce {
+		return fmt.Errorf("nonce domain: %d lanes, want %d", len(d.Lanes), lenNonce)
+	}
+	for j, w := range d.Lanes {
+		if w.Radius < 0 || int64(w.Radius) > bound {
+			return fmt.Errorf("nonce domain: lane %d radius %d outside [0,%d]", j, w.Radius, bound)
+		}
+	}
+	return nil
+}
+
+// Contains reports whether nonce lies in the domain.
+func (d *NonceDomain) Contains(nonce []prf.Elem, q uint64) bool {
+	if len(nonce) != len(d.Lanes) {
+		return false
+	}
+	for j, w := range d.Lanes {
+		off := (uint64(nonce[j])%q + q - modQ(w.Center, q)) % q
+		r := uint64(w.Radius)
+		if off > r && off < q-r {
+			return false
+		}
+	}
+	return true
+}
+
+// label encodes the domain for the FS transcript; an empty domain has none.
+func (d *NonceDomain) label() []byte {
+	if d.empty() {
+		return nil
+	}
+	out := binary.LittleEndian.AppendUint64(nil, uint64(len(d.Lanes)))
+	for _, w := range d.Lanes {
+		out = binary.LittleEndian.AppendUint64(out, uint64(w.Center))
+		out = binary.LittleEndian.AppendUint64(out, uint64(w.Radius))
+	}
+	return out
+}
+
+func modQ(v int64, q uint64) uint64 {
+	v %= int64(q)
+	if v < 0 {
+		v += int64(q)
+	}
+	return uint64(v)
+}
+
+// NonceDomainConfig recomputes the nonce-domain residuals of a hidden-nonce
+// showing, one per nonce lane in lane order.
+type NonceDomainConfig struct {
+	Ring     *ring.Ring
+	StartIdx int // first PRF trace row, x^(0)_0
+	LenKey   int
+
+	centers []uint64
+	specs   []RangeMembershipSpec
+}
+
+// NewNonceDomainConfig checks dom and builds the window polynomials of the
+// nonce lanes of the PRF trace starting at row startIdx.
+func NewNonceDomainConfig(ringQ *ring.Ring, dom *NonceDomain, startIdx int, params *prf.Params, bound int64) (*NonceDomainConfig, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
+	}
+	if dom.empty() {
+		return nil, fmt.Errorf("empty nonce domain")
+	}
+	if err := dom.Check(params.LenNonce, bound); err != nil {
+		return nil, err
+	}
+	q := ringQ.Modulus[0]
+	cfg := &NonceDomainConfig{Ring: ringQ, StartIdx: startIdx, LenKey: params.LenKey}
+	for _, w := range dom.Lanes {
+		cfg.centers = append(cfg.centers, modQ(w.Center, q))
+		cfg.specs = append(cfg.specs, NewRangeMembershipSpec(q, w.Radius))
+	}
+	return cfg, nil
+}
+
+// residuals builds the nonce-domain F-polynomials from the committed rows (NTT).
+func (cfg NonceDomainConfig) residuals(rowsNTT []*ring.Poly) ([]*ring.Poly, error) {
+	r := cfg.Ring
+	q := r.Modulus[0]
+	out := make([]*ring.Poly, 0, len(cfg.specs))
+	for j, spec := range cfg.specs {
+		idx := cfg.StartIdx + cfg.LenKey + j
+		if idx >= len(rowsNTT) || rowsNTT[idx] == nil {
+			return nil, fmt.Errorf("nonce domain: missing PRF trace row %d", idx)
+		}
+		row := rowsNTT[idx].Coeffs[0]
+		res := r.NewPoly()
+		for k := range res.Coeffs[0] {
+			res.Coeffs[0][k] = EvalPoly(spec.Coeffs, (row[k]%q+q-cfg.centers[j])%q, q)
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// NonceDomainEvaluator returns the evaluator of the nonce-domain residuals at
+// eval points.
+func (cfg NonceDomainConfig) NonceDomainEvaluator() ConstraintEvaluator {
+	return func(evalIdx uint64, rows []uint64) ([]uint64, []uint64, error) {
+		q := cfg.Ring.Modulus[0]
+		fpar := make([]uint64, 0, len(cfg.specs))
+		for j, spec := range cfg.specs {
+			var row uint64
+			if idx := cfg.StartIdx + cfg.LenKey + j; idx < len(rows) {
+				row = rows[idx] % q
+			}
+			fpar = append(fpar, EvalPoly(spec.Coeffs, (row+q-cfg.centers[j])%q, q))
+		}
+		return fpar, nil, nil
+	}
+}
+
+// NonceDomainKEvaluator returns the K-point evaluator of the nonce-domain
+// residuals.
+func (cfg NonceDomainConfig) NonceDomainKEvaluator(K *kf.Field) (KConstraintEvaluator, error) {
+	if K == nil {
+		return nil, fmt.Errorf("nil K field")
+	}
+	return func(e kf.Elem, rows []kf.Elem) ([]kf.Elem, []kf.Elem, error) {
+		fpar := make([]kf.Elem, 0, len(cfg.specs))
+		for j, spec := range cfg.specs {
+			row := K.Zero()
+			if idx := cfg.StartIdx + cfg.LenKey + j; idx < len(rows) {
+				row = rows[idx]
+			}
+			x := K.Sub(row, K.EmbedF(cfg.centers[j]))
+			res := K.Zero()
+			for i := len(spec.Coeffs) - 1; i >= 0; i-- {
+				res = K.Add(K.Mul(res, x), K.EmbedF(spec.Coeffs[i]))
+			}
+			fpar = append(fpar, res)
+		}
+		return fpar, nil, nil
+	}, nil
+}
+
+// Families describes the residual layout of NonceDomainEvaluator.
+func (cfg NonceDomainConfig) Families() []ConstraintFamily {
+	return familyLayout([]string{"nonce-domain"}, []int{len(cfg.specs)})
+}
@@ -0,0 +1,243 @@
+package PIOP
+
+import (
+	"fmt"
+
+	lvcs "vSIS-Signature/LVCS"
+	kf "vSIS-Signature/internal/kfield"
+	"vSIS-Signature/prf"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// A pseudonym showing (PublicInputs.Scope set) proves Tag = PRF(key,
+// prf.ScopeNonce(Scope)) for the key held in the credential's M2: key lane i
+// is the M2 value on slot ncols/2 + i mod (ncols/2) of M2 row i / (ncols/2).
+// Plain showings leave the PRF key free, so a holder could pick a new one per
+// showing; pseudonyms need it fixed. Key binding adds one residual per M2 row
+// carrying key lanes,
+//
+//	Σ_j S_j ⊙ (M2_r − x^(0)_{r·ncols/2+j}),
+//
+// with S_j the Θ-interpolated indicator of slot ncols/2 + j. The terms have
+// disjoint support on Ω, so the residual vanishes there iff every lane of the
+// first PRF state equals its M2 slot.
+
+// bindsKey reports whether the PRF key of pub's showing is bound to M2.
+func (pub PublicInputs) bindsKey() bool {
+	return pub.Scope != "" || pub.BindKey || !pub.NonceDomain.empty()
+}
+
+// KeySlots returns the number of PRF key lanes the M2 block of a credential
+// with the given blocks holds over ncols columns.
+func KeySlots(blocks CredentialBlocks, ncols int) int {
+	return blocks.norm().M2 * (ncols / 2)
+}
+
+// PRFKeyFromM2 reads the PRF key of a pseudonym showing from M2 rows given
+// in coefficient form (see KeySlots for the layout).
+func PRFKeyFromM2(ringQ *ring.Ring, m2 []*ring.Poly, ncols, lenKey int) ([]prf.Elem, error) {
+	half := ncols / 2
+	if half <= 0 || len(m2)*half < lenKey {
+		return nil, fmt.Errorf("m2 holds %d key slots over ncols=%d, want %d", len(m2)*half, ncols, lenKey)
+	}
+	key := make([]prf.Elem, 0, lenKey)
+	for _, p := range m2 {
+		pNTT := ringQ.NewPoly()
+		ring.Copy(p, pNTT)
+		ringQ.NTT(pNTT, pNTT)
+		for j := 0; j < half && len(key) < lenKey; j++ {
+			key = append(key, prf.Elem(pNTT.Coeffs[0][half+j]))
+		}
+	}
+	return key, nil
+}
+
+// scopeNonceLanes returns the nonce lanes of a pseudonym showing for scope.
+func scopeNonceLanes(scope string, params *prf.Params, ncols int) ([][]int64, error) {
+	nonce, err := prf.ScopeNonce(scope, params)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]int64, len(nonce))
+	for i, v := range nonce {
+		out[i] = make([]int64, ncols)
+		for j := range out[i] {
+			out[i][j] = int64(v)
+		}
+	}
+	return out, nil
+}
+
+// checkScopeNonce checks that pub.Nonce is the nonce of pub.Scope.
+func checkScopeNonce(pub PublicInputs, params *prf.Params, ncols int) error {
+	want, err := scopeNonceLanes(pub.Scope, params, ncols)
+	if err != nil {
+		return err
+	}
+	if len(pub.Nonce) != len(want) {
+		return fmt.Errorf("pseudonym showing: %d nonce lanes, want %d", len(pub.Nonce), len(want))
+	}
+	for i := range want {
+		if len(pub.Nonce[i]) < ncols {
+			return fmt.Errorf("pseudonym showing: nonce lane %d too short", i)
+		}
+		for j := 0; j < ncols; j++ {
+			if pub.Nonce[i][j] != want[i][j] {
+				return fmt.Errorf("pseudonym showing: nonce is not the scope nonce")
+			}
+		}
+	}
+	return nil
+}
+
+// keyTerm is the key-binding residual of M2 row Row.
+type keyTerm struct {
+	Row       int
+	Lanes     []int
+	Sels      []*ring.Poly // Θ of the slot indicators (NTT)
+	SelCoeffs [][]uint64
+}
+
+// KeyBindingConfig recomputes the key-binding residuals of a pseudonym
+// showing, one per M2 row holding key lanes in row order.
+type KeyBindingConfig struct {
+	Ring     *ring.Ring
+	IdxM2    int
+	StartIdx int // first PRF trace row, x^(0)_0
+	NCols    int
+
+	terms []keyTerm
+}
+
+// NewKeyBindingConfig interpolates the slot indicators binding the lenKey
+// key lanes of the PRF trace starting at row startIdx to M2.
+func NewKeyBindingConfig(ringQ *ring.Ring, blocks CredentialBlocks, startIdx, lenKey, ncols int) (*KeyBindingConfig, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
+	}
+	if ncols <= 0 || ncols%2 != 0 {
+		return nil, fmt.Errorf("key binding: ncols %d is not even", ncols)
+	}
+	if n := KeySlots(blocks, ncols); n < lenKey {
+		return nil, fmt.Errorf("key binding: M2 holds %d key slots over ncols=%d, want %d", n, ncols, lenKey)
+	}
+	half := ncols / 2
+	cfg := &KeyBindingConfig{Ring: ringQ, IdxM2: blocks.rowIdx().M2, StartIdx: startIdx, NCols: ncols}
+	sels := make([]*ring.Poly, half)
+	selCoeffs := make([][]uint64, half)
+	for j := range sels {
+		head := ringQ.NewPoly()
+		head.Coeffs[0][half+j] = 1
+		tp, err := thetaPolyFromNTT(ringQ, head, ncols)
+		if err != nil {
+			return nil, fmt.Errorf("theta key sel[%d]: %w", j, err)
+		}
+		tc, err := thetaCoeffFromNTT(ringQ, head, ncols)
+		if err != nil {
+			return nil, fmt.Errorf("theta key sel[%d]: %w", j, err)
+		}
+		sels[j], selCoeffs[j] = tp, tc
+	}
+	for lane := 0; lane < lenKey; lane += half {
+		n := half
+		if lenKey-lane < n {
+			n = lenKey - lane
+		}
+		t := keyTerm{Row: lane / half, Sels: sels[:n], SelCoeffs: selCoeffs[:n]}
+		for j := 0; j < n; j++ {
+			t.Lanes = append(t.Lanes, lane+j)
+		}
+		cfg.terms = append(cfg.terms, t)
+	}
+	return cfg, nil
+}
+
+// residuals builds the key-binding F-polynomials from the committed rows (NTT).
+func (cfg KeyBindingConfig) residuals(rowsNTT []*ring.Poly) ([]*ring.Poly, error) {
+	r := cfg.Ring
+	q := r.Modulus[0]
+	out := make([]*ring.Poly, 0, len(cfg.terms))
+	for _, t := range cfg.terms {
+		idx := cfg.IdxM2 + t.Row
+		if idx >= len(rowsNTT) || rowsNTT[idx] == nil {
+			return nil, fmt.Errorf("key binding: missing M2 row %d", idx)
+		}
+		if last := cfg.StartIdx + t.Lanes[len(t.Lanes)-1]; last >= len(rowsNTT) {
+			return nil, fmt.Errorf("key binding: missing PRF trace row %d", last)
+		}
+		row := rowsNTT[idx].Coeffs[0]
+		res := r.NewPoly()
+		for j, lane := range t.Lanes {
+			x := rowsNTT[cfg.StartIdx+lane].Coeffs[0]
+			sel := t.Sels[j].Coeffs[0]
+			for k := range res.Coeffs[0] {
+				d := (row[k]%q + q - x[k]%q) % q
+				res.Coeffs[0][k] = (res.Coeffs[0][k] + lvcs.MulModReduced(sel[k]%q, d, q)) % q
+			}
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// KeyBindingEvaluator returns the evaluator of the key-binding residuals at
+// eval points.
+func (cfg KeyBindingConfig) KeyBindingEvaluator() ConstraintEvaluator {
+	return func(evalIdx uint64, rows []uint64) ([]uint64, []uint64, error) {
+		q := cfg.Ring.Modulus[0]
+		ptIdx := int(evalIdx)
+		get := func(i int) uint64 {
+			if i < len(rows) {
+				return rows[i] % q
+			}
+			return 0
+		}
+		fpar := make([]uint64, 0, len(cfg.terms))
+		for _, t := range cfg.terms {
+			row := get(cfg.IdxM2 + t.Row)
+			acc := uint64(0)
+			for j, lane := range t.Lanes {
+				if ptIdx >= len(t.Sels[j].Coeffs[0]) {
+					return nil, nil, fmt.Errorf("key binding: eval index %d out of range", ptIdx)
+				}
+				d := (row + q - get(cfg.StartIdx+lane)) % q
+				acc = (acc + lvcs.MulModReduced(t.Sels[j].Coeffs[0][ptIdx]%q, d, q)) % q
+			}
+			fpar = append(fpar, acc)
+		}
+		return fpar, nil, nil
+	}
+}
+
+// KeyBindingKEvaluator returns the K-point evaluator of the key-binding
+// residuals.
+func (cfg KeyBindingConfig) KeyBindingKEvaluator(K *kf.Field) (KConstraintEvaluator, error) {
+	if K == nil {
+		return nil, fmt.Errorf("nil K field")
+	}
+	return func(e kf.Elem, rows []kf.Elem) ([]kf.Elem, []kf.Elem, error) {
+		get := func(i int) kf.Elem {
+			if i < len(rows) {
+				return rows[i]
+			}
+			return K.Zero()
+		}
+		fpar := make([]kf.Elem, 0, len(cfg.terms))
+		for _, t := range cfg.terms {
+			row := get(cfg.IdxM2 + t.Row)
+			acc := K.Zero()
+			for j, lane := range t.Lanes {
+				sel := K.EvalFPolyAtK(t.SelCoeffs[j], e)
+				acc = K.Add(acc, K.Mul(sel, K.Sub(row, get(cfg.StartIdx+lane))))
+			}
+			fpar = append(fpar, acc)
+		}
+		return fpar, nil, nil
+	}, nil
+}
+
+// Families describes the residual layout of KeyBindingEvaluator.
+func (cfg KeyBindingConfig) Families() []ConstraintFamily {
+	return familyLayout([]string{"prf-key"}, []int{len(cfg.terms)})
+}
@@ -0,0 +1,338 @@
+package PIOP
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	lvcs "vSIS-Signature/LVCS"
+	kf "vSIS-Signature/internal/kfield"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// ShowingPolicy asks a showing to disclose some credential attributes and to
+// prove predicates on others. Attributes are the M1 values on the lower half
+// of Ω: attribute a is slot a mod (ncols/2) of M1 row a / (ncols/2).
+//
+// Disclosures add one residual per M1 row with disclosed attributes,
+//
+//	D_i ⊙ M1_i − V_i,
+//
+// with D_i the Θ-interpolated indicator of the row's disclosed slots and V_i
+// their values; every predicate adds sel_a ⊙ ∏_{s∈S}(M1_i − s) with sel_a
+// the indicator of its slot. Both vanish on Ω iff the policy holds.
+type ShowingPolicy struct {
+	Disclosed  []DisclosedAttr
+	Predicates []AttrPredicate
+}
+
+// DisclosedAttr fixes attribute Attr to the public Value.
+type DisclosedAttr struct {
+	Attr  int
+	Value int64
+}
+
+// AttrPredicate requires attribute Attr to take one of the values in Set.
+// Set must be a proper subset of [-B, B]: the bounds already imply the full
+// range, and the subset keeps the residual degree below that of the bounds.
+type AttrPredicate struct {
+	Attr int
+	Set  []int64
+}
+
+// AttrCount returns the number of attributes of a credential with the given
+// blocks over ncols columns.
+func AttrCount(blocks CredentialBlocks, ncols int) int {
+	return blocks.norm().M1 * (ncols / 2)
+}
+
+// AttrValues reads the attributes of M1 rows given in coefficient form,
+// centred in (-q/2, q/2].
+func AttrValues(ringQ *ring.Ring, m1 []*ring.Poly, ncols int) []int64 {
+	q := int64(ringQ.Modulus[0])
+	half := ncols / 2
+	out := make([]int64, 0, len(m1)*half)
+	for _, p := range m1 {
+		pNTT := ringQ.NewPoly()
+		ring.Copy(p, pNTT)
+		ringQ.NTT(pNTT, pNTT)
+		for j := 0; j < half; j++ {
+			v := int64(pNTT.Coeffs[0][j])
+			if v > q/2 {
+				v -= q
+			}
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (p *ShowingPolicy) empty() bool {
+	return p == nil || len(p.Disclosed)+len(p.Predicates) == 0
+}
+
+// Check validates the policy against the attribute count of a credential
+// with the given blocks over ncols columns and the bound B.
+func (p *ShowingPolicy) Check(blocks CredentialBlocks, bound int64, ncols int) error {
+	if ncols <= 0 || ncols%2 != 0 {
+		return fmt.Errorf("policy: ncols %d is not even", ncols)
+	}
+	n := AttrCount(blocks, ncols)
+	inRange := func(v int64) bool { return v >= -bound && v <= bound }
+	seen := make(map[int]bool, len(p.Disclosed))
+	for _, d := range p.Disclosed {
+		if d.Attr < 0 || d.Attr >= n {
+			return fmt.Errorf("policy: disclosed attribute %d out of range [0,%d)", d.Attr, n)
+		}
+		if seen[d.Attr] {
+			return fmt.Errorf("policy: attribute %d disclosed twice", d.Attr)
+		}
+		seen[d.Attr] = true
+		if !inRange(d.Value) {
+			return fmt.Errorf("policy: disclosed value %d of attribute %d outside [-%d,%d]", d.Value, d.Attr, bound, bound)
+		}
+	}
+	for i, pr := range p.Predicates {
+		if pr.Attr < 0 || pr.Attr >= n {
+			return fmt.Errorf("policy: predicate %d: attribute %d out of range [0,%d)", i, pr.Attr, n)
+		}
+		if len(pr.Set) == 0 || int64(len(pr.Set)) > 2*bound {
+			return fmt.Errorf("policy: predicate %d: set of %d values, want 1..%d", i, len(pr.Set), 2*bound)
+		}
+		vals := make(map[int64]bool, len(pr.Set))
+		for _, v := range pr.Set {
+			if !inRange(v) {
+				return fmt.Errorf("policy: predicate %d: value %d outside [-%d,%d]", i, v, bound, bound)
+			}
+			if vals[v] {
+				return fmt.Errorf("policy: predicate %d: value %d repeated", i, v)
+			}
+			vals[v] = true
+		}
+	}
+	return nil
+}
+
+// label encodes the policy for the FS transcript; an empty policy has none.
+func (p *ShowingPolicy) label() []byte {
+	if p.empty() {
+		return nil
+	}
+	var out []byte
+	put := func(v int64) { out = binary.LittleEndian.AppendUint64(out, uint64(v)) }
+	put(int64(len(p.Disclosed)))
+	for _, d := range p.Disclosed {
+		put(int64(d.Attr))
+		put(d.Value)
+	}
+	put(int64(len(p.Predicates)))
+	for _, pr := range p.Predicates {
+		put(int64(pr.Attr))
+		put(int64(len(pr.Set)))
+		for _, v := range pr.Set {
+			put(v)
+		}
+	}
+	return out
+}
+
+// policyTerm is one residual: the disclosures of M1 row Row (Val set) or a
+// predicate on one of its slots (Set set).
+type policyTerm struct {
+	Row      int
+	Sel      *ring.Poly // Θ of the slot indicator (NTT)
+	Val      *ring.Poly // Θ of the disclosed values (NTT); nil for predicates
+	SelCoeff []uint64
+	ValCoeff []uint64
+	Set      []uint64
+}
+
+// PolicyConstraintConfig recomputes the disclosure and predicate residuals of
+// a ShowingPolicy from row evaluations. Disclosure residuals come first, one
+// per M1 row with a disclosed attribute in row order, then one residual per
+// predicate in policy order.
+type PolicyConstraintConfig struct {
+	Ring  *ring.Ring
+	IdxM1 int
+	NCols int
+
+	terms     []policyTerm
+	disclosed int
+}
+
+// NewPolicyConstraintConfig checks pol and interpolates its public Θ
+// polynomials over the first ncols slots of Ω.
+func NewPolicyConstraintConfig(ringQ *ring.Ring, pol *ShowingPolicy, blocks CredentialBlocks, bound int64, ncols int) (*PolicyConstraintConfig, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
+	}
+	if pol.empty() {
+		return nil, fmt.Errorf("empty showing policy")
+	}
+	if err := pol.Check(blocks, bound, ncols); err != nil {
+		return nil, err
+	}
+	q := ringQ.Modulus[0]
+	half := ncols / 2
+	mod := func(v int64) uint64 {
+		v %= int64(q)
+		if v < 0 {
+			v += int64(q)
+		}
+		return uint64(v)
+	}
+	theta := func(head *ring.Poly) (*ring.Poly, []uint64, error) {
+		tp, err := thetaPolyFromNTT(ringQ, head, ncols)
+		if err != nil {
+			return nil, nil, err
+		}
+		tc, err := thetaCoeffFromNTT(ringQ, head, ncols)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tp, tc, nil
+	}
+	cfg := &PolicyConstraintConfig{Ring: ringQ, IdxM1: blocks.rowIdx().M1, NCols: ncols}
+
+	rows := blocks.norm().M1
+	sels := make([]*ring.Poly, rows)
+	vals := make([]*ring.Poly, rows)
+	for _, d := range pol.Disclosed {
+		i := d.Attr / half
+		if sels[i] == nil {
+			sels[i], vals[i] = ringQ.NewPoly(), ringQ.NewPoly()
+		}
+		sels[i].Coeffs[0][d.Attr%half] = 1
+		vals[i].Coeffs[0][d.Attr%half] = mod(d.Value)
+	}
+	for i := range sels {
+		if sels[i] == nil {
+			continue
+		}
+		sel, selCoeff, err := theta(sels[i])
+		if err != nil {
+			return nil, fmt.Errorf("theta disclose sel[%d]: %w", i, err)
+		}
+		val, valCoeff, err := theta(vals[i])
+		if err != nil {
+			return nil, fmt.Errorf("theta disclose val[%d]: %w", i, err)
+		}
+		cfg.terms = append(cfg.terms, policyTerm{Row: i, Sel: sel, Val: val, SelCoeff: selCoeff, ValCoeff: valCoeff})
+	}
+	cfg.disclosed = len(cfg.terms)
+	for k, pr := range pol.Predicates {
+		head := ringQ.NewPoly()
+		head.Coeffs[0][pr.Attr%half] = 1
+		sel, selCoeff, err := theta(head)
+		if err != nil {
+			return nil, fmt.Errorf("theta predicate sel[%d]: %w", k, err)
+		}
+		set := make([]uint64, len(pr.Set))
+		for j, v := range pr.Set {
+			set[j] = mod(v)
+		}
+		cfg.terms = append(cfg.terms, policyTerm{Row: pr.Attr / half, Sel: sel, SelCoeff: selCoeff, Set: set})
+	}
+	return cfg, nil
+}
+
+// residuals builds the policy F-polynomials from the committed rows (NTT).
+func (cfg PolicyConstraintConfig) residuals(rowsNTT []*ring.Poly) ([]*ring.Poly, error) {
+	r := cfg.Ring
+	q := r.Modulus[0]
+	out := make([]*ring.Poly, 0, len(cfg.terms))
+	for _, t := range cfg.terms {
+		idx := cfg.IdxM1 + t.Row
+		if idx >= len(rowsNTT) || rowsNTT[idx] == nil {
+			return nil, fmt.Errorf("policy: missing M1 row %d", idx)
+		}
+		row := rowsNTT[idx].Coeffs[0]
+		res := r.NewPoly()
+		for k := range res.Coeffs[0] {
+			sel := t.Sel.Coeffs[0][k]
+			if t.Val != nil {
+				v := lvcs.MulModReduced(sel, row[k]%q, q)
+				res.Coeffs[0][k] = (v + q - t.Val.Coeffs[0][k]%q) % q
+				continue
+			}
+			res.Coeffs[0][k] = lvcs.MulModReduced(sel, setPoly(row[k]%q, t.Set, q), q)
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// setPoly evaluates ∏_{s∈set}(x − s) mod q.
+func setPoly(x uint64, set []uint64, q uint64) uint64 {
+	res := uint64(1 % q)
+	for _, s := range set {
+		res = lvcs.MulModReduced(res, (x+q-s)%q, q)
+	}
+	return res
+}
+
+// setPolyK is setPoly over K.
+func setPolyK(K *kf.Field, x kf.Elem, set []uint64) kf.Elem {
+	res := K.One()
+	for _, s := range set {
+		res = K.Mul(res, K.Sub(x, K.EmbedF(s)))
+	}
+	return res
+}
+
+// PolicyEvaluator returns the evaluator of the policy residuals at eval points.
+func (cfg PolicyConstraintConfig) PolicyEvaluator() ConstraintEvaluator {
+	return func(evalIdx uint64, rows []uint64) ([]uint64, []uint64, error) {
+		q := cfg.Ring.Modulus[0]
+		ptIdx := int(evalIdx)
+		fpar := make([]uint64, 0, len(cfg.terms))
+		for _, t := range cfg.terms {
+			if ptIdx >= len(t.Sel.Coeffs[0]) {
+				return nil, nil, fmt.Errorf("policy: eval index %d out of range", ptIdx)
+			}
+			var row uint64
+			if idx := cfg.IdxM1 + t.Row; idx < len(rows) {
+				row = rows[idx] % q
+			}
+			sel := t.Sel.Coeffs[0][ptIdx] % q
+			if t.Val != nil {
+				v := lvcs.MulModReduced(sel, row, q)
+				fpar = append(fpar, (v+q-t.Val.Coeffs[0][ptIdx]%q)%q)
+				continue
+			}
+			fpar = append(fpar, lvcs.MulModReduced(sel, setPoly(row, t.Set, q), q))
+		}
+		return fpar, nil, nil
+	}
+}
+
+// PolicyKEvaluator returns the K-point evaluator of the policy residuals.
+func (cfg PolicyConstraintConfig) PolicyKEvaluator(K *kf.Field) (KConstraintEvaluator, error) {
+	if K == nil {
+		return nil, fmt.Errorf("nil K field")
+	}
+	return func(e kf.Elem, rows []kf.Elem) ([]kf.Elem, []kf.Elem, error) {
+		fpar := make([]kf.Elem, 0, len(cfg.terms))
+		for _, t := range cfg.terms {
+			row := K.Zero()
+			if idx := cfg.IdxM1 + t.Row; idx < len(rows) {
+				row = rows[idx]
+			}
+			sel := K.EvalFPolyAtK(t.SelCoeff, e)
+			if t.Val != nil {
+				fpar = append(fpar, K.Sub(K.Mul(sel, row), K.EvalFPolyAtK(t.ValCoeff, e)))
+				continue
+			}
+			fpar = append(fpar, K.Mul(sel, setPolyK(K, row, t.Set)))
+		}
+		return fpar, nil, nil
+	}, nil
+}
+
+// Families describes the residual layout of PolicyEvaluator.
+func (cfg PolicyConstraintConfig) Families() []ConstraintFamily {
+	return familyLayout(
+		[]string{"disclose", "predicate"},
+		[]int{cfg.disclosed, len(cfg.terms) - cfg.disclosed},
+	)
+}
@@ -0,0 +1,250 @@
+package PIOP
+
+import (
+	"fmt"
+	"math"
+
+	decs "vSIS-Signature/DECS"
+)
+
+// Fixed transcript material of the masked FS flow (runMaskFS).
+const (
+	sizeModelSaltBytes   = 32 // FS salt
+	sizeModelDigestBytes = 64 // SHAKE-256 output per grinding round
+	sizeModelNonceBytes  = 16 // DECS nonce seed (decs.Params.NonceBytes)
+)
+
+// EstimateProofSize predicts MeasureProofSize from parameters alone.
+// rowCount is the number of committed witness rows (Proof.MaskRowOffset, all
+// blocks of a batch) and witnessCols the packed witness columns s
+// (opts.NCols). The ring is opts.Ring, or Parameters/Parameters.json when
+// nil; its N is the Merkle leaf count and its modulus fixes the packed width
+// of the evaluation matrices. No transmitted part depends on the constraint
+// degrees (Q is replayed rather than sent), so they are not an input.
+//
+// opts.Credential selects the layout: credential proofs (BuildWithConstraints
+// and the showing builders) exist only for θ>1, while RunOnce proofs (θ=1 or
+// θ>1) also carry the opened evaluations at the mask and tail indices.
+//
+// Every part is exact except the Merkle frontier of RowOpening and, for θ>1,
+// the varint-coded tail indices of MOpening: both depend on the FS-sampled
+// tail E. Total uses their expectation and StdDev their standard deviation
+// over E. The frontier dominates StdDev: about 2.4 nodes at ℓ = 4, with a
+// heavy lower tail when E falls next to the mask leaves and the paths merge
+// early.
+func EstimateProofSize(opts SimOpts, rowCount, witnessCols int) (ProofSizeReport, error) {
+	shape := StatementShape{Rows: rowCount}
+	if opts.Ring != nil {
+		shape.Q, shape.RingN = opts.Ring.Modulus[0], opts.Ring.N
+	}
+	if witnessCols <= 0 {
+		return ProofSizeReport{}, fmt.Errorf("need witnessCols>0, got %d", witnessCols)
+	}
+	opts.NCols = witnessCols
+	return estimateShapeSize(opts, shape)
+}
+
+// estimateShapeSize is EstimateProofSize over a StatementShape.
+func estimateShapeSize(opts SimOpts, shape StatementShape) (ProofSizeReport, error) {
+	shape, err := shape.withDefaults()
+	if err != nil {
+		return ProofSizeReport{}, err
+	}
+	opts.applyDefaults()
+	if opts.Credential && opts.Theta < 2 {
+		return ProofSizeReport{}, fmt.Errorf("credential proofs use the masked FS layout (theta>1), got theta=%d", opts.Theta)
+	}
+	if shape.Rows <= 0 || opts.Ell <= 0 {
+		return ProofSizeReport{}, fmt.Errorf("need rows>0 and ell>0 (rows=%d ell=%d)", shape.Rows, opts.Ell)
+	}
+	ncols, ell, ellPrime, theta := opts.NCols, opts.Ell, opts.EllPrime, opts.Theta
+	tailStart := ncols + ell
+	if shape.RingN-tailStart < ell {
+		return ProofSizeReport{}, fmt.Errorf("insufficient tail: N=%d ncols=%d ell=%d", shape.RingN, ncols, ell)
+	}
+	maxResidue := shape.Q - 1
+	// Witness rows plus the mask rows: ρ for RunOnce, one per LVCS query
+	// (θ·ℓ′) for credential proofs.
+	committed := shape.Rows + opts.Rho
+	if opts.Credential {
+		committed = shape.Rows + theta*ellPrime
+	}
+
+	parts := map[string]int{
+		"Salt":           sizeModelSaltBytes,
+		"Root":           16,
+		"Ctr":            4 * 8,
+		"Digests":        4 * sizeModelDigestBytes,
+		"EvalPoints":     0,
+		"PvalsEvalBits":  0,
+		"MvalsEvalBits":  0,
+		"MaskEvalBits":   0,
+		"Chi":            0,
+		"Zeta":           0,
+		"TailIndices":    ell * 4,
+		"VTargets":       decs.PackedUintMatrixSize(ellPrime*theta, ncols, maxResidue),
+		"BarSets":        decs.PackedUintMatrixSize(ellPrime*theta, ell, maxResidue),
+		"PvalsKEvalBits": 0,
+	}
+	if theta > 1 {
+		parts["Chi"] = (theta + 1) * 8 // monic irreducible χ of degree θ
+		parts["Zeta"] = theta * 8
+	}
+	if opts.Credential {
+		parts["PvalsKEvalBits"] = decs.PackedUintMatrixSize(ellPrime, shape.Rows*theta, maxResidue)
+	} else {
+		// RunOnce keeps the raw evaluations at the ℓ tail indices.
+		parts["EvalPoints"] = ell * 8
+		parts["PvalsEvalBits"] = ell * committed * 8
+		parts["MvalsEvalBits"] = ell * opts.Eta * 8
+		parts["MaskEvalBits"] = opts.Rho * ell * 8
+	}
+
+	// MOpening: the tail indices and the ρ mask values at E, packed to 13-bit
+	// indices and 20-bit residues for θ=1 and sent as varints and words above.
+	variance := 0.0
+	if theta > 1 {
+		mean, sq := 0.0, 0.0
+		for idx := tailStart; idx < shape.RingN; idx++ {
+			v := float64(varintSize(idx))
+			mean += v
+			sq += v * v
+		}
+		tailLen := float64(shape.RingN - tailStart)
+		mean /= tailLen
+		parts["MOpening"] = int(math.Round(mean*float64(ell))) + ell*opts.Rho*8
+		// ℓ draws without replacement from the tail's varint sizes.
+		if tailLen > 1 {
+			variance += float64(ell) * (sq/tailLen - mean*mean) * (tailLen - float64(ell)) / (tailLen - 1)
+		}
+	} else {
+		parts["MOpening"] = decs.PackedIndexSize(ell) + varintSize(ell) + decs.PackedUint20Size(ell*opts.Rho)
+	}
+
+	// RowOpening: the tail E, each entry carrying the committed rows and η
+	// DECS masks.
+	depth := merkleDepth(shape.RingN)
+	frontierMean, frontierVar := frontierNodeMoments(shape.RingN, ncols, ell)
+	frontier := int(math.Round(frontierMean))
+	row := decs.PackedIndexSize(ell) + varintSize(ell)
+	row += decs.PackedUint20Size(ell * committed)
+	row += decs.PackedUint20Size(ell * opts.Eta)
+	row += frontierBytes(frontier)
+	row += 2 * ((ell*depth + 7) / 8) // FrontierProof and FrontierLR bitmaps
+	row += 4                         // FrontierDepth
+	row += sizeModelNonceBytes + varintSize(sizeModelNonceBytes)
+	parts["RowOpening"] = row
+	// The varints and the frontier both follow E; their covariance is
+	// negligible next to the frontier's own variance and is left out. A
+	// frontier node costs its hash plus the growth of the packed references,
+	// taken as the slope of frontierBytes over one standard deviation.
+	span := int(math.Max(1, math.Round(math.Sqrt(frontierVar))))
+	lo := frontier - span
+	if lo < 0 {
+		lo = 0
+	}
+	nodeBytes := float64(frontierBytes(frontier+span)-frontierBytes(lo)) / float64(frontier+span-lo)
+	variance += frontierVar * nodeBytes * nodeBytes
+
+	total := 0
+	for _, v := range parts {
+		total += v
+	}
+	return ProofSizeReport{Total: total, Parts: parts, StdDev: math.Sqrt(variance)}, nil
+}
+
+// frontierBytes is the RowOpening share of a frontier of n nodes: the
+// hashes and their packed reference table.
+func frontierBytes(n int) int {
+	size := n * 16
+	if _, refs := decs.PackedRefSize(n, n); refs > 0 {
+		size += refs + 1 + varintSize(n)
+	}
+	return size
+}
+
+func merkleDepth(leaves int) int {
+	depth := 0
+	for size := 1; size < leaves; size <<= 1 {
+		depth++
+	}
+	return depth
+}
+
+// frontierNodeMoments returns the mean and variance of the number of sibling
+// hashes packFrontier emits when opening ℓ distinct tail leaves drawn
+// uniformly from [ncols+ℓ, N). Level h of the walk emits 2·A_{h+1} − A_h
+// nodes, A_h being the number of distinct opened ancestors at height h, so
+// the count is a fixed term plus the sum of the indicators "E hits the
+// subtree of v" over every node v. The mean follows by linearity and the
+// variance from the pairwise joint hit probabilities: for nested subtrees the
+// inner hit implies the outer one, and for disjoint ones inclusion–exclusion
+// over the union applies.
+func frontierNodeMoments(leaves, ncols, ell int) (mean, variance float64) {
+	depth := merkleDepth(leaves)
+	tailStart := ncols + ell
+	tailLen := leaves - tailStart
+	// miss[c] is the probability that E avoids c given tail leaves.
+	miss := make([]float64, tailLen+1)
+	for c := range miss {
+		m := 1.0
+		for t := 0; t < ell && m > 0; t++ {
+			m *= float64(tailLen-c-t) / float64(tailLen-t)
+			if m < 0 {
+				m = 0
+			}
+		}
+		miss[c] = m
+	}
+	// coef[h] weighs A_h in Σ_h (2·A_{h+1} − A_h).
+	coef := func(h int) float64 {
+		c := 0.0
+		if h > 0 {
+			c += 2
+		}
+		if h < depth {
+			c--
+		}
+		return c
+	}
+	type node struct {
+		h, j, covered int
+		w             float64
+	}
+	var random []node
+	mean = coef(0) * float64(ell)
+	for h := 1; h <= depth; h++ {
+		span := 1 << h
+		for j := 0; j < (1<<depth)>>h; j++ {
+			lo, hi := j*span, (j+1)*span
+			if lo < tailStart {
+				lo = tailStart
+			}
+			if hi > leaves {
+				hi = leaves
+			}
+			if hi-lo <= 0 {
+				continue
+			}
+			n := node{h: h, j: j, covered: hi - lo, w: coef(h)}
+			mean += n.w * (1 - miss[n.covered])
+			random = append(random, n)
+		}
+	}
+	for a, u := range random {
+		pu := 1 - miss[u.covered]
+		variance += u.w * u.w * pu * (1 - pu)
+		for _, v := range random[a+1:] {
+			// random is ordered by height, so v is never below u.
+			pv := 1 - miss[v.covered]
+			var both float64
+			if v.h > u.h && u.j>>(v.h-u.h) == v.j {
+				both = pu
+			} else {
+				both = 1 - miss[u.covered] - miss[v.covered] + miss[u.covered+v.covered]
+			}
+			variance += 2 * u.w * v.w * (both - pu*pv)
+		}
+	}
+	return mean, variance
+}
@@ -0,0 +1,57 @@
+package PIOP
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// frontierNodes counts the sibling hashes packFrontier emits for the opened
+// leaves: 2·A_{h+1} − A_h per level.
+func frontierNodes(leaves int, opened []int) int {
+	cur := map[int]bool{}
+	for _, i := range opened {
+		cur[i] = true
+	}
+	nodes := 0
+	for h := 0; h < merkleDepth(leaves); h++ {
+		next := map[int]bool{}
+		for i := range cur {
+			next[i>>1] = true
+		}
+		nodes += 2*len(next) - len(cur)
+		cur = next
+	}
+	return nodes
+}
+
+// TestFrontierNodeMoments checks the analytic mean and variance of the
+// frontier against tails sampled as the prover samples them.
+func TestFrontierNodeMoments(t *testing.T) {
+	const leaves, samples = 1024, 20000
+	rng := rand.New(rand.NewSource(1))
+	for _, ncols := range []int{4, 6, 8} {
+		for _, ell := range []int{1, 2, 4} {
+			mean, variance := frontierNodeMoments(leaves, ncols, ell)
+			tailStart := ncols + ell
+			sum, sq := 0.0, 0.0
+			for k := 0; k < samples; k++ {
+				opened := make([]int, 0, ell)
+				for _, p := range rng.Perm(leaves - tailStart)[:ell] {
+					opened = append(opened, tailStart+p)
+				}
+				x := float64(frontierNodes(leaves, opened))
+				sum += x
+				sq += x * x
+			}
+			gotMean := sum / samples
+			gotVar := sq/samples - gotMean*gotMean
+			if math.Abs(gotMean-mean) > 0.05 {
+				t.Errorf("ncols=%d ell=%d: mean %.3f, sampled %.3f", ncols, ell, mean, gotMean)
+			}
+			if math.Abs(gotVar-variance) > 0.05*variance+1e-9 {
+				t.Errorf("ncols=%d ell=%d: variance %.3f, sampled %.3f", ncols, ell, variance, gotVar)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package PIOP
 
 import (
 	"fmt"
+	"io"
 
 	kf "vSIS-Signature/internal/kfield"
 
@@ -20,7 +21,8 @@ type smallFieldParams struct {
 // deriveSmallFieldParamsNoRows derives K/chi and omegaS1/muInv without
 // converting witness columns to small-field rows. This is used by credential
 // mode to keep a row-oriented layout while still enabling theta>1 sampling.
-func deriveSmallFieldParamsNoRows(ringQ *ring.Ring, omega []uint64, theta int) (smallFieldParams, error) {
+// rnd supplies the random χ and ω_s1 (nil = crypto/rand).
+func deriveSmallFieldParamsNoRows(ringQ *ring.Ring, omega []uint64, theta int, rnd io.Reader) (smallFieldParams, error) {
 	var out smallFieldParams
 	if ringQ == nil {
 		return out, fmt.Errorf("nil ring")
@@ -32,7 +34,7 @@ func deriveSmallFieldParamsNoRows(ringQ *ring.Ring, omega []uint64, theta int) (
 		return out, fmt.Errorf("empty omega")
 	}
 	q := ringQ.Modulus[0]
-	chi, chiErr := kf.FindIrreducible(q, theta, nil)
+	chi, chiErr := kf.FindIrreducible(q, theta, rnd)
 	if chiErr != nil {
 		return out, fmt.Errorf("FindIrreducible: %w", chiErr)
 	}
@@ -44,7 +46,7 @@ func deriveSmallFieldParamsNoRows(ringQ *ring.Ring, omega []uint64, theta int) (
 	var muDenomInv kf.Elem
 	const maxAttempts = 1 << 12
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		candidate, randErr := K.RandomElement(nil)
+		candidate, randErr := K.RandomElement(rnd)
 		if randErr != nil {
 			return out, fmt.Errorf("sample omegaS1: %w", randErr)
 		}
@@ -0,0 +1,255 @@
+package PIOP
+
+import (
+	"encoding/json"
+	"fmt"
+
+	kf "vSIS-Signature/internal/kfield"
+)
+
+// ConstraintFamily names a contiguous block of parallel residuals returned by
+// a ConstraintEvaluator (fpar[Start:Start+Count]).
+type ConstraintFamily struct {
+	Name  string `json:"name"`
+	Start int    `json:"start"`
+	Count int    `json:"count"`
+}
+
+// ResidualFailure is one replayed residual that disagrees with the value of
+// the prover's committed F-polynomial at the same point. Values are a single
+// field element at tail points and θ limbs at K-points.
+type ResidualFailure struct {
+	Point     uint64   `json:"point"`
+	KPoint    bool     `json:"k_point,omitempty"`
+	Index     int      `json:"index"`
+	Residual  []uint64 `json:"residual"`
+	Committed []uint64 `json:"committed"`
+}
+
+// FamilyReport summarises the replay of one constraint family.
+type FamilyReport struct {
+	Name        string            `json:"name"`
+	Constraints int               `json:"constraints"`
+	Checked     int               `json:"checked"`
+	Failures    []ResidualFailure `json:"failures,omitempty"`
+}
+
+// Eq4Failure records an Eq.(4) row that did not balance at a replay point.
+type Eq4Failure struct {
+	Point  uint64   `json:"point"`
+	KPoint bool     `json:"k_point,omitempty"`
+	Row    int      `json:"row"`
+	LHS    []uint64 `json:"lhs"`
+	RHS    []uint64 `json:"rhs"`
+}
+
+// VerificationReport is the opt-in diagnostic output of
+// VerifyWithConstraintsReport. Unlike the plain verifier it does not stop at
+// the first mismatch: every tail point and K-point is replayed and each
+// residual is compared with the committed F-polynomials, so a broken
+// constraint family shows up by name.
+type VerificationReport struct {
+	OK       bool           `json:"ok"`
+	OKLin    bool           `json:"ok_lin"`
+	OKEq4    bool           `json:"ok_eq4"`
+	OKSum    bool           `json:"ok_sum"`
+	Error    string         `json:"error,omitempty"`
+	Families []FamilyReport `json:"families"`
+	Eq4      []Eq4Failure   `json:"eq4_failures,omitempty"`
+
+	layout []ConstraintFamily
+}
+
+// maxFailuresPerFamily caps the failures kept per family so a fully broken
+// constraint does not produce a report proportional to |E′|·|K′|.
+const maxFailuresPerFamily = 16
+
+// NewVerificationReport returns an empty report for the given residual layout.
+func NewVerificationReport(layout []ConstraintFamily) *VerificationReport {
+	r := &VerificationReport{layout: append([]ConstraintFamily(nil), layout...)}
+	r.Families = make([]FamilyReport, len(layout))
+	for i, fam := range layout {
+		r.Families[i] = FamilyReport{Name: fam.Name, Constraints: fam.Count}
+	}
+	return r
+}
+
+// FailedFamilies lists the families with at least one residual mismatch.
+func (r *VerificationReport) FailedFamilies() []string {
+	if r == nil {
+		return nil
+	}
+	var out []string
+	for _, fam := range r.Families {
+		if len(fam.Failures) > 0 {
+			out = append(out, fam.Name)
+		}
+	}
+	return out
+}
+
+// JSON renders the report as indented JSON.
+func (r *VerificationReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// family returns the report entry owning residual j, growing an
+// "unclassified" entry for indices outside the declared layout.
+func (r *VerificationReport) family(j int) (*FamilyReport, int) {
+	for i, fam := range r.layout {
+		if j >= fam.Start && j < fam.Start+fam.Count {
+			return &r.Families[i], j - fam.Start
+		}
+	}
+	last := len(r.Families) - 1
+	if last < 0 || r.Families[last].Name != "unclassified" {
+		r.Families = append(r.Families, FamilyReport{Name: "unclassified"})
+		last++
+	}
+	end := 0
+	for _, fam := range r.layout {
+		if fam.Start+fam.Count > end {
+			end = fam.Start + fam.Count
+		}
+	}
+	if j-end+1 > r.Families[last].Constraints {
+		r.Families[last].Constraints = j - end + 1
+	}
+	return &r.Families[last], j - end
+}
+
+func (r *VerificationReport) recordResidual(point uint64, kpoint bool, j int, residual, committed []uint64) {
+	fam, idx := r.family(j)
+	fam.Checked++
+	if equalUint64s(residual, committed) || len(fam.Failures) >= maxFailuresPerFamily {
+		return
+	}
+	fam.Failures = append(fam.Failures, ResidualFailure{
+		Point:     point,
+		KPoint:    kpoint,
+		Index:     idx,
+		Residual:  residual,
+		Committed: committed,
+	})
+}
+
+func (r *VerificationReport) recordEq4(point uint64, kpoint bool, row int, lhs, rhs []uint64) {
+	r.Eq4 = append(r.Eq4, Eq4Failure{Point: point, KPoint: kpoint, Row: row, LHS: lhs, RHS: rhs})
+}
+
+func (r *VerificationReport) finish(okLin, okEq4, okSum bool, err error) {
+	r.OKLin, r.OKEq4, r.OKSum = okLin, okEq4, okSum
+	r.OK = okLin && okEq4 && okSum && err == nil
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+func equalUint64s(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func kElemLimbs(K *kf.Field, e kf.Elem) []uint64 {
+	return K.PhiInv(K.Normalize(e))
+}
+
+// appendFamilies concatenates residual layouts in evaluator composition order.
+func appendFamilies(a []ConstraintFamily, b ...ConstraintFamily) []ConstraintFamily {
+	off := 0
+	for _, fam := range a {
+		if fam.Start+fam.Count > off {
+			off = fam.Start + fam.Count
+		}
+	}
+	out := append([]ConstraintFamily(nil), a...)
+	for _, fam := range b {
+		fam.Start += off
+		out = append(out, fam)
+	}
+	return out
+}
+
+// familyLayout builds a layout from (name, count) pairs, skipping empty ones.
+func familyLayout(names []string, counts []int) []ConstraintFamily {
+	var out []ConstraintFamily
+	off := 0
+	for i, name := range names {
+		if counts[i] <= 0 {
+			continue
+		}
+		out = append(out, ConstraintFamily{Name: name, Start: off, Count: counts[i]})
+		off += counts[i]
+	}
+	return out
+}
+
+// Families describes the residual layout of CredentialEvaluator.
+func (cfg CredentialConstraintConfig) Families() []ConstraintFamily {
+	n := cfg.Blocks.norm()
+	center, hash, packing := 0, 0, 0
+	if cfg.Bound > 0 {
+		center = n.RU0 + n.RU1
+	}
+	if len(cfg.B) >= n.HashLayout().KeyLen() {
+		hash = 1
+	}
+	if len(cfg.PackingSelNTT) > 0 || cfg.PackingNCols > 0 {
+		packing = n.M1 + n.M2
+	}
+	return familyLayout(
+		[]string{"commit", "center", "hash", "packing", "bounds", "carry"},
+		[]int{len(cfg.Ac), center, hash, packing, len(cfg.BoundRows), len(cfg.CarryRows)},
+	)
+}
+
+// Families describes the residual layout of PostSignEvaluator; core and
+// bounds select the PostSignEvaluatorCore / PostSignEvaluatorBounds halves.
+func (cfg PostSignConstraintConfig) Families(core, bounds bool) []ConstraintFamily {
+	names := []string{"signature", "hash", "packing", "bounds"}
+	counts := make([]int, 4)
+	if core {
+		n := cfg.Blocks.norm()
+		counts[0] = len(cfg.A)
+		if len(cfg.B) >= n.HashLayout().KeyLen() {
+			counts[1] = 1
+		}
+		if len(cfg.PackingSelNTT) > 0 {
+			counts[2] = n.M1 + n.M2
+		}
+	}
+	if bounds {
+		counts[3] = len(cfg.BoundRows)
+	}
+	return familyLayout(names, counts)
+}
+
+// Families describes the residual layout of PRFEvaluator: one family per
+// permutation round, then the tag and nonce bindings.
+func (cfg PRFConstraintConfig) Families() []ConstraintFamily {
+	if cfg.Params == nil {
+		return nil
+	}
+	t := cfg.Params.T()
+	rounds := cfg.Params.RF + cfg.Params.RP
+	names := make([]string, 0, rounds+2)
+	counts := make([]int, 0, rounds+2)
+	for r := 0; r < rounds; r++ {
+		names = append(names, fmt.Sprintf("prf.round[%d]", r))
+		counts = append(counts, t)
+	}
+	nonce := 0
+	if len(cfg.NonceTheta) > 0 {
+		nonce = cfg.Params.LenNonce
+	}
+	names = append(names, "prf.tag", "prf.nonce")
+	counts = append(counts, cfg.Params.LenTag, nonce)
+	return familyLayout(names, counts)
+}
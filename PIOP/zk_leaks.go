@@ -0,0 +1,305 @@
+package PIOP
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+
+	decs "vSIS-Signature/DECS"
+	lvcs "vSIS-Signature/LVCS"
+)
+
+// FSChallenges fixes the digests answered by the four FS rounds (Γ, Γ'/γ',
+// the evaluation/K-points and the tail set E) of a programmed transcript.
+type FSChallenges [4][]byte
+
+// SampleFSChallenges draws uniform 64-byte round digests from rng, clearing
+// the κ leading bits each round's grinding requires.
+func SampleFSChallenges(kappa [4]int, rng io.Reader) (FSChallenges, error) {
+	var ch FSChallenges
+	for round := range ch {
+		d := make([]byte, 64)
+		if _, err := io.ReadFull(rng, d); err != nil {
+			return ch, fmt.Errorf("sample FS round %d: %w", round, err)
+		}
+		k := kappa[round]
+		if k > 8*len(d) {
+			return ch, fmt.Errorf("sample FS round %d: kappa %d exceeds digest", round, k)
+		}
+		for i := 0; i < k/8; i++ {
+			d[i] = 0
+		}
+		if rem := k % 8; rem > 0 {
+			d[k/8] &= 0xFF >> rem
+		}
+		ch[round] = d
+	}
+	return ch, nil
+}
+
+// Oracle returns a ProgrammedXOF answering every FS round with c. In testonly
+// builds, SimOpts.SetFSOracle builds (and verifies) proofs under it.
+func (c FSChallenges) Oracle() *ProgrammedXOF {
+	x := NewProgrammedXOF(NewShake256XOF(64))
+	for round, d := range c {
+		if d != nil {
+			x.ProgramRound(round, d)
+		}
+	}
+	return x
+}
+
+// zkFamilies names the opened-value families compared by CompareOpenedValues,
+// in OpenedValues field order.
+var zkFamilies = [...]string{"Pvals", "Mvals", "VTargets", "BarSets", "PvalsK"}
+
+// OpenedValues flattens the values a proof opens to the verifier, family by
+// family and row-major: the committed rows (Pvals) and DECS masks (Mvals) at
+// the opened positions, the LVCS targets on Ω (VTargets) and on the masked
+// positions (BarSets), and the row evaluations at the K-points (PvalsK).
+type OpenedValues struct {
+	Pvals    []uint64
+	Mvals    []uint64
+	VTargets []uint64
+	BarSets  []uint64
+	PvalsK   []uint64
+}
+
+func (v *OpenedValues) family(i int) []uint64 {
+	return [...][]uint64{v.Pvals, v.Mvals, v.VTargets, v.BarSets, v.PvalsK}[i]
+}
+
+// OpenedValuesOf extracts the opened values of proof.
+func OpenedValuesOf(proof *Proof) (OpenedValues, error) {
+	var v OpenedValues
+	if proof == nil || proof.RowOpening == nil {
+		return v, fmt.Errorf("opened values: missing row opening: %w", ErrMalformedProof)
+	}
+	flat := func(m [][]uint64) []uint64 {
+		var out []uint64
+		for _, row := range m {
+			out = append(out, row...)
+		}
+		return out
+	}
+	open := expandPackedOpening(proof.RowOpening)
+	defer decs.PackOpening(proof.RowOpening)
+	v.Pvals = flat(open.Pvals)
+	v.Mvals = flat(open.Mvals)
+	v.VTargets = flat(proof.VTargetsMatrix())
+	v.BarSets = flat(proof.BarSetsMatrix())
+	v.PvalsK = flat(proof.PvalsKEvalMatrix())
+	return v, nil
+}
+
+// ZKFamilyStat is the verdict on one opened-value family.
+type ZKFamilyStat struct {
+	Family string
+	Coords int
+	// ChiSq is the two-sample χ² statistic over Buckets equal ranges of
+	// [0,q), pooling all coordinates; PValue is its upper tail with DF
+	// degrees of freedom (Wilson–Hilferty).
+	ChiSq  float64
+	DF     int
+	PValue float64
+	// RealRank, BaseRank and JointRank are the dimensions over F_q of the
+	// affine hulls of the real samples, the baseline samples and both.
+	// A family whose openings satisfy a witness-dependent affine relation
+	// (masking with fewer random degrees of freedom than opened values)
+	// yields JointRank > max(RealRank, BaseRank) once the hulls are
+	// Saturated, i.e. each side has more samples than its hull dimension.
+	RealRank  int
+	BaseRank  int
+	JointRank int
+	Saturated bool
+	// Distinguished is set when PValue < α or a saturated joint hull grows.
+	Distinguished bool
+}
+
+// ZKReport collects the per-family verdicts of CompareOpenedValues.
+type ZKReport struct {
+	Alpha    float64
+	Buckets  int
+	Families []ZKFamilyStat
+}
+
+// Distinguished returns the families on which real and baseline openings
+// were told apart.
+func (r *ZKReport) Distinguished() []string {
+	var out []string
+	for _, f := range r.Families {
+		if f.Distinguished {
+			out = append(out, f.Family)
+		}
+	}
+	return out
+}
+
+// CompareOpenedValues is a two-sample leak test between the openings of real
+// proofs and of a baseline (e.g. SimulateProof transcripts) taken under the
+// same FS challenges: a marginal χ² test catches values that stop looking
+// uniform (e.g. unmasked witness residues), and the affine-hull test catches
+// linear leaks invisible to marginals. A family told apart leaks the witness;
+// passing is evidence, not a proof of zero knowledge. Families empty on both
+// sides are skipped.
+func CompareOpenedValues(q uint64, real, base []OpenedValues, buckets int, alpha float64) (*ZKReport, error) {
+	if q < 2 {
+		return nil, fmt.Errorf("zk harness: modulus %d", q)
+	}
+	if len(real) < 2 || len(base) < 2 {
+		return nil, fmt.Errorf("zk harness: need at least 2 samples per side, got %d/%d", len(real), len(base))
+	}
+	if buckets < 2 || uint64(buckets) > q {
+		return nil, fmt.Errorf("zk harness: buckets=%d out of range", buckets)
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return nil, fmt.Errorf("zk harness: alpha=%v out of range", alpha)
+	}
+	rep := &ZKReport{Alpha: alpha, Buckets: buckets}
+	for fi, name := range zkFamilies {
+		a := make([][]uint64, len(real))
+		b := make([][]uint64, len(base))
+		for i := range real {
+			a[i] = real[i].family(fi)
+		}
+		for i := range base {
+			b[i] = base[i].family(fi)
+		}
+		d := len(a[0])
+		for _, s := range append(append([][]uint64(nil), a...), b...) {
+			if len(s) != d {
+				return nil, fmt.Errorf("zk harness: %s has %d values, want %d", name, len(s), d)
+			}
+		}
+		if d == 0 {
+			continue
+		}
+		st := ZKFamilyStat{Family: name, Coords: d}
+		st.ChiSq, st.DF = chiSqHomogeneity(bucketCounts(q, a, buckets), bucketCounts(q, b, buckets))
+		st.PValue = chiSqUpperTail(st.ChiSq, st.DF)
+		ha := newAffineHull(q, a[0])
+		for _, s := range a[1:] {
+			ha.add(s)
+		}
+		hb := newAffineHull(q, b[0])
+		for _, s := range b[1:] {
+			hb.add(s)
+		}
+		st.RealRank, st.BaseRank = ha.rank(), hb.rank()
+		for _, s := range b {
+			ha.add(s)
+		}
+		st.JointRank = ha.rank()
+		st.Saturated = st.RealRank < len(a)-1 && st.BaseRank < len(b)-1
+		st.Distinguished = st.PValue < alpha ||
+			(st.Saturated && st.JointRank > max(st.RealRank, st.BaseRank))
+		rep.Families = append(rep.Families, st)
+	}
+	return rep, nil
+}
+
+// bucketCounts histograms every value of samples into buckets equal ranges
+// of [0,q).
+func bucketCounts(q uint64, samples [][]uint64, buckets int) []float64 {
+	counts := make([]float64, buckets)
+	for _, s := range samples {
+		for _, v := range s {
+			hi, lo := bits.Mul64(v%q, uint64(buckets))
+			b, _ := bits.Div64(hi, lo, q)
+			counts[b]++
+		}
+	}
+	return counts
+}
+
+// chiSqHomogeneity returns the two-sample χ² statistic of histograms a and b
+// and its degrees of freedom (non-empty buckets minus one).
+func chiSqHomogeneity(a, b []float64) (float64, int) {
+	var na, nb float64
+	for i := range a {
+		na += a[i]
+		nb += b[i]
+	}
+	ka, kb := math.Sqrt(nb/na), math.Sqrt(na/nb)
+	stat, df := 0.0, -1
+	for i := range a {
+		if a[i]+b[i] == 0 {
+			continue
+		}
+		d := ka*a[i] - kb*b[i]
+		stat += d * d / (a[i] + b[i])
+		df++
+	}
+	return stat, df
+}
+
+// chiSqUpperTail returns P[χ²_df ≥ x] via the Wilson–Hilferty normal
+// approximation.
+func chiSqUpperTail(x float64, df int) float64 {
+	if df <= 0 {
+		return 1
+	}
+	k := float64(df)
+	s := 2 / (9 * k)
+	z := (math.Cbrt(x/k) - (1 - s)) / math.Sqrt(s)
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+// affineHull maintains an echelon basis over F_q of the differences between
+// added points and the origin point.
+type affineHull struct {
+	q      uint64
+	origin []uint64
+	basis  [][]uint64 // basis[i] has a 1 at pivot[i] and zeros at earlier pivots
+	pivot  []int
+}
+
+func newAffineHull(q uint64, origin []uint64) *affineHull {
+	return &affineHull{q: q, origin: append([]uint64(nil), origin...)}
+}
+
+func (h *affineHull) rank() int { return len(h.basis) }
+
+// add extends the hull with point p and reports whether its dimension grew.
+func (h *affineHull) add(p []uint64) bool {
+	q := h.q
+	v := make([]uint64, len(p))
+	for i := range p {
+		v[i] = lvcs.AddMod64(p[i], q-h.origin[i]%q, q)
+	}
+	for i, b := range h.basis {
+		c := v[h.pivot[i]]
+		if c == 0 {
+			continue
+		}
+		for j := range v {
+			v[j] = lvcs.AddMod64(v[j], q-lvcs.MulMod64(c, b[j], q), q)
+		}
+	}
+	piv := -1
+	for j := range v {
+		if v[j] != 0 {
+			piv = j
+			break
+		}
+	}
+	if piv < 0 {
+		return false
+	}
+	inv := modInv(v[piv], q)
+	for j := range v {
+		v[j] = lvcs.MulMod64(v[j], inv, q)
+	}
+	// Keep earlier rows reduced at the new pivot so reduction stays one pass.
+	for _, b := range h.basis {
+		if c := b[piv]; c != 0 {
+			for j := range b {
+				b[j] = lvcs.AddMod64(b[j], q-lvcs.MulMod64(c, v[j], q), q)
+			}
+		}
+	}
+	h.basis = append(h.basis, v)
+	h.pivot = append(h.pivot, piv)
+	return true
+}
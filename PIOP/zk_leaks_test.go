@@ -0,0 +1,59 @@
+package PIOP
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func zkSamples(rng *rand.Rand, n, d int, q uint64, gen func(i int) uint64) []OpenedValues {
+	out := make([]OpenedValues, n)
+	for s := range out {
+		v := make([]uint64, d)
+		for i := range v {
+			v[i] = gen(i)
+		}
+		out[s] = OpenedValues{Pvals: v}
+	}
+	return out
+}
+
+func TestCompareOpenedValuesDetectsLeaks(t *testing.T) {
+	const q, d, n = 1038337, 6, 60
+	rng := rand.New(rand.NewSource(1))
+	uniform := func(int) uint64 { return uint64(rng.Int63n(q)) }
+	// Masked: the last coordinate is a fixed combination of the others, so
+	// the openings live on a hyperplane through a witness-dependent point.
+	masked := func(w uint64) []OpenedValues {
+		s := zkSamples(rng, n, d, q, uniform)
+		for _, v := range s {
+			sum := w
+			for _, x := range v.Pvals[:d-1] {
+				sum = (sum + x) % q
+			}
+			v.Pvals[d-1] = sum
+		}
+		return s
+	}
+	cases := []struct {
+		name       string
+		real, base []OpenedValues
+		want       bool
+	}{
+		{"uniform", zkSamples(rng, n, d, q, uniform), zkSamples(rng, n, d, q, uniform), false},
+		{"same hyperplane", masked(7), masked(7), false},
+		{"affine leak", masked(7), masked(0), true},
+		{"unmasked", zkSamples(rng, n, d, q, uniform), zkSamples(rng, n, d, q, func(int) uint64 { return uint64(rng.Intn(17)) }), true},
+	}
+	for _, tc := range cases {
+		rep, err := CompareOpenedValues(q, tc.real, tc.base, 16, 1e-4)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(rep.Families) != 1 {
+			t.Fatalf("%s: got %d families, want only Pvals", tc.name, len(rep.Families))
+		}
+		if got := rep.Families[0].Distinguished; got != tc.want {
+			t.Fatalf("%s: distinguished=%v want %v (%+v)", tc.name, got, tc.want, rep.Families[0])
+		}
+	}
+}
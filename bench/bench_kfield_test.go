@@ -0,0 +1,101 @@
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	kf "vSIS-Signature/internal/kfield"
+)
+
+const kfieldQ = 1038337
+
+func benchField(b *testing.B, theta int) (*kf.Field, []kf.Elem) {
+	chi, err := kf.FindIrreducible(kfieldQ, theta, nil)
+	if err != nil {
+		b.Fatalf("FindIrreducible: %v", err)
+	}
+	K, err := kf.New(kfieldQ, theta, chi)
+	if err != nil {
+		b.Fatalf("kfield.New: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	xs := make([]kf.Elem, 256)
+	for i := range xs {
+		coords := make([]uint64, theta)
+		for j := range coords {
+			coords[j] = 1 + rng.Uint64()%(kfieldQ-1)
+		}
+		xs[i] = K.Phi(coords)
+	}
+	return K, xs
+}
+
+func BenchmarkKFieldMul(b *testing.B) {
+	for _, theta := range []int{2, 3, 4, 6, 8} {
+		b.Run(fmt.Sprintf("theta=%d", theta), func(b *testing.B) {
+			K, xs := benchField(b, theta)
+			acc := K.One()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				acc = K.Mul(acc, xs[i&255])
+			}
+		})
+	}
+}
+
+func BenchmarkKFieldInv(b *testing.B) {
+	for _, theta := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("theta=%d", theta), func(b *testing.B) {
+			K, xs := benchField(b, theta)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				K.Inv(xs[i&255])
+			}
+		})
+	}
+}
+
+func BenchmarkKFieldBatchInv256(b *testing.B) {
+	K, xs := benchField(b, 4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		K.BatchInv(xs)
+	}
+}
+
+// benchRows returns count random F_q polynomials of length n.
+func benchRows(count, n int) [][]uint64 {
+	rng := rand.New(rand.NewSource(2))
+	rows := make([][]uint64, count)
+	for i := range rows {
+		rows[i] = make([]uint64, n)
+		for j := range rows[i] {
+			rows[i][j] = rng.Uint64() % kfieldQ
+		}
+	}
+	return rows
+}
+
+// BenchmarkKFieldEvalRows evaluates 256 rows of length N=1024 at ℓ′=2
+// K-points with θ=4, by Horner and by MultiEval (table build included).
+func BenchmarkKFieldEvalRows(b *testing.B) {
+	const n, count = 1024, 256
+	K, xs := benchField(b, 4)
+	points := xs[:2]
+	rows := benchRows(count, n)
+	b.Run("horner", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, row := range rows {
+				for _, e := range points {
+					K.EvalFPolyAtK(row, e)
+				}
+			}
+		}
+	})
+	b.Run("multieval", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			K.NewMultiEval(points, n).EvalFMany(rows)
+		}
+	})
+}
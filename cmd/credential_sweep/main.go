@@ -7,6 +7,9 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
+	"math/big"
+	"math/bits"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -21,6 +24,7 @@ import (
 	"vSIS-Signature/ntru/keys"
 	"vSIS-Signature/ntru/signverify"
 	"vSIS-Signature/prf"
+	"vSIS-Signature/security"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
@@ -35,26 +39,45 @@ type sweepConfig struct {
 }
 
 type sweepRow struct {
-	TargetBits    int     `json:"target_bits"`
-	NCols         int     `json:"ncols"`
-	Ell           int     `json:"ell"`
-	EllPrime      int     `json:"ell_prime"`
-	Rho           int     `json:"rho"`
-	Theta         int     `json:"theta"`
-	Eta           int     `json:"eta"`
-	IssBits       float64 `json:"issuance_bits"`
-	ShowBits      float64 `json:"showing_bits"`
-	MinBits       float64 `json:"min_bits"`
-	IssKB         float64 `json:"issuance_kb"`
-	ShowKB        float64 `json:"showing_kb"`
-	IssTimeSec    float64 `json:"issuance_time_sec"`
-	ShowTimeSec   float64 `json:"showing_time_sec"`
-	IssDQ         int     `json:"issuance_dq"`
-	ShowDQ        int     `json:"showing_dq"`
-	IssFpar       int     `json:"issuance_fpar"`
-	ShowFpar      int     `json:"showing_fpar"`
-	IssFagg       int     `json:"issuance_fagg"`
-	ShowFagg      int     `json:"showing_fagg"`
+	TargetBits   int     `json:"target_bits"`
+	NCols        int     `json:"ncols"`
+	Ell          int     `json:"ell"`
+	EllPrime     int     `json:"ell_prime"`
+	Rho          int     `json:"rho"`
+	Theta        int     `json:"theta"`
+	Eta          int     `json:"eta"`
+	IssBits      float64 `json:"issuance_bits"`
+	ShowBits     float64 `json:"showing_bits"`
+	MinBits      float64 `json:"min_bits"`
+	IssKB        float64 `json:"issuance_kb"`
+	ShowKB       float64 `json:"showing_kb"`
+	IssTimeSec   float64 `json:"issuance_time_sec"`
+	ShowTimeSec  float64 `json:"showing_time_sec"`
+	IssDQ        int     `json:"issuance_dq"`
+	ShowDQ       int     `json:"showing_dq"`
+	IssFpar      int     `json:"issuance_fpar"`
+	ShowFpar     int     `json:"showing_fpar"`
+	IssFagg      int     `json:"issuance_fagg"`
+	ShowFagg     int     `json:"showing_fagg"`
+	BatchK       int     `json:"batch_k,omitempty"`
+	BatchKB      float64 `json:"batch_kb,omitempty"`
+	BatchTimeSec float64 `json:"batch_time_sec,omitempty"`
+	IssEstKB     float64 `json:"issuance_est_kb,omitempty"`
+	ShowEstKB    float64 `json:"showing_est_kb,omitempty"`
+	// Issuance proven over a lifted prime field (-lift-bits).
+	LiftPrimeBits  int     `json:"lift_prime_bits,omitempty"`
+	LiftEll        int     `json:"lift_ell,omitempty"`
+	LiftEllPrime   int     `json:"lift_ellp,omitempty"`
+	LiftRho        int     `json:"lift_rho,omitempty"`
+	LiftTheta      int     `json:"lift_theta,omitempty"`
+	LiftEta        int     `json:"lift_eta,omitempty"`
+	LiftIssBits    float64 `json:"lift_issuance_bits,omitempty"`
+	LiftIssKB      float64 `json:"lift_issuance_kb,omitempty"`
+	LiftIssTimeSec float64 `json:"lift_issuance_time_sec,omitempty"`
+	// Lattice hardness from package security; -1 means no attack applies.
+	KeyBits       float64 `json:"ntru_key_bits"`
+	ForgeBits     float64 `json:"forgery_bits"`
+	BindBits      float64 `json:"binding_bits"`
 	ProofsChecked bool    `json:"proofs_checked"`
 }
 
@@ -73,6 +96,13 @@ type showArtifacts struct {
 	dur   time.Duration
 }
 
+// batchArtifacts holds one BuildShowingBatch proof covering k showings.
+type batchArtifacts struct {
+	k     int
+	proof *PIOP.Proof
+	dur   time.Duration
+}
+
 type sweepWriter struct {
 	csv      *csv.Writer
 	csvFile  *os.File
@@ -83,22 +113,26 @@ type sweepWriter struct {
 
 func main() {
 	var (
-		mode      = flag.String("mode", "both", "issuance|showing|both")
-		ncolsSpec = flag.String("ncols", "4,6,8", "comma-separated NCols values")
-		ellSpec   = flag.String("ell", "1,2,4", "comma-separated ell values")
-		ellpSpec  = flag.String("ellp", "1,2", "comma-separated ell' values")
-		rhoSpec   = flag.String("rho", "1,2", "comma-separated rho values")
-		thetaSpec = flag.String("theta", "2", "comma-separated theta values")
-		etaSpec   = flag.String("eta", "7,11,17", "comma-separated eta values")
-		targets   = flag.String("targets", "128,256", "comma-separated target soundness bits")
-		boundB    = flag.Int64("bound", 8, "bound B for sampling and constraints")
-		maxTrials = flag.Int("max-trials", 2048, "max NTRU signing trials")
-		seed      = flag.Int64("seed", 0, "rng seed (0 = time-based)")
-		maxRuns   = flag.Int("max", 0, "max grid points to run (0 = all)")
+		mode       = flag.String("mode", "both", "issuance|showing|both")
+		ncolsSpec  = flag.String("ncols", "4,6,8", "comma-separated NCols values")
+		ellSpec    = flag.String("ell", "1,2,4", "comma-separated ell values")
+		ellpSpec   = flag.String("ellp", "1,2", "comma-separated ell' values")
+		rhoSpec    = flag.String("rho", "1,2", "comma-separated rho values")
+		thetaSpec  = flag.String("theta", "2", "comma-separated theta values")
+		etaSpec    = flag.String("eta", "7,11,17", "comma-separated eta values")
+		targets    = flag.String("targets", "128,256", "comma-separated target soundness bits")
+		boundB     = flag.Int64("bound", 8, "bound B for sampling and constraints")
+		maxTrials  = flag.Int("max-trials", 2048, "max NTRU signing trials")
+		seed       = flag.Int64("seed", 0, "rng seed (0 = time-based)")
+		maxRuns    = flag.Int("max", 0, "max grid points to run (0 = all)")
 		skipVerify = flag.Bool("skip-verify", false, "skip proof verification for speed")
-		csvPath   = flag.String("csv", "", "write csv results to path")
-		jsonPath  = flag.String("jsonl", "", "write jsonl results to path")
-		verbose   = flag.Bool("v", false, "verbose logging")
+		batchK     = flag.Int("batch", 0, "also prove k showings as one batch proof and report its size (0 = off)")
+		liftBits   = flag.Int("lift-bits", 0, "also prove issuance over a lifted NTT prime of this many bits, e.g. 61 (0 = off)")
+		csvPath    = flag.String("csv", "", "write csv results to path")
+		jsonPath   = flag.String("jsonl", "", "write jsonl results to path")
+		verbose    = flag.Bool("v", false, "verbose logging")
+		latModel   = flag.String("lattice-model", "classical", "lattice cost model: classical|quantum|bkz")
+		kgAlpha    = flag.Float64("keygen-alpha", 1.20, "annulus keygen α used for the NTRU key-recovery estimate")
 	)
 	flag.Parse()
 
@@ -133,6 +167,21 @@ func main() {
 		log.Fatalf("load public key: %v", err)
 	}
 
+	lattice, err := latticeSecurity(ringQ, *boundB, *kgAlpha, *latModel)
+	if err != nil {
+		log.Fatalf("lattice security: %v", err)
+	}
+	log.Printf("[sweep] lattice security (%s): min=%s bits", *latModel, security.FormatBits(lattice.MinBits()))
+
+	var liftRing *ring.Ring
+	if *liftBits > 0 {
+		liftRing, err = ring.NewRing(ringQ.N, ring.GenerateNTTPrimes(*liftBits, 2*ringQ.N, 1))
+		if err != nil {
+			log.Fatalf("lift ring: %v", err)
+		}
+		log.Printf("[sweep] lifted issuance over p=%d (%d bits)", liftRing.Modulus[0], *liftBits)
+	}
+
 	writer, err := newSweepWriter(*csvPath, *jsonPath)
 	if err != nil {
 		log.Fatalf("init writer: %v", err)
@@ -196,11 +245,27 @@ func main() {
 								}
 							}
 
+							var batch *batchArtifacts
+							if show != nil && *batchK > 1 {
+								batch, err = runShowingBatch(ringQ, prfParams, pk, opts, *boundB, rng, *maxTrials, *skipVerify, iss, *batchK)
+								if err != nil {
+									log.Printf("[sweep] batch showing failed (k=%d ncols=%d ell=%d ellp=%d rho=%d theta=%d eta=%d): %v", *batchK, ncols, ell, ellp, rho, theta, eta, err)
+								}
+							}
+
 							for _, target := range targetList {
-								row, ok := buildSweepRow(ringQ, opts, target, iss, show)
+								row, ok := buildSweepRow(ringQ, opts, target, iss, show, batch)
 								if !ok {
 									continue
 								}
+								if liftRing != nil && iss != nil {
+									if err := addLiftColumns(&row, ringQ, liftRing, opts, target, iss, *boundB, rng, *maxTrials, *skipVerify); err != nil {
+										log.Printf("[sweep] lifted issuance failed (target=%d ncols=%d ell=%d ellp=%d rho=%d theta=%d eta=%d): %v", target, ncols, ell, ellp, rho, theta, eta, err)
+									}
+								}
+								row.KeyBits = finiteBits(lattice.KeyBits().Bits)
+								row.ForgeBits = finiteBits(lattice.Forgery.Bits)
+								row.BindBits = finiteBits(lattice.Binding.Bits)
 								if err := writer.Write(row); err != nil {
 									log.Printf("[sweep] write row: %v", err)
 								}
@@ -265,7 +330,7 @@ func runIssuance(ringQ *ring.Ring, opts PIOP.SimOpts, bound int64, rng *rand.Ran
 	Ac := sampleAc(ringQ, cols, cols, rng)
 	params := &credential.Params{
 		Ac:     Ac,
-		BPath:  "Parameters/Bmatrix.json",
+		BPath:  "Parameters/Bmatrix.json", // synthetic params, no bundle
 		BoundB: bound,
 		RingQ:  ringQ,
 		LenM1:  lenM1,
@@ -323,40 +388,91 @@ func runIssuance(ringQ *ring.Ring, opts PIOP.SimOpts, bound int64, rng *rand.Ran
 }
 
 func runShowing(ringQ *ring.Ring, prfParams *prf.Params, pk *keys.PublicKey, opts PIOP.SimOpts, bound int64, rng *rand.Rand, maxTrials int, skipVerify bool, iss *runArtifacts) (*showArtifacts, error) {
+	pub, wit, err := prepareShowing(ringQ, prfParams, pk, opts, bound, rng, maxTrials, iss)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		return nil, fmt.Errorf("build showing: %w", err)
+	}
+	dur := time.Since(start)
+	if !skipVerify {
+		ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub, opts, PIOP.FSModeCredential)
+		if err != nil || !ok {
+			return nil, fmt.Errorf("verify showing failed: ok=%v err=%v", ok, err)
+		}
+	}
+	return &showArtifacts{proof: proof, dur: dur}, nil
+}
+
+// runShowingBatch prepares k showings of the same credential under fresh
+// nonces and proves them with a single BuildShowingBatch proof.
+func runShowingBatch(ringQ *ring.Ring, prfParams *prf.Params, pk *keys.PublicKey, opts PIOP.SimOpts, bound int64, rng *rand.Rand, maxTrials int, skipVerify bool, iss *runArtifacts, k int) (*batchArtifacts, error) {
+	pubs := make([]PIOP.PublicInputs, k)
+	wits := make([]PIOP.WitnessInputs, k)
+	for s := 0; s < k; s++ {
+		pub, wit, err := prepareShowing(ringQ, prfParams, pk, opts, bound, rng, maxTrials, iss)
+		if err != nil {
+			return nil, fmt.Errorf("showing %d: %w", s, err)
+		}
+		pubs[s], wits[s] = pub, wit
+	}
+
+	start := time.Now()
+	proof, err := PIOP.BuildShowingBatch(pubs, wits, opts)
+	if err != nil {
+		return nil, fmt.Errorf("build batch: %w", err)
+	}
+	dur := time.Since(start)
+	if !skipVerify {
+		ok, err := PIOP.VerifyShowingBatch(proof, pubs, opts)
+		if err != nil || !ok {
+			return nil, fmt.Errorf("verify batch failed: ok=%v err=%v", ok, err)
+		}
+	}
+	return &batchArtifacts{k: k, proof: proof, dur: dur}, nil
+}
+
+// prepareShowing signs the issuance target and derives a fresh PRF tag,
+// returning the showing publics and witness.
+func prepareShowing(ringQ *ring.Ring, prfParams *prf.Params, pk *keys.PublicKey, opts PIOP.SimOpts, bound int64, rng *rand.Rand, maxTrials int, iss *runArtifacts) (PIOP.PublicInputs, PIOP.WitnessInputs, error) {
 	if iss == nil || iss.state == nil {
-		return nil, errors.New("missing issuance state")
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, errors.New("missing issuance state")
 	}
 	sig, err := signverify.SignTarget(iss.state.T, maxTrials, ntru.SamplerOpts{})
 	if err != nil {
-		return nil, fmt.Errorf("sign target: %w", err)
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("sign target: %w", err)
 	}
 	if sig.Signature.Rejected {
-		return nil, fmt.Errorf("signature rejected")
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("signature rejected")
 	}
 	uRows := signatureRows(ringQ, sig)
 	A, err := buildSignatureMatrix(ringQ, pk, len(uRows))
 	if err != nil {
-		return nil, fmt.Errorf("build A: %w", err)
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("build A: %w", err)
 	}
 
 	key, err := prfKeyFromPoly(iss.inputs.M2[0], prfParams.LenKey, ringQ)
 	if err != nil {
-		return nil, fmt.Errorf("prf key: %w", err)
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("prf key: %w", err)
 	}
 	nonce, noncePublic := sampleNonce(prfParams.LenNonce, opts.NCols, ringQ.Modulus[0], rng)
 	tag, err := prf.Tag(key, nonce, prfParams)
 	if err != nil {
-		return nil, fmt.Errorf("prf tag: %w", err)
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("prf tag: %w", err)
 	}
 	tagPublic := lanesFromElems(tag, opts.NCols)
 
 	x0, err := prf.ConcatKeyNonce(key, nonce, prfParams)
 	if err != nil {
-		return nil, fmt.Errorf("concat key/nonce: %w", err)
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("concat key/nonce: %w", err)
 	}
 	trace, err := prf.Trace(x0, prfParams)
 	if err != nil {
-		return nil, fmt.Errorf("prf trace: %w", err)
+		return PIOP.PublicInputs{}, PIOP.WitnessInputs{}, fmt.Errorf("prf trace: %w", err)
 	}
 	traceRows := traceToPolys(ringQ, trace)
 
@@ -383,31 +499,18 @@ func runShowing(ringQ *ring.Ring, prfParams *prf.Params, pk *keys.PublicKey, opt
 		Nonce:  noncePublic,
 		BoundB: bound,
 	}
-
-	start := time.Now()
-	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
-	if err != nil {
-		return nil, fmt.Errorf("build showing: %w", err)
-	}
-	dur := time.Since(start)
-	if !skipVerify {
-		ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub, opts, PIOP.FSModeCredential)
-		if err != nil || !ok {
-			return nil, fmt.Errorf("verify showing failed: ok=%v err=%v", ok, err)
-		}
-	}
-	return &showArtifacts{proof: proof, dur: dur}, nil
+	return pub, wit, nil
 }
 
-func buildSweepRow(ringQ *ring.Ring, opts PIOP.SimOpts, target int, iss *runArtifacts, show *showArtifacts) (sweepRow, bool) {
+func buildSweepRow(ringQ *ring.Ring, opts PIOP.SimOpts, target int, iss *runArtifacts, show *showArtifacts, batch *batchArtifacts) (sweepRow, bool) {
 	row := sweepRow{
-		TargetBits: target,
-		NCols:      opts.NCols,
-		Ell:        opts.Ell,
-		EllPrime:   opts.EllPrime,
-		Rho:        opts.Rho,
-		Theta:      opts.Theta,
-		Eta:        opts.Eta,
+		TargetBits:    target,
+		NCols:         opts.NCols,
+		Ell:           opts.Ell,
+		EllPrime:      opts.EllPrime,
+		Rho:           opts.Rho,
+		Theta:         opts.Theta,
+		Eta:           opts.Eta,
 		ProofsChecked: true,
 	}
 	if iss != nil {
@@ -423,6 +526,7 @@ func buildSweepRow(ringQ *ring.Ring, opts PIOP.SimOpts, target int, iss *runArti
 		row.IssDQ = issRep.DQ
 		row.IssFpar = len(iss.proof.FparNTT)
 		row.IssFagg = len(iss.proof.FaggNTT)
+		row.IssEstKB = estimatedKB(ringQ, opts, iss.proof)
 	}
 	if show != nil {
 		optsShow := opts
@@ -437,6 +541,17 @@ func buildSweepRow(ringQ *ring.Ring, opts PIOP.SimOpts, target int, iss *runArti
 		row.ShowDQ = showRep.DQ
 		row.ShowFpar = len(show.proof.FparNTT)
 		row.ShowFagg = len(show.proof.FaggNTT)
+		row.ShowEstKB = estimatedKB(ringQ, opts, show.proof)
+	}
+	if batch != nil {
+		optsBatch := opts
+		optsBatch.Lambda = target
+		batchRep, err := PIOP.BuildProofReport(batch.proof, optsBatch, ringQ)
+		if err == nil {
+			row.BatchK = batch.k
+			row.BatchKB = batchRep.ProofKB
+			row.BatchTimeSec = batch.dur.Seconds()
+		}
 	}
 	row.MinBits = minFloat(row.IssBits, row.ShowBits)
 	if row.MinBits < float64(target) {
@@ -445,6 +560,123 @@ func buildSweepRow(ringQ *ring.Ring, opts PIOP.SimOpts, target int, iss *runArti
 	return row, true
 }
 
+// liftedOpts are the starting PIOP options of the lifted issuance proof:
+// ℓ′, ρ and η at 1 and θ = 2, which the K-field replay of credential proofs
+// needs. The column count and ℓ follow the small-field run, so ε₄ (which does
+// not depend on the field) is the same for both proofs.
+func liftedOpts(opts PIOP.SimOpts, liftRing *ring.Ring) PIOP.SimOpts {
+	lifted := PIOP.SimOpts{
+		Credential: true,
+		Ring:       liftRing,
+		NCols:      opts.NCols,
+		Ell:        opts.Ell,
+		EllPrime:   1,
+		Rho:        1,
+		Theta:      2,
+		Eta:        1,
+	}
+	lifted.ApplyDefaultsExported()
+	return lifted
+}
+
+// maxLiftSteps caps the parameter increments addLiftColumns tries.
+const maxLiftSteps = 32
+
+// addLiftColumns proves issuance over liftRing at soundness matched to the
+// small-field run and reports it next to that run. Starting from liftedOpts,
+// it raises the knob of the round whose ε exceeds the small-field one by the
+// most (η for ε₁, ρ for ε₂, ℓ′ for ε₃, ℓ for ε₄) and proves again until the
+// lifted union bound is at least the small-field one at the same target, so
+// the two sizes are compared at equal soundness.
+func addLiftColumns(row *sweepRow, ringQ, liftRing *ring.Ring, opts PIOP.SimOpts, target int, iss *runArtifacts, bound int64, rng *rand.Rand, maxTrials int, skipVerify bool) error {
+	optsIss := opts
+	optsIss.Lambda = target
+	small, err := PIOP.BuildProofReport(iss.proof, optsIss, ringQ)
+	if err != nil {
+		return err
+	}
+	lifted := liftedOpts(opts, liftRing)
+	lifted.Lambda = target
+	for step := 0; step < maxLiftSteps; step++ {
+		lift, err := runIssuance(ringQ, lifted, bound, rng, maxTrials, skipVerify)
+		if err != nil {
+			return err
+		}
+		rep, err := PIOP.BuildProofReport(lift.proof, lifted, liftRing)
+		if err != nil {
+			return err
+		}
+		if rep.Soundness.TotalBits >= small.Soundness.TotalBits {
+			row.LiftPrimeBits = bits.Len64(liftRing.Modulus[0])
+			row.LiftEll = lifted.Ell
+			row.LiftEllPrime = lifted.EllPrime
+			row.LiftRho = lifted.Rho
+			row.LiftTheta = lifted.Theta
+			row.LiftEta = lifted.Eta
+			row.LiftIssBits = rep.Soundness.TotalBits
+			row.LiftIssKB = rep.ProofKB
+			row.LiftIssTimeSec = lift.dur.Seconds()
+			return nil
+		}
+		worst, excess := 0, math.Inf(-1)
+		for i := range rep.Soundness.Eps {
+			if d := rep.Soundness.Eps[i] - small.Soundness.Eps[i]; d > excess {
+				worst, excess = i, d
+			}
+		}
+		switch worst {
+		case 0:
+			lifted.Eta++
+		case 1:
+			lifted.Rho++
+		case 2:
+			lifted.EllPrime++
+		default:
+			lifted.Ell++
+		}
+	}
+	return fmt.Errorf("no lifted parameters reach %.2f bits within %d steps", small.Soundness.TotalBits, maxLiftSteps)
+}
+
+// estimatedKB is PIOP.EstimateProofSize for the statement proof was built
+// over, reported next to the measured size; 0 when the model does not apply.
+func estimatedKB(ringQ *ring.Ring, opts PIOP.SimOpts, proof *PIOP.Proof) float64 {
+	opts.Ring = ringQ
+	est, err := PIOP.EstimateProofSize(opts, proof.MaskRowOffset, opts.NCols)
+	if err != nil {
+		return 0
+	}
+	return float64(est.Total) / 1024.0
+}
+
+// sweepAcShape is the Ac shape runIssuance samples: one column per block of
+// m1, m2, rU0, rU1, r, and as many rows.
+const sweepAcShape = 5
+
+// latticeSecurity estimates the lattice assumptions behind every sweep row;
+// they depend on the ring, B and the Ac shape, not on the PIOP options.
+func latticeSecurity(ringQ *ring.Ring, bound int64, alpha float64, modelName string) (security.Report, error) {
+	model, err := security.ParseCostModel(modelName)
+	if err != nil {
+		return security.Report{}, err
+	}
+	par, err := ntru.NewParams(ringQ.N, new(big.Int).SetUint64(ringQ.Modulus[0]))
+	if err != nil {
+		return security.Report{}, err
+	}
+	in := security.NewInputs(par, alpha, ntru.SamplerOpts{}, nil)
+	in.BoundB, in.AcRows, in.AcCols = bound, sweepAcShape, sweepAcShape
+	return security.Analyze(in, model)
+}
+
+// finiteBits maps statistical security (+Inf) to -1 so rows stay valid JSON.
+func finiteBits(bits float64) float64 {
+	if math.IsInf(bits, 1) {
+		return -1
+	}
+	return bits
+}
+
 func minFloat(a, b float64) float64 {
 	if a == 0 {
 		return b
@@ -506,7 +738,7 @@ func (w *sweepWriter) Write(row sweepRow) error {
 	}
 	if w.csv != nil {
 		if !w.wroteHdr {
-			header := []string{"target_bits", "ncols", "ell", "ellp", "rho", "theta", "eta", "issuance_bits", "showing_bits", "min_bits", "issuance_kb", "showing_kb", "issuance_time_s", "showing_time_s", "issuance_dq", "showing_dq", "issuance_fpar", "showing_fpar", "issuance_fagg", "showing_fagg"}
+			header := []string{"target_bits", "ncols", "ell", "ellp", "rho", "theta", "eta", "issuance_bits", "showing_bits", "min_bits", "issuance_kb", "showing_kb", "issuance_time_s", "showing_time_s", "issuance_dq", "showing_dq", "issuance_fpar", "showing_fpar", "issuance_fagg", "showing_fagg", "batch_k", "batch_kb", "batch_time_s", "issuance_est_kb", "showing_est_kb", "ntru_key_bits", "forgery_bits", "binding_bits", "lift_prime_bits", "lift_ell", "lift_ellp", "lift_rho", "lift_theta", "lift_eta", "lift_issuance_bits", "lift_issuance_kb", "lift_issuance_time_s"}
 			if err := w.csv.Write(header); err != nil {
 				return err
 			}
@@ -533,6 +765,23 @@ func (w *sweepWriter) Write(row sweepRow) error {
 			strconv.Itoa(row.ShowFpar),
 			strconv.Itoa(row.IssFagg),
 			strconv.Itoa(row.ShowFagg),
+			strconv.Itoa(row.BatchK),
+			fmt.Sprintf("%.2f", row.BatchKB),
+			fmt.Sprintf("%.4f", row.BatchTimeSec),
+			fmt.Sprintf("%.2f", row.IssEstKB),
+			fmt.Sprintf("%.2f", row.ShowEstKB),
+			fmt.Sprintf("%.2f", row.KeyBits),
+			fmt.Sprintf("%.2f", row.ForgeBits),
+			fmt.Sprintf("%.2f", row.BindBits),
+			strconv.Itoa(row.LiftPrimeBits),
+			strconv.Itoa(row.LiftEll),
+			strconv.Itoa(row.LiftEllPrime),
+			strconv.Itoa(row.LiftRho),
+			strconv.Itoa(row.LiftTheta),
+			strconv.Itoa(row.LiftEta),
+			fmt.Sprintf("%.2f", row.LiftIssBits),
+			fmt.Sprintf("%.2f", row.LiftIssKB),
+			fmt.Sprintf("%.4f", row.LiftIssTimeSec),
 		}
 		if err := w.csv.Write(rec); err != nil {
 			return err
@@ -546,6 +795,14 @@ func (w *sweepWriter) Write(row sweepRow) error {
 	}
 	fmt.Printf("target=%d bits; min=%.2f (iss=%.2f show=%.2f) params NCols=%d ℓ=%d ℓ'=%d ρ=%d θ=%d η=%d\n",
 		row.TargetBits, row.MinBits, row.IssBits, row.ShowBits, row.NCols, row.Ell, row.EllPrime, row.Rho, row.Theta, row.Eta)
+	if row.BatchK > 1 {
+		fmt.Printf("  batch k=%d: %.2f KB (%.2f KB/showing) vs %d×%.2f KB separate\n",
+			row.BatchK, row.BatchKB, row.BatchKB/float64(row.BatchK), row.BatchK, row.ShowKB)
+	}
+	if row.LiftPrimeBits > 0 {
+		fmt.Printf("  lifted %d-bit field (ℓ=%d ℓ'=%d ρ=%d θ=%d η=%d): issuance %.2f bits, %.2f KB, %.3fs vs %.2f bits, %.2f KB, %.3fs over q\n",
+			row.LiftPrimeBits, row.LiftEll, row.LiftEllPrime, row.LiftRho, row.LiftTheta, row.LiftEta, row.LiftIssBits, row.LiftIssKB, row.LiftIssTimeSec, row.IssBits, row.IssKB, row.IssTimeSec)
+	}
 	return nil
 }
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"math/rand"
@@ -12,15 +13,33 @@ import (
 	"time"
 
 	"vSIS-Signature/PIOP"
+	"vSIS-Signature/commitment"
 	"vSIS-Signature/credential"
 	"vSIS-Signature/issuance"
 	"vSIS-Signature/ntru"
+	ntrurio "vSIS-Signature/ntru/io"
 	"vSIS-Signature/ntru/keys"
+	"vSIS-Signature/pubparams"
+	"vSIS-Signature/security"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
 
 func main() {
+	reportPath := flag.String("report", "", "write a JSON constraint-family diagnostic report of the pre-sign verification to this path")
+	lenM1 := flag.Int("len-m1", 1, "number of ring elements in the m1 block")
+	lenM2 := flag.Int("len-m2", 1, "number of ring elements in the m2 block")
+	lenRU0 := flag.Int("len-ru0", 1, "number of ring elements in the rU0 block (and r0, k0)")
+	lenRU1 := flag.Int("len-ru1", 1, "number of ring elements in the rU1 block (and r1, k1)")
+	lenR := flag.Int("len-r", 5, "number of ring elements in the commitment randomness r")
+	acRows := flag.Int("ac-rows", 1, "rows of the commitment matrix Ac = [I | A']")
+	commitBits := flag.Float64("commit-bits", credential.MinHidingBits, "if > 0, derive -ac-rows and -len-r so that Ac is binding and hiding at this many bits")
+	challengeMode := flag.String("challenge", "interactive", "issuer challenge: interactive (issuer sends RI0/RI1) or non-interactive (derived from Com, issuer key and -label)")
+	label := flag.String("label", "vSIS-issuance-demo", "issuance context bound into a non-interactive challenge; must be unique per issuance")
+	flag.Parse()
+	if *challengeMode != "interactive" && *challengeMode != "non-interactive" {
+		log.Fatalf("unknown -challenge %q (want interactive or non-interactive)", *challengeMode)
+	}
 	log.Println("[issuance-cli] starting issuance demo")
 
 	ringQ, err := credential.LoadDefaultRing()
@@ -30,88 +49,147 @@ func main() {
 	bound := int64(8)
 	opts := PIOP.SimOpts{Credential: true, Theta: 4, EllPrime: 2, Rho: 2, NCols: 4, Ell: 24, Eta: 17}
 
-	// Sample a fresh random Ac of correct dimensions (lenM1.. = 1 each for demo) and save it.
-	lenM1, lenM2, lenRU0, lenRU1, lenR := 1, 1, 1, 1, 1
-	cols := lenM1 + lenM2 + lenRU0 + lenRU1 + lenR
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	sampleMatrix := func() [][]*ring.Poly {
-		mat := make([][]*ring.Poly, cols)
-		for i := 0; i < cols; i++ {
-			mat[i] = make([]*ring.Poly, cols)
-			for j := 0; j < cols; j++ {
-				p := ringQ.NewPoly()
-				for k := 0; k < ringQ.N; k++ {
-					p.Coeffs[0][k] = uint64(rng.Int63()) % ringQ.Modulus[0]
-				}
-				ringQ.NTT(p, p)
-				mat[i][j] = p
-			}
+	blocks := PIOP.CredentialBlocks{M1: *lenM1, M2: *lenM2, RU0: *lenRU0, RU1: *lenRU1, R: *lenR}
+	height := *acRows
+	if *commitBits > 0 {
+		msgCols := blocks.M1 + blocks.M2 + blocks.RU0 + blocks.RU1
+		size, err := security.SizeCommitment(ringQ.N, float64(ringQ.Modulus[0]), bound, msgCols, *commitBits, security.CoreSVPClassical)
+		if err != nil {
+			log.Fatalf("size commitment: %v", err)
 		}
-		return mat
+		height, blocks.R = size.Height, size.RandCols
+	}
+	if err := blocks.Validate(); err != nil {
+		log.Fatalf("block lengths: %v", err)
+	}
+	// Publish a fresh bundle (seeds for B and Ac = [I | A'], digest of the
+	// signer key behind A) and save the params pointing to it.
+	var pkPath string
+	h, err := ntrurio.LoadPublicH("ntru_keys/public.json")
+	if err == nil {
+		pkPath = "ntru_keys/public.json"
+	} else {
+		log.Printf("[issuance-cli] warning: no public key bound into the bundle: %v", err)
+	}
+	manifest, err := pubparams.New(ringQ, height, blocks.CommitCols(), pkPath, h)
+	if err != nil {
+		log.Fatalf("public parameters: %v", err)
 	}
-	Ac := sampleMatrix()
-	if err := saveAcJSON("credential/Ac.json", Ac); err != nil {
-		log.Printf("[issuance-cli] warning: could not save Ac.json: %v", err)
+	if err := pubparams.Save("credential/manifest.json", manifest); err != nil {
+		log.Fatalf("save manifest: %v", err)
 	}
-	// Save params file pointing to B/Ac.
-	if err := saveParamsJSON("credential/params.json", "credential/Ac.json", "Parameters/Bmatrix.json", bound, lenM1, lenM2, lenRU0, lenRU1, lenR); err != nil {
+	bundle, err := pubparams.Load("credential/manifest.json", ringQ)
+	if err != nil {
+		log.Fatalf("load manifest: %v", err)
+	}
+	acParams := bundle.AcParams
+	log.Printf("[issuance-cli] public parameters %s (digest %x)", "credential/manifest.json", bundle.Digest)
+	binding := security.AcBinding(ringQ.N, acParams.Height, acParams.Width, float64(ringQ.Modulus[0]), bound, security.CoreSVPClassical)
+	hiding := commitment.HidingBits(ringQ.Modulus[0], ringQ.N, acParams.Height, blocks.R, bound)
+	log.Printf("[issuance-cli] Ac %d×%d: binding=%s bits, hiding=%.2f bits", acParams.Height, acParams.Width, security.FormatBits(binding.Bits), hiding)
+	if err := saveParamsJSON("credential/params.json", "manifest.json", bound, blocks.M1, blocks.M2, blocks.RU0, blocks.RU1, blocks.R); err != nil {
 		log.Printf("[issuance-cli] warning: could not save params.json: %v", err)
 	}
 	params := &credential.Params{
-		Ac:     Ac,
-		BPath:  "Parameters/Bmatrix.json",
-		AcPath: "credential/Ac.json",
-		BoundB: bound,
-		RingQ:  ringQ,
-		LenM1:  lenM1,
-		LenM2:  lenM2,
-		LenRU0: lenRU0,
-		LenRU1: lenRU1,
-		LenR:   lenR,
+		Ac:         bundle.Ac,
+		Commitment: acParams,
+		Bundle:     bundle,
+		BoundB:     bound,
+		RingQ:      ringQ,
+		LenM1:      blocks.M1,
+		LenM2:      blocks.M2,
+		LenRU0:     blocks.RU0,
+		LenRU1:     blocks.RU1,
+		LenR:       blocks.R,
 	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	// Holder secrets (coeff domain) sampled by setting bounded evaluation-domain values,
 	// then inverse-NTT back to coeffs (so bound checks in NTT pass).
 	ncols := opts.NCols
-	m1 := samplePackedHalfEval(ringQ, params.BoundB, ncols, rng, true)
-	m2 := samplePackedHalfEval(ringQ, params.BoundB, ncols, rng, false)
-	ru0 := sampleBoundedEval(ringQ, params.BoundB, rng)
-	ru1 := sampleBoundedEval(ringQ, params.BoundB, rng)
-	rPoly := sampleBoundedEval(ringQ, params.BoundB, rng)
-
-	// Issuer challenge (evaluation domain / NTT): RI*=1.
-	ri0 := makePolyConstNTT(ringQ, 1)
-	ri1 := makePolyConstNTT(ringQ, 1)
-	ch := issuance.Challenge{RI0: []*ring.Poly{ri0}, RI1: []*ring.Poly{ri1}}
-
-	// Prepare commit.
-	inputs := issuance.Inputs{
-		M1:  []*ring.Poly{m1},
-		M2:  []*ring.Poly{m2},
-		RU0: []*ring.Poly{ru0},
-		RU1: []*ring.Poly{ru1},
-		R:   []*ring.Poly{rPoly},
+	sampleBlock := func(n int, sample func() *ring.Poly) []*ring.Poly {
+		out := make([]*ring.Poly, n)
+		for i := range out {
+			out[i] = sample()
+		}
+		return out
 	}
-	com, err := issuance.PrepareCommit(params, inputs)
-	if err != nil {
-		log.Fatalf("prepare commit: %v", err)
+	inputs := issuance.Inputs{
+		M1:  sampleBlock(blocks.M1, func() *ring.Poly { return samplePackedHalfEval(ringQ, params.BoundB, ncols, rng, true) }),
+		M2:  sampleBlock(blocks.M2, func() *ring.Poly { return samplePackedHalfEval(ringQ, params.BoundB, ncols, rng, false) }),
+		RU0: sampleBlock(blocks.RU0, func() *ring.Poly { return sampleBoundedEval(ringQ, params.BoundB, rng) }),
+		RU1: sampleBlock(blocks.RU1, func() *ring.Poly { return sampleBoundedEval(ringQ, params.BoundB, rng) }),
+		R:   sampleBlock(blocks.R, func() *ring.Poly { return sampleBoundedEval(ringQ, params.BoundB, rng) }),
 	}
-	log.Printf("[issuance-cli] Com rows=%d", len(com))
 
-	// Apply challenge → R0/R1/K*/T.
-	state, err := issuance.ApplyChallenge(params, inputs, ch)
-	if err != nil {
-		log.Fatalf("apply challenge: %v", err)
-	}
-	log.Printf("[issuance-cli] T[0]=%d", state.T[0])
+	var (
+		ch         issuance.Challenge
+		com        commitment.Vector
+		state      *issuance.State
+		proof      *PIOP.Proof
+		proofDur   time.Duration
+		proofStart = time.Now()
+	)
+	if *challengeMode == "interactive" {
+		// Issuer challenge (evaluation domain / NTT): RI*=1.
+		ch = issuance.Challenge{
+			RI0: sampleBlock(blocks.RU0, func() *ring.Poly { return makePolyConstNTT(ringQ, 1) }),
+			RI1: sampleBlock(blocks.RU1, func() *ring.Poly { return makePolyConstNTT(ringQ, 1) }),
+		}
 
-	// Build and verify pre-sign proof.
-	proofStart := time.Now()
-	proof, err := issuance.ProvePreSign(params, ch, com, inputs, state, opts)
-	if err != nil {
-		log.Fatalf("prove pre-sign: %v", err)
+		// Prepare commit.
+		com, err = issuance.PrepareCommit(params, inputs)
+		if err != nil {
+			log.Fatalf("prepare commit: %v", err)
+		}
+		log.Printf("[issuance-cli] Com rows=%d", len(com))
+
+		// Apply challenge → R0/R1/K*/T.
+		state, err = issuance.ApplyChallenge(params, inputs, ch)
+		if err != nil {
+			log.Fatalf("apply challenge: %v", err)
+		}
+		state.Com = com
+		log.Printf("[issuance-cli] T[0]=%d", state.T[0])
+
+		// Build and verify pre-sign proof.
+		proofStart = time.Now()
+		proof, err = issuance.ProvePreSign(params, ch, com, inputs, state, opts)
+		if err != nil {
+			log.Fatalf("prove pre-sign: %v", err)
+		}
+		proofDur = time.Since(proofStart)
+	} else {
+		// One holder message (Com, T, π_t); RI* is derived from Com.
+		issuerPK, err := keys.LoadPublic()
+		if err != nil {
+			log.Fatalf("load issuer public key: %v", err)
+		}
+		var req *issuance.Request
+		req, ch, state, err = issuance.RequestNonInteractive(params, issuerPK, []byte(*label), inputs, opts)
+		if err != nil {
+			log.Fatalf("non-interactive request: %v", err)
+		}
+		proofDur = time.Since(proofStart)
+		com, proof = req.Com, req.Proof
+		log.Printf("[issuance-cli] non-interactive request: Com rows=%d T[0]=%d label=%q", len(com), req.T[0], *label)
+		if _, ok, err := issuance.VerifyRequest(params, issuerPK, []byte(*label), req, opts); err != nil || !ok {
+			log.Fatalf("issuer rejected request: ok=%v err=%v", ok, err)
+		}
+	}
+	if *reportPath != "" {
+		report, err := issuance.VerifyPreSignReport(params, ch, com, state, proof, opts)
+		if report != nil {
+			if werr := writeReport(*reportPath, report); werr != nil {
+				log.Printf("[issuance-cli] warning: could not write report: %v", werr)
+			} else {
+				log.Printf("[issuance-cli] verification report written to %s (failed families: %v)", *reportPath, report.FailedFamilies())
+			}
+		}
+		if err != nil {
+			log.Fatalf("verify pre-sign failed: %v", err)
+		}
 	}
-	proofDur := time.Since(proofStart)
 	ok, err := issuance.VerifyPreSign(params, ch, com, state, proof, opts)
 	if err != nil || !ok {
 		log.Fatalf("verify pre-sign failed: ok=%v err=%v", ok, err)
@@ -209,35 +287,9 @@ func printProofReport(prefix string, proof *PIOP.Proof, opts PIOP.SimOpts, ringQ
 }
 
 func printWitnessRowBreakdown(prefix string, in issuance.Inputs, st *issuance.State, maskRows int) {
-	base := 0
-	if len(in.M1) > 0 {
-		base++
-	}
-	if len(in.M2) > 0 {
-		base++
-	}
-	if len(in.RU0) > 0 {
-		base++
-	}
-	if len(in.RU1) > 0 {
-		base++
-	}
-	if len(in.R) > 0 {
-		base++
-	}
+	base := len(in.M1) + len(in.M2) + len(in.RU0) + len(in.RU1) + len(in.R)
 	if st != nil {
-		if len(st.R0) > 0 {
-			base++
-		}
-		if len(st.R1) > 0 {
-			base++
-		}
-		if len(st.K0) > 0 {
-			base++
-		}
-		if len(st.K1) > 0 {
-			base++
-		}
+		base += len(st.R0) + len(st.R1) + len(st.K0) + len(st.K1)
 	}
 	if base == 0 {
 		log.Printf("%sno witness rows (base=0)", prefix)
@@ -299,21 +351,23 @@ func saveCredentialState(p *credential.Params, in issuance.Inputs, st *issuance.
 		return out
 	}
 	state := credential.State{
-		M1:     polyVec(in.M1, false),
-		M2:     polyVec(in.M2, false),
-		RU0:    polyVec(in.RU0, false),
-		RU1:    polyVec(in.RU1, false),
-		R:      polyVec(in.R, false),
-		R0:     polyVec(st.R0, false),
-		R1:     polyVec(st.R1, false),
-		K0:     polyVec(st.K0, false),
-		K1:     polyVec(st.K1, false),
-		T:      st.T,
-		Com:    polyVec(st.Com, true),
-		RI0:    polyVec(ch.RI0, true),
-		RI1:    polyVec(ch.RI1, true),
-		BPath:  p.BPath,
-		AcPath: p.AcPath,
+		M1:           polyVec(in.M1, false),
+		M2:           polyVec(in.M2, false),
+		RU0:          polyVec(in.RU0, false),
+		RU1:          polyVec(in.RU1, false),
+		R:            polyVec(in.R, false),
+		R0:           polyVec(st.R0, false),
+		R1:           polyVec(st.R1, false),
+		K0:           polyVec(st.K0, false),
+		K1:           polyVec(st.K1, false),
+		T:            st.T,
+		Com:          polyVec(st.Com, true),
+		RI0:          polyVec(ch.RI0, true),
+		RI1:          polyVec(ch.RI1, true),
+		BPath:        p.BPath,
+		AcPath:       p.AcPath,
+		Manifest:     "credential/manifest.json",
+		ParamsDigest: fmt.Sprintf("%x", p.ParamsDigest()),
 	}
 	// If signature is present, store s0 (preimage) as U.
 	if sig != nil && len(sig.Signature.S0) > 0 {
@@ -348,27 +402,25 @@ func polyToInt64Local(p *ring.Poly, ringQ *ring.Ring) []int64 {
 	return out
 }
 
-// saveParamsJSON writes params.json pointing to Ac/B and lengths.
-func saveParamsJSON(path, acPath, bPath string, bound int64, lenM1, lenM2, lenRU0, lenRU1, lenR int) error {
+// saveParamsJSON writes params.json with the manifest path and lengths.
+func saveParamsJSON(path, manifest string, bound int64, lenM1, lenM2, lenRU0, lenRU1, lenR int) error {
 	type paramsFile struct {
-		AcPath string `json:"AcPath"`
-		BPath  string `json:"BPath"`
-		BoundB int64  `json:"BoundB"`
-		LenM1  int    `json:"LenM1"`
-		LenM2  int    `json:"LenM2"`
-		LenRU0 int    `json:"LenRU0"`
-		LenRU1 int    `json:"LenRU1"`
-		LenR   int    `json:"LenR"`
+		Manifest string `json:"Manifest"`
+		BoundB   int64  `json:"BoundB"`
+		LenM1    int    `json:"LenM1"`
+		LenM2    int    `json:"LenM2"`
+		LenRU0   int    `json:"LenRU0"`
+		LenRU1   int    `json:"LenRU1"`
+		LenR     int    `json:"LenR"`
 	}
 	pf := paramsFile{
-		AcPath: acPath,
-		BPath:  bPath,
-		BoundB: bound,
-		LenM1:  lenM1,
-		LenM2:  lenM2,
-		LenRU0: lenRU0,
-		LenRU1: lenRU1,
-		LenR:   lenR,
+		Manifest: manifest,
+		BoundB:   bound,
+		LenM1:    lenM1,
+		LenM2:    lenM2,
+		LenRU0:   lenRU0,
+		LenRU1:   lenRU1,
+		LenR:     lenR,
 	}
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
@@ -380,40 +432,6 @@ func saveParamsJSON(path, acPath, bPath string, bound int64, lenM1, lenM2, lenRU
 	return os.WriteFile(path, data, 0o644)
 }
 
-// saveAcJSON writes Ac (NTT) into coeff-domain JSON for reuse.
-func saveAcJSON(path string, Ac [][]*ring.Poly) error {
-	if len(Ac) == 0 {
-		return fmt.Errorf("empty Ac")
-	}
-	rows := len(Ac)
-	cols := len(Ac[0])
-	acOut := make([][][]uint64, rows)
-	for i := 0; i < rows; i++ {
-		acOut[i] = make([][]uint64, cols)
-		for j := 0; j < cols; j++ {
-			p := Ac[i][j]
-			cp := p.CopyNew()
-			// Inverse NTT to coeff
-			// We need ring; assume modulus same; use default ring from lengths.
-			// Here we assume cp already coeff (since sampled in NTT); so just copy coeffs.
-			acOut[i][j] = make([]uint64, len(cp.Coeffs[0]))
-			copy(acOut[i][j], cp.Coeffs[0])
-		}
-	}
-	type acJSON struct {
-		Ac [][][]uint64 `json:"Ac"`
-	}
-	payload := acJSON{Ac: acOut}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(payload, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(path, data, 0o644)
-}
-
 // sampleBounded samples coefficients in [-bound, bound] uniformly.
 func sampleBoundedEval(r *ring.Ring, bound int64, rng *rand.Rand) *ring.Poly {
 	pNTT := r.NewPoly()
@@ -503,3 +521,17 @@ func loadKeyCoeffs(path string) ([][]int64, error) {
 	}
 	return out, nil
 }
+
+// writeReport stores a verification report as JSON.
+func writeReport(path string, report *PIOP.VerificationReport) error {
+	data, err := report.JSON()
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -91,6 +91,7 @@ func main() {
 		if _, err := crand.Read(x1Seed); err != nil {
 			log.Fatal(err)
 		}
+		// Same standalone B file as ntru/signverify; not a bundle.
 		coeffs, err := ntru.ComputeTargetFromSeeds(&sys, "Parameters/Bmatrix.json", mSeed, x0Seed, x1Seed)
 		if err != nil {
 			log.Fatal(err)
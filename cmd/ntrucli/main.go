@@ -12,10 +12,12 @@ import (
 	"time"
 
 	PIOP "vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
 	measure "vSIS-Signature/measure"
 	ntru "vSIS-Signature/ntru"
 	"vSIS-Signature/ntru/keys"
 	"vSIS-Signature/ntru/signverify"
+	"vSIS-Signature/security"
 )
 
 func usage() {
@@ -46,7 +48,30 @@ Subcommands:
   verify   Verify ./ntru_keys/signature.json against embedded params & public key
 
   pacs          Run a PACS simulation (large-field defaults)
-  pacs-small    Run a PACS simulation using the small-field variant (θ>1)`)
+  pacs-small    Run a PACS simulation using the small-field variant (θ>1)
+
+  params   Select SimOpts for a target soundness level (Pareto set)
+           Flags:
+             -rows        <int>        committed witness rows (required)
+             -constraints <int>        parallel constraints |F_par|
+             -agg         <int>        aggregated constraints |F_agg|
+             -degree      <int>        max parallel constraint degree d (default: 2)
+             -aggdegree   <int>        max aggregated constraint degree d' (default: 1)
+             -target      <int>        target soundness bits (default: 128)
+             -objective   <string>     size|time|balanced (default: size)
+             -theta       <list>       candidate extension degrees θ (default: 1,2,3,4)
+             -ncols       <list>       candidate |Ω| values (default: 4,8,12,16,24,32)
+             -kappa       <list>       candidate grinding bits per round (default: 0,4,8,12,16)
+             -top         <int>        print at most this many candidates (0 = all)
+
+  security Estimate bit-security of NTRU key recovery, forgery and Ac binding
+           Flags:
+             -model       <string>     classical|quantum|bkz cost model (default: classical)
+             -alpha       <float>      annulus keygen quality window α (default: 1.20)
+             -credparams  <path>       credential params JSON (BoundB and Ac shape)
+             -bound       <int>        commitment bound B when -credparams is unset
+             -ac-rows     <int>        Ac rows when -credparams is unset (0 = skip binding)
+             -ac-cols     <int>        Ac columns when -credparams is unset`)
 	os.Exit(1)
 }
 
@@ -80,6 +105,10 @@ func main() {
 		runPACS(os.Args[2:])
 	case "pacs-small":
 		runPACSSmall(os.Args[2:])
+	case "params":
+		runParams(os.Args[2:])
+	case "security":
+		runSecurity(os.Args[2:])
 	default:
 		usage()
 	}
@@ -262,9 +291,10 @@ func renderPACSReport(prefix string, rep PIOP.SimReport) {
 		prefix,
 		rep.NCols, rep.Ell, rep.EllPrime, rep.Rho, rep.Eta, rep.Theta,
 		rep.Soundness.DQ, rep.Opts.Lambda, rep.Opts.Kappa)
-	fmt.Printf("%sMerkle leaves: N=%d opened=%d (mask=%d tail=%d)\n",
+	fmt.Printf("%sGrinding attempts: %v\n", prefix, rep.GrindAttempts)
+	fmt.Printf("%sMerkle leaves: N=%d opened=%d (tail=%d)\n",
 		prefix,
-		rep.NLeaves, rep.MerkleOpens, rep.MaskLeaves, rep.TailLeaves)
+		rep.NLeaves, rep.MerkleOpens, rep.TailLeaves)
 	fmt.Printf("%sRows: witness-cols=%d parallel=%d aggregated=%d\n",
 		prefix,
 		rep.WitnessCols, rep.ParallelRows, rep.AggregatedRows)
@@ -322,6 +352,7 @@ func runPACS(args []string) {
 	k3 := fs.Int("kappa3", 0, "grinding bits κ₃")
 	k4 := fs.Int("kappa4", 0, "grinding bits κ₄")
 	lambda := fs.Int("lambda", 256, "Fiat–Shamir security parameter λ")
+	grindWorkers := fs.Int("grind-workers", 0, "goroutines used for FS grinding (0 = GOMAXPROCS)")
 	chainW := fs.Int("W", 4, "ℓ∞ chain window bits (B = 2^W)")
 	chainLFlag := fs.String("L", "auto", "ℓ∞ chain digit count (integer) or 'auto'")
 	fs.Parse(args)
@@ -332,18 +363,19 @@ func runPACS(args []string) {
 	}
 
 	opts := PIOP.SimOpts{
-		NCols:      *ncols,
-		Ell:        *ell,
-		EllPrime:   *ellp,
-		Rho:        *rho,
-		Eta:        *eta,
-		NLeaves:    *nLeaves,
-		Theta:      *theta,
-		DQOverride: *dq,
-		Kappa:      [4]int{*k1, *k2, *k3, *k4},
-		Lambda:     *lambda,
-		ChainW:     *chainW,
-		ChainL:     chainL,
+		NCols:        *ncols,
+		Ell:          *ell,
+		EllPrime:     *ellp,
+		Rho:          *rho,
+		Eta:          *eta,
+		NLeaves:      *nLeaves,
+		Theta:        *theta,
+		DQOverride:   *dq,
+		Kappa:        [4]int{*k1, *k2, *k3, *k4},
+		Lambda:       *lambda,
+		ChainW:       *chainW,
+		ChainL:       chainL,
+		GrindWorkers: *grindWorkers,
 	}
 
 	rep, err := PIOP.RunOnce(opts)
@@ -371,6 +403,7 @@ func runPACSSmall(args []string) {
 	k3 := fs.Int("kappa3", 0, "grinding bits κ₃")
 	k4 := fs.Int("kappa4", 0, "grinding bits κ₄")
 	lambda := fs.Int("lambda", 256, "Fiat–Shamir security parameter λ")
+	grindWorkers := fs.Int("grind-workers", 0, "goroutines used for FS grinding (0 = GOMAXPROCS)")
 	ellPrime := fs.Int("ellp", 2, "ℓ' evaluation queries")
 	chainW := fs.Int("W", 3, "ℓ∞ chain window bits (B = 2^W)")
 	chainLFlag := fs.String("L", "auto", "ℓ∞ chain digit count (integer) or 'auto'")
@@ -384,18 +417,19 @@ func runPACSSmall(args []string) {
 		log.Fatalf("pacs-small: %v", err)
 	}
 	rep, err := PIOP.RunOnce(PIOP.SimOpts{
-		NCols:      *ncols,
-		Ell:        *ell,
-		EllPrime:   *ellPrime,
-		Rho:        *rho,
-		Eta:        *eta,
-		NLeaves:    *nLeaves,
-		Theta:      *theta,
-		DQOverride: *dq,
-		Kappa:      [4]int{*k1, *k2, *k3, *k4},
-		Lambda:     *lambda,
-		ChainW:     *chainW,
-		ChainL:     chainL,
+		NCols:        *ncols,
+		Ell:          *ell,
+		EllPrime:     *ellPrime,
+		Rho:          *rho,
+		Eta:          *eta,
+		NLeaves:      *nLeaves,
+		Theta:        *theta,
+		DQOverride:   *dq,
+		Kappa:        [4]int{*k1, *k2, *k3, *k4},
+		Lambda:       *lambda,
+		ChainW:       *chainW,
+		ChainL:       chainL,
+		GrindWorkers: *grindWorkers,
 	})
 	if err != nil {
 		log.Fatalf("pacs-small: %v", err)
@@ -406,3 +440,125 @@ func runPACSSmall(args []string) {
 		reportVerifyMetrics("[small-field] ", proof)
 	}
 }
+
+// runParams prints the SelectParams Pareto set for a statement shape.
+func runParams(args []string) {
+	fs := flag.NewFlagSet("params", flag.ExitOnError)
+	rows := fs.Int("rows", 0, "committed witness rows")
+	constraints := fs.Int("constraints", 0, "parallel constraints |F_par|")
+	agg := fs.Int("agg", 0, "aggregated constraints |F_agg|")
+	degree := fs.Int("degree", 2, "max parallel constraint degree d")
+	aggDegree := fs.Int("aggdegree", 1, "max aggregated constraint degree d'")
+	target := fs.Int("target", 128, "target soundness bits")
+	objectiveFlag := fs.String("objective", "size", "size|time|balanced")
+	thetaFlag := fs.String("theta", "", "candidate extension degrees θ (comma-separated)")
+	ncolsFlag := fs.String("ncols", "", "candidate |Ω| values (comma-separated)")
+	kappaFlag := fs.String("kappa", "", "candidate grinding bits per round (comma-separated)")
+	top := fs.Int("top", 20, "print at most this many candidates (0 = all)")
+	fs.Parse(args)
+
+	if *rows <= 0 {
+		log.Fatalf("params: -rows must be > 0")
+	}
+	objective, err := PIOP.ParseParamObjective(*objectiveFlag)
+	if err != nil {
+		log.Fatalf("params: %v", err)
+	}
+	space := PIOP.DefaultParamSpace()
+	for _, f := range []struct {
+		spec string
+		dst  *[]int
+	}{{*thetaFlag, &space.Theta}, {*ncolsFlag, &space.NCols}, {*kappaFlag, &space.Kappa}} {
+		if f.spec == "" {
+			continue
+		}
+		vals, err := parseIntCSV(f.spec)
+		if err != nil {
+			log.Fatalf("params: %v", err)
+		}
+		*f.dst = vals
+	}
+	shape := PIOP.StatementShape{
+		Rows:           *rows,
+		Constraints:    *constraints,
+		AggConstraints: *agg,
+		Degree:         *degree,
+		AggDegree:      *aggDegree,
+	}
+	front, err := PIOP.SelectParamsIn(shape, *target, objective, space)
+	if err != nil {
+		log.Fatalf("params: %v", err)
+	}
+	fmt.Printf("[params] %d Pareto-optimal candidates for %d bits (objective=%s)\n", len(front), *target, *objectiveFlag)
+	fmt.Printf("%6s %5s %4s %4s %4s %4s %4s %-15s %8s %10s %8s\n", "ncols", "theta", "ell", "ellp", "rho", "eta", "dQ", "kappa", "bits", "est_bytes", "cost")
+	for i, c := range front {
+		if *top > 0 && i >= *top {
+			break
+		}
+		o := c.Opts
+		kappa := fmt.Sprintf("%d,%d,%d,%d", o.Kappa[0], o.Kappa[1], o.Kappa[2], o.Kappa[3])
+		fmt.Printf("%6d %5d %4d %4d %4d %4d %4d %-15s %8.2f %10d %8.3g\n",
+			o.NCols, o.Theta, o.Ell, o.EllPrime, o.Rho, o.Eta, c.Budget.DQ, kappa, c.Bits, c.SizeBytes, c.ProverCost)
+	}
+}
+
+// runSecurity prints lattice hardness estimates for the system parameters.
+func runSecurity(args []string) {
+	fs := flag.NewFlagSet("security", flag.ExitOnError)
+	modelFlag := fs.String("model", "classical", "classical|quantum|bkz")
+	alpha := fs.Float64("alpha", 1.20, "annulus keygen quality window α")
+	credParams := fs.String("credparams", "", "credential params JSON (BoundB and Ac shape)")
+	bound := fs.Int64("bound", 0, "commitment bound B when -credparams is unset")
+	acRows := fs.Int("ac-rows", 0, "Ac rows when -credparams is unset (0 = skip binding)")
+	acCols := fs.Int("ac-cols", 0, "Ac columns when -credparams is unset")
+	fs.Parse(args)
+
+	model, err := security.ParseCostModel(*modelFlag)
+	if err != nil {
+		log.Fatalf("security: %v", err)
+	}
+	pp, err := signverify.LoadParamsForCLI()
+	if err != nil {
+		log.Fatalf("load params: %v", err)
+	}
+	par, err := ntru.NewParams(pp.N, new(big.Int).SetUint64(pp.Q))
+	if err != nil {
+		log.Fatalf("params: %v", err)
+	}
+	var cp *credential.Params
+	if *credParams != "" {
+		if cp, err = credential.LoadParamsFromFile(*credParams); err != nil {
+			log.Fatalf("security: %v", err)
+		}
+	}
+	in := security.NewInputs(par, *alpha, ntru.SamplerOpts{}, cp)
+	in.Beta = float64(pp.Beta)
+	if cp == nil {
+		in.BoundB, in.AcRows, in.AcCols = *bound, *acRows, *acCols
+	}
+	rep, err := security.Analyze(in, model)
+	if err != nil {
+		log.Fatalf("security: %v", err)
+	}
+	fmt.Printf("[security] N=%d q=%d model=%s σ_fg=%.3f γ=%.1f\n", par.N, pp.Q, model, in.KeySigma, in.ForgeBound)
+	for _, line := range rep.Lines() {
+		fmt.Println("  " + line)
+	}
+	fmt.Printf("[security] min=%s bits\n", security.FormatBits(rep.MinBits()))
+}
+
+func parseIntCSV(spec string) ([]int, error) {
+	var out []int
+	for _, tok := range strings.Split(spec, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		v, err := strconv.Atoi(tok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q: %w", tok, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
+	"flag"
 	"fmt"
 	"log"
 	"math/big"
@@ -12,14 +14,25 @@ import (
 
 	"vSIS-Signature/PIOP"
 	"vSIS-Signature/credential"
-	"vSIS-Signature/ntru/keys"
 	ntrurio "vSIS-Signature/ntru/io"
+	"vSIS-Signature/ntru/keys"
+	"vSIS-Signature/presentation"
 	"vSIS-Signature/prf"
+	"vSIS-Signature/pubparams"
+	vsishash "vSIS-Signature/vSIS-HASH"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
 
 func main() {
+	reportPath := flag.String("report", "", "write a JSON constraint-family diagnostic report of the showing verification to this path")
+	requestPath := flag.String("request", "", "answer this presentation request (JSON) instead of proving a bare showing")
+	counter := flag.Int("counter", 0, "rate-limit counter of the presentation (with -request)")
+	outPath := flag.String("out", "", "write the presentation to this path (with -request)")
+	nonceWindow := flag.Int("nonce-window", -1, "hide the nonce: its epoch lane (Unix days) is proven within this many epochs of today; -1 for a public random nonce")
+	rebind := flag.Bool("rebind", false, "re-bind the showing to the issuance commitment Com (credential issued under a bundle)")
+	scope := flag.String("scope", "", "prove a pseudonym for this verifier scope instead of a one-time tag (the credential's M2 must hold the PRF key)")
+	flag.Parse()
 	log.Printf("[showing-cli] starting showing demo")
 	ringQ, err := credential.LoadDefaultRing()
 	if err != nil {
@@ -37,7 +50,11 @@ func main() {
 	opts := PIOP.SimOpts{Credential: true, Theta: 4, EllPrime: 2, Rho: 2, NCols: 4, Ell: 24, Eta: 17}
 
 	// Build public matrices.
-	B, err := loadBFromState(ringQ, state)
+	bundle, err := loadBundleFromState(ringQ, state)
+	if err != nil {
+		log.Fatalf("load public parameters: %v", err)
+	}
+	B, err := loadBFromState(ringQ, state, bundle)
 	if err != nil {
 		log.Fatalf("load B: %v", err)
 	}
@@ -45,13 +62,23 @@ func main() {
 	if err != nil {
 		log.Fatalf("build witness: %v", err)
 	}
-	if err := checkPackedHalfEval(ringQ, wit.M1[0], opts.NCols, true); err != nil {
-		log.Fatalf("state m1 packing mismatch for ncols=%d: %v", opts.NCols, err)
+	for i, m1 := range wit.M1 {
+		if err := checkPackedHalfEval(ringQ, m1, opts.NCols, true); err != nil {
+			log.Fatalf("state m1[%d] packing mismatch for ncols=%d: %v", i, opts.NCols, err)
+		}
+	}
+	for i, m2 := range wit.M2 {
+		if err := checkPackedHalfEval(ringQ, m2, opts.NCols, false); err != nil {
+			log.Fatalf("state m2[%d] packing mismatch for ncols=%d: %v", i, opts.NCols, err)
+		}
 	}
-	if err := checkPackedHalfEval(ringQ, wit.M2[0], opts.NCols, false); err != nil {
-		log.Fatalf("state m2 packing mismatch for ncols=%d: %v", opts.NCols, err)
+	blocks := PIOP.BlocksOf(wit)
+	if !blocks.Single() {
+		if B, err = vsishash.ExpandB(ringQ, B, blocks.HashLayout()); err != nil {
+			log.Fatalf("expand B: %v", err)
+		}
 	}
-	A, err := buildSignatureMatrix(ringQ, state, len(wit.U))
+	A, err := buildSignatureMatrix(ringQ, state, bundle, len(wit.U))
 	if err != nil {
 		log.Fatalf("build A: %v", err)
 	}
@@ -62,7 +89,32 @@ func main() {
 	if err != nil {
 		log.Fatalf("prf key: %v", err)
 	}
+	if *requestPath != "" {
+		runPresentation(ringQ, bundle, wit, key, opts, *requestPath, *counter, *outPath)
+		return
+	}
 	nonce, noncePublic := sampleNonce(params.LenNonce, opts.NCols, ringQ.Modulus[0])
+	if *scope != "" {
+		if key, err = PIOP.PRFKeyFromM2(ringQ, wit.M2, opts.NCols, params.LenKey); err != nil {
+			log.Fatalf("pseudonym key (issue with -len-m2 %d or more): %v", (params.LenKey+opts.NCols/2-1)/(opts.NCols/2), err)
+		}
+		if nonce, err = prf.ScopeNonce(*scope, params); err != nil {
+			log.Fatalf("scope nonce: %v", err)
+		}
+		noncePublic = lanesFromElems(nonce, opts.NCols)
+	}
+	var domain *PIOP.NonceDomain
+	if *nonceWindow >= 0 {
+		if *scope != "" {
+			log.Fatalf("a pseudonym has a public scope nonce; drop -nonce-window")
+		}
+		// A hidden nonce binds the key to M2, as a pseudonym does.
+		if key, err = PIOP.PRFKeyFromM2(ringQ, wit.M2, opts.NCols, params.LenKey); err != nil {
+			log.Fatalf("hidden-nonce key (issue with -len-m2 %d or more): %v", (params.LenKey+opts.NCols/2-1)/(opts.NCols/2), err)
+		}
+		nonce, domain = epochNonce(params.LenNonce, *nonceWindow, time.Now())
+		noncePublic = nil
+	}
 	tag, err := prf.Tag(key, nonce, params)
 	if err != nil {
 		log.Fatalf("prf tag: %v", err)
@@ -90,6 +142,25 @@ func main() {
 		Tag:    tagPublic,
 		Nonce:  noncePublic,
 		BoundB: int64(8),
+		Blocks: blocks,
+		Scope:  *scope,
+	}
+	pub.NonceDomain = domain
+	if bundle != nil {
+		pub.ParamsDigest = bundle.Digest[:]
+	}
+	if *rebind {
+		if bundle == nil {
+			log.Fatalf("re-bound showing needs a credential issued under a bundle (for Ac)")
+		}
+		// The commitment opens with the issuance RU0, RU1 and R.
+		wit.RU0, wit.RU1, wit.R = polysFromInt64(ringQ, state.RU0), polysFromInt64(ringQ, state.RU1), polysFromInt64(ringQ, state.R)
+		pub.Ac = bundle.Ac
+		pub.Com = polysFromInt64(ringQ, state.Com)
+		for _, p := range pub.Com {
+			ringQ.NTT(p, p)
+		}
+		log.Printf("[showing-cli] re-binding to issuance commitment (%d rows)", len(pub.Com))
 	}
 
 	log.Printf("[showing-cli] building proof")
@@ -99,23 +170,82 @@ func main() {
 		log.Fatalf("build showing: %v", err)
 	}
 	proofDur := time.Since(proofStart)
+	if *reportPath != "" {
+		report, err := PIOP.VerifyWithConstraintsReport(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub, opts, PIOP.FSModeCredential)
+		if werr := writeReport(*reportPath, report); werr != nil {
+			log.Printf("[showing-cli] warning: could not write report: %v", werr)
+		} else {
+			log.Printf("[showing-cli] verification report written to %s (failed families: %v)", *reportPath, report.FailedFamilies())
+		}
+		if err != nil {
+			log.Fatalf("verify showing failed: %v", err)
+		}
+	}
 	ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub, opts, PIOP.FSModeCredential)
 	if err != nil || !ok {
 		log.Fatalf("verify showing failed: ok=%v err=%v", ok, err)
 	}
 	log.Printf("[showing-cli] showing proof verified")
+	if *scope != "" {
+		log.Printf("[showing-cli] pseudonym for %q: %v", *scope, tag)
+	}
+	if domain != nil {
+		log.Printf("[showing-cli] hidden nonce in epoch window %d±%d, tag %v", domain.Lanes[0].Center, domain.Lanes[0].Radius, tag)
+	}
 	printProofReport("[showing-cli] ", proof, opts, ringQ, proofDur)
 	printTranscriptBreakdown("[showing-cli] ", proof)
 }
 
-func loadBFromState(r *ring.Ring, st credential.State) ([]*ring.Poly, error) {
-	if len(st.B) > 0 {
-		out := make([]*ring.Poly, len(st.B))
-		for i := range st.B {
-			out[i] = polyFromInt64(r, st.B[i])
-			r.NTT(out[i], out[i])
+// runPresentation answers the request at path with the credential, verifies
+// the answer against the same request and optionally saves it.
+func runPresentation(r *ring.Ring, bundle *pubparams.Bundle, wit PIOP.WitnessInputs, key []prf.Elem, opts PIOP.SimOpts, path string, counter int, outPath string) {
+	if bundle == nil || bundle.H == nil || len(wit.U) != 2 {
+		log.Fatalf("presentation needs a credential issued under a bundle with a public key and a two-part signature")
+	}
+	req, err := presentation.LoadRequest(path)
+	if err != nil {
+		log.Fatalf("load request: %v", err)
+	}
+	ctx := context.Background()
+	log.Printf("[showing-cli] answering request of %s (context %q, counter %d)", req.Verifier, req.Context, counter)
+	proofStart := time.Now()
+	p, err := presentation.Prove(ctx, req, r, bundle, int64(8), wit, key, counter, opts)
+	if err != nil {
+		log.Fatalf("prove presentation: %v", err)
+	}
+	proofDur := time.Since(proofStart)
+	if err := presentation.Verify(ctx, req, r, bundle, int64(8), p, opts); err != nil {
+		log.Fatalf("verify presentation: %v", err)
+	}
+	log.Printf("[showing-cli] presentation verified in %s (proved in %s): disclosed %v, tag %s", time.Since(proofStart)-proofDur, proofDur, p.Disclosed, p.TagID())
+	if outPath != "" {
+		if err := p.Save(outPath); err != nil {
+			log.Fatalf("save presentation: %v", err)
 		}
-		return out, nil
+		log.Printf("[showing-cli] presentation written to %s", outPath)
+	}
+}
+
+// loadBundleFromState loads the public-parameter bundle the state names and
+// checks it is the one the credential was issued under; nil if the state
+// predates bundles.
+func loadBundleFromState(r *ring.Ring, st credential.State) (*pubparams.Bundle, error) {
+	if st.Manifest == "" {
+		return nil, nil
+	}
+	bundle, err := pubparams.Load(st.Manifest, r)
+	if err != nil {
+		return nil, err
+	}
+	if got := fmt.Sprintf("%x", bundle.Digest); got != st.ParamsDigest {
+		return nil, fmt.Errorf("manifest %s has digest %s, credential was issued under %s", st.Manifest, got, st.ParamsDigest)
+	}
+	return bundle, nil
+}
+
+func loadBFromState(r *ring.Ring, st credential.State, bundle *pubparams.Bundle) ([]*ring.Poly, error) {
+	if bundle != nil {
+		return bundle.B, nil
 	}
 	if st.BPath == "" {
 		return nil, fmt.Errorf("missing B in state")
@@ -134,7 +264,10 @@ func loadBFromState(r *ring.Ring, st credential.State) ([]*ring.Poly, error) {
 	return out, nil
 }
 
-func buildSignatureMatrix(r *ring.Ring, st credential.State, uCount int) ([][]*ring.Poly, error) {
+func buildSignatureMatrix(r *ring.Ring, st credential.State, bundle *pubparams.Bundle, uCount int) ([][]*ring.Poly, error) {
+	if bundle != nil && bundle.H != nil {
+		return bundle.SignatureMatrix(r, uCount)
+	}
 	if len(st.NTRUPublic) == 0 {
 		pk, err := keys.LoadPublic()
 		if err != nil {
@@ -209,12 +342,21 @@ func buildWitnessFromState(r *ring.Ring, st credential.State) (PIOP.WitnessInput
 		k1 = []*ring.Poly{base}
 	}
 
+	// RU0/RU1/R are committed but unconstrained after signing; zero rows of
+	// the stored block lengths keep the row layout of the credential.
+	zeros := func(n int) []*ring.Poly {
+		out := make([]*ring.Poly, max(n, 1))
+		for i := range out {
+			out[i] = base
+		}
+		return out
+	}
 	return PIOP.WitnessInputs{
 		M1:  m1,
 		M2:  m2,
-		RU0: []*ring.Poly{base},
-		RU1: []*ring.Poly{base},
-		R:   []*ring.Poly{base},
+		RU0: zeros(len(r0)),
+		RU1: zeros(len(r1)),
+		R:   zeros(len(st.R)),
 		R0:  r0,
 		R1:  r1,
 		K0:  k0,
@@ -255,6 +397,18 @@ func sampleNonce(lennonce, ncols int, q uint64) ([]prf.Elem, [][]int64) {
 	return nonce, public
 }
 
+// epochNonce returns a hidden nonce whose lane 0 is the epoch of now (Unix
+// days) and the domain a verifier in the same epoch accepts: lane 0 within
+// radius epochs, the other lanes pinned to 0.
+func epochNonce(lennonce, radius int, now time.Time) ([]prf.Elem, *PIOP.NonceDomain) {
+	epoch := now.Unix() / 86400
+	nonce := make([]prf.Elem, lennonce)
+	nonce[0] = prf.Elem(epoch)
+	domain := &PIOP.NonceDomain{Lanes: make([]PIOP.NonceWindow, lennonce)}
+	domain.Lanes[0] = PIOP.NonceWindow{Center: epoch, Radius: radius}
+	return nonce, domain
+}
+
 func randElem(q uint64) uint64 {
 	n, err := rand.Int(rand.Reader, new(big.Int).SetUint64(q))
 	if err != nil {
@@ -328,34 +482,8 @@ func buildConstLane(ncols int, v int64) []int64 {
 }
 
 func printWitnessRowBreakdown(prefix string, wit PIOP.WitnessInputs, prfRows int, maskRows int) {
-	base := 0
-	if len(wit.M1) > 0 {
-		base++
-	}
-	if len(wit.M2) > 0 {
-		base++
-	}
-	if len(wit.RU0) > 0 {
-		base++
-	}
-	if len(wit.RU1) > 0 {
-		base++
-	}
-	if len(wit.R) > 0 {
-		base++
-	}
-	if len(wit.R0) > 0 {
-		base++
-	}
-	if len(wit.R1) > 0 {
-		base++
-	}
-	if len(wit.K0) > 0 {
-		base++
-	}
-	if len(wit.K1) > 0 {
-		base++
-	}
+	base := len(wit.M1) + len(wit.M2) + len(wit.RU0) + len(wit.RU1) + len(wit.R) +
+		len(wit.R0) + len(wit.R1) + len(wit.K0) + len(wit.K1)
 	if len(wit.T) > 0 {
 		base++
 	}
@@ -449,3 +577,17 @@ func printProofReport(prefix string, proof *PIOP.Proof, opts PIOP.SimOpts, ringQ
 		prefix, rep.ProofKB, dur.Seconds(), rep.Soundness.TotalBits,
 		rep.NCols, rep.Ell, rep.EllPrime, rep.Rho, rep.Theta, rep.Eta)
 }
+
+// writeReport stores a verification report as JSON.
+func writeReport(path string, report *PIOP.VerificationReport) error {
+	data, err := report.JSON()
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"vSIS-Signature/credential"
+	"vSIS-Signature/wallet"
+)
+
+const defaultWallet = "credential/keys/wallet.json"
+
+func usage() {
+	fmt.Println(`usage: wallet <list|import|show|delete> [options]
+
+The passphrase is read from the environment variable named by -pass-env
+(default: VSIS_WALLET_PASSPHRASE).
+
+Common flags:
+  -wallet   <path>   wallet file (default: credential/keys/wallet.json)
+  -pass-env <name>   environment variable holding the passphrase
+
+Subcommands:
+  list     List stored credentials
+           Flags:
+             -issuer <id>      only this issuer
+             -schema <name>    only this schema
+
+  import   Import a holder state written by cmd/issuance (creates the wallet)
+           Flags:
+             -state  <path>    state file (default: credential/keys/credential_state.json)
+             -schema <name>    schema name (default: derived from the block lengths)
+             -label  <string>  free-form label
+
+  show     Print one credential's metadata and verifier usage
+           Usage: wallet show [flags] <id or unique ID prefix>
+           Flags:
+             -state            also print the stored holder state
+
+  delete   Remove one credential and its usage records
+           Usage: wallet delete [flags] <id or unique ID prefix>`)
+	os.Exit(1)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "list":
+		runList(os.Args[2:])
+	case "import":
+		runImport(os.Args[2:])
+	case "show":
+		runShow(os.Args[2:])
+	case "delete":
+		runDelete(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+type common struct {
+	path    *string
+	passEnv *string
+}
+
+func commonFlags(fs *flag.FlagSet) common {
+	return common{
+		path:    fs.String("wallet", defaultWallet, "wallet file"),
+		passEnv: fs.String("pass-env", "VSIS_WALLET_PASSPHRASE", "environment variable holding the passphrase"),
+	}
+}
+
+func (c common) passphrase() []byte {
+	pass := os.Getenv(*c.passEnv)
+	if pass == "" {
+		log.Fatalf("set %s to the wallet passphrase", *c.passEnv)
+	}
+	return []byte(pass)
+}
+
+func (c common) open() *wallet.Wallet {
+	w, err := wallet.Open(*c.path, c.passphrase())
+	if err != nil {
+		log.Fatalf("open wallet: %v", err)
+	}
+	return w
+}
+
+// short abbreviates a hex digest to its first 16 characters for display.
+func short(s string) string {
+	if len(s) > 16 {
+		return s[:16]
+	}
+	return s
+}
+
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	c := commonFlags(fs)
+	issuer := fs.String("issuer", "", "only credentials of this issuer")
+	schema := fs.String("schema", "", "only credentials of this schema")
+	fs.Parse(args)
+
+	w := c.open()
+	creds := w.List()
+	if *issuer != "" {
+		creds = w.ByIssuer(*issuer)
+	}
+	fmt.Printf("%-16s  %-16s  %-20s  %-20s  %s\n", "ID", "ISSUER", "SCHEMA", "ADDED", "LABEL")
+	for _, cr := range creds {
+		if *schema != "" && cr.Schema != *schema {
+			continue
+		}
+		fmt.Printf("%-16s  %-16s  %-20s  %-20s  %s\n", cr.ID, short(cr.Issuer), cr.Schema, cr.Added.Format("2006-01-02 15:04:05"), cr.Label)
+	}
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	c := commonFlags(fs)
+	statePath := fs.String("state", "credential/keys/credential_state.json", "holder state file")
+	schema := fs.String("schema", "", "schema name (default: derived from the block lengths)")
+	label := fs.String("label", "", "free-form label")
+	fs.Parse(args)
+
+	st, err := credential.LoadState(*statePath)
+	if err != nil {
+		log.Fatalf("load state: %v", err)
+	}
+	w, err := wallet.OpenOrCreate(*c.path, c.passphrase())
+	if err != nil {
+		log.Fatalf("open wallet: %v", err)
+	}
+	cr, err := w.Import(st, *schema, *label)
+	if err != nil {
+		log.Fatalf("import: %v", err)
+	}
+	if err := w.Save(); err != nil {
+		log.Fatalf("save wallet: %v", err)
+	}
+	fmt.Printf("imported %s (issuer %s, schema %s) into %s\n", cr.ID, short(cr.Issuer), cr.Schema, w.Path())
+	if len(st.NTRUPrivate) > 0 {
+		fmt.Println("note: the issuer private key in the state file was not imported")
+	}
+}
+
+func runShow(args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	c := commonFlags(fs)
+	withState := fs.Bool("state", false, "also print the stored holder state")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	cr, err := c.open().Get(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("id:      %s\n", cr.ID)
+	fmt.Printf("issuer:  %s\n", cr.Issuer)
+	fmt.Printf("schema:  %s\n", cr.Schema)
+	fmt.Printf("label:   %s\n", cr.Label)
+	fmt.Printf("added:   %s\n", cr.Added.Format("2006-01-02 15:04:05 MST"))
+	if cr.State.ParamsDigest != "" {
+		fmt.Printf("params:  %s (%s)\n", cr.State.ParamsDigest, cr.State.Manifest)
+	}
+	verifiers := make([]string, 0, len(cr.Usage))
+	for v := range cr.Usage {
+		verifiers = append(verifiers, v)
+	}
+	sort.Strings(verifiers)
+	for _, v := range verifiers {
+		u := cr.Usage[v]
+		contexts := make([]string, 0, len(u.Counts))
+		for ctx, n := range u.Counts {
+			contexts = append(contexts, fmt.Sprintf("%s=%d", ctx, n))
+		}
+		sort.Strings(contexts)
+		fmt.Printf("verifier %s: %d nonces, counters [%s], last %s\n", v, len(u.Nonces), strings.Join(contexts, " "), u.Last.Format("2006-01-02 15:04:05"))
+	}
+	if *withState {
+		data, err := json.MarshalIndent(cr.State, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(data))
+	}
+}
+
+func runDelete(args []string) {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	c := commonFlags(fs)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	w := c.open()
+	cr, err := w.Get(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Delete(cr.ID); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Save(); err != nil {
+		log.Fatalf("save wallet: %v", err)
+	}
+	fmt.Printf("deleted %s\n", cr.ID)
+}
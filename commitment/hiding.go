@@ -0,0 +1,30 @@
+package commitment
+
+import "math"
+
+// HidingBits is the statistical hiding level of com = [I | A']·vec when the
+// last randCols entries of vec are uniform with coefficients in [−bound, bound]
+// and sit under uniform columns of A' (height ≤ width − randCols). By the
+// leftover hash lemma r ↦ A'_r·r is then within
+// ½·√(q^{height·n} / (2·bound+1)^{randCols·n}) of uniform on R_q^height, which
+// masks every other column. The ring is treated as Z_q^n, the model
+// security.Analyze prices binding in.
+func HidingBits(q uint64, n, height, randCols int, bound int64) float64 {
+	dom := float64(randCols*n) * math.Log2(float64(2*bound+1))
+	rng := float64(height*n) * math.Log2(float64(q))
+	return (dom-rng)/2 + 1
+}
+
+// RandomnessCols returns the smallest randCols with HidingBits ≥ bits.
+func RandomnessCols(q uint64, n, height int, bound int64, bits float64) int {
+	per := float64(n) * math.Log2(float64(2*bound+1))
+	need := 2*(bits-1) + float64(height*n)*math.Log2(float64(q))
+	k := int(math.Ceil(need / per))
+	if k < 1 {
+		k = 1
+	}
+	for HidingBits(q, n, height, k, bound) < bits {
+		k++
+	}
+	return k
+}
@@ -0,0 +1,183 @@
+package commitment
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+	"golang.org/x/crypto/sha3"
+)
+
+// SeedSize is the length in bytes of the public seed Ac is expanded from.
+const SeedSize = 32
+
+// Seed is the public seed of a commitment key.
+type Seed [SeedSize]byte
+
+// ErrNotDerived is returned when a matrix does not match the expansion of
+// its published seed.
+var ErrNotDerived = errors.New("commitment: matrix is not derived from the published seed")
+
+// NewSeed samples a fresh seed from crypto/rand.
+func NewSeed() (Seed, error) {
+	var s Seed
+	if _, err := rand.Read(s[:]); err != nil {
+		return Seed{}, fmt.Errorf("seed: %w", err)
+	}
+	return s, nil
+}
+
+// String returns the seed in hex, the form it is stored in.
+func (s Seed) String() string { return hex.EncodeToString(s[:]) }
+
+// ParseSeed decodes a hex seed.
+func ParseSeed(s string) (Seed, error) {
+	var out Seed
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return out, fmt.Errorf("seed: %w", err)
+	}
+	if len(raw) != SeedSize {
+		return out, fmt.Errorf("seed: %d bytes, want %d", len(raw), SeedSize)
+	}
+	copy(out[:], raw)
+	return out, nil
+}
+
+// Params describes a seed-expanded commitment key Ac = [I_Height | A'] with
+// Height rows and Width columns; A' is derived from Seed.
+type Params struct {
+	Height int
+	Width  int
+	Seed   Seed
+}
+
+// Validate checks 1 ≤ Height ≤ Width.
+func (p Params) Validate() error {
+	if p.Height < 1 || p.Width < p.Height {
+		return fmt.Errorf("commitment: invalid shape %d×%d (need 1 ≤ height ≤ width)", p.Height, p.Width)
+	}
+	return nil
+}
+
+// Expand derives Ac = [I | A'] in NTT form. The entries of A' are read from
+// SHAKE-256 over the seed, the ring and the shape, and are rejection-sampled
+// directly as uniform NTT vectors, so the same Params always give the same
+// matrix.
+func Expand(ringQ *ring.Ring, p Params) (Matrix, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	q := ringQ.Modulus[0]
+	var buf [8]byte
+	root := sha3.NewShake256()
+	root.Write([]byte("vSIS-Commitment/Ac/v1"))
+	for _, v := range []uint64{q, uint64(ringQ.N), uint64(p.Height), uint64(p.Width)} {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		root.Write(buf[:])
+	}
+	root.Write(p.Seed[:])
+	var key [32]byte
+	root.Read(key[:])
+
+	mask := uint64(1)<<bits.Len64(q-1) - 1
+	one := ringQ.NewPoly()
+	for k := range one.Coeffs[0] {
+		one.Coeffs[0][k] = 1
+	}
+	Ac := make(Matrix, p.Height)
+	for i := range Ac {
+		Ac[i] = make([]*ring.Poly, p.Width)
+		for j := 0; j < p.Height; j++ {
+			if i == j {
+				Ac[i][j] = one.CopyNew()
+			} else {
+				Ac[i][j] = ringQ.NewPoly()
+			}
+		}
+		for j := p.Height; j < p.Width; j++ {
+			xof := sha3.NewShake256()
+			xof.Write(key[:])
+			binary.LittleEndian.PutUint64(buf[:], uint64(i*p.Width+j))
+			xof.Write(buf[:])
+			poly := ringQ.NewPoly()
+			for k := range poly.Coeffs[0] {
+				for {
+					xof.Read(buf[:])
+					if v := binary.LittleEndian.Uint64(buf[:]) & mask; v < q {
+						poly.Coeffs[0][k] = v
+						break
+					}
+				}
+			}
+			Ac[i][j] = poly
+		}
+	}
+	return Ac, nil
+}
+
+// CheckDerived returns ErrNotDerived unless Ac is exactly Expand(ringQ, p).
+func CheckDerived(ringQ *ring.Ring, p Params, Ac Matrix) error {
+	want, err := Expand(ringQ, p)
+	if err != nil {
+		return err
+	}
+	if len(Ac) != len(want) {
+		return fmt.Errorf("%w: %d rows, seed gives %d", ErrNotDerived, len(Ac), len(want))
+	}
+	for i := range want {
+		if len(Ac[i]) != len(want[i]) {
+			return fmt.Errorf("%w: row %d has %d columns, seed gives %d", ErrNotDerived, i, len(Ac[i]), len(want[i]))
+		}
+		for j := range want[i] {
+			if Ac[i][j] == nil || !ringQ.Equal(Ac[i][j], want[i][j]) {
+				return fmt.Errorf("%w: entry (%d,%d) differs", ErrNotDerived, i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// CommitSystematic computes com = Ac · vec for Ac = [I_height | A'] without
+// multiplying by the identity block: com_i = vec_i + Σ_j A'_ij · vec_{height+j}.
+// The identity block is not read, so Ac must come from Expand or have been
+// checked with CheckDerived.
+func CommitSystematic(ringQ *ring.Ring, Ac Matrix, height int, vec Vector) (Vector, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
+	}
+	if height < 1 || len(Ac) != height {
+		return nil, fmt.Errorf("systematic matrix: %d rows, height %d", len(Ac), height)
+	}
+	nCols := len(Ac[0])
+	if nCols < height || nCols != len(vec) {
+		return nil, fmt.Errorf("dimension mismatch: cols=%d vec=%d height=%d", nCols, len(vec), height)
+	}
+	com := make(Vector, height)
+	tmp := ringQ.NewPoly()
+	for i := range Ac {
+		if len(Ac[i]) != nCols {
+			return nil, fmt.Errorf("ragged matrix at row %d", i)
+		}
+		if vec[i] == nil {
+			return nil, fmt.Errorf("nil polynomial at col %d", i)
+		}
+		acc := vec[i].CopyNew()
+		for j := height; j < nCols; j++ {
+			if Ac[i][j] == nil || vec[j] == nil {
+				return nil, fmt.Errorf("nil polynomial at row %d col %d", i, j)
+			}
+			ringQ.MulCoeffs(Ac[i][j], vec[j], tmp)
+			ringQ.Add(acc, tmp, acc)
+		}
+		com[i] = acc
+	}
+	return com, nil
+}
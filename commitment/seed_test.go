@@ -0,0 +1,136 @@
+package commitment
+
+import (
+	"errors"
+	"testing"
+
+	ntrurio "vSIS-Signature/ntru/io"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+	"github.com/tuneinsight/lattigo/v4/utils"
+)
+
+func testRing(t *testing.T) *ring.Ring {
+	t.Helper()
+	par, err := ntrurio.LoadParams("../Parameters/Parameters.json", true)
+	if err != nil {
+		t.Fatalf("load params: %v", err)
+	}
+	ringQ, err := ring.NewRing(par.N, []uint64{par.Q})
+	if err != nil {
+		t.Fatalf("ring: %v", err)
+	}
+	return ringQ
+}
+
+func TestExpandSystematic(t *testing.T) {
+	ringQ := testRing(t)
+	seed, err := NewSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Params{Height: 2, Width: 5, Seed: seed}
+	Ac, err := Expand(ringQ, p)
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	again, err := Expand(ringQ, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckDerived(ringQ, p, again); err != nil {
+		t.Fatalf("re-expansion not derived: %v", err)
+	}
+	for i := 0; i < p.Height; i++ {
+		for j := 0; j < p.Height; j++ {
+			want := uint64(0)
+			if i == j {
+				want = 1
+			}
+			for _, c := range Ac[i][j].Coeffs[0] {
+				if c != want {
+					t.Fatalf("identity block (%d,%d) has %d", i, j, c)
+				}
+			}
+		}
+	}
+	if ringQ.Equal(Ac[0][2], Ac[1][2]) || ringQ.Equal(Ac[0][2], Ac[0][3]) {
+		t.Fatal("A' entries repeat")
+	}
+
+	prng, err := utils.NewPRNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vec := make(Vector, p.Width)
+	for i := range vec {
+		vec[i] = randPoly(ringQ, prng)
+	}
+	slow, err := Commit(ringQ, Ac, vec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := CommitSystematic(ringQ, Ac, p.Height, vec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range slow {
+		if !ringQ.Equal(slow[i], fast[i]) {
+			t.Fatalf("systematic commit differs at row %d", i)
+		}
+	}
+}
+
+func TestCheckDerivedRejects(t *testing.T) {
+	ringQ := testRing(t)
+	p := Params{Height: 1, Width: 3, Seed: Seed{1}}
+	Ac, err := Expand(ringQ, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := p
+	other.Seed[0] ^= 1
+	wide := p
+	wide.Width++
+	tampered, _ := Expand(ringQ, p)
+	tampered[0][2].Coeffs[0][7]++
+	for name, tc := range map[string]struct {
+		p  Params
+		Ac Matrix
+	}{
+		"other seed": {other, Ac},
+		"shape":      {wide, Ac},
+		"entry":      {p, tampered},
+	} {
+		if err := CheckDerived(ringQ, tc.p, tc.Ac); !errors.Is(err, ErrNotDerived) {
+			t.Errorf("%s: got %v, want ErrNotDerived", name, err)
+		}
+	}
+	if _, err := Expand(ringQ, Params{Height: 3, Width: 2}); err == nil {
+		t.Error("height > width accepted")
+	}
+}
+
+func TestSeedRoundTrip(t *testing.T) {
+	seed, err := NewSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := ParseSeed(seed.String())
+	if err != nil || back != seed {
+		t.Fatalf("round trip: %v", err)
+	}
+	if _, err := ParseSeed("abcd"); err == nil {
+		t.Error("short seed accepted")
+	}
+}
+
+func TestRandomnessCols(t *testing.T) {
+	const q, n, bound = 1038337, 1024, 8
+	for h := 1; h <= 3; h++ {
+		k := RandomnessCols(q, n, h, bound, 128)
+		if HidingBits(q, n, h, k, bound) < 128 || k > 1 && HidingBits(q, n, h, k-1, bound) >= 128 {
+			t.Fatalf("height %d: %d columns is not the minimum", h, k)
+		}
+	}
+}
@@ -2,15 +2,22 @@ package credential
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"math/big"
 
+	"vSIS-Signature/commitment"
+	"vSIS-Signature/ntru/keys"
+
 	"github.com/tuneinsight/lattigo/v4/ring"
 	"github.com/tuneinsight/lattigo/v4/utils"
+	"golang.org/x/crypto/sha3"
 )
 
-// NewIssuerChallenge samples RI0, RI1 with coefficients in [-BoundB, BoundB],
-// lifts them to NTT, and returns the IssuerChallenge.
+// NewIssuerChallenge samples RI0, RI1 uniformly in [-BoundB, BoundB] in the
+// evaluation domain and returns them as NTT polys. This is the domain
+// DeriveIssuerChallenge draws from and the one the pre-sign proof centers
+// RU*+RI* in, so both challenges keep the carries K* in {-1,0,1}.
 func NewIssuerChallenge(p *Params) (IssuerChallenge, error) {
 	if p == nil || p.RingQ == nil {
 		return IssuerChallenge{}, fmt.Errorf("nil params or ring")
@@ -35,6 +42,95 @@ func NewIssuerChallenge(p *Params) (IssuerChallenge, error) {
 	return IssuerChallenge{RI0: ri0, RI1: ri1}, nil
 }
 
+// challengeDomain separates DeriveIssuerChallenge from every other use of
+// SHAKE-256 in the module.
+const challengeDomain = "vSIS-Credential/IssuerChallenge/v1"
+
+// DeriveIssuerChallenge is the non-interactive counterpart of
+// NewIssuerChallenge: RI0, RI1 are expanded with SHAKE-256 from
+// (Com, issuer public key, context) and the parameters that shape them, so a
+// holder can send (Com, t, π_t) in a single message.
+//
+// The values are uniform in [-BoundB, BoundB] in the evaluation domain (the
+// returned polys are NTT), where the pre-sign proof centers RU*+RI* slot by
+// slot; this keeps the carries K* in {-1,0,1}.
+//
+// Security: RI* plays the role of the issuer's coins, so this is a
+// Fiat–Shamir transform, sound in the random-oracle model only and at most
+// at SHAKE-256's 128-bit level. A holder can grind Com (fresh r) and pick
+// among 2^k challenges for 2^k XOF calls, so any event on RI* the issuer
+// relies on must have probability well below 2^-128 per challenge, not just
+// be unlikely for one sample. context must be unique per issuance (issuer
+// id, epoch, session) so that a (Com, t, π_t) message cannot be replayed
+// into another issuance.
+func DeriveIssuerChallenge(p *Params, com commitment.Vector, issuerPK *keys.PublicKey, context []byte) (IssuerChallenge, error) {
+	if p == nil || p.RingQ == nil {
+		return IssuerChallenge{}, fmt.Errorf("nil params or ring")
+	}
+	if p.BoundB <= 0 {
+		return IssuerChallenge{}, fmt.Errorf("bound must be > 0")
+	}
+	if len(com) == 0 {
+		return IssuerChallenge{}, fmt.Errorf("empty commitment")
+	}
+	if issuerPK == nil || len(issuerPK.HCoeffs) == 0 {
+		return IssuerChallenge{}, fmt.Errorf("missing issuer public key")
+	}
+	ringQ := p.RingQ
+	xof := sha3.NewShake256()
+	var buf [8]byte
+	putU64 := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		xof.Write(buf[:])
+	}
+	xof.Write([]byte(challengeDomain))
+	for _, v := range []uint64{ringQ.Modulus[0], uint64(ringQ.N), uint64(p.BoundB), uint64(p.LenRU0), uint64(p.LenRU1), uint64(len(com))} {
+		putU64(v)
+	}
+	for i, poly := range com {
+		if poly == nil {
+			return IssuerChallenge{}, fmt.Errorf("com[%d]: nil poly", i)
+		}
+		for _, c := range poly.Coeffs[0] {
+			putU64(c)
+		}
+	}
+	putU64(uint64(len(issuerPK.HCoeffs)))
+	for _, c := range issuerPK.HCoeffs {
+		putU64(uint64(c))
+	}
+	putU64(uint64(len(context)))
+	xof.Write(context)
+
+	// Rejection-sample 32-bit words into [0, 2B] so every value is uniform.
+	mod := uint64(2*p.BoundB + 1)
+	limit := (uint64(1) << 32) / mod * mod
+	q := ringQ.Modulus[0]
+	next := func() uint64 {
+		for {
+			xof.Read(buf[:4])
+			if v := uint64(binary.LittleEndian.Uint32(buf[:4])); v < limit {
+				return v % mod
+			}
+		}
+	}
+	sample := func(n int) []*ring.Poly {
+		out := make([]*ring.Poly, n)
+		for i := range out {
+			out[i] = ringQ.NewPoly()
+			for j := range out[i].Coeffs[0] {
+				out[i].Coeffs[0][j] = (next() + q - uint64(p.BoundB)) % q
+			}
+		}
+		return out
+	}
+	ri0 := sample(p.LenRU0)
+	ri1 := sample(p.LenRU1)
+	return IssuerChallenge{RI0: ri0, RI1: ri1}, nil
+}
+
+// sampleBoundedPoly returns an NTT poly whose evaluations are uniform in
+// [-bound, bound].
 func sampleBoundedPoly(ringQ *ring.Ring, bound int64) (*ring.Poly, error) {
 	if bound <= 0 {
 		return nil, fmt.Errorf("bound must be > 0")
@@ -58,7 +154,6 @@ func sampleBoundedPoly(ringQ *ring.Ring, bound int64) (*ring.Poly, error) {
 			p.Coeffs[0][i] = uint64(v)
 		}
 	}
-	ringQ.NTT(p, p)
 	return p, nil
 }
 
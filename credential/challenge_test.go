@@ -0,0 +1,120 @@
+package credential
+
+import (
+	"testing"
+
+	"vSIS-Signature/commitment"
+	"vSIS-Signature/ntru/keys"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+func TestDeriveIssuerChallenge(t *testing.T) {
+	ringQ, err := LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("ring: %v", err)
+	}
+	p := &Params{RingQ: ringQ, BoundB: 8, LenRU0: 2, LenRU1: 3}
+	com := make(commitment.Vector, 3)
+	for i := range com {
+		com[i] = ringQ.NewPoly()
+		for j := range com[i].Coeffs[0] {
+			com[i].Coeffs[0][j] = uint64(7*i+j) % ringQ.Modulus[0]
+		}
+	}
+	pk := &keys.PublicKey{HCoeffs: []int64{3, -1, 4, 1, -5}}
+	label := []byte("issuer-A/epoch-1/session-42")
+
+	ch, err := DeriveIssuerChallenge(p, com, pk, label)
+	if err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	if len(ch.RI0) != p.LenRU0 || len(ch.RI1) != p.LenRU1 {
+		t.Fatalf("lengths RI0=%d RI1=%d", len(ch.RI0), len(ch.RI1))
+	}
+	q := ringQ.Modulus[0]
+	seen := map[int64]bool{}
+	for _, poly := range append(append([]*ring.Poly{}, ch.RI0...), ch.RI1...) {
+		for _, c := range poly.Coeffs[0] {
+			v := int64(c)
+			if c > q/2 {
+				v -= int64(q)
+			}
+			if v < -p.BoundB || v > p.BoundB {
+				t.Fatalf("evaluation value %d outside [-%d,%d]", v, p.BoundB, p.BoundB)
+			}
+			seen[v] = true
+		}
+	}
+	if len(seen) != int(2*p.BoundB+1) {
+		t.Fatalf("only %d of %d values drawn", len(seen), 2*p.BoundB+1)
+	}
+
+	equal := func(a, b IssuerChallenge) bool {
+		for i := range a.RI0 {
+			if !a.RI0[i].Equals(b.RI0[i]) {
+				return false
+			}
+		}
+		for i := range a.RI1 {
+			if !a.RI1[i].Equals(b.RI1[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	again, err := DeriveIssuerChallenge(p, com, pk, label)
+	if err != nil || !equal(ch, again) {
+		t.Fatalf("derivation is not deterministic (err=%v)", err)
+	}
+	otherCom := append(commitment.Vector{}, com...)
+	otherCom[2] = com[2].CopyNew()
+	otherCom[2].Coeffs[0][0]++
+	otherPK := &keys.PublicKey{HCoeffs: []int64{3, -1, 4, 1, -4}}
+	otherLabel := []byte("issuer-A/epoch-1/session-43")
+	for name, derive := range map[string]func() (IssuerChallenge, error){
+		"com":   func() (IssuerChallenge, error) { return DeriveIssuerChallenge(p, otherCom, pk, label) },
+		"key":   func() (IssuerChallenge, error) { return DeriveIssuerChallenge(p, com, otherPK, label) },
+		"label": func() (IssuerChallenge, error) { return DeriveIssuerChallenge(p, com, pk, otherLabel) },
+	} {
+		other, err := derive()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if equal(ch, other) {
+			t.Fatalf("changing the %s leaves the challenge unchanged", name)
+		}
+	}
+	if _, err := DeriveIssuerChallenge(p, com, nil, label); err == nil {
+		t.Fatalf("missing issuer key accepted")
+	}
+	if _, err := DeriveIssuerChallenge(p, nil, pk, label); err == nil {
+		t.Fatalf("empty commitment accepted")
+	}
+}
+
+// NewIssuerChallenge draws from the same domain as DeriveIssuerChallenge:
+// bounded values in the evaluation (NTT) domain.
+func TestNewIssuerChallengeDomain(t *testing.T) {
+	ringQ, err := LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("ring: %v", err)
+	}
+	p := &Params{RingQ: ringQ, BoundB: 8, LenRU0: 1, LenRU1: 2}
+	ch, err := NewIssuerChallenge(p)
+	if err != nil {
+		t.Fatalf("sample: %v", err)
+	}
+	q := ringQ.Modulus[0]
+	for _, poly := range append(append([]*ring.Poly{}, ch.RI0...), ch.RI1...) {
+		for _, c := range poly.Coeffs[0] {
+			v := int64(c)
+			if c > q/2 {
+				v -= int64(q)
+			}
+			if v < -p.BoundB || v > p.BoundB {
+				t.Fatalf("evaluation value %d outside [-%d,%d]", v, p.BoundB, p.BoundB)
+			}
+		}
+	}
+}
@@ -57,7 +57,7 @@ func BuildCommit(p *Params, h HolderState) (commitment.Vector, error) {
 		}
 	}
 	vec := flattenState(h)
-	com, err := commitment.Commit(ringQ, p.Ac, vec)
+	com, err := p.Commit(vec)
 	if err != nil {
 		return nil, err
 	}
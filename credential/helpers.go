@@ -56,30 +56,43 @@ func HashMessage(
 	B []*ring.Poly,
 	m1, m2, r0, r1 *ring.Poly,
 ) ([]int64, error) {
-	if ringQ == nil {
-		return nil, fmt.Errorf("nil ring")
+	if m1 == nil || m2 == nil || r0 == nil || r1 == nil {
+		return nil, fmt.Errorf("nil input polynomial")
 	}
 	if len(B) != 4 {
 		return nil, fmt.Errorf("b must contain 4 polynomials, got %d", len(B))
 	}
-	if m1 == nil || m2 == nil || r0 == nil || r1 == nil {
-		return nil, fmt.Errorf("nil input polynomial")
-	}
+	return HashMessageVec(ringQ, B, []*ring.Poly{m1}, []*ring.Poly{m2}, []*ring.Poly{r0}, []*ring.Poly{r1})
+}
 
-	clone := func(p *ring.Poly) *ring.Poly {
-		cp := ringQ.NewPoly()
-		ring.Copy(p, cp)
-		return cp
+// HashMessageVec is HashMessage for vector-valued blocks. Message block i is
+// m1[i]+m2[i] (a missing side counts as zero), and B must be the key
+// expanded for the block lengths (see Params.HashKey).
+func HashMessageVec(ringQ *ring.Ring, B []*ring.Poly, m1, m2, r0, r1 []*ring.Poly) ([]int64, error) {
+	if ringQ == nil {
+		return nil, fmt.Errorf("nil ring")
 	}
-
-	mCombined := clone(m1)
-	ringQ.Add(mCombined, m2, mCombined)
-
-	mPoly := clone(mCombined)
-	x0 := clone(r0)
-	x1 := clone(r1)
-
-	tNTT, err := vsishash.ComputeBBSHash(ringQ, B, mPoly, x0, x1)
+	if len(m1) == 0 || len(m2) == 0 || len(r0) == 0 || len(r1) == 0 {
+		return nil, fmt.Errorf("empty hash input block")
+	}
+	for _, blk := range [][]*ring.Poly{m1, m2, r0, r1} {
+		for _, p := range blk {
+			if p == nil {
+				return nil, fmt.Errorf("nil input polynomial")
+			}
+		}
+	}
+	msg := make([]*ring.Poly, max(len(m1), len(m2)))
+	for i := range msg {
+		msg[i] = ringQ.NewPoly()
+		if i < len(m1) {
+			ringQ.Add(msg[i], m1[i], msg[i])
+		}
+		if i < len(m2) {
+			ringQ.Add(msg[i], m2[i], msg[i])
+		}
+	}
+	tNTT, err := vsishash.ComputeBBSHashVec(ringQ, B, msg, r0, r1)
 	if err != nil {
 		return nil, err
 	}
@@ -7,6 +7,7 @@ import (
 	ntru "vSIS-Signature/ntru"
 	ntrurio "vSIS-Signature/ntru/io"
 	ntrukeys "vSIS-Signature/ntru/keys"
+	vsishash "vSIS-Signature/vSIS-HASH"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 	"github.com/tuneinsight/lattigo/v4/utils"
@@ -107,3 +108,111 @@ func TestHashMessageMatchesSeededTarget(t *testing.T) {
 		}
 	}
 }
+
+// hashTestKey returns a random B0…B3 in NTT and polys with small random
+// coefficients for the hash tests below.
+func hashTestKey(t *testing.T) (*ring.Ring, []*ring.Poly, func() *ring.Poly) {
+	t.Helper()
+	ringQ, err := LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("ring: %v", err)
+	}
+	prng, err := utils.NewPRNG()
+	if err != nil {
+		t.Fatalf("prng: %v", err)
+	}
+	B, err := vsishash.GenerateB(ringQ, prng)
+	if err != nil {
+		t.Fatalf("generate B: %v", err)
+	}
+	small := func() *ring.Poly {
+		p, err := sampleBoundedPoly(ringQ, 8)
+		if err != nil {
+			t.Fatalf("sample: %v", err)
+		}
+		// Read as coefficients, the bounded values are a short poly.
+		return p
+	}
+	return ringQ, B, small
+}
+
+func TestHashMessageVecSingleMatchesBBSHash(t *testing.T) {
+	ringQ, B, small := hashTestKey(t)
+	m1, m2, r0, r1 := small(), small(), small(), small()
+	key, err := vsishash.ExpandB(ringQ, B, vsishash.VecLayout{Msg: 1, X0: 1, X1: 1})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	got, err := HashMessageVec(ringQ, key, []*ring.Poly{m1}, []*ring.Poly{m2}, []*ring.Poly{r0}, []*ring.Poly{r1})
+	if err != nil {
+		t.Fatalf("hash vec: %v", err)
+	}
+	m := ringQ.NewPoly()
+	ringQ.Add(m1, m2, m)
+	ref, err := vsishash.ComputeBBSHash(ringQ, B, m, r0.CopyNew(), r1.CopyNew())
+	if err != nil {
+		t.Fatalf("bbs hash: %v", err)
+	}
+	ringQ.InvNTT(ref, ref)
+	want := polyToInt64(ref, ringQ)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("coeff %d: vec %d, single %d", i, got[i], want[i])
+		}
+	}
+}
+
+// TestHashMessageVecBlocks checks that zero extra blocks leave the hash of
+// the first blocks unchanged and that every extra block is bound.
+func TestHashMessageVecBlocks(t *testing.T) {
+	ringQ, B, small := hashTestKey(t)
+	blocks := func(n int, first *ring.Poly) []*ring.Poly {
+		out := []*ring.Poly{first}
+		for len(out) < n {
+			out = append(out, ringQ.NewPoly())
+		}
+		return out
+	}
+	m1, m2, r0, r1 := small(), small(), small(), small()
+	single, err := HashMessage(ringQ, B, m1, m2, r0, r1)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	for _, n := range []int{2, 3, 4} {
+		key, err := vsishash.ExpandB(ringQ, B, vsishash.VecLayout{Msg: n, X0: n, X1: n})
+		if err != nil {
+			t.Fatalf("expand %d: %v", n, err)
+		}
+		if len(key) != 4+3*(n-1) {
+			t.Fatalf("len %d: key has %d polys", n, len(key))
+		}
+		in := [][]*ring.Poly{blocks(n, m1), blocks(n, m2), blocks(n, r0), blocks(n, r1)}
+		got, err := HashMessageVec(ringQ, key, in[0], in[1], in[2], in[3])
+		if err != nil {
+			t.Fatalf("hash %d: %v", n, err)
+		}
+		for i := range single {
+			if got[i] != single[i] {
+				t.Fatalf("len %d: zero extra blocks changed coeff %d", n, i)
+			}
+		}
+		for b := range in {
+			in[b][n-1] = small()
+			changed, err := HashMessageVec(ringQ, key, in[0], in[1], in[2], in[3])
+			if err != nil {
+				t.Fatalf("hash %d: %v", n, err)
+			}
+			in[b][n-1] = ringQ.NewPoly()
+			same := true
+			for i := range single {
+				same = same && changed[i] == single[i]
+			}
+			if same {
+				t.Fatalf("len %d: block %d index %d does not enter the hash", n, b, n-1)
+			}
+		}
+	}
+	if _, err := HashMessageVec(ringQ, B, []*ring.Poly{m1, m1}, []*ring.Poly{m2}, []*ring.Poly{r0}, []*ring.Poly{r1}); err == nil {
+		t.Fatalf("unexpanded key accepted for two message blocks")
+	}
+}
@@ -8,43 +8,65 @@ import (
 
 	"vSIS-Signature/commitment"
 	"vSIS-Signature/ntru/io"
+	"vSIS-Signature/pubparams"
+	vsishash "vSIS-Signature/vSIS-HASH"
 
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
 
-// Params captures the public inputs required during issuance.
+// MinHidingBits is the statistical hiding level (commitment.HidingBits of
+// Ac over the LenR randomness columns) below which parameters are rejected.
+const MinHidingBits = 128
+
+// Params captures the public inputs required during issuance. Ac is the
+// expansion of Commitment, whose seed is what the params file publishes.
+// With a manifest, Bundle holds B, Ac and the key behind A, and BPath is
+// unused.
 type Params struct {
-	Ac     commitment.Matrix
-	BPath  string
-	AcPath string
-	BoundB int64
-	LenM1  int
-	LenM2  int
-	LenRU0 int
-	LenRU1 int
-	LenR   int
-	RingQ  *ring.Ring
+	Ac         commitment.Matrix
+	Commitment commitment.Params
+	Bundle     *pubparams.Bundle
+	BPath      string
+	AcPath     string
+	BoundB     int64
+	LenM1      int
+	LenM2      int
+	LenRU0     int
+	LenRU1     int
+	LenR       int
+	RingQ      *ring.Ring
 }
 
 // paramsFile mirrors the JSON schema stored on disk.
 type paramsFile struct {
-	AcPath string `json:"AcPath"`
-	BPath  string `json:"BPath"`
-	BoundB int64  `json:"BoundB"`
-	LenM1  int    `json:"LenM1"`
-	LenM2  int    `json:"LenM2"`
-	LenRU0 int    `json:"LenRU0"`
-	LenRU1 int    `json:"LenRU1"`
-	LenR   int    `json:"LenR"`
+	Manifest string `json:"Manifest,omitempty"`
+	AcSeed   string `json:"AcSeed,omitempty"`
+	AcRows   int    `json:"AcRows,omitempty"`
+	AcPath   string `json:"AcPath,omitempty"`
+	BPath    string `json:"BPath,omitempty"`
+	BoundB   int64  `json:"BoundB"`
+	LenM1    int    `json:"LenM1"`
+	LenM2    int    `json:"LenM2"`
+	LenRU0   int    `json:"LenRU0"`
+	LenRU1   int    `json:"LenRU1"`
+	LenR     int    `json:"LenR"`
 }
 
 // LoadParamsFromFile reads the credential parameters JSON and materialises Ac in
 // NTT form using the default ring (Parameters/Parameters.json).
 //
-// Expected Ac JSON schema (coeff-domain):
+// Ac is expanded from the hex AcSeed to AcRows rows and one column per
+// committed polynomial (see commitment.Expand). AcPath is optional; if set it
+// names a cached matrix with the coeff-domain schema
 // { "Ac": [ [ [c00, c01, ...], [c01, ...] ], ... ] }
-// i.e. Ac[row][col][coeff] with all rows having the same column count and all
-// polynomials of length N. Polys are lifted to NTT internally.
+// i.e. Ac[row][col][coeff], and loading fails with commitment.ErrNotDerived
+// unless it equals the expansion of the seed.
+//
+// Alternatively Manifest names a public-parameter bundle (see pubparams),
+// relative to the params file; B and Ac then come from it and AcSeed,
+// AcRows, AcPath and BPath must be absent.
+//
+// Either way the commitment must hide com at MinHidingBits or more.
 func LoadParamsFromFile(path string) (*Params, error) {
 	ringQ, err := LoadDefaultRing()
 	if err != nil {
@@ -62,37 +84,17 @@ func loadParamsInternal(path string, ringQ *ring.Ring) (*Params, error) {
 	if err := json.Unmarshal(raw, &pf); err != nil {
 		return nil, fmt.Errorf("parse params: %w", err)
 	}
-	if pf.BPath == "" {
-		pf.BPath = "Parameters/Bmatrix.json"
-	}
 	if pf.BoundB == 0 {
 		return nil, fmt.Errorf("params: BoundB must be non-zero")
 	}
 	if pf.LenM1 < 0 || pf.LenM2 < 0 || pf.LenRU0 < 0 || pf.LenRU1 < 0 || pf.LenR < 0 {
 		return nil, fmt.Errorf("params: lengths must be non-negative")
 	}
-	acPath := pf.AcPath
-	if acPath == "" {
-		return nil, fmt.Errorf("params: AcPath required")
-	}
-	if !filepath.IsAbs(acPath) {
-		acPath = filepath.Join(filepath.Dir(resolved), acPath)
-	}
-	acMat, err := loadAc(acPath, ringQ)
-	if err != nil {
-		return nil, fmt.Errorf("load Ac: %w", err)
-	}
 	expectedCols := pf.LenM1 + pf.LenM2 + pf.LenRU0 + pf.LenRU1 + pf.LenR
 	if expectedCols == 0 {
 		return nil, fmt.Errorf("params: expectedCols is zero")
 	}
-	if len(acMat) == 0 || len(acMat[0]) != expectedCols {
-		return nil, fmt.Errorf("params: Ac columns=%d, expected=%d", len(acMat[0]), expectedCols)
-	}
-	return &Params{
-		Ac:     acMat,
-		BPath:  pf.BPath,
-		AcPath: acPath,
+	p := &Params{
 		BoundB: pf.BoundB,
 		LenM1:  pf.LenM1,
 		LenM2:  pf.LenM2,
@@ -100,7 +102,144 @@ func loadParamsInternal(path string, ringQ *ring.Ring) (*Params, error) {
 		LenRU1: pf.LenRU1,
 		LenR:   pf.LenR,
 		RingQ:  ringQ,
-	}, nil
+	}
+	if pf.Manifest != "" {
+		if pf.AcSeed != "" || pf.AcRows != 0 || pf.AcPath != "" || pf.BPath != "" {
+			return nil, fmt.Errorf("params: Manifest excludes AcSeed, AcRows, AcPath and BPath")
+		}
+		manifest := pf.Manifest
+		if !filepath.IsAbs(manifest) {
+			manifest = filepath.Join(filepath.Dir(resolved), manifest)
+		}
+		bundle, err := pubparams.Load(manifest, ringQ)
+		if err != nil {
+			return nil, fmt.Errorf("params: %w", err)
+		}
+		if bundle.AcParams.Width != expectedCols {
+			return nil, fmt.Errorf("params: manifest Ac columns=%d, expected=%d", bundle.AcParams.Width, expectedCols)
+		}
+		if err := checkHiding(ringQ, bundle.AcParams.Height, pf.LenR, pf.BoundB); err != nil {
+			return nil, err
+		}
+		p.Bundle, p.Ac, p.Commitment = bundle, bundle.Ac, bundle.AcParams
+		return p, nil
+	}
+	if pf.BPath == "" {
+		pf.BPath = "Parameters/Bmatrix.json"
+	}
+	if pf.AcSeed == "" {
+		return nil, fmt.Errorf("params: AcSeed required (matrices without a published seed are not accepted)")
+	}
+	seed, err := commitment.ParseSeed(pf.AcSeed)
+	if err != nil {
+		return nil, fmt.Errorf("params: AcSeed: %w", err)
+	}
+	if err := checkHiding(ringQ, pf.AcRows, pf.LenR, pf.BoundB); err != nil {
+		return nil, err
+	}
+	cp := commitment.Params{Height: pf.AcRows, Width: expectedCols, Seed: seed}
+	acMat, err := commitment.Expand(ringQ, cp)
+	if err != nil {
+		return nil, fmt.Errorf("params: %w", err)
+	}
+	acPath := pf.AcPath
+	if acPath != "" {
+		if !filepath.IsAbs(acPath) {
+			acPath = filepath.Join(filepath.Dir(resolved), acPath)
+		}
+		cached, err := loadAc(acPath, ringQ)
+		if err != nil {
+			return nil, fmt.Errorf("load Ac: %w", err)
+		}
+		if err := commitment.CheckDerived(ringQ, cp, cached); err != nil {
+			return nil, fmt.Errorf("load Ac: %w", err)
+		}
+	}
+	p.Ac, p.Commitment, p.BPath, p.AcPath = acMat, cp, pf.BPath, acPath
+	return p, nil
+}
+
+// checkHiding rejects an Ac of the given height whose lenR randomness
+// columns hide the commitment at fewer than MinHidingBits.
+func checkHiding(ringQ *ring.Ring, height, lenR int, bound int64) error {
+	if h := commitment.HidingBits(ringQ.Modulus[0], ringQ.N, height, lenR, bound); h < MinHidingBits {
+		return fmt.Errorf("params: Ac %d rows with LenR=%d hides at %.0f bits, need %d (see security.SizeCommitment)", height, lenR, h, MinHidingBits)
+	}
+	return nil
+}
+
+// ParamsDigest returns the bundle digest statements over p bind, or nil
+// without a bundle.
+func (p *Params) ParamsDigest() []byte {
+	if p.Bundle == nil {
+		return nil
+	}
+	return p.Bundle.Digest[:]
+}
+
+// Commit computes com = Ac · vec, using the systematic form of Ac when p
+// carries its seed parameters.
+func (p *Params) Commit(vec commitment.Vector) (commitment.Vector, error) {
+	if p.Commitment.Height > 0 {
+		return commitment.CommitSystematic(p.RingQ, p.Ac, p.Commitment.Height, vec)
+	}
+	return commitment.Commit(p.RingQ, p.Ac, vec)
+}
+
+// HashLayout returns the vector-hash layout of the credential blocks: one
+// message block per index of the longer of m1/m2, one mask block per
+// r0/r1 polynomial.
+func (p *Params) HashLayout() vsishash.VecLayout {
+	return vsishash.VecLayout{Msg: max(p.LenM1, p.LenM2), X0: p.LenRU0, X1: p.LenRU1}
+}
+
+// HashKey expands B0…B3 (NTT) to the hash key of the block lengths in p.
+// Single-polynomial parameters return B unchanged.
+func (p *Params) HashKey(B []*ring.Poly) ([]*ring.Poly, error) {
+	return vsishash.ExpandB(p.RingQ, B, p.HashLayout())
+}
+
+// LoadHashKey returns the hash key of p: B0…B3 (NTT) from the bundle when p
+// has one, else from BPath, expanded with HashKey.
+func (p *Params) LoadHashKey() ([]*ring.Poly, error) {
+	var (
+		B   []*ring.Poly
+		err error
+	)
+	if p.Bundle != nil {
+		B = p.Bundle.B
+	} else if B, err = loadB(p.RingQ, p.BPath); err != nil {
+		return nil, err
+	}
+	if len(B) < 4 {
+		return nil, fmt.Errorf("b matrix has %d polynomials, want 4", len(B))
+	}
+	B, err = p.HashKey(B[:4])
+	if err != nil {
+		return nil, fmt.Errorf("hash key: %w", err)
+	}
+	return B, nil
+}
+
+// loadB loads the B-matrix from path (legacy params without a bundle) and
+// lifts it to NTT.
+func loadB(r *ring.Ring, path string) ([]*ring.Poly, error) {
+	_, resolved, err := readFileWithFallback(path)
+	if err != nil {
+		return nil, fmt.Errorf("load B: %w", err)
+	}
+	coeffs, err := io.LoadBMatrixCoeffs(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("load B: %w", err)
+	}
+	out := make([]*ring.Poly, len(coeffs))
+	for i := range coeffs {
+		p := r.NewPoly()
+		copy(p.Coeffs[0], coeffs[i])
+		r.NTT(p, p)
+		out[i] = p
+	}
+	return out, nil
 }
 
 func readFileWithFallback(path string) ([]byte, string, error) {
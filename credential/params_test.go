@@ -0,0 +1,160 @@
+package credential
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vSIS-Signature/commitment"
+	"vSIS-Signature/pubparams"
+)
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// saveCoeffAc writes Ac in the coeff-domain schema loadAc reads.
+func saveCoeffAc(t *testing.T, path string, p *Params, Ac commitment.Matrix) {
+	t.Helper()
+	out := make([][][]uint64, len(Ac))
+	for i := range Ac {
+		out[i] = make([][]uint64, len(Ac[i]))
+		for j := range Ac[i] {
+			c := Ac[i][j].CopyNew()
+			p.RingQ.InvNTT(c, c)
+			out[i][j] = c.Coeffs[0]
+		}
+	}
+	writeJSON(t, path, map[string]any{"Ac": out})
+}
+
+func TestLoadParamsFromSeed(t *testing.T) {
+	ringQ, err := LoadDefaultRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	seed := commitment.Seed{7, 7, 7}
+	pf := paramsFile{AcSeed: seed.String(), AcRows: 1, BoundB: 8, LenM1: 1, LenM2: 1, LenRU0: 1, LenRU1: 1, LenR: 5}
+	path := filepath.Join(dir, "params.json")
+	writeJSON(t, path, pf)
+
+	p, err := loadParamsInternal(path, ringQ)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.Commitment.Height != 1 || p.Commitment.Width != 9 || len(p.Ac) != 1 || len(p.Ac[0]) != 9 {
+		t.Fatalf("shape %d×%d, want 1×9", len(p.Ac), len(p.Ac[0]))
+	}
+	if err := commitment.CheckDerived(ringQ, p.Commitment, p.Ac); err != nil {
+		t.Fatal(err)
+	}
+
+	// A cached matrix is accepted only if it is the expansion of the seed.
+	saveCoeffAc(t, filepath.Join(dir, "Ac.json"), p, p.Ac)
+	pf.AcPath = "Ac.json"
+	writeJSON(t, path, pf)
+	if _, err := loadParamsInternal(path, ringQ); err != nil {
+		t.Fatalf("derived cache rejected: %v", err)
+	}
+	forged, err := commitment.Expand(ringQ, commitment.Params{Height: 1, Width: 9, Seed: commitment.Seed{8}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveCoeffAc(t, filepath.Join(dir, "Ac.json"), p, forged)
+	if _, err := loadParamsInternal(path, ringQ); !errors.Is(err, commitment.ErrNotDerived) {
+		t.Fatalf("foreign matrix: got %v, want ErrNotDerived", err)
+	}
+
+	pf.AcSeed = ""
+	writeJSON(t, path, pf)
+	if _, err := loadParamsInternal(path, ringQ); err == nil {
+		t.Fatal("params without a seed accepted")
+	}
+}
+
+func TestLoadParamsFromManifest(t *testing.T) {
+	ringQ, err := LoadDefaultRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	m, err := pubparams.New(ringQ, 1, 9, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pubparams.Save(filepath.Join(dir, "manifest.json"), m); err != nil {
+		t.Fatal(err)
+	}
+	pf := paramsFile{Manifest: "manifest.json", BoundB: 8, LenM1: 1, LenM2: 1, LenRU0: 1, LenRU1: 1, LenR: 5}
+	path := filepath.Join(dir, "params.json")
+	writeJSON(t, path, pf)
+	p, err := loadParamsInternal(path, ringQ)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if p.Bundle == nil || len(p.ParamsDigest()) != 32 || p.Commitment != p.Bundle.AcParams {
+		t.Fatalf("params not taken from the bundle")
+	}
+	// The hash key comes from the bundle too: BPath is empty.
+	key, err := p.LoadHashKey()
+	if err != nil {
+		t.Fatalf("hash key: %v", err)
+	}
+	for i := range key {
+		if !ringQ.Equal(key[i], p.Bundle.B[i]) {
+			t.Fatalf("hash key B%d is not the bundle's", i)
+		}
+	}
+
+	pf.BPath = "Parameters/Bmatrix.json"
+	writeJSON(t, path, pf)
+	if _, err := loadParamsInternal(path, ringQ); err == nil {
+		t.Fatal("BPath accepted next to a manifest")
+	}
+	pf.BPath, pf.LenR = "", 6
+	writeJSON(t, path, pf)
+	if _, err := loadParamsInternal(path, ringQ); err == nil {
+		t.Fatal("manifest Ac width not checked against the block lengths")
+	}
+}
+
+// TestLoadParamsRejectsWeakHiding checks the MinHidingBits floor: one
+// randomness column under a 1-row Ac leaves com binding only.
+func TestLoadParamsRejectsWeakHiding(t *testing.T) {
+	ringQ, err := LoadDefaultRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	m, err := pubparams.New(ringQ, 1, 5, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pubparams.Save(filepath.Join(dir, "manifest.json"), m); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "params.json")
+	writeJSON(t, path, paramsFile{Manifest: "manifest.json", BoundB: 8, LenM1: 1, LenM2: 1, LenRU0: 1, LenRU1: 1, LenR: 1})
+	if _, err := loadParamsInternal(path, ringQ); err == nil {
+		t.Fatal("manifest with LenR=1 accepted")
+	}
+	writeJSON(t, path, paramsFile{AcSeed: commitment.Seed{1}.String(), AcRows: 2, BoundB: 8, LenM1: 1, LenM2: 1, LenRU0: 1, LenRU1: 1, LenR: 5})
+	if _, err := loadParamsInternal(path, ringQ); err == nil {
+		t.Fatal("2-row Ac with LenR=5 accepted")
+	}
+
+	// The shipped parameters clear the floor.
+	if _, err := LoadParamsFromFile("credential/params.json"); err != nil {
+		t.Fatalf("shipped params: %v", err)
+	}
+}
@@ -30,9 +30,11 @@ type State struct {
 	// Paths to public parameters.
 	BPath  string `json:"b_path"`
 	AcPath string `json:"ac_path"`
-	// Embedded public parameters (coeff domain) for portability.
-	B  [][]int64   `json:"b,omitempty"`
-	Ac [][][]int64 `json:"ac,omitempty"`
+	// Manifest names the public-parameter bundle B, Ac and A are expanded
+	// from; ParamsDigest is its hex digest, checked when the bundle is
+	// reloaded.
+	Manifest     string `json:"manifest,omitempty"`
+	ParamsDigest string `json:"params_digest,omitempty"`
 	// NTRU keys (coeff form).
 	NTRUPublic  [][]int64 `json:"ntru_public,omitempty"`
 	NTRUPrivate [][]int64 `json:"ntru_private,omitempty"`
@@ -0,0 +1,50 @@
+package credential
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// FuzzLoadState feeds arbitrary state files to LoadState; whatever it accepts
+// must survive a SaveState/LoadState round trip unchanged.
+func FuzzLoadState(f *testing.F) {
+	if raw, err := os.ReadFile("keys/credential_state.json"); err == nil {
+		f.Add(raw)
+	}
+	f.Add([]byte(`{"m1":[[1,-2]],"t":[3],"ac":[[[1]],[]],"b_path":"x"}`))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "state.json")
+		if err := os.WriteFile(path, raw, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		st, err := LoadState(path)
+		if err != nil {
+			return
+		}
+		again := filepath.Join(dir, "again.json")
+		if err := SaveState(again, nil, st); err != nil {
+			t.Fatalf("SaveState: %v", err)
+		}
+		back, err := LoadState(again)
+		if err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		if !reflect.DeepEqual(normalizeState(st), normalizeState(back)) {
+			t.Fatalf("state changed across a save/load round trip")
+		}
+	})
+}
+
+// normalizeState maps empty slices to nil so omitempty fields compare equal.
+func normalizeState(st State) State {
+	v := reflect.ValueOf(&st).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Slice && f.Len() == 0 {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+	return st
+}
@@ -3,8 +3,6 @@ package credential
 import (
 	"fmt"
 
-	ntrurio "vSIS-Signature/ntru/io"
-
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
 
@@ -102,35 +100,17 @@ func ComputeCombinedTarget(p *Params, h HolderState, chal IssuerChallenge) (*Com
 	if len(r0) == 0 || len(r1) == 0 {
 		return nil, fmt.Errorf("empty randomness blocks")
 	}
-	// Build concatenated m1/m2 for hashing.
 	if len(m1c) == 0 || len(m2c) == 0 {
 		return nil, fmt.Errorf("empty m1 or m2 not supported")
 	}
-	if len(m1c) != 1 || len(m2c) != 1 || len(r0) != 1 || len(r1) != 1 {
-		return nil, fmt.Errorf("only single-poly m1/m2/r0/r1 supported; got m1=%d m2=%d r0=%d r1=%d",
-			len(m1c), len(m2c), len(r0), len(r1))
-	}
-	// Load B in NTT.
-	bPath := p.BPath
-	bCoeffs, err := ntrurio.LoadBMatrixCoeffs(bPath)
+	// B0…B3 from the bundle (or the legacy BPath), expanded to the block
+	// lengths.
+	B, err := p.LoadHashKey()
 	if err != nil {
 		return nil, err
 	}
-	toNTT := func(raw []uint64) *ring.Poly {
-		poly := ringQ.NewPoly()
-		copy(poly.Coeffs[0], raw)
-		ringQ.NTT(poly, poly)
-		return poly
-	}
-	B := []*ring.Poly{
-		toNTT(bCoeffs[0]),
-		toNTT(bCoeffs[1]),
-		toNTT(bCoeffs[2]),
-		toNTT(bCoeffs[3]),
-	}
 
-	// For now, hash only the first poly of each; extend when multi-block messages are defined.
-	tCoeffs, err := HashMessage(ringQ, B, m1c[0], m2c[0], r0[0], r1[0])
+	tCoeffs, err := HashMessageVec(ringQ, B, m1c, m2c, r0, r1)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,164 @@
+package kfield
+
+import "math/bits"
+
+// maxFastTheta bounds the extension degrees served by the allocation-free
+// multiplication kernels (θ ∈ {2,3,4,6,8} in practice).
+const maxFastTheta = 8
+
+// barrett reduces x < 2^64 modulo q < 2^32 with m = ⌊(2^64−1)/q⌋; the
+// quotient estimate is short by at most two.
+type barrett struct {
+	q, m uint64
+}
+
+func newBarrett(q uint64) barrett {
+	return barrett{q: q, m: ^uint64(0) / q}
+}
+
+func (br barrett) reduce(x uint64) uint64 {
+	hi, _ := bits.Mul64(x, br.m)
+	r := x - hi*br.q
+	if r >= br.q {
+		r -= br.q
+	}
+	if r >= br.q {
+		r -= br.q
+	}
+	return r
+}
+
+// setup precomputes the reduction data used by Mul. It is only enabled when
+// the schoolbook sums Σ a_i·b_j (θ terms) and the folds t + c·t′ cannot
+// overflow 64 bits, which holds for every q < 2^28 and θ ≤ 8.
+func (f *Field) setup() {
+	if f.Theta > maxFastTheta || f.Q >= 1<<32 {
+		return
+	}
+	qm1 := f.Q - 1
+	hi, lo := bits.Mul64(qm1, qm1)
+	if hi != 0 {
+		return
+	}
+	terms := uint64(f.Theta)
+	if terms < 2 {
+		terms = 2
+	}
+	if lo > ^uint64(0)/terms {
+		return
+	}
+	f.fast = true
+	f.red = newBarrett(f.Q)
+	f.negChi = make([]uint64, f.Theta)
+	f.binomial = true
+	for i := 0; i < f.Theta; i++ {
+		f.negChi[i] = (f.Q - f.Chi[i]%f.Q) % f.Q
+		if i > 0 && f.Chi[i] != 0 {
+			f.binomial = false
+		}
+	}
+}
+
+// mulFast is Mul for fields prepared by setup. With χ = X^θ − c the high
+// half folds back as t_i + c·t_{i+θ}; otherwise each high coefficient is
+// folded through −χ from the top down.
+func (f *Field) mulFast(a, b Elem) Elem {
+	q, br, deg := f.Q, f.red, f.Theta
+	var x, y [maxFastTheta]uint64
+	for i := 0; i < deg; i++ {
+		x[i], y[i] = a.Limb[i], b.Limb[i]
+		if x[i] >= q {
+			x[i] %= q
+		}
+		if y[i] >= q {
+			y[i] %= q
+		}
+	}
+	res := make([]uint64, deg)
+	if deg == 2 && f.binomial {
+		// (x0 + x1·X)(y0 + y1·X) with X² = c.
+		c := f.negChi[0]
+		hi := br.reduce(x[1] * y[1])
+		res[0] = br.reduce(x[0]*y[0] + c*hi)
+		res[1] = br.reduce(x[0]*y[1] + x[1]*y[0])
+		return Elem{Limb: res}
+	}
+	var t [2 * maxFastTheta]uint64
+	for i := 0; i < deg; i++ {
+		if x[i] == 0 {
+			continue
+		}
+		for j := 0; j < deg; j++ {
+			t[i+j] += x[i] * y[j]
+		}
+	}
+	for k := 0; k < 2*deg-1; k++ {
+		t[k] = br.reduce(t[k])
+	}
+	if f.binomial {
+		c := f.negChi[0]
+		for i := 0; i < deg-1; i++ {
+			res[i] = br.reduce(t[i] + c*t[i+deg])
+		}
+		res[deg-1] = t[deg-1]
+		return Elem{Limb: res}
+	}
+	for k := 2*deg - 2; k >= deg; k-- {
+		coeff := t[k]
+		if coeff == 0 {
+			continue
+		}
+		m := k - deg
+		for j := 0; j < deg; j++ {
+			t[m+j] = br.reduce(t[m+j] + coeff*f.negChi[j])
+		}
+	}
+	copy(res, t[:deg])
+	return Elem{Limb: res}
+}
+
+// invEuclid inverts a modulo χ with the extended Euclidean algorithm over
+// F_q[X]: s·a ≡ g (mod χ) with g a non-zero constant, so a^{-1} = s/g.
+func (f *Field) invEuclid(a Elem) Elem {
+	q := f.Q
+	r0, r1 := polyTrim(poly(f.Chi), q), polyTrim(poly(f.PhiInv(a)), q)
+	s0, s1 := poly{0}, poly{1}
+	for !(len(r1) == 1 && r1[0] == 0) {
+		quo, rem := polyDivMod(r0, r1, q)
+		r0, r1 = r1, rem
+		s0, s1 = s1, polySub(s0, polyMul(quo, s1, q), q)
+	}
+	if len(r0) != 1 {
+		panic("kfield: element not invertible modulo chi")
+	}
+	gInv := modInv(r0[0], q)
+	out := f.Zero()
+	for i := 0; i < len(s0) && i < f.Theta; i++ {
+		out.Limb[i] = modMul(s0[i], gInv, q)
+	}
+	return out
+}
+
+// BatchInv returns the inverses of xs with a single field inversion
+// (Montgomery's trick). It panics if any element is zero.
+func (f *Field) BatchInv(xs []Elem) []Elem {
+	out := make([]Elem, len(xs))
+	if len(xs) == 0 {
+		return out
+	}
+	prefix := make([]Elem, len(xs))
+	acc := f.One()
+	for i, x := range xs {
+		if f.IsZero(x) {
+			panic("kfield: inverse of zero element")
+		}
+		prefix[i] = acc
+		acc = f.Mul(acc, x)
+	}
+	inv := f.Inv(acc)
+	for i := len(xs) - 1; i >= 0; i-- {
+		out[i] = f.Mul(inv, prefix[i])
+		inv = f.Mul(inv, xs[i])
+	}
+	return out
+}
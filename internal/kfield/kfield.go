@@ -18,6 +18,12 @@ type Field struct {
 	Q     uint64
 	Theta int
 	Chi   []uint64
+
+	// Set by New when q and theta admit the Barrett kernels in fast.go.
+	fast     bool
+	binomial bool // chi = X^theta - c
+	red      barrett
+	negChi   []uint64
 }
 
 // Elem is a K element represented by its theta limbs in the power basis.
@@ -46,14 +52,60 @@ func New(q uint64, theta int, chi []uint64) (*Field, error) {
 	if !isIrreducible(q, chiNorm) {
 		return nil, fmt.Errorf("kfield: chi is reducible")
 	}
-	return &Field{Q: q, Theta: theta, Chi: chiNorm}, nil
+	f := &Field{Q: q, Theta: theta, Chi: chiNorm}
+	f.setup()
+	return f, nil
 }
 
-// FindIrreducible samples random monic irreducible polynomials of degree theta over F_q.
+// FindIrreducible samples a random monic irreducible polynomial of degree theta
+// over F_q. Binomials X^theta - c are tried first, since Mul reduces modulo
+// them with one multiply per limb; a dense polynomial is returned only when
+// no irreducible binomial of that degree exists.
 func FindIrreducible(q uint64, theta int, rnd io.Reader) ([]uint64, error) {
 	if q == 0 || theta <= 0 {
 		return nil, fmt.Errorf("kfield: invalid q or theta")
 	}
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	if q > 2 && binomialExists(q, theta) {
+		const binomialTries = 1 << 8
+		for try := 0; try < binomialTries; try++ {
+			chi := make([]uint64, theta+1)
+			chi[theta] = 1
+			chi[0] = 1 + randU64(rnd)%(q-1)
+			if isIrreducible(q, chi) {
+				return chi, nil
+			}
+		}
+	}
+	return findDenseIrreducible(q, theta, rnd)
+}
+
+// binomialExists reports whether some X^theta - c is irreducible over F_q:
+// every prime factor of theta must divide q-1, and q ≡ 1 (mod 4) if 4 | theta.
+func binomialExists(q uint64, theta int) bool {
+	if theta%4 == 0 && q%4 != 1 {
+		return false
+	}
+	n := uint64(theta)
+	for p := uint64(2); p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		if (q-1)%p != 0 {
+			return false
+		}
+		for n%p == 0 {
+			n /= p
+		}
+	}
+	return n == 1 || (q-1)%n == 0
+}
+
+// findDenseIrreducible samples monic polynomials with random coefficients
+// until one is irreducible.
+func findDenseIrreducible(q uint64, theta int, rnd io.Reader) ([]uint64, error) {
 	if rnd == nil {
 		rnd = rand.Reader
 	}
@@ -117,6 +169,23 @@ func (f *Field) PhiInv(e Elem) []uint64 {
 
 // Add returns a + b in K.
 func (f *Field) Add(a, b Elem) Elem {
+	if f.fast {
+		out := make([]uint64, f.Theta)
+		for i := range out {
+			x, y := a.Limb[i], b.Limb[i]
+			if x >= f.Q {
+				x %= f.Q
+			}
+			if y >= f.Q {
+				y %= f.Q
+			}
+			if x += y; x >= f.Q {
+				x -= f.Q
+			}
+			out[i] = x
+		}
+		return Elem{Limb: out}
+	}
 	out := f.Zero()
 	for i := 0; i < f.Theta; i++ {
 		out.Limb[i] = modAdd(a.Limb[i]%f.Q, b.Limb[i]%f.Q, f.Q)
@@ -133,8 +202,12 @@ func (f *Field) Sub(a, b Elem) Elem {
 	return out
 }
 
-// Mul multiplies two K-elements using schoolbook arithmetic followed by modular reduction.
+// Mul multiplies two K-elements using schoolbook arithmetic followed by modular
+// reduction; fields set up for it use the Barrett kernels of mulFast.
 func (f *Field) Mul(a, b Elem) Elem {
+	if f.fast {
+		return f.mulFast(a, b)
+	}
 	deg := f.Theta
 	tmp := make([]uint64, 2*deg)
 	for i := 0; i < deg; i++ {
@@ -243,19 +316,25 @@ func (f *Field) Inv(a Elem) Elem {
 	if f.IsZero(a) {
 		panic("kfield: inverse of zero element")
 	}
-	qBig := big.NewInt(int64(f.Q))
-	thetaBig := big.NewInt(int64(f.Theta))
-	exp := new(big.Int).Exp(qBig, thetaBig, nil)
-	exp.Sub(exp, big.NewInt(2))
-	return f.Pow(a, exp)
+	return f.invEuclid(a)
 }
 
 // EvalFPolyAtK evaluates an F_q-coefficient polynomial at a K-element using Horner's method.
+// Points of the embedded F_q are evaluated in F_q directly. To evaluate many
+// polynomials at the same points, use NewMultiEval.
 func (f *Field) EvalFPolyAtK(coeff []uint64, e Elem) Elem {
 	acc := f.Zero()
+	if f.inBaseField(e) {
+		x := e.Limb[0] % f.Q
+		for i := len(coeff) - 1; i >= 0; i-- {
+			acc.Limb[0] = modAdd(modMul(acc.Limb[0], x, f.Q), coeff[i], f.Q)
+		}
+		return acc
+	}
 	for i := len(coeff) - 1; i >= 0; i-- {
 		acc = f.Mul(acc, e)
-		acc = f.Add(acc, f.EmbedF(coeff[i]))
+		// Adding an embedded F_q element only touches limb 0.
+		acc.Limb[0] = modAdd(acc.Limb[0], coeff[i], f.Q)
 		if i == 0 {
 			break
 		}
@@ -263,6 +342,16 @@ func (f *Field) EvalFPolyAtK(coeff []uint64, e Elem) Elem {
 	return acc
 }
 
+// inBaseField reports whether e lies in the embedded F_q (limbs 1..θ-1 zero).
+func (f *Field) inBaseField(e Elem) bool {
+	for _, limb := range e.Limb[1:] {
+		if limb%f.Q != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // randU64 reads 8 random bytes and returns them as a uint64 in little endian.
 func randU64(r io.Reader) uint64 {
 	var buf [8]byte
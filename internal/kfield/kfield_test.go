@@ -0,0 +1,182 @@
+package kfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const testQ = 1038337
+
+// mulReference is the schoolbook product reduced by polyMod, independent of
+// the kernels selected in setup.
+func mulReference(f *Field, a, b Elem) Elem {
+	prod := polyMul(poly(f.PhiInv(a)), poly(f.PhiInv(b)), f.Q)
+	return f.Phi(polyMod(prod, poly(f.Chi), f.Q))
+}
+
+func randElem(f *Field, rng *rand.Rand) Elem {
+	e := f.Zero()
+	for i := range e.Limb {
+		e.Limb[i] = rng.Uint64() % f.Q
+	}
+	return e
+}
+
+func elemEqual(a, b Elem) bool {
+	for i := range a.Limb {
+		if a.Limb[i] != b.Limb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestMulMatchesReference checks the fast kernels against mulReference for
+// binomial and dense moduli at every specialised degree.
+func TestMulMatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, theta := range []int{2, 3, 4, 6, 8} {
+		binomial, err := FindIrreducible(testQ, theta, nil)
+		if err != nil {
+			t.Fatalf("θ=%d: FindIrreducible: %v", theta, err)
+		}
+		dense, err := findDenseIrreducible(testQ, theta, nil)
+		if err != nil {
+			t.Fatalf("θ=%d: dense modulus: %v", theta, err)
+		}
+		for name, chi := range map[string][]uint64{"binomial": binomial, "dense": dense} {
+			f, err := New(testQ, theta, chi)
+			if err != nil {
+				t.Fatalf("θ=%d %s: New: %v", theta, name, err)
+			}
+			if !f.fast {
+				t.Fatalf("θ=%d %s: fast path not selected", theta, name)
+			}
+			if name == "binomial" && !f.binomial {
+				t.Fatalf("θ=%d: FindIrreducible returned %v, want X^θ − c", theta, chi)
+			}
+			for i := 0; i < 200; i++ {
+				a, b := randElem(f, rng), randElem(f, rng)
+				if got, want := f.Mul(a, b), mulReference(f, a, b); !elemEqual(got, want) {
+					t.Fatalf("θ=%d %s: Mul(%v, %v) = %v, want %v", theta, name, a.Limb, b.Limb, got.Limb, want.Limb)
+				}
+				// Unreduced limbs must be accepted as before.
+				a.Limb[0] += f.Q
+				if got, want := f.Mul(a, b), mulReference(f, a, b); !elemEqual(got, want) {
+					t.Fatalf("θ=%d %s: unreduced limb: got %v, want %v", theta, name, got.Limb, want.Limb)
+				}
+			}
+		}
+	}
+}
+
+// TestInvAndBatchInv checks Inv and BatchInv against a·a^{-1} = 1.
+func TestInvAndBatchInv(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, theta := range []int{2, 3, 4, 6, 8} {
+		chi, err := FindIrreducible(testQ, theta, nil)
+		if err != nil {
+			t.Fatalf("θ=%d: FindIrreducible: %v", theta, err)
+		}
+		f, err := New(testQ, theta, chi)
+		if err != nil {
+			t.Fatalf("θ=%d: New: %v", theta, err)
+		}
+		xs := make([]Elem, 17)
+		for i := range xs {
+			for f.IsZero(xs[i]) {
+				xs[i] = randElem(f, rng)
+			}
+			if prod := f.Mul(xs[i], f.Inv(xs[i])); !elemEqual(prod, f.One()) {
+				t.Fatalf("θ=%d: a·Inv(a) = %v", theta, prod.Limb)
+			}
+		}
+		for i, inv := range f.BatchInv(xs) {
+			if prod := f.Mul(xs[i], inv); !elemEqual(prod, f.One()) {
+				t.Fatalf("θ=%d: a·BatchInv(a)[%d] = %v", theta, i, prod.Limb)
+			}
+		}
+	}
+}
+
+// TestFindIrreducibleFallsBack covers a degree with no irreducible binomial:
+// 5 ∤ q−1, so FindIrreducible must return a dense modulus.
+func TestFindIrreducibleFallsBack(t *testing.T) {
+	chi, err := FindIrreducible(testQ, 5, nil)
+	if err != nil {
+		t.Fatalf("FindIrreducible: %v", err)
+	}
+	if _, err := New(testQ, 5, chi); err != nil {
+		t.Fatalf("New: %v", err)
+	}
+}
+
+// hornerK evaluates an F_q polynomial at e with K-multiplications only,
+// bypassing the base-field shortcut of EvalFPolyAtK.
+func hornerK(f *Field, coeff []uint64, e Elem) Elem {
+	acc := f.Zero()
+	for i := len(coeff) - 1; i >= 0; i-- {
+		acc = f.Add(f.Mul(acc, e), f.EmbedF(coeff[i]))
+	}
+	return acc
+}
+
+// TestMultiEvalMatchesHorner checks MultiEval against Horner for F_q and
+// K[X] polynomials, including unreduced coefficients and short inputs.
+func TestMultiEvalMatchesHorner(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	const n = 1024
+	for _, theta := range []int{2, 4, 5} {
+		chi, err := FindIrreducible(testQ, theta, nil)
+		if err != nil {
+			t.Fatalf("θ=%d: FindIrreducible: %v", theta, err)
+		}
+		f, err := New(testQ, theta, chi)
+		if err != nil {
+			t.Fatalf("θ=%d: New: %v", theta, err)
+		}
+		points := []Elem{randElem(f, rng), randElem(f, rng), f.EmbedF(12345)}
+		me := f.NewMultiEval(points, n)
+		polys := make([][]uint64, 4)
+		for i := range polys {
+			polys[i] = make([]uint64, n-i*100)
+			for j := range polys[i] {
+				polys[i][j] = rng.Uint64() % f.Q
+			}
+		}
+		polys[1][7] += f.Q
+		polys[2] = nil
+		got := me.EvalFMany(polys)
+		for i, coeff := range polys {
+			for p, e := range points {
+				want := hornerK(f, coeff, e)
+				if !elemEqual(got[i][p], want) {
+					t.Fatalf("θ=%d poly %d point %d: got %v want %v", theta, i, p, got[i][p].Limb, want.Limb)
+				}
+				if direct := f.EvalFPolyAtK(coeff, e); !elemEqual(direct, want) {
+					t.Fatalf("θ=%d poly %d point %d: EvalFPolyAtK %v want %v", theta, i, p, direct.Limb, want.Limb)
+				}
+			}
+		}
+		// P ∈ K[X] from its limbs: P(e) = Σ_j Φ(c_j)·e^j.
+		limbs := [][]uint64{polys[0], polys[3]}
+		for p, e := range points {
+			want := f.Zero()
+			pow := f.One()
+			for j := 0; j < n; j++ {
+				c := []uint64{0, 0}
+				if j < len(limbs[0]) {
+					c[0] = limbs[0][j]
+				}
+				if j < len(limbs[1]) {
+					c[1] = limbs[1][j]
+				}
+				want = f.Add(want, f.Mul(f.Phi(c), pow))
+				pow = f.Mul(pow, e)
+			}
+			if got := me.EvalKAt(p, limbs); !elemEqual(got, want) {
+				t.Fatalf("θ=%d point %d: EvalKAt %v want %v", theta, p, got.Limb, want.Limb)
+			}
+		}
+	}
+}
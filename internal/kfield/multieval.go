@@ -0,0 +1,136 @@
+package kfield
+
+// MultiEval evaluates many polynomials of length at most n at a fixed set of
+// K-points. It stores the power table e^0..e^{n-1} of every point limb by
+// limb, so an F_q-coefficient polynomial costs n·θ multiply-adds per point
+// with one reduction per limb, instead of n full K-multiplications by
+// Horner. The PIOP evaluates hundreds of rows at ℓ′ ≤ 4 points; a
+// subproduct tree or chirp-z transform only pays off when the number of
+// points approaches n, while the table is built once with n K-products per
+// point and then shared by every row.
+type MultiEval struct {
+	f      *Field
+	n      int
+	points []Elem
+	// pow[p][l][j] is limb l of points[p]^j.
+	pow [][][]uint64
+	// basis[l] is X^l, used to recombine limb-wise evaluations of K[X] polys.
+	basis []Elem
+	// lazy is how many products (q-1)^2 fit in a uint64 accumulator.
+	lazy int
+}
+
+// NewMultiEval prepares evaluation of polynomials with up to n coefficients
+// at points.
+func (f *Field) NewMultiEval(points []Elem, n int) *MultiEval {
+	m := &MultiEval{f: f, n: n, points: make([]Elem, len(points))}
+	for p, e := range points {
+		m.points[p] = f.Normalize(e)
+		table := make([][]uint64, f.Theta)
+		for l := range table {
+			table[l] = make([]uint64, n)
+		}
+		cur := f.One()
+		for j := 0; j < n; j++ {
+			for l := range table {
+				table[l][j] = cur.Limb[l]
+			}
+			cur = f.Mul(cur, m.points[p])
+		}
+		m.pow = append(m.pow, table)
+	}
+	m.basis = make([]Elem, f.Theta)
+	for l := range m.basis {
+		m.basis[l] = f.Zero()
+		m.basis[l].Limb[l] = 1 % f.Q
+	}
+	if f.Q > 1 && f.Q < 1<<32 {
+		m.lazy = int(min(^uint64(0)/((f.Q-1)*(f.Q-1)), uint64(n)+1))
+	}
+	return m
+}
+
+// Points returns the number of evaluation points.
+func (m *MultiEval) Points() int { return len(m.points) }
+
+// EvalFAt returns P(points[p]) for P with F_q coefficients coeff. It panics if
+// coeff is longer than the n given to NewMultiEval.
+func (m *MultiEval) EvalFAt(p int, coeff []uint64) Elem {
+	if len(coeff) > m.n {
+		panic("kfield: polynomial longer than MultiEval table")
+	}
+	out := m.f.Zero()
+	for l, row := range m.pow[p] {
+		out.Limb[l] = m.dot(coeff, row[:len(coeff)])
+	}
+	return out
+}
+
+// EvalF returns P at every point.
+func (m *MultiEval) EvalF(coeff []uint64) []Elem {
+	out := make([]Elem, len(m.points))
+	for p := range m.points {
+		out[p] = m.EvalFAt(p, coeff)
+	}
+	return out
+}
+
+// EvalFMany evaluates every polynomial at every point; out[i][p] is
+// polys[i] at points[p], and nil polynomials evaluate to zero.
+func (m *MultiEval) EvalFMany(polys [][]uint64) [][]Elem {
+	out := make([][]Elem, len(polys))
+	for i, coeff := range polys {
+		if coeff == nil {
+			out[i] = make([]Elem, len(m.points))
+			for p := range out[i] {
+				out[i][p] = m.f.Zero()
+			}
+			continue
+		}
+		out[i] = m.EvalF(coeff)
+	}
+	return out
+}
+
+// EvalKAt returns P(points[p]) for P ∈ K[X] given limb-wise: limbs[l][j] is
+// limb l of the coefficient of X^j. Since P = Σ_l X^l·P_l with P_l ∈ F_q[X],
+// this is θ table evaluations recombined by θ K-products.
+func (m *MultiEval) EvalKAt(p int, limbs [][]uint64) Elem {
+	acc := m.f.Zero()
+	for l, coeff := range limbs {
+		if l >= m.f.Theta {
+			break
+		}
+		v := m.EvalFAt(p, coeff)
+		if l > 0 {
+			v = m.f.Mul(m.basis[l], v)
+		}
+		acc = m.f.Add(acc, v)
+	}
+	return acc
+}
+
+// dot returns Σ c_j·v_j mod q, reducing once every m.lazy products.
+func (m *MultiEval) dot(c, v []uint64) uint64 {
+	q := m.f.Q
+	if m.lazy == 0 {
+		var acc uint64
+		for j := range c {
+			acc = modAdd(acc, modMul(c[j], v[j], q), q)
+		}
+		return acc
+	}
+	var acc, total uint64
+	run := 0
+	for j, cj := range c {
+		if cj >= q {
+			cj %= q
+		}
+		acc += cj * v[j]
+		if run++; run == m.lazy {
+			total += acc % q
+			acc, run = 0, 0
+		}
+	}
+	return (total + acc%q) % q
+}
@@ -1,6 +1,7 @@
 package issuance
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -8,7 +9,6 @@ import (
 	"vSIS-Signature/commitment"
 	"vSIS-Signature/credential"
 	"vSIS-Signature/ntru"
-	ntrurio "vSIS-Signature/ntru/io"
 	"vSIS-Signature/ntru/keys"
 	"vSIS-Signature/ntru/signverify"
 
@@ -51,21 +51,20 @@ func PrepareCommit(p *credential.Params, in Inputs) (commitment.Vector, error) {
 	if p == nil || p.RingQ == nil {
 		return nil, fmt.Errorf("nil params or ring")
 	}
-	blocks := [][]*ring.Poly{in.M1, in.M2, in.RU0, in.RU1, in.R}
-	names := []string{"M1", "M2", "RU0", "RU1", "R"}
-	for i, b := range blocks {
-		if len(b) == 0 {
-			return nil, fmt.Errorf("missing block %s", names[i])
-		}
+	if err := checkInputs(p, in); err != nil {
+		return nil, err
 	}
-	vec := make([]*ring.Poly, 0, len(blocks))
+	blocks := [][]*ring.Poly{in.M1, in.M2, in.RU0, in.RU1, in.R}
+	vec := make([]*ring.Poly, 0, Blocks(p).CommitCols())
 	for _, blk := range blocks {
-		ntt := p.RingQ.NewPoly()
-		ring.Copy(blk[0], ntt)
-		p.RingQ.NTT(ntt, ntt)
-		vec = append(vec, ntt)
+		for _, poly := range blk {
+			ntt := p.RingQ.NewPoly()
+			ring.Copy(poly, ntt)
+			p.RingQ.NTT(ntt, ntt)
+			vec = append(vec, ntt)
+		}
 	}
-	com, err := commitment.Commit(p.RingQ, p.Ac, vec)
+	com, err := p.Commit(vec)
 	if err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
@@ -73,53 +72,113 @@ func PrepareCommit(p *credential.Params, in Inputs) (commitment.Vector, error) {
 	return com, nil
 }
 
-// loadB loads the B-matrix from the configured path and lifts to NTT.
-func loadB(r *ring.Ring, path string) ([]*ring.Poly, error) {
-	coeffs, err := ntrurio.LoadBMatrixCoeffs(path)
-	if err != nil {
-		alt := []string{
-			"Parameters/Bmatrix.json",
-			"../Parameters/Bmatrix.json",
-			"../../Parameters/Bmatrix.json",
+// Blocks returns the block lengths of p as the PIOP witness layout.
+func Blocks(p *credential.Params) PIOP.CredentialBlocks {
+	return PIOP.CredentialBlocks{M1: p.LenM1, M2: p.LenM2, RU0: p.LenRU0, RU1: p.LenRU1, R: p.LenR}
+}
+
+// checkInputs checks that every block of in has the length p gives it.
+func checkInputs(p *credential.Params, in Inputs) error {
+	blocks := [][]*ring.Poly{in.M1, in.M2, in.RU0, in.RU1, in.R}
+	names := []string{"M1", "M2", "RU0", "RU1", "R"}
+	want := []int{p.LenM1, p.LenM2, p.LenRU0, p.LenRU1, p.LenR}
+	for i, b := range blocks {
+		if len(b) == 0 {
+			return fmt.Errorf("missing block %s", names[i])
+		}
+		if err := credential.CheckLengths(b, want[i], names[i]); err != nil {
+			return err
 		}
-		for _, pth := range alt {
-			coeffs, err = ntrurio.LoadBMatrixCoeffs(pth)
-			if err == nil {
-				break
+		for j, poly := range b {
+			if poly == nil {
+				return fmt.Errorf("%s[%d]: nil poly", names[i], j)
 			}
 		}
 	}
+	return nil
+}
+
+// DeriveChallenge derives the issuer challenge from com without a round trip
+// to the issuer (see credential.DeriveIssuerChallenge for the security
+// conditions on label).
+func DeriveChallenge(p *credential.Params, com commitment.Vector, issuerPK *keys.PublicKey, label []byte) (Challenge, error) {
+	ch, err := credential.DeriveIssuerChallenge(p, com, issuerPK, label)
+	if err != nil {
+		return Challenge{}, err
+	}
+	return Challenge{RI0: ch.RI0, RI1: ch.RI1}, nil
+}
+
+// Request is the single holder message of non-interactive issuance: the
+// commitment, the hash target and its pre-sign proof π_t.
+type Request struct {
+	Com   commitment.Vector
+	T     []int64
+	Proof *PIOP.Proof
+}
+
+// RequestNonInteractive runs the holder side of non-interactive issuance:
+// it commits to in, derives the challenge from the commitment, applies it and
+// proves π_t. The returned state is what the holder keeps for the credential.
+func RequestNonInteractive(p *credential.Params, issuerPK *keys.PublicKey, label []byte, in Inputs, opts PIOP.SimOpts) (*Request, Challenge, *State, error) {
+	com, err := PrepareCommit(p, in)
+	if err != nil {
+		return nil, Challenge{}, nil, err
+	}
+	ch, err := DeriveChallenge(p, com, issuerPK, label)
+	if err != nil {
+		return nil, Challenge{}, nil, fmt.Errorf("derive challenge: %w", err)
+	}
+	st, err := ApplyChallenge(p, in, ch)
+	if err != nil {
+		return nil, Challenge{}, nil, err
+	}
+	st.Com = com
+	proof, err := ProvePreSign(p, ch, com, in, st, opts)
+	if err != nil {
+		return nil, Challenge{}, nil, err
+	}
+	return &Request{Com: com, T: st.T, Proof: proof}, ch, st, nil
+}
+
+// VerifyRequest runs the issuer side of non-interactive issuance: it derives
+// the challenge from req.Com again and verifies π_t for req.T. The issuer
+// signs req.T only if it returns true; label must be the one the issuer
+// expects for this issuance, not one taken from the holder.
+func VerifyRequest(p *credential.Params, issuerPK *keys.PublicKey, label []byte, req *Request, opts PIOP.SimOpts) (Challenge, bool, error) {
+	if req == nil || req.Proof == nil {
+		return Challenge{}, false, fmt.Errorf("missing request or proof")
+	}
+	ch, err := DeriveChallenge(p, req.Com, issuerPK, label)
 	if err != nil {
-		return nil, fmt.Errorf("load B: %w", err)
+		return Challenge{}, false, fmt.Errorf("derive challenge: %w", err)
 	}
-	out := make([]*ring.Poly, len(coeffs))
-	for i := range coeffs {
-		p := r.NewPoly()
-		copy(p.Coeffs[0], coeffs[i])
-		r.NTT(p, p)
-		out[i] = p
+	B, err := p.LoadHashKey()
+	if err != nil {
+		return Challenge{}, false, err
 	}
-	return out, nil
+	ok, err := VerifyPreSign(p, ch, req.Com, &State{T: req.T, B: B}, req.Proof, opts)
+	return ch, ok, err
 }
 
-// ApplyChallenge computes R0/R1 = center(RU*+RI*), carries K0/K1, and T = HashMessage.
+// ApplyChallenge computes R0/R1 = center(RU*+RI*) per index, carries K0/K1,
+// and T = HashMessageVec under the key expanded for the block lengths.
 // Inputs RU*, R*, M1/M2 are coeff; RI* and B are public/NTT.
 func ApplyChallenge(p *credential.Params, in Inputs, ch Challenge) (*State, error) {
 	log.Printf("[issuance] applying issuer challenge and hashing to target")
 	if p == nil || p.RingQ == nil {
 		return nil, fmt.Errorf("nil params or ring")
 	}
-	if len(ch.RI0) == 0 || len(ch.RI1) == 0 {
-		return nil, fmt.Errorf("missing RI0/RI1")
+	if err := checkInputs(p, in); err != nil {
+		return nil, err
+	}
+	if len(ch.RI0) != p.LenRU0 || len(ch.RI1) != p.LenRU1 {
+		return nil, fmt.Errorf("challenge: RI0/RI1 lengths %d/%d, want %d/%d", len(ch.RI0), len(ch.RI1), p.LenRU0, p.LenRU1)
 	}
 	r := p.RingQ
 	bound := p.BoundB
 	q := int64(r.Modulus[0])
 
-	r0 := r.NewPoly()
-	r1 := r.NewPoly()
-	k0 := r.NewPoly()
-	k1 := r.NewPoly()
 	delta := int64(2*bound + 1)
 
 	sumCarry := func(ru, riNTT, rOut, kOut *ring.Poly) {
@@ -157,24 +216,34 @@ func ApplyChallenge(p *credential.Params, in Inputs, ch Challenge) (*State, erro
 		r.InvNTT(k0NTT, kOut)
 	}
 
-	sumCarry(in.RU0[0], ch.RI0[0], r0, k0)
-	sumCarry(in.RU1[0], ch.RI1[0], r1, k1)
+	// Center every mask index separately: R*[i] = center(RU*[i]+RI*[i]).
+	sumCarryAll := func(ru, ri []*ring.Poly) (rOut, kOut []*ring.Poly) {
+		rOut = make([]*ring.Poly, len(ru))
+		kOut = make([]*ring.Poly, len(ru))
+		for i := range ru {
+			rOut[i], kOut[i] = r.NewPoly(), r.NewPoly()
+			sumCarry(ru[i], ri[i], rOut[i], kOut[i])
+		}
+		return rOut, kOut
+	}
+	r0, k0 := sumCarryAll(in.RU0, ch.RI0)
+	r1, k1 := sumCarryAll(in.RU1, ch.RI1)
 
-	B, err := loadB(r, p.BPath)
+	B, err := p.LoadHashKey()
 	if err != nil {
 		return nil, err
 	}
-	tCoeff, err := credential.HashMessage(r, B, in.M1[0], in.M2[0], r0, r1)
+	tCoeff, err := credential.HashMessageVec(r, B, in.M1, in.M2, r0, r1)
 	if err != nil {
 		return nil, fmt.Errorf("hash message: %w", err)
 	}
 
 	log.Printf("[issuance] derived R0/R1 and T; bound=%d delta=%d", bound, delta)
 	return &State{
-		R0: rPolys(r0),
-		R1: rPolys(r1),
-		K0: rPolys(k0),
-		K1: rPolys(k1),
+		R0: r0,
+		R1: r1,
+		K0: k0,
+		K1: k1,
 		T:  tCoeff,
 		B:  B,
 	}, nil
@@ -182,18 +251,26 @@ func ApplyChallenge(p *credential.Params, in Inputs, ch Challenge) (*State, erro
 
 // ProvePreSign builds the credential pre-sign proof (π_t) with public T.
 func ProvePreSign(p *credential.Params, ch Challenge, com commitment.Vector, in Inputs, st *State, opts PIOP.SimOpts) (*PIOP.Proof, error) {
+	return ProvePreSignContext(context.Background(), p, ch, com, in, st, opts)
+}
+
+// ProvePreSignContext is ProvePreSign with cancellation; it returns a
+// *PIOP.CanceledError once ctx is done.
+func ProvePreSignContext(ctx context.Context, p *credential.Params, ch Challenge, com commitment.Vector, in Inputs, st *State, opts PIOP.SimOpts) (*PIOP.Proof, error) {
 	log.Printf("[issuance] building pre-sign proof (credential mode)")
 	if p == nil || p.RingQ == nil {
 		return nil, fmt.Errorf("nil params or ring")
 	}
 	pub := PIOP.PublicInputs{
-		Com:    com,
-		RI0:    ch.RI0,
-		RI1:    ch.RI1,
-		Ac:     p.Ac,
-		B:      st.B,
-		T:      st.T,
-		BoundB: p.BoundB,
+		Com:          com,
+		RI0:          ch.RI0,
+		RI1:          ch.RI1,
+		Ac:           p.Ac,
+		B:            st.B,
+		T:            st.T,
+		BoundB:       p.BoundB,
+		Blocks:       Blocks(p),
+		ParamsDigest: p.ParamsDigest(),
 	}
 	wit := PIOP.WitnessInputs{
 		M1:  in.M1,
@@ -213,8 +290,11 @@ func ProvePreSign(p *credential.Params, ch Challenge, com commitment.Vector, in
 	if opts.NCols == 0 {
 		opts.NCols = p.RingQ.N
 	}
-	builder := PIOP.NewCredentialBuilder(opts)
-	proof, err := builder.Build(pub, wit, PIOP.MaskConfig{})
+	builder, err := preSignBuilder(p, opts)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := builder.BuildContext(ctx, pub, wit, PIOP.MaskConfig{})
 	if err != nil {
 		return nil, fmt.Errorf("build proof: %w", err)
 	}
@@ -222,34 +302,82 @@ func ProvePreSign(p *credential.Params, ch Challenge, com commitment.Vector, in
 	return proof, nil
 }
 
+// preSignBuilder returns the pre-sign statement builder. When opts.Ring
+// selects a proof field other than the credential ring, the statement mod q
+// is lifted to it with quotient witnesses (PIOP.NewLiftedCredentialBuilder).
+func preSignBuilder(p *credential.Params, opts PIOP.SimOpts) (PIOP.ContextStatementBuilder, error) {
+	if opts.Ring == nil || opts.Ring.Modulus[0] == p.RingQ.Modulus[0] {
+		return PIOP.NewCredentialBuilder(opts), nil
+	}
+	return PIOP.NewLiftedCredentialBuilder(p.RingQ, opts)
+}
+
 // VerifyPreSign verifies the credential pre-sign proof (π_t) with public T.
 func VerifyPreSign(p *credential.Params, ch Challenge, com commitment.Vector, st *State, proof *PIOP.Proof, opts PIOP.SimOpts) (bool, error) {
+	return VerifyPreSignContext(context.Background(), p, ch, com, st, proof, opts)
+}
+
+// VerifyPreSignContext is VerifyPreSign with cancellation.
+func VerifyPreSignContext(ctx context.Context, p *credential.Params, ch Challenge, com commitment.Vector, st *State, proof *PIOP.Proof, opts PIOP.SimOpts) (bool, error) {
 	log.Printf("[issuance] verifying pre-sign proof")
 	if p == nil || p.RingQ == nil {
 		return false, fmt.Errorf("nil params or ring")
 	}
 	pub := PIOP.PublicInputs{
-		Com:    com,
-		RI0:    ch.RI0,
-		RI1:    ch.RI1,
-		Ac:     p.Ac,
-		B:      st.B,
-		T:      st.T,
-		BoundB: p.BoundB,
+		Com:          com,
+		RI0:          ch.RI0,
+		RI1:          ch.RI1,
+		Ac:           p.Ac,
+		B:            st.B,
+		T:            st.T,
+		BoundB:       p.BoundB,
+		Blocks:       Blocks(p),
+		ParamsDigest: p.ParamsDigest(),
 	}
 	opts.Credential = true
-	builder := PIOP.NewCredentialBuilder(opts)
-	ok, err := builder.Verify(pub, proof)
+	builder, err := preSignBuilder(p, opts)
+	if err != nil {
+		return false, err
+	}
+	ok, err := builder.VerifyContext(ctx, pub, proof)
 	if err != nil {
 		return false, fmt.Errorf("verify: %w", err)
 	}
 	return ok, nil
 }
 
+// VerifyPreSignReport verifies the pre-sign proof in diagnostic mode and
+// returns the per-constraint-family report alongside the verdict (see
+// PIOP.VerifyWithConstraintsReport).
+func VerifyPreSignReport(p *credential.Params, ch Challenge, com commitment.Vector, st *State, proof *PIOP.Proof, opts PIOP.SimOpts) (*PIOP.VerificationReport, error) {
+	if p == nil || p.RingQ == nil {
+		return nil, fmt.Errorf("nil params or ring")
+	}
+	pub := PIOP.PublicInputs{
+		Com:          com,
+		RI0:          ch.RI0,
+		RI1:          ch.RI1,
+		Ac:           p.Ac,
+		B:            st.B,
+		T:            st.T,
+		BoundB:       p.BoundB,
+		Blocks:       Blocks(p),
+		ParamsDigest: p.ParamsDigest(),
+	}
+	opts.Credential = true
+	return PIOP.VerifyWithConstraintsReport(proof, PIOP.ConstraintSet{}, pub, opts, PIOP.FSModeCredential)
+}
+
 // SignTargetAndSave signs the provided target coefficients using the stored
 // NTRU trapdoor and persists the signature to ./ntru_keys/signature.json.
 // maxTrials/opts let callers tune the sampler; defaults are applied when zero.
 func SignTargetAndSave(t []int64, maxTrials int, opts ntru.SamplerOpts) (*keys.Signature, error) {
+	return SignTargetAndSaveContext(context.Background(), t, maxTrials, opts)
+}
+
+// SignTargetAndSaveContext is SignTargetAndSave with cancellation of the
+// sampler's rejection loop; it returns a *ntru.CanceledError once ctx is done.
+func SignTargetAndSaveContext(ctx context.Context, t []int64, maxTrials int, opts ntru.SamplerOpts) (*keys.Signature, error) {
 	log.Printf("[issuance] signing target (len=%d) with NTRU trapdoor", len(t))
 	if maxTrials == 0 {
 		maxTrials = 2048
@@ -257,7 +385,7 @@ func SignTargetAndSave(t []int64, maxTrials int, opts ntru.SamplerOpts) (*keys.S
 	if opts.Prec == 0 {
 		opts.Prec = 256
 	}
-	sig, err := signverify.SignTarget(t, maxTrials, opts)
+	sig, err := signverify.SignTargetContext(ctx, t, maxTrials, opts)
 	if err != nil {
 		return nil, fmt.Errorf("sign target: %w", err)
 	}
@@ -267,8 +395,3 @@ func SignTargetAndSave(t []int64, maxTrials int, opts ntru.SamplerOpts) (*keys.S
 	log.Printf("[issuance] signature saved to ./ntru_keys/signature.json (trials_used=%d rejected=%v)", sig.Signature.TrialsUsed, sig.Signature.Rejected)
 	return sig, nil
 }
-
-// rPolys wraps a single poly into a slice (for WitnessInputs convenience).
-func rPolys(p *ring.Poly) []*ring.Poly {
-	return []*ring.Poly{p}
-}
@@ -1,6 +1,7 @@
 package issuance
 
 import (
+	"context"
 	"fmt"
 
 	"vSIS-Signature/PIOP"
@@ -28,6 +29,11 @@ type ShowPRFInputs struct {
 // ProveShowPRF builds a PRF-only proof (tag binding) using the generic PIOP pipeline.
 // It builds PRF trace states as column-constant polys and feeds BuildPRFConstraintSet.
 func ProveShowPRF(p *credential.Params, in ShowPRFInputs, opts PIOP.SimOpts) (*PIOP.Proof, [][]*ring.Poly, error) {
+	return ProveShowPRFContext(context.Background(), p, in, opts)
+}
+
+// ProveShowPRFContext is ProveShowPRF with cancellation.
+func ProveShowPRFContext(ctx context.Context, p *credential.Params, in ShowPRFInputs, opts PIOP.SimOpts) (*PIOP.Proof, [][]*ring.Poly, error) {
 	if p == nil || p.RingQ == nil {
 		return nil, nil, fmt.Errorf("nil params or ring")
 	}
@@ -107,17 +113,10 @@ func ProveShowPRF(p *credential.Params, in ShowPRFInputs, opts PIOP.SimOpts) (*P
 	for _, st := range states {
 		rows = append(rows, st...)
 	}
-	// Base rows in builder order.
-	baseRows := []*ring.Poly{
-		in.M1[0],
-		in.M2[0],
-		in.RU0[0],
-		in.RU1[0],
-		in.R[0],
-		in.R0[0],
-		in.R1[0],
-		in.K0[0],
-		in.K1[0],
+	// Base rows in builder order, block after block.
+	var baseRows []*ring.Poly
+	for _, blk := range [][]*ring.Poly{in.M1, in.M2, in.RU0, in.RU1, in.R, in.R0, in.R1, in.K0, in.K1} {
+		baseRows = append(baseRows, blk...)
 	}
 	startIdx := len(baseRows)
 	rowsFull := append(append([]*ring.Poly{}, baseRows...), rows...)
@@ -163,7 +162,7 @@ func ProveShowPRF(p *credential.Params, in ShowPRFInputs, opts PIOP.SimOpts) (*P
 		opts.Theta = 2 // default to theta>1 for showing
 	}
 	// Build proof using generic pipeline.
-	proof, err := PIOP.BuildWithConstraints(pub, wit, cs, opts, "PACS-Credential")
+	proof, err := PIOP.BuildWithConstraintsContext(ctx, pub, wit, cs, opts, "PACS-Credential")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -174,6 +173,11 @@ func ProveShowPRF(p *credential.Params, in ShowPRFInputs, opts PIOP.SimOpts) (*P
 // NOTE: This is a demo; a real verifier should not receive witness states. This mirrors the
 // current constraint builder pattern where residuals are precomputed.
 func VerifyShowPRF(p *credential.Params, states [][]*ring.Poly, tagPublic [][]int64, proof *PIOP.Proof, opts PIOP.SimOpts) (bool, error) {
+	return VerifyShowPRFContext(context.Background(), p, states, tagPublic, proof, opts)
+}
+
+// VerifyShowPRFContext is VerifyShowPRF with cancellation.
+func VerifyShowPRFContext(ctx context.Context, p *credential.Params, states [][]*ring.Poly, tagPublic [][]int64, proof *PIOP.Proof, opts PIOP.SimOpts) (bool, error) {
 	if p == nil || p.RingQ == nil {
 		return false, fmt.Errorf("nil params or ring")
 	}
@@ -209,7 +213,7 @@ func VerifyShowPRF(p *credential.Params, states [][]*ring.Poly, tagPublic [][]in
 		Tag:    tagPublic,
 	}
 	opts.Credential = true
-	return PIOP.VerifyWithConstraints(proof, cs, pub, opts, "PACS-Credential")
+	return PIOP.VerifyWithConstraintsContext(ctx, proof, cs, pub, opts, "PACS-Credential")
 }
 
 // coeffToElems converts a coeff poly to prf elems.
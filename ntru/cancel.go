@@ -0,0 +1,36 @@
+package ntru
+
+import (
+	"context"
+	"fmt"
+)
+
+// CanceledError reports that a long-running operation (grinding, rejection
+// sampling, row processing) stopped because its context was cancelled or its
+// deadline expired. Err is the context error, so errors.Is(err,
+// context.Canceled) and errors.Is(err, context.DeadlineExceeded) keep working.
+type CanceledError struct {
+	Op  string
+	Err error
+}
+
+func (e *CanceledError) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("canceled: %v", e.Err)
+	}
+	return fmt.Sprintf("%s: canceled: %v", e.Op, e.Err)
+}
+
+func (e *CanceledError) Unwrap() error { return e.Err }
+
+// CheckContext returns a *CanceledError tagged with op when ctx is done, and
+// nil otherwise. A nil ctx is never done.
+func CheckContext(ctx context.Context, op string) error {
+	if ctx == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return &CanceledError{Op: op, Err: err}
+	}
+	return nil
+}
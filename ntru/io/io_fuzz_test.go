@@ -0,0 +1,34 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzLoadSignatureBundle feeds arbitrary signature.json files to the loader;
+// an accepted bundle always has full-length signature rows.
+func FuzzLoadSignatureBundle(f *testing.F) {
+	for _, path := range []string{"../../ntru_keys/signature.json", "../../credential/keys/signature.json"} {
+		if raw, err := os.ReadFile(path); err == nil {
+			f.Add(raw)
+		}
+	}
+	f.Add([]byte(`{"params":{"N":1024,"Q":"0xfd801"},"signature":{"s0":[1],"s1":[],"s2":null}}`))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		path := filepath.Join(t.TempDir(), "signature.json")
+		if err := os.WriteFile(path, raw, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		sig, err := LoadSignatureBundle(path)
+		if err != nil {
+			return
+		}
+		if sig.N != 1024 || len(sig.S0) != sig.N || len(sig.S1) != sig.N {
+			t.Fatalf("accepted bundle with N=%d |s0|=%d |s1|=%d", sig.N, len(sig.S0), len(sig.S1))
+		}
+		if len(sig.S2) != 0 && len(sig.S2) != sig.N {
+			t.Fatalf("accepted bundle with |s2|=%d", len(sig.S2))
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package ntru
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -177,6 +178,13 @@ func (S *Sampler) rebuildV1V2From(z0, z1 []int64) (v1r, v2r []float64, err error
 // - Accepts on CheckNormC(s1, c2 - v2) only (C-style), no congruence in-loop.
 // - After acceptance, computes s0 := t - h*s1 (mod Q), then recenters and returns (s0,s1).
 func (S *Sampler) SamplePreimageTargetOptionB(t ModQPoly, maxTrials int) (s0, s1 *CoeffPoly, trials int, err error) {
+	return S.SamplePreimageTargetOptionBContext(context.Background(), t, maxTrials)
+}
+
+// SamplePreimageTargetOptionBContext is SamplePreimageTargetOptionB with
+// cancellation: ctx is checked before every rejection trial and a
+// *CanceledError is returned once it is done.
+func (S *Sampler) SamplePreimageTargetOptionBContext(ctx context.Context, t ModQPoly, maxTrials int) (s0, s1 *CoeffPoly, trials int, err error) {
 	if S.Opts.ReduceIters <= 0 {
 		S.Opts.ReduceIters = 64
 	}
@@ -226,6 +234,9 @@ func (S *Sampler) SamplePreimageTargetOptionB(t ModQPoly, maxTrials int) (s0, s1
 		return nil, nil, 0, err
 	}
 	for trials = 1; trials <= maxTrials; trials++ {
+		if err := CheckContext(ctx, "OptionB"); err != nil {
+			return nil, nil, trials - 1, err
+		}
 		if trials == 1 || trials%16 == 0 {
 			dbg(os.Stderr, "[OptionB] trial=%d\n", trials)
 		}
@@ -1,6 +1,7 @@
 package signverify
 
 import (
+	"context"
 	crand "crypto/rand"
 	"crypto/sha256"
 	"errors"
@@ -138,8 +139,14 @@ type targetMeta struct {
 // returns a signature bundle. It bypasses seed generation and does not persist
 // the result to disk.
 func SignTarget(tCoeffs []int64, maxTrials int, opts ntru.SamplerOpts) (*keys.Signature, error) {
+	return SignTargetContext(context.Background(), tCoeffs, maxTrials, opts)
+}
+
+// SignTargetContext is SignTarget with cancellation of the rejection loop; it
+// returns a *ntru.CanceledError once ctx is done.
+func SignTargetContext(ctx context.Context, tCoeffs []int64, maxTrials int, opts ntru.SamplerOpts) (*keys.Signature, error) {
 	meta := targetMeta{Persist: false}
-	return signWithTCoeffs(tCoeffs, maxTrials, opts, meta)
+	return signWithTCoeffs(ctx, tCoeffs, maxTrials, opts, meta)
 }
 
 // SignWithOpts mirrors Sign but allows callers to override sampler options.
@@ -160,7 +167,8 @@ func SignWithOpts(message []byte, maxTrials int, opts ntru.SamplerOpts) (*keys.S
 	if _, err := crand.Read(x1Seed); err != nil {
 		return nil, err
 	}
-	// target t
+	// target t, hashed under the standalone B file: plain signatures sit
+	// outside the credential bundle (pubparams) and bind no manifest digest.
 	tCoeffs, err := ntru.ComputeTargetFromSeeds(sys, "Parameters/Bmatrix.json", mSeed, x0Seed, x1Seed)
 	if err != nil {
 		return nil, err
@@ -172,10 +180,10 @@ func SignWithOpts(message []byte, maxTrials int, opts ntru.SamplerOpts) (*keys.S
 		X1Seed:  x1Seed,
 		Persist: true,
 	}
-	return signWithTCoeffs(tCoeffs, maxTrials, opts, meta)
+	return signWithTCoeffs(context.Background(), tCoeffs, maxTrials, opts, meta)
 }
 
-func signWithTCoeffs(tCoeffs []int64, maxTrials int, opts ntru.SamplerOpts, meta targetMeta) (*keys.Signature, error) {
+func signWithTCoeffs(ctx context.Context, tCoeffs []int64, maxTrials int, opts ntru.SamplerOpts, meta targetMeta) (*keys.Signature, error) {
 	pk, err := keys.LoadPublic()
 	if err != nil {
 		return nil, err
@@ -220,7 +228,7 @@ func signWithTCoeffs(tCoeffs []int64, maxTrials int, opts ntru.SamplerOpts, meta
 	S.Opts.ApplyDefaults(S.Par)
 
 	tPoly := ntru.Int64ToModQPoly(tCoeffs, par)
-	s0, s1, trials, err := S.SamplePreimageTargetOptionB(tPoly, maxTrials)
+	s0, s1, trials, err := S.SamplePreimageTargetOptionBContext(ctx, tPoly, maxTrials)
 	if err != nil {
 		return nil, err
 	}
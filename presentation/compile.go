@@ -0,0 +1,170 @@
+package presentation
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/prf"
+	"vSIS-Signature/pubparams"
+	vsishash "vSIS-Signature/vSIS-HASH"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// Presentation is a holder's answer to a Request: the public values the
+// showing was proven for and the proof.
+type Presentation struct {
+	// Blocks is the block layout of the shown credential.
+	Blocks PIOP.CredentialBlocks `json:"blocks"`
+	// Disclosed holds the values of Request.Disclose, in request order.
+	Disclosed []int64 `json:"disclosed,omitempty"`
+	// Counter is the rate-limit slot the nonce was derived for.
+	Counter int `json:"counter"`
+	// Tag is the PRF tag; with a rate limit, verifiers keep the tags they
+	// accepted per context (see TagID). For a pseudonym request it is the
+	// holder's pseudonym for the verifier.
+	Tag   []uint64            `json:"tag"`
+	Proof *PIOP.ProofSnapshot `json:"proof"`
+}
+
+// TagID returns the tag in hex, the key under which a verifier records
+// accepted presentations.
+func (p *Presentation) TagID() string {
+	b := make([]byte, 0, 8*len(p.Tag))
+	for _, v := range p.Tag {
+		b = binary.LittleEndian.AppendUint64(b, v)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Save writes p as JSON.
+func (p *Presentation) Save(path string) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadPresentation reads a presentation file.
+func LoadPresentation(path string) (*Presentation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Presentation
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse presentation: %w", err)
+	}
+	return &p, nil
+}
+
+// Compile builds the showing statement answering r: the constraint set and
+// public inputs PIOP.BuildShowingCombined proves and PIOP.VerifyWithConstraints
+// checks. The issuer's bundle must bind the key r names (and be the bundle r
+// pins, if any); A = [h | 1] is taken from it, so the showing proves a
+// signature under that key. p supplies the holder's public values; its Proof
+// is not read. bound is the credential bound B and ncols the packing width
+// the attributes are numbered over.
+func (r *Request) Compile(ringQ *ring.Ring, bundle *pubparams.Bundle, bound int64, p *Presentation, ncols int) (PIOP.ConstraintSet, PIOP.PublicInputs, error) {
+	var pub PIOP.PublicInputs
+	if err := r.Validate(); err != nil {
+		return PIOP.ConstraintSet{}, pub, err
+	}
+	if bundle == nil || bundle.Manifest.A == nil || bundle.Manifest.A.KeyDigest != r.Issuer {
+		return PIOP.ConstraintSet{}, pub, ErrIssuer
+	}
+	if r.Params != "" && r.Params != hex.EncodeToString(bundle.Digest[:]) {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("%w: bundle %x, request pins %s", ErrIssuer, bundle.Digest, r.Params)
+	}
+	if ncols <= 0 {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("presentation: ncols must be set to number attributes")
+	}
+	if p == nil {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("presentation: nil presentation")
+	}
+	if err := p.Blocks.Validate(); err != nil {
+		return PIOP.ConstraintSet{}, pub, err
+	}
+	if len(p.Disclosed) != len(r.Disclose) {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("presentation: %d disclosed values for %d requested attributes", len(p.Disclosed), len(r.Disclose))
+	}
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("load prf params: %w", err)
+	}
+	if len(p.Tag) != params.LenTag {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("presentation: tag has %d elements, want %d", len(p.Tag), params.LenTag)
+	}
+	nonce, err := r.Nonce(p.Counter, params)
+	if err != nil {
+		return PIOP.ConstraintSet{}, pub, err
+	}
+
+	A, err := bundle.SignatureMatrix(ringQ, 2)
+	if err != nil {
+		return PIOP.ConstraintSet{}, pub, err
+	}
+	B := bundle.B
+	if !p.Blocks.Single() {
+		if B, err = vsishash.ExpandB(ringQ, B, p.Blocks.HashLayout()); err != nil {
+			return PIOP.ConstraintSet{}, pub, fmt.Errorf("expand B: %w", err)
+		}
+	}
+	policy := &PIOP.ShowingPolicy{}
+	for i, a := range r.Disclose {
+		policy.Disclosed = append(policy.Disclosed, PIOP.DisclosedAttr{Attr: a, Value: p.Disclosed[i]})
+	}
+	for i, set := range r.sets() {
+		policy.Predicates = append(policy.Predicates, PIOP.AttrPredicate{Attr: r.Predicates[i].Attr, Set: set})
+	}
+	if err := policy.Check(p.Blocks, bound, ncols); err != nil {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("%w: %v", ErrRequest, err)
+	}
+	if n := PIOP.KeySlots(p.Blocks, ncols); r.bindsKey() && n < params.LenKey {
+		return PIOP.ConstraintSet{}, pub, fmt.Errorf("presentation: M2 holds %d PRF key slots, a pseudonym or rate limit needs %d", n, params.LenKey)
+	}
+	digest := r.Digest()
+	pub = PIOP.PublicInputs{
+		A:            A,
+		B:            B,
+		Tag:          lanes(p.Tag, ncols),
+		Nonce:        lanes(elems(nonce), ncols),
+		BoundB:       bound,
+		Blocks:       p.Blocks,
+		ParamsDigest: bundle.Digest[:],
+		Policy:       policy,
+		Scope:        r.scope(),
+		BindKey:      r.RateLimit > 0,
+		Extras:       map[string]interface{}{"presentation": digest[:]},
+	}
+	layout, err := PIOP.ShowingLayout(pub)
+	if err != nil {
+		return PIOP.ConstraintSet{}, pub, err
+	}
+	return PIOP.ConstraintSet{PRFLayout: layout}, pub, nil
+}
+
+// lanes spreads every value over the ncols slots of Ω.
+func lanes(vals []uint64, ncols int) [][]int64 {
+	out := make([][]int64, len(vals))
+	for i, v := range vals {
+		out[i] = make([]int64, ncols)
+		for j := range out[i] {
+			out[i][j] = int64(v)
+		}
+	}
+	return out
+}
+
+func elems(v []prf.Elem) []uint64 {
+	out := make([]uint64, len(v))
+	for i := range v {
+		out[i] = uint64(v[i])
+	}
+	return out
+}
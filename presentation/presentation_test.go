@@ -0,0 +1,319 @@
+package presentation
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/commitment"
+	"vSIS-Signature/credential"
+	"vSIS-Signature/prf"
+	"vSIS-Signature/pubparams"
+	vsishash "vSIS-Signature/vSIS-HASH"
+	"vSIS-Signature/wallet"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+func testRequest(t *testing.T, issuer string) *Request {
+	t.Helper()
+	ch, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Request{
+		Version:   Version,
+		Verifier:  "shop.example",
+		Issuer:    issuer,
+		Challenge: ch,
+	}
+}
+
+func TestRequestValidate(t *testing.T) {
+	issuer := hex.EncodeToString(make([]byte, 32))
+	data := []byte(`{"version":1,"verifier":"v","issuer":"` + issuer + `","disclose":[0,2],` +
+		`"predicates":[{"attr":1,"range":[-2,3]},{"attr":3,"in":[5,7]}],` +
+		`"context":"c","challenge":"00112233445566778899aabbccddeeff","rate_limit":2}`)
+	r, err := ParseRequest(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if sets := r.sets(); len(sets[0]) != 6 || sets[0][0] != -2 || len(sets[1]) != 2 {
+		t.Fatalf("sets %v", sets)
+	}
+
+	for name, mut := range map[string]func(*Request){
+		"version":     func(r *Request) { r.Version = 2 },
+		"verifier":    func(r *Request) { r.Verifier = "" },
+		"issuer":      func(r *Request) { r.Issuer = "abcd" },
+		"params":      func(r *Request) { r.Params = "zz" },
+		"challenge":   func(r *Request) { r.Challenge = "0011" },
+		"rate-limit":  func(r *Request) { r.RateLimit = -1 },
+		"repeat":      func(r *Request) { r.Disclose = []int{1, 1} },
+		"both":        func(r *Request) { r.Predicates[0].In = []int64{1} },
+		"empty-range": func(r *Request) { r.Predicates[0].Range = []int64{3, 2} },
+		"pseudonym":   func(r *Request) { r.Pseudonym = true },
+	} {
+		r2 := *r
+		r2.Predicates = append([]Predicate(nil), r.Predicates...)
+		mut(&r2)
+		if err := r2.Validate(); !errors.Is(err, ErrRequest) {
+			t.Errorf("%s: got %v, want ErrRequest", name, err)
+		}
+	}
+}
+
+func TestRequestNonce(t *testing.T) {
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	r := testRequest(t, hex.EncodeToString(make([]byte, 32)))
+	if _, err := r.Nonce(1, params); !errors.Is(err, ErrRateLimit) {
+		t.Fatalf("counter without limit: %v", err)
+	}
+	a, _ := r.Nonce(0, params)
+	r2 := *r
+	r2.Challenge, _ = NewChallenge()
+	if b, _ := r2.Nonce(0, params); equalElems(a, b) {
+		t.Fatal("unlimited nonce ignores the challenge")
+	}
+
+	r.RateLimit, r2.RateLimit = 2, 2
+	a, _ = r.Nonce(1, params)
+	if b, _ := r2.Nonce(1, params); !equalElems(a, b) {
+		t.Fatal("rate-limited nonce depends on the challenge")
+	}
+	if b, _ := r.Nonce(0, params); equalElems(a, b) {
+		t.Fatal("counters share a nonce")
+	}
+	if _, err := r.Nonce(2, params); !errors.Is(err, ErrRateLimit) {
+		t.Fatalf("counter at the limit: %v", err)
+	}
+
+	r2.RateLimit, r2.Pseudonym = 0, true
+	want, _ := prf.ScopeNonce(r2.Verifier, params)
+	if b, _ := r2.Nonce(0, params); !equalElems(b, want) {
+		t.Fatal("pseudonym nonce is not the verifier's scope nonce")
+	}
+
+	c := &wallet.Credential{Usage: map[string]*wallet.Usage{r.Verifier: {Counts: map[string]int{r.Context: 1}}}}
+	if n := r.Counter(c); n != 1 {
+		t.Fatalf("counter %d, want 1", n)
+	}
+	if q := r.Query(); q.Issuer != r.Issuer || q.Limit != 2 || len(q.Nonce) != 32 {
+		t.Fatalf("query %+v", q)
+	}
+}
+
+func equalElems(a, b []prf.Elem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// presentationFixture returns a bundle under a random key h and a credential
+// signed under it by U = (0, T), which satisfies h·0 + T = T. Attributes
+// 0…3 hold 3, -2, 0, 5; M2 holds a full PRF key, which rate-limited showings
+// bind, and key is another one.
+func presentationFixture(t *testing.T) (*ring.Ring, *pubparams.Bundle, PIOP.WitnessInputs, []prf.Elem, PIOP.SimOpts) {
+	t.Helper()
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	const ncols = 8
+	seed, err := commitment.NewSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	B, err := pubparams.ExpandB(ringQ, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := make([]int64, ringQ.N)
+	for i := range h {
+		h[i] = int64(seed[i%len(seed)]) + int64(i)
+	}
+	kd := pubparams.KeyDigest(h)
+	bundle := &pubparams.Bundle{
+		Manifest: pubparams.Manifest{A: &pubparams.AEntry{KeyDigest: hex.EncodeToString(kd[:])}},
+		Digest:   seed,
+		B:        B,
+		H:        h,
+	}
+
+	packed := func(vals []int64, lower bool) *ring.Poly {
+		p := ringQ.NewPoly()
+		q := int64(ringQ.Modulus[0])
+		for j, v := range vals {
+			if !lower {
+				j += ncols / 2
+			}
+			p.Coeffs[0][j] = uint64((v%q + q) % q)
+		}
+		ringQ.InvNTT(p, p)
+		return p
+	}
+	constant := func(v uint64) *ring.Poly {
+		p := ringQ.NewPoly()
+		p.Coeffs[0][0] = v
+		return p
+	}
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	m1 := packed([]int64{3, -2, 0, 5}, true)
+	half := ncols / 2
+	m2 := make([]*ring.Poly, (params.LenKey+half-1)/half)
+	for i := range m2 {
+		vals := make([]int64, half)
+		for j := range vals {
+			vals[j] = int64((i*half+j)*5%17) - 8
+		}
+		m2[i] = packed(vals, false)
+	}
+	r0, r1 := constant(3), constant(4)
+	blocks := PIOP.CredentialBlocks{M1: 1, M2: len(m2), RU0: 1, RU1: 1, R: 1}
+	hashKey, err := vsishash.ExpandB(ringQ, B, blocks.HashLayout())
+	if err != nil {
+		t.Fatal(err)
+	}
+	T, err := credential.HashMessageVec(ringQ, hashKey, []*ring.Poly{m1}, m2, []*ring.Poly{r0}, []*ring.Poly{r1})
+	if err != nil {
+		t.Fatalf("hash message: %v", err)
+	}
+	u0 := ringQ.NewPoly()
+	q := int64(ringQ.Modulus[0])
+	for i, c := range T {
+		u0.Coeffs[0][i] = uint64((c%q + q) % q)
+	}
+	base := ringQ.NewPoly()
+	wit := PIOP.WitnessInputs{
+		M1: []*ring.Poly{m1}, M2: m2,
+		RU0: []*ring.Poly{base}, RU1: []*ring.Poly{base}, R: []*ring.Poly{base},
+		R0: []*ring.Poly{r0}, R1: []*ring.Poly{r1},
+		K0: []*ring.Poly{base}, K1: []*ring.Poly{base},
+		T: T,
+		U: []*ring.Poly{ringQ.NewPoly(), u0},
+	}
+
+	key := make([]prf.Elem, params.LenKey)
+	for i := range key {
+		key[i] = prf.Elem(i + 7)
+	}
+	opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+	return ringQ, bundle, wit, key, opts
+}
+
+func TestProveVerify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("proves and verifies several presentations")
+	}
+	ringQ, bundle, wit, key, opts := presentationFixture(t)
+	const bound = 8
+	ctx := context.Background()
+	r := testRequest(t, bundle.Manifest.A.KeyDigest)
+	r.Disclose = []int{1}
+	r.Predicates = []Predicate{{Attr: 3, Range: []int64{4, 8}}, {Attr: 0, In: []int64{1, 3}}}
+	r.Context, r.RateLimit = "daily", 2
+
+	p, err := Prove(ctx, r, ringQ, bundle, bound, wit, key, 1, opts)
+	if err != nil {
+		t.Fatalf("prove: %v", err)
+	}
+	if len(p.Disclosed) != 1 || p.Disclosed[0] != -2 {
+		t.Fatalf("disclosed %v, want [-2]", p.Disclosed)
+	}
+	if err := Verify(ctx, r, ringQ, bundle, bound, p, opts); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	t.Run("other-request", func(t *testing.T) {
+		r2 := *r
+		r2.Challenge, _ = NewChallenge()
+		if err := Verify(ctx, &r2, ringQ, bundle, bound, p, opts); !errors.Is(err, ErrProof) {
+			t.Fatalf("got %v, want ErrProof", err)
+		}
+	})
+	t.Run("false-disclosure", func(t *testing.T) {
+		p2 := *p
+		p2.Disclosed = []int64{-1}
+		if err := Verify(ctx, r, ringQ, bundle, bound, &p2, opts); !errors.Is(err, ErrProof) {
+			t.Fatalf("got %v, want ErrProof", err)
+		}
+	})
+	t.Run("other-counter", func(t *testing.T) {
+		p2 := *p
+		p2.Counter = 0
+		if err := Verify(ctx, r, ringQ, bundle, bound, &p2, opts); !errors.Is(err, ErrProof) {
+			t.Fatalf("got %v, want ErrProof", err)
+		}
+	})
+	t.Run("other-issuer", func(t *testing.T) {
+		r2 := *r
+		r2.Issuer = hex.EncodeToString(make([]byte, 32))
+		if err := Verify(ctx, &r2, ringQ, bundle, bound, p, opts); !errors.Is(err, ErrIssuer) {
+			t.Fatalf("got %v, want ErrIssuer", err)
+		}
+	})
+	t.Run("unsatisfied", func(t *testing.T) {
+		r2 := *r
+		r2.Predicates = []Predicate{{Attr: 2, In: []int64{1}}}
+		if _, err := Prove(ctx, &r2, ringQ, bundle, bound, wit, key, 0, opts); !errors.Is(err, ErrUnsatisfied) {
+			t.Fatalf("got %v, want ErrUnsatisfied", err)
+		}
+	})
+	t.Run("unbound-key", func(t *testing.T) {
+		// A showing under a key other than M2's, proven without the key
+		// binding, must not answer a rate-limited request.
+		params, err := prf.LoadDefaultParams()
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonce, err := r.Nonce(1, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, _ := prf.Tag(key, nonce, params)
+		x0, _ := prf.ConcatKeyNonce(key, nonce, params)
+		trace, err := prf.Trace(x0, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2 := &Presentation{Blocks: p.Blocks, Counter: 1, Disclosed: p.Disclosed, Tag: elems(tag)}
+		_, pub, err := r.Compile(ringQ, bundle, bound, p2, opts.NCols)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.BindKey {
+			t.Fatal("rate-limited request compiled without key binding")
+		}
+		pub.BindKey = false
+		wit2 := wit
+		wit2.Extras = map[string]interface{}{"prf_trace": traceRows(ringQ, trace)}
+		proof, err := PIOP.BuildShowingCombined(pub, wit2, opts)
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		snap := proof.Snapshot()
+		p2.Proof = &snap
+		if err := Verify(ctx, r, ringQ, bundle, bound, p2, opts); !errors.Is(err, ErrProof) {
+			t.Fatalf("got %v, want ErrProof", err)
+		}
+	})
+	t.Run("rate-limit", func(t *testing.T) {
+		if _, err := Prove(ctx, r, ringQ, bundle, bound, wit, key, 2, opts); !errors.Is(err, ErrRateLimit) {
+			t.Fatalf("got %v, want ErrRateLimit", err)
+		}
+	})
+}
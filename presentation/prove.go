@@ -0,0 +1,132 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/prf"
+	"vSIS-Signature/pubparams"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// Prove answers r with the credential in wit (the post-sign witness of
+// PIOP.BuildShowingCombined; its U must be a signature under the issuer's key,
+// and any PRF trace in wit.Extras is replaced) and the holder's PRF key; a
+// pseudonym or rate-limited request reads the key from M2 instead
+// (PIOP.PRFKeyFromM2), since its showing binds the key to the credential.
+// counter is the rate-limit slot to use (see Request.Counter). The
+// credential's attributes are checked against the request first, so an
+// unsatisfiable request fails with ErrUnsatisfied instead of a bad proof.
+func Prove(ctx context.Context, r *Request, ringQ *ring.Ring, bundle *pubparams.Bundle, bound int64, wit PIOP.WitnessInputs, key []prf.Elem, counter int, opts PIOP.SimOpts) (*Presentation, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		return nil, fmt.Errorf("load prf params: %w", err)
+	}
+	nonce, err := r.Nonce(counter, params)
+	if err != nil {
+		return nil, err
+	}
+	if r.bindsKey() {
+		if key, err = PIOP.PRFKeyFromM2(ringQ, wit.M2, opts.NCols, params.LenKey); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsatisfied, err)
+		}
+	}
+	tag, err := prf.Tag(key, nonce, params)
+	if err != nil {
+		return nil, fmt.Errorf("prf tag: %w", err)
+	}
+	x0, err := prf.ConcatKeyNonce(key, nonce, params)
+	if err != nil {
+		return nil, fmt.Errorf("concat key/nonce: %w", err)
+	}
+	trace, err := prf.Trace(x0, params)
+	if err != nil {
+		return nil, fmt.Errorf("prf trace: %w", err)
+	}
+
+	attrs := PIOP.AttrValues(ringQ, wit.M1, opts.NCols)
+	p := &Presentation{Blocks: PIOP.BlocksOf(wit), Counter: counter, Tag: elems(tag)}
+	for _, a := range r.Disclose {
+		if a >= len(attrs) {
+			return nil, fmt.Errorf("%w: attribute %d of %d", ErrUnsatisfied, a, len(attrs))
+		}
+		p.Disclosed = append(p.Disclosed, attrs[a])
+	}
+	for i, set := range r.sets() {
+		a := r.Predicates[i].Attr
+		if a >= len(attrs) || !contains(set, attrs[a]) {
+			return nil, fmt.Errorf("%w: predicate %d on attribute %d", ErrUnsatisfied, i, a)
+		}
+	}
+
+	_, pub, err := r.Compile(ringQ, bundle, bound, p, opts.NCols)
+	if err != nil {
+		return nil, err
+	}
+	extras := make(map[string]interface{}, len(wit.Extras)+1)
+	for k, v := range wit.Extras {
+		extras[k] = v
+	}
+	extras["prf_trace"] = traceRows(ringQ, trace)
+	wit.Extras = extras
+	proof, err := PIOP.BuildShowingCombinedContext(ctx, pub, wit, opts)
+	if err != nil {
+		return nil, fmt.Errorf("build showing: %w", err)
+	}
+	snap := proof.Snapshot()
+	p.Proof = &snap
+	return p, nil
+}
+
+// Verify checks that p answers r: the showing proves a signature under the
+// issuer key r names on a credential with the disclosed attributes and
+// satisfying the predicates, and a tag for the nonce r and p.Counter give.
+// Recording p.TagID per context to enforce a rate limit is the caller's job.
+func Verify(ctx context.Context, r *Request, ringQ *ring.Ring, bundle *pubparams.Bundle, bound int64, p *Presentation, opts PIOP.SimOpts) error {
+	if p == nil || p.Proof == nil {
+		return fmt.Errorf("%w: no proof", ErrProof)
+	}
+	set, pub, err := r.Compile(ringQ, bundle, bound, p, opts.NCols)
+	if err != nil {
+		return err
+	}
+	proof := p.Proof.Restore()
+	proof.PRFLayout = set.PRFLayout
+	opts.Credential = true
+	ok, err := PIOP.VerifyWithConstraintsContext(ctx, proof, set, pub, opts, PIOP.FSModeCredential)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrProof, err)
+	}
+	if !ok {
+		return ErrProof
+	}
+	return nil
+}
+
+func contains(set []int64, v int64) bool {
+	for _, s := range set {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// traceRows lays the PRF trace out as constant rows, as the showing rows
+// expect.
+func traceRows(ringQ *ring.Ring, trace [][]prf.Elem) []*ring.Poly {
+	var rows []*ring.Poly
+	for _, st := range trace {
+		for _, v := range st {
+			p := ringQ.NewPoly()
+			p.Coeffs[0][0] = uint64(v) % ringQ.Modulus[0]
+			rows = append(rows, p)
+		}
+	}
+	return rows
+}
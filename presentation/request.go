@@ -0,0 +1,264 @@
+// Package presentation lets a relying party say what a showing must prove.
+// A Request names the issuer key, the attributes to disclose, predicates on
+// others, a fresh challenge and an optional rate limit per context; Compile
+// turns it into the showing statement of PIOP.BuildShowingCombined, Prove
+// answers it from a holder's credential and Verify checks the answer against
+// the same request.
+package presentation
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"vSIS-Signature/prf"
+	"vSIS-Signature/wallet"
+)
+
+// Version is the request format version this package reads and writes.
+const Version = 1
+
+// maxValue bounds range endpoints before Compile checks them against the
+// credential bound, so expanding a range stays cheap.
+const maxValue = 1 << 16
+
+var (
+	// ErrRequest is returned for a malformed request.
+	ErrRequest = errors.New("presentation: invalid request")
+	// ErrIssuer is returned when the public parameters are not those of the
+	// issuer (or bundle) the request names.
+	ErrIssuer = errors.New("presentation: public parameters do not match the requested issuer")
+	// ErrRateLimit is returned for a showing counter outside the rate limit.
+	ErrRateLimit = errors.New("presentation: counter exceeds the rate limit")
+	// ErrUnsatisfied is returned when the credential does not satisfy a
+	// requested predicate.
+	ErrUnsatisfied = errors.New("presentation: credential does not satisfy the request")
+	// ErrProof is returned when a presentation's proof does not verify.
+	ErrProof = errors.New("presentation: proof rejected")
+)
+
+// Request is a relying party's presentation request.
+//
+// Attributes are numbered as in PIOP.ShowingPolicy. The PRF nonce of the
+// showing is derived from the request: with RateLimit = 0 it hashes the
+// challenge, so every showing has a fresh, unlinkable tag; with RateLimit = k
+// it hashes only (Verifier, Context, counter) with counter < k, and the PRF
+// key is bound to the credential's M2 (PIOP.PublicInputs.BindKey), so a
+// credential has at most k distinct tags per context and the verifier
+// rejects a tag it has already accepted in that context. A rate limit, like
+// a pseudonym, needs an M2 block holding a full PRF key (PIOP.KeySlots).
+// With Pseudonym set the showing is a pseudonym showing scoped to Verifier
+// (PublicInputs.Scope): the tag is the holder's fixed pseudonym for this
+// verifier, unlinkable to those it shows to others.
+type Request struct {
+	Version  int    `json:"version"`
+	Verifier string `json:"verifier"`
+	// Issuer is the hex pubparams.KeyDigest of the issuer's NTRU public key;
+	// Params optionally pins the whole public-parameter bundle by digest.
+	Issuer string `json:"issuer"`
+	Params string `json:"params,omitempty"`
+	// Schema only steers the holder's wallet; it is not proven.
+	Schema     string      `json:"schema,omitempty"`
+	Disclose   []int       `json:"disclose,omitempty"`
+	Predicates []Predicate `json:"predicates,omitempty"`
+	Context    string      `json:"context,omitempty"`
+	// Challenge is a fresh hex string of at least 16 bytes.
+	Challenge string `json:"challenge"`
+	RateLimit int    `json:"rate_limit,omitempty"`
+	Pseudonym bool   `json:"pseudonym,omitempty"`
+}
+
+// Predicate requires attribute Attr to be one of In, or to lie in the
+// inclusive Range [min, max]. Exactly one of In and Range is set.
+type Predicate struct {
+	Attr  int     `json:"attr"`
+	In    []int64 `json:"in,omitempty"`
+	Range []int64 `json:"range,omitempty"`
+}
+
+// NewChallenge returns a fresh 32-byte challenge in hex.
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("presentation: challenge: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ParseRequest decodes and validates a JSON request.
+func ParseRequest(data []byte) (*Request, error) {
+	var r Request
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequest, err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// LoadRequest reads a request file.
+func LoadRequest(path string) (*Request, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRequest(data)
+}
+
+// Save writes r as indented JSON.
+func (r *Request) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Validate checks the request's syntax. Attribute indices and values are
+// checked against the credential layout by Compile.
+func (r *Request) Validate() error {
+	bad := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrRequest, fmt.Sprintf(format, args...))
+	}
+	if r.Version != Version {
+		return bad("version %d, want %d", r.Version, Version)
+	}
+	if r.Verifier == "" {
+		return bad("no verifier")
+	}
+	if b, err := hex.DecodeString(r.Issuer); err != nil || len(b) != sha256.Size {
+		return bad("issuer is not a hex key digest")
+	}
+	if r.Params != "" {
+		if b, err := hex.DecodeString(r.Params); err != nil || len(b) != sha256.Size {
+			return bad("params is not a hex bundle digest")
+		}
+	}
+	if b, err := hex.DecodeString(r.Challenge); err != nil || len(b) < 16 {
+		return bad("challenge must be at least 16 hex-encoded bytes")
+	}
+	if r.RateLimit < 0 {
+		return bad("negative rate limit")
+	}
+	if r.Pseudonym && r.RateLimit > 0 {
+		return bad("a pseudonym takes no rate limit")
+	}
+	seen := make(map[int]bool, len(r.Disclose))
+	for _, a := range r.Disclose {
+		if a < 0 || seen[a] {
+			return bad("disclosed attribute %d negative or repeated", a)
+		}
+		seen[a] = true
+	}
+	for i, p := range r.Predicates {
+		if p.Attr < 0 {
+			return bad("predicate %d: negative attribute", i)
+		}
+		switch {
+		case len(p.In) > 0 && len(p.Range) == 0:
+		case len(p.In) == 0 && len(p.Range) == 2 && -maxValue <= p.Range[0] && p.Range[0] <= p.Range[1] && p.Range[1] <= maxValue:
+		default:
+			return bad("predicate %d: want either \"in\" or a \"range\" [min, max]", i)
+		}
+	}
+	return nil
+}
+
+// Digest hashes the request; proofs bind it into their FS labels, so a
+// presentation answers exactly one request.
+func (r *Request) Digest() [32]byte {
+	data, _ := json.Marshal(r)
+	return sha256.Sum256(append([]byte("vSIS-presentation/request"), data...))
+}
+
+// Nonce derives the PRF nonce of the showing with the given counter (see
+// Request).
+func (r *Request) Nonce(counter int, params *prf.Params) ([]prf.Elem, error) {
+	if r.RateLimit > 0 && (counter < 0 || counter >= r.RateLimit) {
+		return nil, fmt.Errorf("%w: counter %d, limit %d", ErrRateLimit, counter, r.RateLimit)
+	}
+	if r.RateLimit == 0 && counter != 0 {
+		return nil, fmt.Errorf("%w: counter %d without a rate limit", ErrRateLimit, counter)
+	}
+	if r.Pseudonym {
+		return prf.ScopeNonce(r.Verifier, params)
+	}
+	seed := sha256.New()
+	seed.Write([]byte("vSIS-presentation/nonce"))
+	for _, s := range []string{r.Verifier, r.Context} {
+		seed.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(s))))
+		seed.Write([]byte(s))
+	}
+	if r.RateLimit > 0 {
+		seed.Write(binary.LittleEndian.AppendUint64(nil, uint64(counter)))
+	} else {
+		seed.Write([]byte(r.Challenge))
+	}
+	key := seed.Sum(nil)
+	out := make([]prf.Elem, params.LenNonce)
+	for i := range out {
+		h := sha256.Sum256(binary.LittleEndian.AppendUint64(append([]byte(nil), key...), uint64(i)))
+		out[i] = prf.Elem(binary.LittleEndian.Uint64(h[:8]) % params.Q)
+	}
+	return out, nil
+}
+
+// Query returns the wallet query selecting a credential for r. The challenge
+// is the query nonce, so a wallet does not answer the same request twice.
+func (r *Request) Query() wallet.Query {
+	nonce, _ := hex.DecodeString(r.Challenge)
+	return wallet.Query{
+		Issuer:   r.Issuer,
+		Schema:   r.Schema,
+		Verifier: r.Verifier,
+		Context:  r.Context,
+		Limit:    r.RateLimit,
+		Nonce:    nonce,
+	}
+}
+
+// Counter returns the counter of c's next showing under r: the number of
+// showings the wallet recorded for r's verifier and context.
+func (r *Request) Counter(c *wallet.Credential) int {
+	if r.RateLimit == 0 {
+		return 0
+	}
+	if u := c.Usage[r.Verifier]; u != nil {
+		return u.Counts[r.Context]
+	}
+	return 0
+}
+
+// sets expands the predicates to the value sets of PIOP.AttrPredicate.
+func (r *Request) sets() [][]int64 {
+	out := make([][]int64, len(r.Predicates))
+	for i, p := range r.Predicates {
+		if len(p.In) > 0 {
+			out[i] = append([]int64(nil), p.In...)
+			continue
+		}
+		for v := p.Range[0]; v <= p.Range[1]; v++ {
+			out[i] = append(out[i], v)
+		}
+	}
+	return out
+}
+
+// scope returns the pseudonym scope of r, empty for a one-time tag.
+func (r *Request) scope() string {
+	if r.Pseudonym {
+		return r.Verifier
+	}
+	return ""
+}
+
+// bindsKey reports whether the showing answering r binds the PRF key to M2.
+func (r *Request) bindsKey() bool {
+	return r.Pseudonym || r.RateLimit > 0
+}
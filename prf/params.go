@@ -1,6 +1,8 @@
 package prf
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -73,6 +75,41 @@ func (p *Params) Validate() error {
 	return nil
 }
 
+// Digest hashes the whole parameter set, matrices and round constants
+// included, in a fixed binary encoding.
+func (p *Params) Digest() [32]byte {
+	d := sha256.New()
+	d.Write([]byte("vSIS-PRF/params"))
+	var buf [8]byte
+	write := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		d.Write(buf[:])
+	}
+	writeMatrix := func(m [][]uint64) {
+		write(uint64(len(m)))
+		for _, row := range m {
+			write(uint64(len(row)))
+			for _, v := range row {
+				write(v)
+			}
+		}
+	}
+	write(p.Q)
+	write(p.D)
+	write(uint64(p.LenKey))
+	write(uint64(p.LenNonce))
+	write(uint64(p.LenTag))
+	write(uint64(p.RF))
+	write(uint64(p.RP))
+	writeMatrix(p.ME)
+	writeMatrix(p.MI)
+	writeMatrix(p.CExt)
+	writeMatrix([][]uint64{p.CInt})
+	var out [32]byte
+	copy(out[:], d.Sum(nil))
+	return out
+}
+
 func checkMatrix(m [][]uint64, t int) error {
 	if len(m) != t {
 		return fmt.Errorf("rows=%d want %d", len(m), t)
@@ -0,0 +1,49 @@
+package prf
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// FuzzLoadParams decodes arbitrary parameter files. Anything LoadParams
+// accepts must drive the permutation without panicking and yield a tag in
+// the field.
+func FuzzLoadParams(f *testing.F) {
+	if raw, err := os.ReadFile("prf_params.json"); err == nil {
+		f.Add(raw)
+	}
+	small, err := json.Marshal(&Params{
+		Q: 101, D: 5, LenKey: 2, LenNonce: 1, LenTag: 1, RF: 2, RP: 1,
+		ME:   [][]uint64{{1, 2, 3}, {4, 5, 6}, {7, 8, 10}},
+		MI:   [][]uint64{{2, 1, 0}, {0, 3, 1}, {1, 0, 2}},
+		CExt: [][]uint64{{1, 1, 1}, {2, 2, 2}},
+		CInt: []uint64{5},
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(small)
+	f.Add([]byte(`{"Q":1,"D":3,"LenKey":1,"LenNonce":1,"LenTag":1,"RF":2,"RP":1}`))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		p, err := LoadParams(bytes.NewReader(raw))
+		if err != nil {
+			return
+		}
+		key := make([]Elem, p.LenKey)
+		nonce := make([]Elem, p.LenNonce)
+		for i := range key {
+			key[i] = Elem(uint64(i+1) % p.Q)
+		}
+		tag, err := Tag(key, nonce, p)
+		if err != nil {
+			t.Fatalf("Tag on validated params: %v", err)
+		}
+		for i, v := range tag {
+			if uint64(v) >= p.Q {
+				t.Fatalf("tag[%d]=%d not reduced mod %d", i, v, p.Q)
+			}
+		}
+	})
+}
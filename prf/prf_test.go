@@ -48,3 +48,49 @@ func TestPRFSanity(t *testing.T) {
 		t.Fatalf("tag[0]=%d want %d", tag[0], expected)
 	}
 }
+
+func TestParamsDigest(t *testing.T) {
+	p, err := LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	d := p.Digest()
+	again, err := LoadDefaultParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Digest() != d {
+		t.Fatal("digest differs between loads")
+	}
+	again.CInt[len(again.CInt)-1]++
+	if again.Digest() == d {
+		t.Fatal("digest ignores the round constants")
+	}
+}
+
+func TestScopeNonce(t *testing.T) {
+	p, err := LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	a, err := ScopeNonce("shop.example", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != p.LenNonce {
+		t.Fatalf("nonce length %d want %d", len(a), p.LenNonce)
+	}
+	again, _ := ScopeNonce("shop.example", p)
+	other, _ := ScopeNonce("shop.example2", p)
+	same, differ := true, false
+	for i := range a {
+		if uint64(a[i]) >= p.Q {
+			t.Fatalf("lane %d = %d not reduced mod q", i, a[i])
+		}
+		same = same && a[i] == again[i]
+		differ = differ || a[i] != other[i]
+	}
+	if !same || !differ {
+		t.Fatalf("scope nonce: deterministic=%v, scope-dependent=%v", same, differ)
+	}
+}
@@ -0,0 +1,24 @@
+package prf
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// ScopeNonce hashes a scope (a verifier's identifier) to a nonce, so that
+// Tag(key, ScopeNonce(scope)) is a pseudonym that is the same for every
+// showing to the scope and unlinkable across scopes. Lane i is the first 8
+// bytes of SHA-256(domain || len(scope) || scope || i) reduced mod Q.
+func ScopeNonce(scope string, params *Params) ([]Elem, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	prefix := append([]byte("vSIS-PRF/scope/v1"), binary.LittleEndian.AppendUint64(nil, uint64(len(scope)))...)
+	prefix = append(prefix, scope...)
+	out := make([]Elem, params.LenNonce)
+	for i := range out {
+		h := sha256.Sum256(binary.LittleEndian.AppendUint64(append([]byte(nil), prefix...), uint64(i)))
+		out[i] = Elem(binary.LittleEndian.Uint64(h[:8]) % params.Q)
+	}
+	return out, nil
+}
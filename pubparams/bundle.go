@@ -0,0 +1,299 @@
+// Package pubparams stores the public matrices of the credential scheme as a
+// seed-compressed bundle: a versioned manifest from which B and Ac are
+// expanded deterministically, and which binds the NTRU public key behind the
+// signature matrix A = [h | 1] by its digest. The manifest digest commits to
+// the whole bundle and is what proofs bind into their FS labels.
+package pubparams
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"vSIS-Signature/commitment"
+	ntrurio "vSIS-Signature/ntru/io"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+	"github.com/tuneinsight/lattigo/v4/utils"
+)
+
+// Version is the manifest version this package reads and writes.
+const Version = 1
+
+// BCount is the number of hash-key polynomials B0…B3 in a bundle; longer
+// vector-hash keys are derived from them with vsishash.ExpandB.
+const BCount = 4
+
+var (
+	// ErrDigest is returned when a manifest's stored digest does not match
+	// its contents.
+	ErrDigest = errors.New("pubparams: manifest digest mismatch")
+	// ErrKey is returned when the public key file does not hash to the
+	// digest the manifest binds.
+	ErrKey = errors.New("pubparams: public key does not match the manifest")
+)
+
+// Manifest is the on-disk description of a bundle.
+type Manifest struct {
+	Version int     `json:"version"`
+	N       int     `json:"n"`
+	Q       uint64  `json:"q"`
+	B       BEntry  `json:"b"`
+	Ac      AcEntry `json:"ac"`
+	// A is optional: pre-sign statements do not use the signature matrix.
+	A      *AEntry `json:"a,omitempty"`
+	Digest string  `json:"digest"`
+}
+
+// BEntry gives the seed of B0…B3.
+type BEntry struct {
+	Seed string `json:"seed"`
+}
+
+// AcEntry gives the seed and shape of the commitment key (commitment.Expand).
+type AcEntry struct {
+	Seed string `json:"seed"`
+	Rows int    `json:"rows"`
+	Cols int    `json:"cols"`
+}
+
+// AEntry names the NTRU public key h behind A = [h | 1] and its digest.
+type AEntry struct {
+	PublicKey string `json:"public_key"`
+	KeyDigest string `json:"key_digest"`
+}
+
+// Bundle is a loaded manifest with its matrices expanded (NTT form).
+type Bundle struct {
+	Manifest Manifest
+	Digest   [32]byte
+	B        []*ring.Poly
+	Ac       commitment.Matrix
+	AcParams commitment.Params
+	// H is the public key in coefficient form; nil without an A entry.
+	H []int64
+}
+
+// ExpandB derives B0…B3 from seed. A keyed PRNG over the seed, the same
+// construction ComputeTargetFromSeeds uses for m/x0/x1, feeds a uniform
+// sampler; the coefficient-domain polys are returned in NTT form.
+func ExpandB(ringQ *ring.Ring, seed commitment.Seed) ([]*ring.Poly, error) {
+	prng, err := utils.NewKeyedPRNG(append([]byte("vSIS-PP/B/v1"), seed[:]...))
+	if err != nil {
+		return nil, fmt.Errorf("b prng: %w", err)
+	}
+	us := ring.NewUniformSampler(prng, ringQ)
+	out := make([]*ring.Poly, BCount)
+	for i := range out {
+		p := ringQ.NewPoly()
+		us.Read(p)
+		ringQ.NTT(p, p)
+		out[i] = p
+	}
+	return out, nil
+}
+
+// KeyDigest hashes the coefficients of an NTRU public key h.
+func KeyDigest(h []int64) [32]byte {
+	d := sha256.New()
+	d.Write([]byte("vSIS-PP/A/v1"))
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(len(h)))
+	d.Write(buf[:])
+	for _, c := range h {
+		binary.LittleEndian.PutUint64(buf[:], uint64(c))
+		d.Write(buf[:])
+	}
+	var out [32]byte
+	copy(out[:], d.Sum(nil))
+	return out
+}
+
+// ComputeDigest hashes every field of m except Digest in a fixed binary
+// encoding, so two manifests share a digest iff they describe the same
+// bundle.
+func (m Manifest) ComputeDigest() [32]byte {
+	d := sha256.New()
+	d.Write([]byte("vSIS-PP/manifest"))
+	var buf [8]byte
+	writeInt := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		d.Write(buf[:])
+	}
+	writeStr := func(s string) {
+		writeInt(uint64(len(s)))
+		d.Write([]byte(s))
+	}
+	writeInt(uint64(m.Version))
+	writeInt(uint64(m.N))
+	writeInt(m.Q)
+	writeStr(m.B.Seed)
+	writeStr(m.Ac.Seed)
+	writeInt(uint64(m.Ac.Rows))
+	writeInt(uint64(m.Ac.Cols))
+	if m.A != nil {
+		writeInt(1)
+		writeStr(m.A.PublicKey)
+		writeStr(m.A.KeyDigest)
+	} else {
+		writeInt(0)
+	}
+	var out [32]byte
+	copy(out[:], d.Sum(nil))
+	return out
+}
+
+// New returns a manifest with fresh B and Ac seeds for an Ac of acRows×acCols
+// ring elements. If pkPath is non-empty, h is bound as the key behind A.
+func New(ringQ *ring.Ring, acRows, acCols int, pkPath string, h []int64) (Manifest, error) {
+	if err := (commitment.Params{Height: acRows, Width: acCols}).Validate(); err != nil {
+		return Manifest{}, err
+	}
+	bSeed, err := commitment.NewSeed()
+	if err != nil {
+		return Manifest{}, err
+	}
+	acSeed, err := commitment.NewSeed()
+	if err != nil {
+		return Manifest{}, err
+	}
+	m := Manifest{
+		Version: Version,
+		N:       ringQ.N,
+		Q:       ringQ.Modulus[0],
+		B:       BEntry{Seed: bSeed.String()},
+		Ac:      AcEntry{Seed: acSeed.String(), Rows: acRows, Cols: acCols},
+	}
+	if pkPath != "" {
+		if len(h) != ringQ.N {
+			return Manifest{}, fmt.Errorf("public key has %d coefficients, want %d", len(h), ringQ.N)
+		}
+		kd := KeyDigest(h)
+		m.A = &AEntry{PublicKey: pkPath, KeyDigest: hex.EncodeToString(kd[:])}
+	}
+	d := m.ComputeDigest()
+	m.Digest = hex.EncodeToString(d[:])
+	return m, nil
+}
+
+// Save writes m as indented JSON.
+func Save(path string, m Manifest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load reads the manifest at path and expands it over ringQ. The manifest
+// must have this package's version, match the ring, and carry the digest of
+// its contents; an A entry's key file (resolved like the manifest, then
+// relative to its directory) must hash to the bound key digest.
+func Load(path string, ringQ *ring.Ring) (*Bundle, error) {
+	raw, resolved, err := readFileWithFallback(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	return Open(m, ringQ, filepath.Dir(resolved))
+}
+
+// Open checks and expands an already decoded manifest; key paths are
+// resolved against dir as a last resort.
+func Open(m Manifest, ringQ *ring.Ring, dir string) (*Bundle, error) {
+	if m.Version != Version {
+		return nil, fmt.Errorf("pubparams: manifest version %d, want %d", m.Version, Version)
+	}
+	if m.N != ringQ.N || m.Q != ringQ.Modulus[0] {
+		return nil, fmt.Errorf("pubparams: manifest ring (N=%d, q=%d) does not match (N=%d, q=%d)", m.N, m.Q, ringQ.N, ringQ.Modulus[0])
+	}
+	digest := m.ComputeDigest()
+	if m.Digest != hex.EncodeToString(digest[:]) {
+		return nil, ErrDigest
+	}
+	bSeed, err := commitment.ParseSeed(m.B.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("pubparams: b: %w", err)
+	}
+	acSeed, err := commitment.ParseSeed(m.Ac.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("pubparams: ac: %w", err)
+	}
+	B, err := ExpandB(ringQ, bSeed)
+	if err != nil {
+		return nil, err
+	}
+	acParams := commitment.Params{Height: m.Ac.Rows, Width: m.Ac.Cols, Seed: acSeed}
+	Ac, err := commitment.Expand(ringQ, acParams)
+	if err != nil {
+		return nil, fmt.Errorf("pubparams: ac: %w", err)
+	}
+	b := &Bundle{Manifest: m, Digest: digest, B: B, Ac: Ac, AcParams: acParams}
+	if m.A != nil {
+		h, err := loadKey(m.A.PublicKey, dir)
+		if err != nil {
+			return nil, fmt.Errorf("pubparams: a: %w", err)
+		}
+		kd := KeyDigest(h)
+		if m.A.KeyDigest != hex.EncodeToString(kd[:]) {
+			return nil, ErrKey
+		}
+		b.H = h
+	}
+	return b, nil
+}
+
+// SignatureMatrix returns A = [h | 1] in NTT form for a signature preimage of
+// uCount ring elements, or [1] when uCount ≤ 1.
+func (b *Bundle) SignatureMatrix(ringQ *ring.Ring, uCount int) ([][]*ring.Poly, error) {
+	one := ringQ.NewPoly()
+	one.Coeffs[0][0] = 1
+	ringQ.NTT(one, one)
+	if uCount <= 1 {
+		return [][]*ring.Poly{{one}}, nil
+	}
+	if len(b.H) != ringQ.N {
+		return nil, fmt.Errorf("pubparams: manifest binds no public key of degree %d", ringQ.N)
+	}
+	h := ringQ.NewPoly()
+	q := int64(ringQ.Modulus[0])
+	for i, c := range b.H {
+		if c %= q; c < 0 {
+			c += q
+		}
+		h.Coeffs[0][i] = uint64(c)
+	}
+	ringQ.NTT(h, h)
+	return [][]*ring.Poly{{h, one}}, nil
+}
+
+func loadKey(path, dir string) ([]int64, error) {
+	if _, resolved, err := readFileWithFallback(path); err == nil {
+		return ntrurio.LoadPublicH(resolved)
+	}
+	return ntrurio.LoadPublicH(filepath.Join(dir, path))
+}
+
+func readFileWithFallback(path string) ([]byte, string, error) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = append(candidates, filepath.Join("..", path), filepath.Join("..", "..", path))
+	}
+	for _, p := range candidates {
+		if data, err := os.ReadFile(p); err == nil {
+			return data, p, nil
+		}
+	}
+	return nil, "", fmt.Errorf("read %s: not found", path)
+}
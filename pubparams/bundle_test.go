@@ -0,0 +1,119 @@
+package pubparams
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ntrurio "vSIS-Signature/ntru/io"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+func testRing(t *testing.T) *ring.Ring {
+	t.Helper()
+	par, err := ntrurio.LoadParams("../Parameters/Parameters.json", true)
+	if err != nil {
+		t.Fatalf("load params: %v", err)
+	}
+	ringQ, err := ring.NewRing(par.N, []uint64{par.Q})
+	if err != nil {
+		t.Fatalf("ring: %v", err)
+	}
+	return ringQ
+}
+
+// writeKey stores h in the public.json schema LoadPublicH reads.
+func writeKey(t *testing.T, path string, h []int64) {
+	t.Helper()
+	data, _ := json.Marshal(map[string]any{"N": len(h), "Q": "1038337", "h_coeffs": h})
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	ringQ := testRing(t)
+	dir := t.TempDir()
+	h := make([]int64, ringQ.N)
+	for i := range h {
+		h[i] = int64(i%17) - 8
+	}
+	writeKey(t, filepath.Join(dir, "public.json"), h)
+
+	m, err := New(ringQ, 2, 6, "public.json", h)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	path := filepath.Join(dir, "manifest.json")
+	if err := Save(path, m); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Load(path, ringQ)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(b.B) != BCount || len(b.Ac) != 2 || len(b.Ac[0]) != 6 || len(b.H) != ringQ.N {
+		t.Fatalf("bundle shape: B=%d Ac=%d×%d H=%d", len(b.B), len(b.Ac), len(b.Ac[0]), len(b.H))
+	}
+	again, err := Load(path, ringQ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range b.B {
+		if !ringQ.Equal(b.B[i], again.B[i]) {
+			t.Fatalf("B[%d] differs between loads", i)
+		}
+	}
+	A, err := b.SignatureMatrix(ringQ, 2)
+	if err != nil || len(A) != 1 || len(A[0]) != 2 {
+		t.Fatalf("signature matrix: %v", err)
+	}
+
+	other, err := New(ringQ, 2, 6, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Digest == m.Digest || other.B.Seed == m.B.Seed {
+		t.Fatal("fresh manifests share seeds")
+	}
+	if _, err := (&Bundle{}).SignatureMatrix(ringQ, 2); err == nil {
+		t.Fatal("A built without a bound key")
+	}
+}
+
+func TestBundleRejects(t *testing.T) {
+	ringQ := testRing(t)
+	dir := t.TempDir()
+	h := make([]int64, ringQ.N)
+	h[0] = 5
+	writeKey(t, filepath.Join(dir, "public.json"), h)
+	m, err := New(ringQ, 1, 5, "public.json", h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed := m
+	seed.B.Seed = m.Ac.Seed
+	if _, err := Open(seed, ringQ, dir); !errors.Is(err, ErrDigest) {
+		t.Errorf("changed B seed: got %v, want ErrDigest", err)
+	}
+	shape := m
+	shape.Ac.Rows = 2
+	if _, err := Open(shape, ringQ, dir); !errors.Is(err, ErrDigest) {
+		t.Errorf("changed Ac shape: got %v, want ErrDigest", err)
+	}
+	version := m
+	version.Version = Version + 1
+	if _, err := Open(version, ringQ, dir); err == nil {
+		t.Error("unknown version accepted")
+	}
+
+	h[0] = 6
+	writeKey(t, filepath.Join(dir, "public.json"), h)
+	if _, err := Open(m, ringQ, dir); !errors.Is(err, ErrKey) {
+		t.Errorf("replaced key: got %v, want ErrKey", err)
+	}
+}
@@ -0,0 +1,146 @@
+package security
+
+import "math"
+
+// Estimate is the cheapest parametrisation found for one attack. Bits is
+// +Inf when the attack cannot succeed; Note then says why.
+type Estimate struct {
+	Attack string
+	Beta   int // BKZ block size
+	Dim    int // dimension of the reduced (sub-)lattice
+	Guess  int // hybrid only: secret coordinates guessed by meet-in-the-middle
+	Bits   float64
+	Note   string
+}
+
+// Statistical reports whether the attack fails for lack of a solution rather
+// than for cost.
+func (e Estimate) Statistical() bool {
+	return math.IsInf(e.Bits, 1)
+}
+
+// PrimalUSVP estimates recovering a short secret of n coordinates from m
+// q-ary equations whose error has m coordinates, all of standard deviation
+// sigma (NTRU: f·h = g with f, g ∈ R of degree n). Keeping m′ ≤ m equations
+// gives a d = n+m′ lattice of volume q^{m′} holding the secret, and BKZ-β
+// recovers it once σ·√β ≤ δ^{2β−d}·q^{m′/d} [ADPS16].
+func PrimalUSVP(n, m int, q, sigma float64, model CostModel) Estimate {
+	lnq, lhs0 := math.Log(q), math.Log(sigma)
+	for beta := minBlockSize; beta <= n+m; beta++ {
+		lnDelta := math.Log(RootHermite(beta))
+		d := optimalDim(n, q, lnDelta, max(n+1, beta), n+m)
+		lhs := lhs0 + 0.5*math.Log(float64(beta))
+		rhs := float64(2*beta-d)*lnDelta + float64(d-n)/float64(d)*lnq
+		if lhs <= rhs {
+			return Estimate{Attack: "primal-usvp", Beta: beta, Dim: d, Bits: model.Cost(beta, d)}
+		}
+	}
+	return Estimate{Attack: "primal-usvp", Bits: math.Inf(1), Note: "secret not unique: no block size up to n+m succeeds"}
+}
+
+// Dual estimates distinguishing the same instance from uniform. BKZ-β on the
+// dual lattice of dimension d = n+m′ and volume q^n yields w of length
+// ℓ = δ^d·q^{n/d}; ⟨w, secret⟩ then has advantage ε = exp(−2π²(ℓσ/q)²), and
+// the 1/ε² samples needed are amortised against the 2^{0.2075β} vectors one
+// sieve call returns.
+func Dual(n, m int, q, sigma float64, model CostModel) Estimate {
+	best := Estimate{Attack: "dual", Bits: math.Inf(1)}
+	lnq := math.Log(q)
+	for beta := minBlockSize; beta <= n+m; beta++ {
+		lnDelta := math.Log(RootHermite(beta))
+		d := optimalDim(n, q, lnDelta, max(n+1, beta), n+m)
+		x := math.Exp(float64(d)*lnDelta+float64(n)/float64(d)*lnq) * sigma / q
+		samples := 4 * math.Pi * math.Pi * x * x / math.Ln2 // log2(1/ε²)
+		bits := model.Cost(beta, d) + math.Max(0, samples-sieveVectors(beta))
+		if bits < best.Bits {
+			best = Estimate{Attack: "dual", Beta: beta, Dim: d, Bits: bits}
+		}
+	}
+	return best
+}
+
+// Hybrid is Howgrave-Graham's attack: BKZ-β reduces, once, the lattice of
+// the n−r secret coordinates that are not guessed; the other r are found by
+// meet-in-the-middle at 2^{r·H/2} (H the entropy of one coordinate), each
+// candidate checked by Babai nearest-plane. Under the geometric series
+// assumption nearest-plane succeeds with p = Π_i erf(‖b*_i‖/(2√2·σ)), and
+// the search is repeated 1/p times. Against wide Gaussian secrets p is tiny
+// and the hybrid rarely beats r = 0, which is PrimalUSVP.
+func Hybrid(n, m int, q, sigma float64, model CostModel) Estimate {
+	best := PrimalUSVP(n, m, q, sigma, model)
+	best.Attack = "hybrid"
+	h := gaussianEntropy(sigma)
+	lnq := math.Log(q)
+	step := max(1, n/64)
+	for r := step; r < n; r += step {
+		guess := float64(r) * h / 2
+		if guess >= best.Bits {
+			break
+		}
+		d := n - r + m
+		lnVol := float64(m) / float64(d) * lnq
+		for beta := minBlockSize; beta <= d; beta += 2 {
+			reduce := model.Cost(beta, d)
+			if reduce >= best.Bits {
+				break
+			}
+			lnDelta := math.Log(RootHermite(beta))
+			log2P := 0.0
+			for i := 0; i < d && guess-log2P < best.Bits; i++ {
+				gs := math.Exp(float64(d-2*i)*lnDelta + lnVol)
+				log2P += math.Log2(math.Erf(gs / (2 * math.Sqrt2 * sigma)))
+			}
+			if bits := log2Sum(reduce, guess-log2P); bits < best.Bits {
+				best = Estimate{Attack: "hybrid", Beta: beta, Dim: d, Guess: r, Bits: bits}
+			}
+		}
+	}
+	return best
+}
+
+// SIS estimates finding a nonzero x ∈ Λ⊥(A) = {x ∈ Z^m : A·x = 0 mod q}
+// with ‖x‖₂ ≤ bound for A ∈ Z_q^{n×m}; inhomogeneous instances (forgery)
+// are priced the same. BKZ-β on a d-column sub-lattice reaches δ^d·q^{n/d}.
+// With bound ≥ q the vector q·e₁ already works (0 bits). With m ≤ n a random
+// A is injective and Λ⊥ = qZ^m; with bound below the Gaussian heuristic
+// √(m/2πe)·q^{n/m} no solution is expected either. Both are +Inf.
+func SIS(n, m int, q, bound float64, model CostModel) Estimate {
+	est := Estimate{Attack: "sis", Bits: math.Inf(1)}
+	if bound >= q {
+		est.Bits, est.Note = 0, "bound ≥ q: q·e₁ is a solution"
+		return est
+	}
+	if m <= n {
+		est.Note = "m ≤ n: Λ⊥ = qZ^m, no vector shorter than q"
+		return est
+	}
+	lnq, lnBound := math.Log(q), math.Log(bound)
+	if lnBound < 0.5*math.Log(float64(m)/(2*math.Pi*math.E))+float64(n)/float64(m)*lnq {
+		est.Note = "bound below the Gaussian heuristic of Λ⊥"
+		return est
+	}
+	for beta := minBlockSize; beta <= m; beta++ {
+		lnDelta := math.Log(RootHermite(beta))
+		d := optimalDim(n, q, lnDelta, max(n+1, beta), m)
+		if float64(d)*lnDelta+float64(n)/float64(d)*lnq <= lnBound {
+			return Estimate{Attack: "sis", Beta: beta, Dim: d, Bits: model.Cost(beta, d)}
+		}
+	}
+	// Only full-dimension SVP reaches the bound.
+	return Estimate{Attack: "sis", Beta: m, Dim: m, Bits: model.Cost(m, m), Note: "needs BKZ-m"}
+}
+
+// gaussianEntropy is the entropy in bits of a discrete Gaussian of width
+// sigma ≥ 1, log2(σ·√(2πe)).
+func gaussianEntropy(sigma float64) float64 {
+	return math.Log2(sigma * math.Sqrt(2*math.Pi*math.E))
+}
+
+// log2Sum returns log2(2^a + 2^b).
+func log2Sum(a, b float64) float64 {
+	hi, lo := math.Max(a, b), math.Min(a, b)
+	if math.IsInf(hi, 1) {
+		return hi
+	}
+	return hi + math.Log2(1+math.Exp2(lo-hi))
+}
@@ -0,0 +1,91 @@
+// Package security estimates the concrete hardness of the lattice problems the
+// scheme rests on: NTRU key recovery, signature forgery as (I)SIS over [1 | h],
+// and binding of the Ajtai commitment Ac. Every attack is reduced to running
+// BKZ with some block size β and priced with a core-SVP style cost model, in
+// the spirit of the "2016 estimate" used by the NIST lattice submissions.
+// The numbers are estimates for parameter selection, not proofs.
+package security
+
+import (
+	"fmt"
+	"math"
+)
+
+// CostModel prices one BKZ-β reduction of a d-dimensional lattice in bits.
+type CostModel int
+
+const (
+	// CoreSVPClassical is the classical sieving exponent 0.292β (BDGL16).
+	CoreSVPClassical CostModel = iota
+	// CoreSVPQuantum is the quantum sieving exponent 0.265β (Laa15).
+	CoreSVPQuantum
+	// BKZSieve charges 8d SVP calls of a practical sieve: 0.292β + 16.4 + log2(8d).
+	BKZSieve
+)
+
+// ParseCostModel maps "classical", "quantum" and "bkz" to a cost model.
+func ParseCostModel(s string) (CostModel, error) {
+	switch s {
+	case "classical":
+		return CoreSVPClassical, nil
+	case "quantum":
+		return CoreSVPQuantum, nil
+	case "bkz":
+		return BKZSieve, nil
+	}
+	return 0, fmt.Errorf("unknown cost model %q (want classical|quantum|bkz)", s)
+}
+
+func (m CostModel) String() string {
+	switch m {
+	case CoreSVPClassical:
+		return "classical"
+	case CoreSVPQuantum:
+		return "quantum"
+	case BKZSieve:
+		return "bkz"
+	}
+	return fmt.Sprintf("CostModel(%d)", int(m))
+}
+
+// Cost returns log2 of the cost of BKZ-β on a d-dimensional lattice.
+func (m CostModel) Cost(beta, d int) float64 {
+	b := float64(beta)
+	switch m {
+	case CoreSVPQuantum:
+		return 0.265 * b
+	case BKZSieve:
+		return 0.292*b + 16.4 + math.Log2(8*float64(d))
+	}
+	return 0.292 * b
+}
+
+// sieveVectors is log2 of the number of short vectors one sieve call in
+// dimension β returns for free, used to amortise the dual distinguisher.
+func sieveVectors(beta int) float64 {
+	return 0.2075 * float64(beta)
+}
+
+// minBlockSize is where the root-Hermite asymptotics below start to hold.
+const minBlockSize = 40
+
+// RootHermite is the root-Hermite factor δ reached by BKZ-β,
+// δ = ((β/2πe)·(πβ)^{1/β})^{1/(2(β−1))} (Chen's thesis).
+func RootHermite(beta int) float64 {
+	b := float64(beta)
+	return math.Pow(b/(2*math.Pi*math.E)*math.Pow(math.Pi*b, 1/b), 1/(2*(b-1)))
+}
+
+// optimalDim is the sub-lattice dimension d minimising δ^d·q^{n/d}, the
+// length BKZ reaches in a q-ary lattice with n-dimensional q-part, clamped to
+// [lo, hi].
+func optimalDim(n int, q, lnDelta float64, lo, hi int) int {
+	d := int(math.Round(math.Sqrt(float64(n) * math.Log(q) / lnDelta)))
+	if d < lo {
+		d = lo
+	}
+	if d > hi {
+		d = hi
+	}
+	return d
+}
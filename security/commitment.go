@@ -0,0 +1,53 @@
+package security
+
+import (
+	"fmt"
+	"math"
+
+	"vSIS-Signature/commitment"
+)
+
+// maxCommitHeight caps the search in SizeCommitment.
+const maxCommitHeight = 64
+
+// CommitmentSize is the Ajtai commitment shape SizeCommitment picks.
+type CommitmentSize struct {
+	Height   int // rows of Ac
+	RandCols int // length of the randomness block r
+	Binding  Estimate
+	Hiding   float64 // statistical hiding bits, see commitment.HidingBits
+}
+
+// Width is the number of columns of Ac for msgCols non-randomness columns.
+func (s CommitmentSize) Width(msgCols int) int { return msgCols + s.RandCols }
+
+// AcBinding prices binding of an Ac with rows×cols ring entries: two openings
+// of one commitment differ by x with ‖x‖∞ ≤ 2B, i.e. ‖x‖₂ ≤ 2B·√(cols·n).
+func AcBinding(n, rows, cols int, q float64, boundB int64, model CostModel) Estimate {
+	m := cols * n
+	return SIS(rows*n, m, q, 2*float64(boundB)*math.Sqrt(float64(m)), model)
+}
+
+// SizeCommitment returns the smallest height, and for it the shortest
+// randomness block, such that Ac = [I | A'] over msgCols message columns is
+// both binding and statistically hiding at the target level. The identity
+// block may not reach the randomness columns, so the height is at most
+// msgCols.
+func SizeCommitment(n int, q float64, boundB int64, msgCols int, bits float64, model CostModel) (CommitmentSize, error) {
+	if n <= 0 || q <= 1 || boundB <= 0 || msgCols <= 0 || bits <= 0 {
+		return CommitmentSize{}, fmt.Errorf("security: invalid commitment sizing inputs")
+	}
+	for h := 1; h <= min(msgCols, maxCommitHeight); h++ {
+		k := commitment.RandomnessCols(uint64(q), n, h, boundB, bits)
+		e := AcBinding(n, h, msgCols+k, q, boundB, model)
+		if e.Bits >= bits {
+			return CommitmentSize{
+				Height:   h,
+				RandCols: k,
+				Binding:  e,
+				Hiding:   commitment.HidingBits(uint64(q), n, h, k, boundB),
+			}, nil
+		}
+	}
+	return CommitmentSize{}, fmt.Errorf("security: no commitment height ≤ %d reaches %.0f bits", min(msgCols, maxCommitHeight), bits)
+}
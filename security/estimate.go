@@ -0,0 +1,166 @@
+package security
+
+import (
+	"fmt"
+	"math"
+
+	"vSIS-Signature/credential"
+	ntru "vSIS-Signature/ntru"
+)
+
+// KeySigma is the per-coordinate width of (f, g) produced by annulus keygen
+// with quality window α: ‖(f, g)‖ ≈ √q·(α + 1/α)/2 spread over 2N
+// coordinates.
+func KeySigma(par ntru.Params, alpha float64) float64 {
+	q := float64(par.Q.Uint64())
+	return math.Sqrt(q) * 0.5 * (alpha + 1/alpha) / math.Sqrt(float64(2*par.N))
+}
+
+// SignatureBound is the ℓ2 acceptance radius γ = slack·σ·√(2N) enforced by
+// ntru.CheckNormC, with σ = √(R²·α²·q) the sampler width.
+func SignatureBound(par ntru.Params, opts ntru.SamplerOpts) float64 {
+	opts.ApplyDefaults(par)
+	q := float64(par.Q.Uint64())
+	sigma := math.Sqrt(opts.RSquare * opts.Alpha * opts.Alpha * q)
+	return opts.Slack * sigma * math.Sqrt(float64(2*par.N))
+}
+
+// Inputs collects what the estimator needs from the scheme parameters.
+type Inputs struct {
+	Par ntru.Params
+	// KeySigma is the per-coordinate width of the NTRU secret (f, g).
+	KeySigma float64
+	// ForgeBound is the ℓ2 bound a forged (s1, s2) must meet.
+	ForgeBound float64
+	// Beta, if positive, is the Parameters.json bound; forgery is also
+	// priced against it.
+	Beta float64
+	// BoundB, AcRows and AcCols describe the Ajtai commitment; AcRows = 0
+	// skips the binding estimate.
+	BoundB int64
+	AcRows int
+	AcCols int
+}
+
+// NewInputs derives Inputs from the NTRU parameters, the annulus α used at
+// keygen, the signer options and, if non-nil, the credential parameters.
+func NewInputs(par ntru.Params, keygenAlpha float64, opts ntru.SamplerOpts, cp *credential.Params) Inputs {
+	in := Inputs{
+		Par:        par,
+		KeySigma:   KeySigma(par, keygenAlpha),
+		ForgeBound: SignatureBound(par, opts),
+	}
+	if cp != nil {
+		in.BoundB = cp.BoundB
+		in.AcRows = len(cp.Ac)
+		if len(cp.Ac) > 0 {
+			in.AcCols = len(cp.Ac[0])
+		}
+	}
+	return in
+}
+
+// Report is the per-assumption outcome of Analyze. Each field is the
+// cheapest attack found for that assumption.
+type Report struct {
+	Model       CostModel
+	KeyRecovery []Estimate // primal, dual, hybrid on (f, g)
+	Forgery     Estimate   // SIS over [1 | h] at ForgeBound
+	ForgeryBeta *Estimate  // SIS over [1 | h] at Beta, if set
+	Binding     *Estimate  // SIS over Ac, if AcRows > 0
+}
+
+// Analyze runs every estimator for in under model.
+func Analyze(in Inputs, model CostModel) (Report, error) {
+	if in.Par.N <= 0 || in.Par.Q == nil || in.Par.Q.Sign() <= 0 {
+		return Report{}, fmt.Errorf("security: invalid NTRU parameters")
+	}
+	if in.KeySigma <= 0 || in.ForgeBound <= 0 {
+		return Report{}, fmt.Errorf("security: key sigma and forge bound must be positive")
+	}
+	n := in.Par.N
+	q := float64(in.Par.Q.Uint64())
+	rep := Report{
+		Model: model,
+		KeyRecovery: []Estimate{
+			PrimalUSVP(n, n, q, in.KeySigma, model),
+			Dual(n, n, q, in.KeySigma, model),
+			Hybrid(n, n, q, in.KeySigma, model),
+		},
+		Forgery: SIS(n, 2*n, q, in.ForgeBound, model),
+	}
+	if in.Beta > 0 {
+		e := SIS(n, 2*n, q, in.Beta, model)
+		rep.ForgeryBeta = &e
+	}
+	if in.AcRows > 0 {
+		if in.BoundB <= 0 || in.AcCols <= 0 {
+			return Report{}, fmt.Errorf("security: Ac binding needs BoundB > 0 and AcCols > 0")
+		}
+		e := AcBinding(n, in.AcRows, in.AcCols, q, in.BoundB, model)
+		rep.Binding = &e
+	}
+	return rep, nil
+}
+
+// KeyBits is the cheapest key-recovery attack.
+func (r Report) KeyBits() Estimate {
+	best := Estimate{Bits: math.Inf(1)}
+	for _, e := range r.KeyRecovery {
+		if e.Bits < best.Bits {
+			best = e
+		}
+	}
+	return best
+}
+
+// MinBits is the security of the weakest assumption; +Inf if no attack
+// applies.
+func (r Report) MinBits() float64 {
+	bits := math.Min(r.KeyBits().Bits, r.Forgery.Bits)
+	if r.ForgeryBeta != nil {
+		bits = math.Min(bits, r.ForgeryBeta.Bits)
+	}
+	if r.Binding != nil {
+		bits = math.Min(bits, r.Binding.Bits)
+	}
+	return bits
+}
+
+// Lines renders r as one "assumption attack bits β d" line per estimate.
+func (r Report) Lines() []string {
+	var out []string
+	add := func(what string, e Estimate) {
+		line := fmt.Sprintf("%-14s %-12s %8s", what, e.Attack, FormatBits(e.Bits))
+		if e.Beta > 0 {
+			line += fmt.Sprintf("  β=%d d=%d", e.Beta, e.Dim)
+		}
+		if e.Guess > 0 {
+			line += fmt.Sprintf(" r=%d", e.Guess)
+		}
+		if e.Note != "" {
+			line += "  (" + e.Note + ")"
+		}
+		out = append(out, line)
+	}
+	for _, e := range r.KeyRecovery {
+		add("ntru-key", e)
+	}
+	add("forgery", r.Forgery)
+	if r.ForgeryBeta != nil {
+		add("forgery@beta", *r.ForgeryBeta)
+	}
+	if r.Binding != nil {
+		add("ac-binding", *r.Binding)
+	}
+	return out
+}
+
+// FormatBits prints bits with two decimals, or "inf" for statistical
+// security.
+func FormatBits(bits float64) string {
+	if math.IsInf(bits, 1) {
+		return "inf"
+	}
+	return fmt.Sprintf("%.2f", bits)
+}
@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+	"vSIS-Signature/issuance"
+	vsishash "vSIS-Signature/vSIS-HASH"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// loadBlocksKey loads B0…B3 and expands them to the hash key of blocks.
+func loadBlocksKey(t *testing.T, ringQ *ring.Ring, blocks PIOP.CredentialBlocks) []*ring.Poly {
+	t.Helper()
+	B, err := loadDefaultB(ringQ)
+	if err != nil {
+		t.Fatalf("load B: %v", err)
+	}
+	key, err := vsishash.ExpandB(ringQ, B[:4], blocks.HashLayout())
+	if err != nil {
+		t.Fatalf("expand B: %v", err)
+	}
+	return key
+}
+
+func uniformBlocks(n int) PIOP.CredentialBlocks {
+	return PIOP.CredentialBlocks{M1: n, M2: n, RU0: n, RU1: n, R: n}
+}
+
+func TestCredentialPreSignBlocks(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	cases := []PIOP.CredentialBlocks{
+		uniformBlocks(2),
+		uniformBlocks(3),
+		uniformBlocks(4),
+		{M1: 3, M2: 2, RU0: 4, RU1: 2, R: 3},
+	}
+	for _, blocks := range cases {
+		t.Run(fmt.Sprintf("%d-%d-%d-%d-%d", blocks.M1, blocks.M2, blocks.RU0, blocks.RU1, blocks.R), func(t *testing.T) {
+			pub, wit := buildPreSignFixture(t, ringQ, ncols, blocks)
+			opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+			b := PIOP.NewCredentialBuilder(opts)
+			proof, err := b.Build(pub, wit, PIOP.MaskConfig{})
+			if err != nil {
+				t.Fatalf("build proof: %v", err)
+			}
+			// commit + centers per mask index + 1 hash + packing per message
+			// row + one membership constraint per witness row.
+			want := blocks.CommitCols() + blocks.RU0 + blocks.RU1 + 1 + blocks.M1 + blocks.M2 + blocks.Witness()
+			if got := len(proof.FparNTT); got != want {
+				t.Fatalf("Fpar constraint count: got %d want %d", got, want)
+			}
+			ok, err := b.Verify(pub, proof)
+			if err != nil || !ok {
+				t.Fatalf("verify failed: ok=%v err=%v", ok, err)
+			}
+		})
+	}
+}
+
+// TestCredentialPreSignBlocksTamper breaks one relation of a later block
+// index and requires the proof to be rejected.
+func TestCredentialPreSignBlocksTamper(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	blocks := uniformBlocks(3)
+	opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+
+	proveTampered := func(t *testing.T, tamper func(pub *PIOP.PublicInputs, wit *PIOP.WitnessInputs)) {
+		t.Helper()
+		pub, wit := buildPreSignFixture(t, ringQ, ncols, blocks)
+		tamper(&pub, &wit)
+		b := PIOP.NewCredentialBuilder(opts)
+		proof, err := b.Build(pub, wit, PIOP.MaskConfig{})
+		if err != nil {
+			return
+		}
+		if ok, _ := b.Verify(pub, proof); ok {
+			t.Fatalf("tampered witness verified")
+		}
+	}
+	t.Run("packing M2[2]", func(t *testing.T) {
+		proveTampered(t, func(_ *PIOP.PublicInputs, wit *PIOP.WitnessInputs) {
+			wit.M2[2] = tamperEvalDomain(ringQ, wit.M2[2], 0, 1)
+		})
+	})
+	t.Run("center R0[1]", func(t *testing.T) {
+		proveTampered(t, func(_ *PIOP.PublicInputs, wit *PIOP.WitnessInputs) {
+			wit.R0[1] = tamperEvalDomain(ringQ, wit.R0[1], 1, 1)
+		})
+	})
+	t.Run("commit R[2]", func(t *testing.T) {
+		proveTampered(t, func(_ *PIOP.PublicInputs, wit *PIOP.WitnessInputs) {
+			wit.R[2] = tamperEvalDomain(ringQ, wit.R[2], 2, 1)
+		})
+	})
+	t.Run("hash key", func(t *testing.T) {
+		proveTampered(t, func(pub *PIOP.PublicInputs, _ *PIOP.WitnessInputs) {
+			last := len(pub.B) - 1
+			pub.B[last] = tamperEvalDomain(ringQ, pub.B[last], 0, 1)
+		})
+	})
+
+	// Publics with other block lengths are rejected outright.
+	pub, wit := buildPreSignFixture(t, ringQ, ncols, blocks)
+	b := PIOP.NewCredentialBuilder(opts)
+	proof, err := b.Build(pub, wit, PIOP.MaskConfig{})
+	if err != nil {
+		t.Fatalf("build proof: %v", err)
+	}
+	other := pub
+	other.Blocks.R = 2
+	if ok, err := b.Verify(other, proof); ok || err == nil {
+		t.Fatalf("mismatched blocks: ok=%v err=%v", ok, err)
+	}
+	short := pub
+	short.B = pub.B[:4]
+	if ok, err := b.Verify(short, proof); ok || err == nil {
+		t.Fatalf("unexpanded hash key: ok=%v err=%v", ok, err)
+	}
+	if _, err := b.Build(pub, PIOP.WitnessInputs{M1: wit.M1[:2], M2: wit.M2, RU0: wit.RU0, RU1: wit.RU1, R: wit.R, R0: wit.R0, R1: wit.R1, K0: wit.K0, K1: wit.K1}, PIOP.MaskConfig{}); err == nil {
+		t.Fatalf("short M1 block accepted")
+	}
+}
+
+// TestCredentialShowingBlocks proves a post-sign showing over multi-poly
+// blocks (A = identity, so U = T).
+func TestCredentialShowingBlocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds two multi-block showing proofs")
+	}
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	blocks := PIOP.CredentialBlocks{M1: 2, M2: 3, RU0: 2, RU1: 4, R: 2}
+	pre, full := buildPreSignFixture(t, ringQ, ncols, blocks)
+	tPoly := polyFromInt64(ringQ, pre.T)
+	zeros := func(n int) []*ring.Poly {
+		out := make([]*ring.Poly, n)
+		for i := range out {
+			out[i] = ringQ.NewPoly()
+		}
+		return out
+	}
+	wit := PIOP.WitnessInputs{
+		M1: full.M1, M2: full.M2,
+		RU0: zeros(blocks.RU0), RU1: zeros(blocks.RU1), R: zeros(blocks.R),
+		R0: full.R0, R1: full.R1,
+		K0: zeros(blocks.RU0), K1: zeros(blocks.RU1),
+		T: pre.T,
+		U: []*ring.Poly{tPoly.CopyNew()},
+	}
+	// A, the PRF trace and tag/nonce come from the single-block fixture.
+	_, pub, base, opts := buildShowingFixture(t)
+	wit.Extras = base.Extras
+	pub.B = pre.B
+	pub.Blocks = blocks
+
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		t.Fatalf("build showing: %v", err)
+	}
+	set := PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}
+	if want := blocks.Witness() + 2; proof.PRFLayout.StartIdx != want {
+		t.Fatalf("PRF trace starts at row %d, want %d", proof.PRFLayout.StartIdx, want)
+	}
+	ok, err := PIOP.VerifyWithConstraints(proof, set, pub, opts, PIOP.FSModeCredential)
+	if err != nil || !ok {
+		t.Fatalf("verify showing: ok=%v err=%v", ok, err)
+	}
+	single := pub
+	single.Blocks = PIOP.CredentialBlocks{}
+	if ok, _ := PIOP.VerifyWithConstraints(proof, set, single, opts, PIOP.FSModeCredential); ok {
+		t.Fatalf("showing verified under the single-block layout")
+	}
+
+	// A showing of a message block the signer never hashed is rejected.
+	bad := wit
+	bad.M2 = append([]*ring.Poly{}, wit.M2...)
+	bad.M2[2] = makePackedHalf(ringQ, ncols, 7, false)
+	proof, err = PIOP.BuildShowingCombined(pub, bad, opts)
+	if err != nil {
+		return
+	}
+	if ok, _ := PIOP.VerifyWithConstraints(proof, set, pub, opts, PIOP.FSModeCredential); ok {
+		t.Fatalf("showing with a changed M2[2] verified")
+	}
+}
+
+// TestIssuanceBlocksEndToEnd runs the issuance flow with LenX = 3: commit,
+// challenge, pre-sign proof and verification.
+func TestIssuanceBlocksEndToEnd(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	blocks := uniformBlocks(3)
+	pre, wit := buildPreSignFixture(t, ringQ, ncols, blocks)
+	p := &credential.Params{
+		Ac:     pre.Ac,
+		BPath:  "../Parameters/Bmatrix.json",
+		BoundB: pre.BoundB,
+		LenM1:  blocks.M1,
+		LenM2:  blocks.M2,
+		LenRU0: blocks.RU0,
+		LenRU1: blocks.RU1,
+		LenR:   blocks.R,
+		RingQ:  ringQ,
+	}
+	if got := issuance.Blocks(p); got != blocks {
+		t.Fatalf("issuance.Blocks = %+v, want %+v", got, blocks)
+	}
+	in := issuance.Inputs{M1: wit.M1, M2: wit.M2, RU0: wit.RU0, RU1: wit.RU1, R: wit.R}
+	ch := issuance.Challenge{RI0: pre.RI0, RI1: pre.RI1}
+	com, err := issuance.PrepareCommit(p, in)
+	if err != nil {
+		t.Fatalf("prepare commit: %v", err)
+	}
+	st, err := issuance.ApplyChallenge(p, in, ch)
+	if err != nil {
+		t.Fatalf("apply challenge: %v", err)
+	}
+	if len(st.R0) != blocks.RU0 || len(st.K1) != blocks.RU1 || len(st.B) != blocks.HashLayout().KeyLen() {
+		t.Fatalf("state blocks: R0=%d K1=%d B=%d", len(st.R0), len(st.K1), len(st.B))
+	}
+	for i := range pre.T {
+		if st.T[i] != pre.T[i] {
+			t.Fatalf("T[%d]: flow %d, fixture %d", i, st.T[i], pre.T[i])
+		}
+	}
+	opts := PIOP.SimOpts{Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+	proof, err := issuance.ProvePreSign(p, ch, com, in, st, opts)
+	if err != nil {
+		t.Fatalf("prove pre-sign: %v", err)
+	}
+	ok, err := issuance.VerifyPreSign(p, ch, com, st, proof, opts)
+	if err != nil || !ok {
+		t.Fatalf("verify pre-sign: ok=%v err=%v", ok, err)
+	}
+	if _, err := issuance.PrepareCommit(p, issuance.Inputs{M1: in.M1[:1], M2: in.M2, RU0: in.RU0, RU1: in.RU1, R: in.R}); err == nil {
+		t.Fatalf("short M1 block accepted")
+	}
+}
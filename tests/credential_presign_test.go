@@ -179,6 +179,90 @@ func loadDefaultB(r *ring.Ring) ([]*ring.Poly, error) {
 	return out, nil
 }
 
+// buildPreSignFixture returns a consistent pre-sign statement over ncols
+// packed columns with an identity Ac and the block lengths of blocks (the
+// zero value holds one polynomial per block). Every index gets its own
+// values, and RU0+RI0 wraps so that K0 ≠ 0. Multi-limb rings are built limb
+// by limb and composed by CRT.
+func buildPreSignFixture(t *testing.T, ringQ *ring.Ring, ncols int, blocks PIOP.CredentialBlocks) (PIOP.PublicInputs, PIOP.WitnessInputs) {
+	t.Helper()
+	if len(ringQ.Modulus) > 1 {
+		return crtPreSignFixture(t, ringQ, ncols, blocks)
+	}
+	n := blocks
+	if n == (PIOP.CredentialBlocks{}) {
+		n = uniformBlocks(1)
+	}
+	bound := int64(8)
+	gen := func(n int, f func(i int) *ring.Poly) []*ring.Poly {
+		out := make([]*ring.Poly, n)
+		for i := range out {
+			out[i] = f(i)
+		}
+		return out
+	}
+	wit := PIOP.WitnessInputs{
+		M1:  gen(n.M1, func(i int) *ring.Poly { return makePackedHalf(ringQ, ncols, int64(1+i), true) }),
+		M2:  gen(n.M2, func(i int) *ring.Poly { return makePackedHalf(ringQ, ncols, int64(-2-i), false) }),
+		RU0: gen(n.RU0, func(i int) *ring.Poly { return makePolyConst(ringQ, int64(5+i)) }),
+		RU1: gen(n.RU1, func(i int) *ring.Poly { return makePolyConst(ringQ, int64(-4-i)) }),
+		R:   gen(n.R, func(i int) *ring.Poly { return makePolyConst(ringQ, int64(1+i)) }),
+	}
+	ri0 := gen(n.RU0, func(i int) *ring.Poly { return makePolyConst(ringQ, int64(2+i)) })
+	ri1 := gen(n.RU1, func(int) *ring.Poly { return makePolyConst(ringQ, -3) })
+	for i := range ri0 {
+		r0, k0 := centerWrapEvalDomain(ringQ, wit.RU0[i], ri0[i], bound)
+		wit.R0, wit.K0 = append(wit.R0, r0), append(wit.K0, k0)
+	}
+	for i := range ri1 {
+		r1, k1 := centerWrapEvalDomain(ringQ, wit.RU1[i], ri1[i], bound)
+		wit.R1, wit.K1 = append(wit.R1, r1), append(wit.K1, k1)
+	}
+
+	B := loadBlocksKey(t, ringQ, blocks)
+	tCoeff, err := credential.HashMessageVec(ringQ, B, wit.M1, wit.M2, wit.R0, wit.R1)
+	if err != nil {
+		t.Fatalf("hash message: %v", err)
+	}
+
+	// Ac is the identity over M1||M2||RU0||RU1||R, in NTT.
+	var vec []*ring.Poly
+	for _, blk := range [][]*ring.Poly{wit.M1, wit.M2, wit.RU0, wit.RU1, wit.R} {
+		for _, p := range blk {
+			vec = append(vec, nttCopy(ringQ, p))
+		}
+	}
+	Ac := make(commitment.Matrix, len(vec))
+	for i := range Ac {
+		Ac[i] = make([]*ring.Poly, len(vec))
+		for j := range Ac[i] {
+			Ac[i][j] = ringQ.NewPoly()
+			if i == j {
+				Ac[i][j].Coeffs[0][0] = 1
+			}
+			ringQ.NTT(Ac[i][j], Ac[i][j])
+		}
+	}
+	comNTT, err := commitment.Commit(ringQ, Ac, vec)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	nttAll := func(ps []*ring.Poly) []*ring.Poly {
+		return gen(len(ps), func(i int) *ring.Poly { return nttCopy(ringQ, ps[i]) })
+	}
+	pub := PIOP.PublicInputs{
+		Com:    comNTT,
+		RI0:    nttAll(ri0),
+		RI1:    nttAll(ri1),
+		Ac:     Ac,
+		B:      B,
+		T:      tCoeff,
+		BoundB: bound,
+		Blocks: blocks,
+	}
+	return pub, wit
+}
+
 func TestCredentialPreSignHappy(t *testing.T) {
 	ringQ, err := credential.LoadDefaultRing()
 	if err != nil {
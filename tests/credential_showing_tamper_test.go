@@ -10,26 +10,45 @@ import (
 	"github.com/tuneinsight/lattigo/v4/ring"
 )
 
+// showingSpec varies the showing built by buildShowingFixtureSpec; the zero
+// value is the single-block showing of buildShowingFixture.
+type showingSpec struct {
+	blocks PIOP.CredentialBlocks
+	m2     []*ring.Poly // M2 block; nil is one packed half
+	key    []prf.Elem   // PRF key; nil is 1, 2, …
+	nonce  []prf.Elem   // PRF nonce; nil is 11, 12, …
+	ncols  int          // |Ω|; 0 is testNCols
+}
+
 func buildShowingFixture(t *testing.T) (*ring.Ring, PIOP.PublicInputs, PIOP.WitnessInputs, PIOP.SimOpts) {
+	t.Helper()
+	return buildShowingFixtureSpec(t, showingSpec{})
+}
+
+// buildShowingFixtureSpec returns a consistent single-signature showing
+// over the blocks, M2, key and nonce of spec, with its PRF trace and tag.
+func buildShowingFixtureSpec(t *testing.T, spec showingSpec) (*ring.Ring, PIOP.PublicInputs, PIOP.WitnessInputs, PIOP.SimOpts) {
 	t.Helper()
 	ringQ, err := credential.LoadDefaultRing()
 	if err != nil {
 		t.Fatalf("load ring: %v", err)
 	}
-	ncols := testNCols(ringQ)
-	bound := int64(8)
-
-	B, err := loadDefaultB(ringQ)
-	if err != nil {
-		t.Fatalf("load B: %v", err)
+	ncols := spec.ncols
+	if ncols == 0 {
+		ncols = testNCols(ringQ)
 	}
+	bound := int64(8)
 
 	m1 := makePackedHalf(ringQ, ncols, 1, true)
-	m2 := makePackedHalf(ringQ, ncols, 2, false)
+	m2 := spec.m2
+	if m2 == nil {
+		m2 = []*ring.Poly{makePackedHalf(ringQ, ncols, 2, false)}
+	}
 	r0 := makePolyConst(ringQ, 3)
 	r1 := makePolyConst(ringQ, 4)
 
-	tCoeff, err := credential.HashMessage(ringQ, B, m1, m2, r0, r1)
+	B := loadBlocksKey(t, ringQ, spec.blocks)
+	tCoeff, err := credential.HashMessageVec(ringQ, B, []*ring.Poly{m1}, m2, []*ring.Poly{r0}, []*ring.Poly{r1})
 	if err != nil {
 		t.Fatalf("hash message: %v", err)
 	}
@@ -44,14 +63,19 @@ func buildShowingFixture(t *testing.T) (*ring.Ring, PIOP.PublicInputs, PIOP.Witn
 	if err != nil {
 		t.Fatalf("load prf params: %v", err)
 	}
-	key := make([]prf.Elem, params.LenKey)
-	nonce := make([]prf.Elem, params.LenNonce)
+	key, nonce := spec.key, spec.nonce
 	q := ringQ.Modulus[0]
-	for i := range key {
-		key[i] = prf.Elem(uint64(i+1) % q)
+	if key == nil {
+		key = make([]prf.Elem, params.LenKey)
+		for i := range key {
+			key[i] = prf.Elem(uint64(i+1) % q)
+		}
 	}
-	for i := range nonce {
-		nonce[i] = prf.Elem(uint64(i+11) % q)
+	if nonce == nil {
+		nonce = make([]prf.Elem, params.LenNonce)
+		for i := range nonce {
+			nonce[i] = prf.Elem(uint64(i+11) % q)
+		}
 	}
 	x0, err := prf.ConcatKeyNonce(key, nonce, params)
 	if err != nil {
@@ -83,7 +107,7 @@ func buildShowingFixture(t *testing.T) (*ring.Ring, PIOP.PublicInputs, PIOP.Witn
 	base := makePolyConst(ringQ, 0)
 	wit := PIOP.WitnessInputs{
 		M1:  []*ring.Poly{m1},
-		M2:  []*ring.Poly{m2},
+		M2:  m2,
 		RU0: []*ring.Poly{base},
 		RU1: []*ring.Poly{base},
 		R:   []*ring.Poly{base},
@@ -103,6 +127,7 @@ func buildShowingFixture(t *testing.T) (*ring.Ring, PIOP.PublicInputs, PIOP.Witn
 		Tag:    tagPublic,
 		Nonce:  noncePublic,
 		BoundB: bound,
+		Blocks: spec.blocks,
 	}
 	opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
 	return ringQ, pub, wit, opts
@@ -0,0 +1,216 @@
+//go:build testonly
+
+package tests
+
+import (
+	"crypto/rand"
+	"fmt"
+	mrand "math/rand/v2"
+	"testing"
+
+	decs "vSIS-Signature/DECS"
+	lvcs "vSIS-Signature/LVCS"
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// rewindBuild builds a proof under oracle with the prover's coins replayed
+// from seed: the prover reads a fresh ChaCha8 stream keyed by seed, so every
+// call commits to the same rows, masks and salt and only the programmed FS
+// rounds differ. This is the rewinding step of the extractor.
+func rewindBuild(t *testing.T, seed [32]byte, opts PIOP.SimOpts, oracle PIOP.XOF, pub PIOP.PublicInputs, wit PIOP.WitnessInputs) (PIOP.StatementBuilder, *PIOP.Proof) {
+	t.Helper()
+	opts.SetFSOracle(oracle)
+	opts.SetCoins(mrand.NewChaCha8(seed))
+	b := PIOP.NewCredentialBuilder(opts)
+	proof, err := b.Build(pub, wit, PIOP.MaskConfig{})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	return b, proof
+}
+
+// lagrangeAt evaluates at x the polynomial through the points (xs[i], ys[i]).
+func lagrangeAt(q uint64, xs, ys []uint64, x uint64) uint64 {
+	var acc uint64
+	for i := range xs {
+		num, den := uint64(1), uint64(1)
+		for j := range xs {
+			if j != i {
+				num = lvcs.MulMod64(num, x+q-xs[j], q)
+				den = lvcs.MulMod64(den, xs[i]+q-xs[j], q)
+			}
+		}
+		term := lvcs.MulMod64(num, ring.ModExp(den, q-2, q), q)
+		acc = lvcs.AddMod64(acc, lvcs.MulMod64(ys[i], term, q), q)
+	}
+	return acc
+}
+
+// TestKnowledgeExtractor rewinds the prover on one LVCS commitment,
+// reprogramming the K-point and tail rounds (FS rounds 2 and 3), until the
+// accepting transcripts open every committed row at ncols+ℓ distinct points.
+// It then interpolates the rows, reads the witness off Ω and checks that the
+// extracted witness satisfies the credential constraints.
+func TestKnowledgeExtractor(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	q := ringQ.Modulus[0]
+	ncols := testNCols(ringQ)
+	pub, wit := buildPreSignFixture(t, ringQ, ncols, PIOP.CredentialBlocks{})
+	const ell = 4
+	opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: ell}
+
+	// Evaluation point of every NTT slot: slot i holds P(pts[i]).
+	px := ringQ.NewPoly()
+	px.Coeffs[0][1] = 1
+	pts := ringQ.NewPoly()
+	ringQ.NTT(px, pts)
+
+	var seed [32]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	need := ncols + ell // row polynomials have degree < ncols+ℓ
+	opened := map[int][]uint64{}
+	var order []int
+	var root [16]byte
+	var lin []*PIOP.Proof
+	for rewind := 0; len(order) < need+ell; rewind++ {
+		if rewind == 4*need {
+			t.Fatalf("only %d distinct openings after %d rewinds", len(order), rewind)
+		}
+		ch, err := PIOP.SampleFSChallenges(opts.Kappa, rand.Reader)
+		if err != nil {
+			t.Fatalf("SampleFSChallenges: %v", err)
+		}
+		oracle := PIOP.NewProgrammedXOF(PIOP.NewShake256XOF(64))
+		oracle.ProgramRound(2, ch[2])
+		oracle.ProgramRound(3, ch[3])
+		b, proof := rewindBuild(t, seed, opts, oracle, pub, wit)
+		if rewind == 0 {
+			root = proof.Root
+		} else if proof.Root != root {
+			t.Fatalf("rewind %d committed to a different root", rewind)
+		}
+		if ok, err := b.Verify(pub, proof); err != nil || !ok {
+			t.Fatalf("rewind %d: transcript rejected: ok=%v err=%v", rewind, ok, err)
+		}
+		lin = append(lin, proof)
+		op := proof.RowOpening
+		if err := decs.EnsureMerkleDecoded(op); err != nil {
+			t.Fatalf("decode opening: %v", err)
+		}
+		for pos := 0; pos < op.EntryCount(); pos++ {
+			idx := op.IndexAt(pos)
+			if _, seen := opened[idx]; seen {
+				continue
+			}
+			vals := make([]uint64, op.R)
+			for j := range vals {
+				vals[j] = decs.GetOpeningPval(op, pos, j)
+			}
+			opened[idx] = vals
+			order = append(order, idx)
+		}
+	}
+
+	// Interpolate on the first ncols+ℓ points; the extra ℓ openings check
+	// that the committed rows really have the claimed degree.
+	xs := make([]uint64, need)
+	for i, idx := range order[:need] {
+		xs[i] = pts.Coeffs[0][idx]
+	}
+	rowCount := len(opened[order[0]])
+	heads := make([][]uint64, rowCount)
+	for j := range heads {
+		ys := make([]uint64, need)
+		for i, idx := range order[:need] {
+			ys[i] = opened[idx][j]
+		}
+		for _, idx := range order[need:] {
+			if got := lagrangeAt(q, xs, ys, pts.Coeffs[0][idx]); got != opened[idx][j] {
+				t.Fatalf("row %d: opening at slot %d off the degree-%d interpolant", j, idx, need-1)
+			}
+		}
+		heads[j] = make([]uint64, ncols)
+		for i := range heads[j] {
+			heads[j][i] = lagrangeAt(q, xs, ys, pts.Coeffs[0][i])
+		}
+	}
+
+	// The LVCS linear-combination openings of every transcript agree with
+	// the extracted rows: VTargets[k] = Σ_j C[k][j]·row_j on Ω.
+	for r, proof := range lin {
+		vt := proof.VTargetsMatrix()
+		for k, c := range proof.CoeffMatrix {
+			for i := 0; i < ncols; i++ {
+				var sum uint64
+				for j := range heads {
+					sum = lvcs.MulAddMod64(sum, c[j], heads[j][i], q)
+				}
+				if sum != vt[k][i] {
+					t.Fatalf("rewind %d: VTargets[%d][%d] disagrees with the extracted rows", r, k, i)
+				}
+			}
+		}
+	}
+
+	// Rows 0..8 are M1, M2, RU0, RU1, R, R0, R1, K0, K1 on Ω.
+	row := func(j int) []*ring.Poly {
+		p := ringQ.NewPoly()
+		copy(p.Coeffs[0], heads[j])
+		ringQ.InvNTT(p, p)
+		return []*ring.Poly{p}
+	}
+	ext := PIOP.WitnessInputs{
+		M1: row(0), M2: row(1), RU0: row(2), RU1: row(3), R: row(4),
+		R0: row(5), R1: row(6), K0: row(7), K1: row(8),
+	}
+	want := []*ring.Poly{wit.M1[0], wit.M2[0], wit.RU0[0], wit.RU1[0], wit.R[0], wit.R0[0], wit.R1[0], wit.K0[0], wit.K1[0]}
+	tmp := ringQ.NewPoly()
+	for j, w := range want {
+		ringQ.NTT(w, tmp)
+		for i := 0; i < ncols; i++ {
+			if heads[j][i] != tmp.Coeffs[0][i] {
+				t.Fatalf("extracted row %d differs from the witness at ω_%d", j, i)
+			}
+		}
+	}
+	if err := checkCredentialOnOmega(ringQ, pub, ext, ncols); err != nil {
+		t.Fatalf("extracted witness: %v", err)
+	}
+	// The check has teeth: a single wrong message value breaks a relation.
+	bad := ext
+	bad.M1 = row(0)
+	ringQ.NTT(bad.M1[0], tmp)
+	tmp.Coeffs[0][0] = (tmp.Coeffs[0][0] + 1) % q
+	ringQ.InvNTT(tmp, bad.M1[0])
+	if err := checkCredentialOnOmega(ringQ, pub, bad, ncols); err == nil {
+		t.Fatalf("constraint check accepted a perturbed witness")
+	}
+	t.Logf("extracted %d rows from %d openings", rowCount, len(order))
+}
+
+// checkCredentialOnOmega rebuilds the pre-sign constraint set for wit and
+// checks that every residual (kept in the evaluation domain) vanishes on Ω.
+func checkCredentialOnOmega(ringQ *ring.Ring, pub PIOP.PublicInputs, wit PIOP.WitnessInputs, ncols int) error {
+	cs, err := PIOP.BuildCredentialConstraintSetPre(ringQ, pub.BoundB, pub, wit, ncols)
+	if err != nil {
+		return err
+	}
+	for name, set := range map[string][]*ring.Poly{"FparInt": cs.FparInt, "FparNorm": cs.FparNorm, "FaggInt": cs.FaggInt, "FaggNorm": cs.FaggNorm} {
+		for k, f := range set {
+			for i := 0; i < ncols; i++ {
+				if f.Coeffs[0][i] != 0 {
+					return fmt.Errorf("%s[%d] nonzero at ω_%d", name, k, i)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+	"vSIS-Signature/issuance"
+	"vSIS-Signature/ntru/keys"
+)
+
+// TestIssuanceNonInteractive sends (Com, T, π_t) in one message and has the
+// issuer re-derive the challenge from Com before verifying π_t.
+func TestIssuanceNonInteractive(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	blocks := uniformBlocks(2)
+	pre, wit := buildPreSignFixture(t, ringQ, ncols, blocks)
+	p := &credential.Params{
+		Ac:     pre.Ac,
+		BPath:  "../Parameters/Bmatrix.json",
+		BoundB: pre.BoundB,
+		LenM1:  blocks.M1,
+		LenM2:  blocks.M2,
+		LenRU0: blocks.RU0,
+		LenRU1: blocks.RU1,
+		LenR:   blocks.R,
+		RingQ:  ringQ,
+	}
+	pk := &keys.PublicKey{HCoeffs: []int64{17, -3, 5, 0, 9}}
+	label := []byte("issuer-A/2026-10/holder-7")
+	in := issuance.Inputs{M1: wit.M1, M2: wit.M2, RU0: wit.RU0, RU1: wit.RU1, R: wit.R}
+	opts := PIOP.SimOpts{Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+
+	req, ch, st, err := issuance.RequestNonInteractive(p, pk, label, in, opts)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	want, err := issuance.DeriveChallenge(p, req.Com, pk, label)
+	if err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	if !ch.RI0[1].Equals(want.RI0[1]) || !ch.RI1[0].Equals(want.RI1[0]) {
+		t.Fatalf("holder challenge differs from the derived one")
+	}
+	if len(st.K0) != blocks.RU0 {
+		t.Fatalf("state has %d K0 polys", len(st.K0))
+	}
+	got, ok, err := issuance.VerifyRequest(p, pk, label, req, opts)
+	if err != nil || !ok {
+		t.Fatalf("issuer rejected an honest request: ok=%v err=%v", ok, err)
+	}
+	if !got.RI1[1].Equals(ch.RI1[1]) {
+		t.Fatalf("issuer derived another challenge")
+	}
+
+	// The proof is bound to the challenge: another label or issuer key
+	// derives other RI*, and a changed T no longer matches π_t.
+	if _, ok, _ := issuance.VerifyRequest(p, pk, []byte("issuer-A/2026-10/holder-8"), req, opts); ok {
+		t.Fatalf("request verified under another label")
+	}
+	otherPK := &keys.PublicKey{HCoeffs: []int64{17, -3, 5, 0, 8}}
+	if _, ok, _ := issuance.VerifyRequest(p, otherPK, label, req, opts); ok {
+		t.Fatalf("request verified for another issuer key")
+	}
+	bad := *req
+	bad.T = append([]int64{}, req.T...)
+	bad.T[0]++
+	if _, ok, _ := issuance.VerifyRequest(p, pk, label, &bad, opts); ok {
+		t.Fatalf("request verified with a changed T")
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	ntru "vSIS-Signature/ntru"
+	"vSIS-Signature/security"
+)
+
+// TestLatticeEstimatesFalcon512 anchors the core-SVP models on Falcon-512
+// (n = 512, q = 12289, σ = 1.17·√(q/2n)), whose key recovery the Falcon
+// specification puts at roughly 2^{130}–2^{140} classically.
+func TestLatticeEstimatesFalcon512(t *testing.T) {
+	n, q := 512, 12289.0
+	sigma := 1.17 * math.Sqrt(q/float64(2*n))
+	primal := security.PrimalUSVP(n, n, q, sigma, security.CoreSVPClassical)
+	if primal.Bits < 120 || primal.Bits > 160 {
+		t.Fatalf("primal uSVP: %.2f bits (β=%d), want 120..160", primal.Bits, primal.Beta)
+	}
+	dual := security.Dual(n, n, q, sigma, security.CoreSVPClassical)
+	if math.Abs(dual.Bits-primal.Bits) > 20 {
+		t.Fatalf("dual %.2f bits far from primal %.2f", dual.Bits, primal.Bits)
+	}
+	if hybrid := security.Hybrid(n, n, q, sigma, security.CoreSVPClassical); hybrid.Bits > primal.Bits {
+		t.Fatalf("hybrid %.2f bits exceeds its r=0 case %.2f", hybrid.Bits, primal.Bits)
+	}
+	quantum := security.PrimalUSVP(n, n, q, sigma, security.CoreSVPQuantum)
+	if quantum.Bits >= primal.Bits {
+		t.Fatalf("quantum %.2f bits not below classical %.2f", quantum.Bits, primal.Bits)
+	}
+	// Falcon-1024 must be markedly harder.
+	if hi := security.PrimalUSVP(2*n, 2*n, q, 1.17*math.Sqrt(q/float64(4*n)), security.CoreSVPClassical); hi.Bits < primal.Bits+100 {
+		t.Fatalf("n=1024 primal %.2f bits not well above n=512 %.2f", hi.Bits, primal.Bits)
+	}
+}
+
+// TestSISEstimateEdges covers the cases SIS prices without running BKZ and
+// checks that a looser bound never costs more.
+func TestSISEstimateEdges(t *testing.T) {
+	n, q := 1024, 1038337.0
+	if e := security.SIS(n, 2*n, q, q, security.CoreSVPClassical); e.Bits != 0 {
+		t.Fatalf("bound ≥ q: %.2f bits, want 0", e.Bits)
+	}
+	if e := security.SIS(5*n, 5*n, q, 1000, security.CoreSVPClassical); !e.Statistical() {
+		t.Fatalf("square SIS: %.2f bits, want +Inf", e.Bits)
+	}
+	if e := security.SIS(n, 2*n, q, 100, security.CoreSVPClassical); !e.Statistical() {
+		t.Fatalf("bound below Gaussian heuristic: %.2f bits, want +Inf", e.Bits)
+	}
+	prev := math.Inf(1)
+	for _, bound := range []float64{2e4, 5e4, 1e5, 3e5} {
+		e := security.SIS(n, 2*n, q, bound, security.CoreSVPClassical)
+		if e.Bits > prev {
+			t.Fatalf("bound %.0f: %.2f bits above tighter bound's %.2f", bound, e.Bits, prev)
+		}
+		prev = e.Bits
+	}
+}
+
+// TestAnalyzeBaseline runs the full report on the repository parameters.
+func TestAnalyzeBaseline(t *testing.T) {
+	par, err := ntru.NewParams(1024, big.NewInt(1038337))
+	if err != nil {
+		t.Fatalf("params: %v", err)
+	}
+	in := security.NewInputs(par, 1.20, ntru.SamplerOpts{}, nil)
+	in.BoundB, in.AcRows, in.AcCols = 8, 1, 5
+	rep, err := security.Analyze(in, security.CoreSVPClassical)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if len(rep.KeyRecovery) != 3 || rep.Binding == nil {
+		t.Fatalf("incomplete report: %+v", rep)
+	}
+	if rep.MinBits() < 128 {
+		t.Fatalf("baseline lattice security %.2f bits < 128", rep.MinBits())
+	}
+	if rep.MinBits() != math.Min(rep.KeyBits().Bits, math.Min(rep.Forgery.Bits, rep.Binding.Bits)) {
+		t.Fatalf("MinBits %.2f is not the weakest assumption", rep.MinBits())
+	}
+	// A looser Parameters.json bound is the weakest assumption when set.
+	in.Beta = 4 * in.ForgeBound
+	rep, err = security.Analyze(in, security.CoreSVPClassical)
+	if err != nil {
+		t.Fatalf("analyze with beta: %v", err)
+	}
+	if rep.ForgeryBeta == nil || rep.ForgeryBeta.Bits >= rep.Forgery.Bits {
+		t.Fatalf("forgery@beta %+v not below forgery %.2f", rep.ForgeryBeta, rep.Forgery.Bits)
+	}
+	if rep.MinBits() > rep.ForgeryBeta.Bits {
+		t.Fatalf("MinBits %.2f ignores forgery@beta %.2f", rep.MinBits(), rep.ForgeryBeta.Bits)
+	}
+	in.Beta = 0
+	in.AcRows = 0
+	if _, err := security.Analyze(in, security.CoreSVPClassical); err != nil {
+		t.Fatalf("analyze without Ac: %v", err)
+	}
+	in.KeySigma = 0
+	if _, err := security.Analyze(in, security.CoreSVPClassical); err == nil {
+		t.Fatalf("expected error for zero key sigma")
+	}
+}
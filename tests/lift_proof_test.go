@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// liftTestRings returns the default ring and a 61-bit NTT prime ring of the
+// same dimension to prove its statements over.
+func liftTestRings(t *testing.T) (*ring.Ring, *ring.Ring) {
+	t.Helper()
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ringP, err := ring.NewRing(ringQ.N, ring.GenerateNTTPrimes(61, 2*ringQ.N, 1))
+	if err != nil {
+		t.Fatalf("ring.NewRing: %v", err)
+	}
+	return ringQ, ringP
+}
+
+func TestLiftedCredentialProof(t *testing.T) {
+	ringQ, ringP := liftTestRings(t)
+	ncols := testNCols(ringQ)
+	for _, tc := range []struct {
+		name   string
+		blocks PIOP.CredentialBlocks
+	}{
+		{"single", PIOP.CredentialBlocks{}},
+		{"blocks", PIOP.CredentialBlocks{M1: 2, M2: 3, RU0: 2, RU1: 3, R: 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pub, wit := buildPreSignFixture(t, ringQ, ncols, tc.blocks)
+			opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1, Ring: ringP}
+			b, err := PIOP.NewLiftedCredentialBuilder(ringQ, opts)
+			if err != nil {
+				t.Fatalf("NewLiftedCredentialBuilder: %v", err)
+			}
+			proof, err := b.Build(pub, wit, PIOP.MaskConfig{})
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			ok, err := b.Verify(pub, proof)
+			if err != nil || !ok {
+				t.Fatalf("verify lifted proof: ok=%v err=%v", ok, err)
+			}
+
+			// The lifted transcript binds the original statement.
+			bad := pub
+			bad.Com = append([]*ring.Poly(nil), pub.Com...)
+			bad.Com[0] = pub.Com[0].CopyNew()
+			bad.Com[0].Coeffs[0][0] = (bad.Com[0].Coeffs[0][0] + 1) % ringQ.Modulus[0]
+			if ok, _ := b.Verify(bad, proof); ok {
+				t.Fatalf("lifted proof verified against a different commitment")
+			}
+		})
+	}
+}
+
+func TestLiftedCredentialRejectsFalseStatement(t *testing.T) {
+	ringQ, ringP := liftTestRings(t)
+	ncols := testNCols(ringQ)
+	pub, wit := buildPreSignFixture(t, ringQ, ncols, PIOP.CredentialBlocks{})
+	// A witness that violates the hash relation mod q has no integer quotient.
+	wit.R0 = []*ring.Poly{wit.R0[0].CopyNew()}
+	wit.R0[0].Coeffs[0][0] = (wit.R0[0].Coeffs[0][0] + 1) % ringQ.Modulus[0]
+	if _, err := PIOP.LiftCredentialWitness(ringQ, ringP, pub, wit, ncols); err == nil {
+		t.Fatalf("expected lifting to reject a false statement")
+	}
+}
+
+func TestLiftLayoutRequiresLargePrime(t *testing.T) {
+	ringQ, ringP := liftTestRings(t)
+	q := ringQ.Modulus[0]
+	if _, err := PIOP.NewLiftLayout(q, ringP.Modulus[0], 8, PIOP.CredentialBlocks{}, 5); err != nil {
+		t.Fatalf("61-bit prime rejected: %v", err)
+	}
+	if _, err := PIOP.NewLiftLayout(q, ring.GenerateNTTPrimes(22, 2*ringQ.N, 1)[0], 8, PIOP.CredentialBlocks{}, 5); err == nil {
+		t.Fatalf("expected a 22-bit prime to be too small for q=%d", q)
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+)
+
+// TestEstimateProofSizeSweepGrid checks the analytic size model against
+// MeasureProofSize over the default credential_sweep grid (pre-sign
+// statement). Parts that do not depend on the FS-sampled tail must match
+// exactly. The Merkle frontier of RowOpening (and the tail varints of
+// MOpening) follow the tail, so a single proof may sit several of the
+// model's standard deviations away, mostly below. Their distribution only
+// depends on (ncols, ℓ); the eight proofs over ℓ′, ρ and η sample it
+// independently, and their mean deviation must stay within 5% of the
+// estimate and within five standard errors of the model.
+func TestEstimateProofSizeSweepGrid(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	// Randomised parts: the frontier and tail varints, and BarSets, which
+	// holds as few as 2θ residues and so occasionally packs at 16 bits.
+	random := map[string]bool{"RowOpening": true, "MOpening": true, "BarSets": true}
+	estSum, gotSum := 0, 0
+	for _, ncols := range []int{4, 6, 8} {
+		pub, wit := buildPreSignFixture(t, ringQ, ncols, PIOP.CredentialBlocks{})
+		for _, ell := range []int{1, 2, 4} {
+			var dev, est, sd float64
+			n := 0
+			for _, ellp := range []int{1, 2} {
+				for _, rho := range []int{1, 2} {
+					for _, eta := range []int{7, 17} {
+						opts := PIOP.SimOpts{Credential: true, Theta: 2, NCols: ncols, Ell: ell, EllPrime: ellp, Rho: rho, Eta: eta}
+						proof, err := PIOP.NewCredentialBuilder(opts).Build(pub, wit, PIOP.MaskConfig{})
+						if err != nil {
+							t.Fatalf("build ncols=%d ell=%d ellp=%d rho=%d eta=%d: %v", ncols, ell, ellp, rho, eta, err)
+						}
+						got := PIOP.MeasureProofSize(proof)
+						rep, err := PIOP.EstimateProofSize(opts, proof.MaskRowOffset, ncols)
+						if err != nil {
+							t.Fatalf("estimate: %v", err)
+						}
+						for name, size := range got.Parts {
+							if !random[name] && rep.Parts[name] != size {
+								t.Errorf("ncols=%d ell=%d ellp=%d rho=%d eta=%d: %s estimate %d vs measured %d", ncols, ell, ellp, rho, eta, name, rep.Parts[name], size)
+							}
+						}
+						dev += float64(got.Total - rep.Total)
+						est += float64(rep.Total)
+						sd += rep.StdDev
+						n++
+						estSum += rep.Total
+						gotSum += got.Total
+					}
+				}
+			}
+			dev, est, sd = dev/float64(n), est/float64(n), sd/float64(n)
+			if math.Abs(dev) > 0.05*est {
+				t.Errorf("ncols=%d ell=%d: mean deviation %.1f bytes is %.1f%% of the %.0f-byte estimate", ncols, ell, dev, 100*math.Abs(dev)/est, est)
+			}
+			if stderr := sd / math.Sqrt(float64(n)); math.Abs(dev) > 5*stderr {
+				t.Errorf("ncols=%d ell=%d: mean deviation %.1f bytes, model standard error %.1f", ncols, ell, dev, stderr)
+			}
+		}
+	}
+	if rel := math.Abs(float64(estSum-gotSum)) / float64(gotSum); rel > 0.015 {
+		t.Fatalf("grid total: estimate %d vs measured %d (%.2f%%)", estSum, gotSum, 100*rel)
+	}
+}
+
+func TestEstimateProofSizeRejects(t *testing.T) {
+	opts := PIOP.SimOpts{Credential: true, Theta: 1, NCols: 8, Ell: 2, EllPrime: 1, Rho: 1, Eta: 7}
+	if _, err := PIOP.EstimateProofSize(opts, 20, 8); err == nil {
+		t.Fatal("credential proof with theta=1 accepted")
+	}
+	// RunOnce proofs exist for θ=1 and carry the opened evaluations.
+	opts.Credential = false
+	est, err := PIOP.EstimateProofSize(opts, 20, 8)
+	if err != nil {
+		t.Fatalf("theta=1: %v", err)
+	}
+	if est.Parts["PvalsEvalBits"] != opts.Ell*(20+opts.Rho)*8 || est.Parts["Chi"] != 0 {
+		t.Fatalf("theta=1 layout: %v", est.Parts)
+	}
+	if _, err := PIOP.EstimateProofSize(opts, 20, 0); err == nil {
+		t.Fatal("zero witness columns accepted")
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+	"vSIS-Signature/issuance"
+	"vSIS-Signature/pubparams"
+)
+
+// TestIssuanceBundleDigestBound issues under a public-parameter bundle and
+// checks the pre-sign proof only verifies against that bundle's digest and
+// the SimOpts its ParamsID names.
+func TestIssuanceBundleDigestBound(t *testing.T) {
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	blocks := uniformBlocks(1)
+	pre, wit := buildPreSignFixture(t, ringQ, ncols, blocks)
+
+	m, err := pubparams.New(ringQ, 1, blocks.CommitCols(), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := pubparams.Save(path, m); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := pubparams.Load(path, ringQ)
+	if err != nil {
+		t.Fatalf("load bundle: %v", err)
+	}
+	p := &credential.Params{
+		Ac:         bundle.Ac,
+		Commitment: bundle.AcParams,
+		Bundle:     bundle,
+		BoundB:     pre.BoundB,
+		LenM1:      blocks.M1,
+		LenM2:      blocks.M2,
+		LenRU0:     blocks.RU0,
+		LenRU1:     blocks.RU1,
+		LenR:       blocks.R,
+		RingQ:      ringQ,
+	}
+	in := issuance.Inputs{M1: wit.M1, M2: wit.M2, RU0: wit.RU0, RU1: wit.RU1, R: wit.R}
+	ch := issuance.Challenge{RI0: pre.RI0, RI1: pre.RI1}
+	com, err := issuance.PrepareCommit(p, in)
+	if err != nil {
+		t.Fatalf("prepare commit: %v", err)
+	}
+	st, err := issuance.ApplyChallenge(p, in, ch)
+	if err != nil {
+		t.Fatalf("apply challenge: %v", err)
+	}
+	if !st.B[1].Equals(bundle.B[1]) {
+		t.Fatalf("hash key not taken from the bundle")
+	}
+	opts := PIOP.SimOpts{Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+	proof, err := issuance.ProvePreSign(p, ch, com, in, st, opts)
+	if err != nil {
+		t.Fatalf("prove pre-sign: %v", err)
+	}
+	ok, err := issuance.VerifyPreSign(p, ch, com, st, proof, opts)
+	if err != nil || !ok {
+		t.Fatalf("verify pre-sign: ok=%v err=%v", ok, err)
+	}
+
+	// The generic entry point recognises a credential proof by its labels
+	// digest even when the caller leaves opts.Credential unset.
+	pub := PIOP.PublicInputs{
+		Com: com, RI0: ch.RI0, RI1: ch.RI1, Ac: p.Ac, B: st.B, T: st.T,
+		BoundB: p.BoundB, Blocks: issuance.Blocks(p), ParamsDigest: p.ParamsDigest(),
+	}
+	if err := PIOP.Verify(proof, PIOP.ConstraintSet{}, pub, opts); err != nil {
+		t.Fatalf("Verify with default credential flag: %v", err)
+	}
+
+	// Same matrices, other digest: the FS transcript no longer matches.
+	other := *bundle
+	other.Digest[0] ^= 1
+	q := *p
+	q.Bundle = &other
+	if ok, err := issuance.VerifyPreSign(&q, ch, com, st, proof, opts); ok || !errors.Is(err, PIOP.ErrParamsID) {
+		t.Fatalf("proof under another bundle digest: ok=%v err=%v, want ErrParamsID", ok, err)
+	}
+
+	// The proof names the SimOpts it was built under …
+	wide := opts
+	wide.Eta++
+	if ok, err := issuance.VerifyPreSign(p, ch, com, st, proof, wide); ok || !errors.Is(err, PIOP.ErrParamsID) {
+		t.Fatalf("proof under other SimOpts: ok=%v err=%v, want ErrParamsID", ok, err)
+	}
+	// … and the ID is part of the round-0 transcript, so it cannot be
+	// swapped for the verifier's.
+	swapped := proof.Snapshot().Restore()
+	swapped.ParamsID = PIOP.ComputeParamsID(ringQ, PIOP.PublicInputs{}, nil, opts, "")
+	if ok, _ := issuance.VerifyPreSign(p, ch, com, st, swapped, opts); ok {
+		t.Fatalf("proof verified with a replaced ParamsID")
+	}
+}
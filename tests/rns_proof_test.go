@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// rnsTestRing returns a two-limb ring of the default dimension with 30-bit
+// NTT primes, so residues no longer fit the 20-bit packing.
+func rnsTestRing(t *testing.T) *ring.Ring {
+	t.Helper()
+	base, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	primes := ring.GenerateNTTPrimes(30, 2*base.N, 2)
+	ringQ, err := ring.NewRing(base.N, primes)
+	if err != nil {
+		t.Fatalf("ring.NewRing: %v", err)
+	}
+	return ringQ
+}
+
+// stackLimbs assembles per-limb polynomials into polynomials of ringQ.
+func stackLimbs(ringQ *ring.Ring, limbs [][]*ring.Poly) []*ring.Poly {
+	out := make([]*ring.Poly, len(limbs[0]))
+	for j := range out {
+		out[j] = ringQ.NewPoly()
+		for i := range limbs {
+			copy(out[j].Coeffs[i], limbs[i][j].Coeffs[0])
+		}
+	}
+	return out
+}
+
+// crtInt64 lifts per-limb residues of T to the unique value mod Q.
+func crtInt64(moduli []uint64, residues [][]int64) []int64 {
+	Q := big.NewInt(1)
+	for _, q := range moduli {
+		Q.Mul(Q, new(big.Int).SetUint64(q))
+	}
+	out := make([]int64, len(residues[0]))
+	for k := range out {
+		acc := new(big.Int)
+		for i, q := range moduli {
+			qi := new(big.Int).SetUint64(q)
+			Mi := new(big.Int).Div(Q, qi)
+			inv := new(big.Int).ModInverse(new(big.Int).Mod(Mi, qi), qi)
+			r := new(big.Int).Mod(big.NewInt(residues[i][k]), qi)
+			acc.Add(acc, r.Mul(r, Mi).Mul(r, inv))
+		}
+		out[k] = acc.Mod(acc, Q).Int64()
+	}
+	return out
+}
+
+// crtPreSignFixture is the multi-limb branch of buildPreSignFixture: it
+// builds the fixture limb by limb and composes it by CRT.
+func crtPreSignFixture(t *testing.T, ringQ *ring.Ring, ncols int, blocks PIOP.CredentialBlocks) (PIOP.PublicInputs, PIOP.WitnessInputs) {
+	t.Helper()
+	limbRings, err := PIOP.SplitRNS(ringQ)
+	if err != nil {
+		t.Fatalf("SplitRNS: %v", err)
+	}
+	pubs := make([]PIOP.PublicInputs, len(limbRings))
+	wits := make([]PIOP.WitnessInputs, len(limbRings))
+	for i, r := range limbRings {
+		pubs[i], wits[i] = buildPreSignFixture(t, r, ncols, blocks)
+	}
+	field := func(get func(i int) []*ring.Poly) []*ring.Poly {
+		limbs := make([][]*ring.Poly, len(limbRings))
+		for i := range limbs {
+			limbs[i] = get(i)
+		}
+		return stackLimbs(ringQ, limbs)
+	}
+	pub := pubs[0]
+	pub.Com = field(func(i int) []*ring.Poly { return pubs[i].Com })
+	pub.RI0 = field(func(i int) []*ring.Poly { return pubs[i].RI0 })
+	pub.RI1 = field(func(i int) []*ring.Poly { return pubs[i].RI1 })
+	pub.B = field(func(i int) []*ring.Poly { return pubs[i].B })
+	pub.Ac = make([][]*ring.Poly, len(pubs[0].Ac))
+	for r := range pub.Ac {
+		pub.Ac[r] = field(func(i int) []*ring.Poly { return pubs[i].Ac[r] })
+	}
+	ts := make([][]int64, len(limbRings))
+	for i := range ts {
+		ts[i] = pubs[i].T
+	}
+	pub.T = crtInt64(ringQ.Modulus, ts)
+
+	wit := PIOP.WitnessInputs{
+		M1:  field(func(i int) []*ring.Poly { return wits[i].M1 }),
+		M2:  field(func(i int) []*ring.Poly { return wits[i].M2 }),
+		RU0: field(func(i int) []*ring.Poly { return wits[i].RU0 }),
+		RU1: field(func(i int) []*ring.Poly { return wits[i].RU1 }),
+		R:   field(func(i int) []*ring.Poly { return wits[i].R }),
+		R0:  field(func(i int) []*ring.Poly { return wits[i].R0 }),
+		R1:  field(func(i int) []*ring.Poly { return wits[i].R1 }),
+		K0:  field(func(i int) []*ring.Poly { return wits[i].K0 }),
+		K1:  field(func(i int) []*ring.Poly { return wits[i].K1 }),
+	}
+	return pub, wit
+}
+
+// TestCredentialPreSignRNS checks that the credential statement, whose
+// norm bounds the limb-by-limb composition cannot carry over Z, is refused
+// over a multi-limb ring. The fixture shows why: its limbs open unrelated
+// short witnesses, whose CRT is not short.
+func TestCredentialPreSignRNS(t *testing.T) {
+	ringQ := rnsTestRing(t)
+	ncols := testNCols(ringQ)
+	pub, wit := buildPreSignFixture(t, ringQ, ncols, PIOP.CredentialBlocks{})
+	opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+
+	if _, err := PIOP.BuildRNS(ringQ, PIOP.NewCredentialBuilder, pub, wit, opts); !errors.Is(err, PIOP.ErrRNSBound) {
+		t.Fatalf("BuildRNS of a bounded statement: err=%v, want ErrRNSBound", err)
+	}
+	proof := &PIOP.RNSProof{N: ringQ.N, Moduli: ringQ.Modulus, Limbs: make([]*PIOP.Proof, len(ringQ.Modulus))}
+	if ok, err := PIOP.VerifyRNS(ringQ, PIOP.NewCredentialBuilder, pub, proof, opts); ok || !errors.Is(err, PIOP.ErrRNSBound) {
+		t.Fatalf("VerifyRNS of a bounded statement: ok=%v err=%v, want ErrRNSBound", ok, err)
+	}
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+	"vSIS-Signature/prf"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// batchNCols is the per-showing |Ω| of the batch tests: four showings merge
+// into the 32 points of a single showing at testNCols.
+const batchNCols = 8
+
+// buildBatchShowing returns showing s of a batch: its own M2, PRF key and
+// nonce, so no two showings share a witness.
+func buildBatchShowing(t *testing.T, s int) (PIOP.PublicInputs, PIOP.WitnessInputs, PIOP.SimOpts) {
+	t.Helper()
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	key := make([]prf.Elem, params.LenKey)
+	for i := range key {
+		key[i] = prf.Elem(uint64(100*s + i + 1))
+	}
+	nonce := make([]prf.Elem, params.LenNonce)
+	for i := range nonce {
+		nonce[i] = prf.Elem(uint64(100*s + i + 11))
+	}
+	m2 := []*ring.Poly{makePackedHalf(ringQ, batchNCols, int64(2+s), false)}
+	_, pub, wit, opts := buildShowingFixtureSpec(t, showingSpec{m2: m2, key: key, nonce: nonce, ncols: batchNCols})
+	return pub, wit, opts
+}
+
+func TestShowingBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("batch showing proofs take minutes; skipped in short mode")
+	}
+	var (
+		pubs []PIOP.PublicInputs
+		wits []PIOP.WitnessInputs
+		opts PIOP.SimOpts
+	)
+	for s := 0; s < 4; s++ {
+		pub, wit, o := buildBatchShowing(t, s)
+		pubs, wits, opts = append(pubs, pub), append(wits, wit), o
+	}
+
+	proofs := map[int]*PIOP.Proof{}
+	sizes := map[int]int{}
+	for _, k := range []int{1, 2, 4} {
+		proof, err := PIOP.BuildShowingBatch(pubs[:k], wits[:k], opts)
+		if err != nil {
+			t.Fatalf("build batch k=%d: %v", k, err)
+		}
+		if ok, err := PIOP.VerifyShowingBatch(proof, pubs[:k], opts); err != nil || !ok {
+			t.Fatalf("batch k=%d verify failed: ok=%v err=%v", k, ok, err)
+		}
+		proofs[k] = proof
+		sizes[k] = PIOP.MeasureProofSize(proof).Total
+		// The analytic model covers batches too, over the merged Ω.
+		batchOpts := opts
+		batchOpts.NCols = k * opts.NCols
+		est, err := PIOP.EstimateProofSize(batchOpts, proof.MaskRowOffset, batchOpts.NCols)
+		if err != nil {
+			t.Fatalf("estimate k=%d: %v", k, err)
+		}
+		if diff := est.Total - sizes[k]; diff*100 > sizes[k] || -diff*100 > sizes[k] {
+			t.Fatalf("k=%d: estimated %d bytes, measured %d", k, est.Total, sizes[k])
+		}
+	}
+	t.Logf("batch sizes: k=1 %d, k=2 %d, k=4 %d bytes", sizes[1], sizes[2], sizes[4])
+	// Merged showings share every opening, so doubling the batch must stay
+	// within 10% of the smaller batch: size(2k) < 2·size(k)·0.55.
+	for _, k := range []int{1, 2} {
+		if sizes[2*k]*100 >= 2*sizes[k]*55 {
+			t.Fatalf("batch k=%d is %d bytes, not under 1.1× batch k=%d (%d bytes)", 2*k, sizes[2*k], k, sizes[k])
+		}
+	}
+
+	t.Run("wrong-publics", func(t *testing.T) {
+		if ok, err := PIOP.VerifyShowingBatch(proofs[2], pubs[:1], opts); ok || !errors.Is(err, PIOP.ErrParamsID) {
+			t.Fatalf("expected params rejection, got ok=%v err=%v", ok, err)
+		}
+		swapped := []PIOP.PublicInputs{pubs[1], pubs[0]}
+		if ok, err := PIOP.VerifyShowingBatch(proofs[2], swapped, opts); ok || err == nil {
+			t.Fatalf("batch verified against swapped publics: ok=%v err=%v", ok, err)
+		}
+	})
+
+	t.Run("tamper-second-tag", func(t *testing.T) {
+		pub2 := pubs[1]
+		pub2.Tag = make([][]int64, len(pubs[1].Tag))
+		for i := range pubs[1].Tag {
+			pub2.Tag[i] = append([]int64(nil), pubs[1].Tag[i]...)
+		}
+		pub2.Tag[0][0]++
+		bad := []PIOP.PublicInputs{pubs[0], pub2}
+		proof, err := PIOP.BuildShowingBatch(bad, wits[:2], opts)
+		if err != nil {
+			t.Fatalf("build tampered batch: %v", err)
+		}
+		if ok, _ := PIOP.VerifyShowingBatch(proof, bad, opts); ok {
+			t.Fatalf("batch with a wrong tag in showing 1 verified")
+		}
+	})
+}
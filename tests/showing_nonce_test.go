@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/prf"
+)
+
+func TestShowingHiddenNonce(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds several hidden-nonce showing proofs")
+	}
+	ringQ, pub, wit, opts, key := buildKeyFixture(t, nil, nil)
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	// The fixture's nonce lane j is j+11: lane 0 is an epoch in a window of
+	// ±2 around 12, the other lanes are pinned.
+	dom := &PIOP.NonceDomain{Lanes: make([]PIOP.NonceWindow, params.LenNonce)}
+	nonce := make([]prf.Elem, params.LenNonce)
+	for j := range dom.Lanes {
+		dom.Lanes[j] = PIOP.NonceWindow{Center: int64(j + 11)}
+		nonce[j] = prf.Elem(j + 11)
+	}
+	dom.Lanes[0] = PIOP.NonceWindow{Center: 12, Radius: 2}
+	if !dom.Contains(nonce, ringQ.Modulus[0]) {
+		t.Fatal("domain does not contain the fixture nonce")
+	}
+	pub.Nonce, pub.NonceDomain = nil, dom
+
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	set := PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}
+	report, err := PIOP.VerifyWithConstraintsReport(proof, set, pub, opts, PIOP.FSModeCredential)
+	if err != nil {
+		t.Fatalf("verify: %v (failed families %v)", err, report.FailedFamilies())
+	}
+	names := map[string]bool{}
+	for _, f := range report.Families {
+		names[f.Name] = true
+	}
+	if !names["nonce-domain"] || !names["prf-key"] || names["prf.nonce"] {
+		t.Fatalf("families %v: want nonce-domain, prf-key and no prf.nonce", names)
+	}
+
+	t.Run("stripped-domain", func(t *testing.T) {
+		pub2 := pub
+		pub2.NonceDomain = nil
+		if ok, err := PIOP.VerifyWithConstraints(proof, set, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("hidden-nonce showing verified without its domain")
+		}
+	})
+
+	t.Run("outside-domain", func(t *testing.T) {
+		pub2 := pub
+		out := &PIOP.NonceDomain{Lanes: append([]PIOP.NonceWindow(nil), dom.Lanes...)}
+		out.Lanes[0] = PIOP.NonceWindow{Center: 20, Radius: 2}
+		if out.Contains(nonce, ringQ.Modulus[0]) {
+			t.Fatal("shifted domain still contains the nonce")
+		}
+		pub2.NonceDomain = out
+		if ok, err := PIOP.VerifyWithConstraints(proof, set, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("showing verified under another domain")
+		}
+		proof2, err := PIOP.BuildShowingCombined(pub2, wit, opts)
+		if err != nil {
+			return
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof2, PIOP.ConstraintSet{PRFLayout: proof2.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("nonce outside the domain verified")
+		}
+	})
+
+	t.Run("second-key", func(t *testing.T) {
+		// The domain bounds the tags of a credential only with its key fixed:
+		// a second key under the same domain and nonce is refused.
+		other := append([]prf.Elem(nil), key...)
+		other[0] = (other[0] + 1) % prf.Elem(params.Q)
+		_, pub2, wit2, _, _ := buildKeyFixture(t, nil, other)
+		pub2.Nonce, pub2.NonceDomain = nil, dom
+		proof2, err := PIOP.BuildShowingCombined(pub2, wit2, opts)
+		if err != nil {
+			return
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof2, PIOP.ConstraintSet{PRFLayout: proof2.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("second key verified under the same nonce domain")
+		}
+	})
+
+	t.Run("invalid-domain", func(t *testing.T) {
+		pub2 := pub
+		pub2.NonceDomain = &PIOP.NonceDomain{Lanes: dom.Lanes[:1]}
+		if _, err := PIOP.BuildShowingCombined(pub2, wit, opts); err == nil {
+			t.Fatal("domain with too few lanes accepted")
+		}
+		wide := &PIOP.NonceDomain{Lanes: append([]PIOP.NonceWindow(nil), dom.Lanes...)}
+		wide.Lanes[0].Radius = int(pub.BoundB) + 1
+		pub2.NonceDomain = wide
+		if _, err := PIOP.BuildShowingCombined(pub2, wit, opts); err == nil {
+			t.Fatal("radius above the bound accepted")
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+	"vSIS-Signature/prf"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+// buildNymFixture returns a single-signature showing whose M2 block holds a
+// full PRF key, with the PRF trace, tag and nonce of a pseudonym for scope
+// under key (nil for the key M2 holds).
+func buildNymFixture(t *testing.T, scope string, key []prf.Elem) (*ring.Ring, PIOP.PublicInputs, PIOP.WitnessInputs, PIOP.SimOpts, []prf.Elem) {
+	t.Helper()
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	nonce, err := prf.ScopeNonce(scope, params)
+	if err != nil {
+		t.Fatalf("scope nonce: %v", err)
+	}
+	ringQ, pub, wit, opts, held := buildKeyFixture(t, nonce, key)
+	pub.Scope = scope
+	return ringQ, pub, wit, opts, held
+}
+
+// buildKeyFixture is buildShowingFixtureSpec over an M2 block holding a full
+// PRF key, showing under key (nil for the key M2 holds) and nonce (nil for
+// the default). It also returns the key M2 holds.
+func buildKeyFixture(t *testing.T, nonce, key []prf.Elem) (*ring.Ring, PIOP.PublicInputs, PIOP.WitnessInputs, PIOP.SimOpts, []prf.Elem) {
+	t.Helper()
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	half := ncols / 2
+	blocks := PIOP.CredentialBlocks{M1: 1, M2: (params.LenKey + half - 1) / half, RU0: 1, RU1: 1, R: 1}
+
+	q := int64(ringQ.Modulus[0])
+	m2 := make([]*ring.Poly, blocks.M2)
+	for i := range m2 {
+		pNTT := ringQ.NewPoly()
+		for j := 0; j < half; j++ {
+			v := int64((i*half+j)*5%17) - 8
+			pNTT.Coeffs[0][half+j] = uint64((v + q) % q)
+		}
+		m2[i] = ringQ.NewPoly()
+		ringQ.InvNTT(pNTT, m2[i])
+	}
+	held, err := PIOP.PRFKeyFromM2(ringQ, m2, ncols, params.LenKey)
+	if err != nil {
+		t.Fatalf("key from m2: %v", err)
+	}
+	if key == nil {
+		key = held
+	}
+	ringQ, pub, wit, opts := buildShowingFixtureSpec(t, showingSpec{blocks: blocks, m2: m2, key: key, nonce: nonce})
+	return ringQ, pub, wit, opts, held
+}
+
+func TestShowingPseudonym(t *testing.T) {
+	_, pub, wit, opts, key := buildNymFixture(t, "shop.example", nil)
+	params, err := prf.LoadDefaultParams()
+	if err != nil {
+		t.Fatalf("load prf params: %v", err)
+	}
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	set := PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}
+	report, err := PIOP.VerifyWithConstraintsReport(proof, set, pub, opts, PIOP.FSModeCredential)
+	if err != nil {
+		t.Fatalf("verify: %v (failed families %v)", err, report.FailedFamilies())
+	}
+	found := false
+	for _, f := range report.Families {
+		found = found || f.Name == "prf-key"
+	}
+	if !found {
+		t.Fatal("report has no prf-key family")
+	}
+
+	// The pseudonym depends only on the key and the scope.
+	nonce, _ := prf.ScopeNonce("shop.example", params)
+	nym, _ := prf.Tag(key, nonce, params)
+	for i, v := range nym {
+		if pub.Tag[i][0] != int64(v) {
+			t.Fatalf("tag lane %d = %d, want pseudonym %d", i, pub.Tag[i][0], v)
+		}
+	}
+	other, _ := prf.ScopeNonce("bank.example", params)
+	if nym2, _ := prf.Tag(key, other, params); nym2[0] == nym[0] && nym2[1] == nym[1] {
+		t.Fatal("two scopes share a pseudonym")
+	}
+
+	t.Run("other-scope", func(t *testing.T) {
+		pub2 := pub
+		pub2.Scope = "bank.example"
+		if ok, err := PIOP.VerifyWithConstraints(proof, set, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("pseudonym verified under another scope")
+		}
+	})
+
+	t.Run("stripped-scope", func(t *testing.T) {
+		pub2 := pub
+		pub2.Scope = ""
+		if ok, err := PIOP.VerifyWithConstraints(proof, set, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("pseudonym verified as a plain showing")
+		}
+	})
+
+	t.Run("free-key", func(t *testing.T) {
+		other := append([]prf.Elem(nil), key...)
+		other[len(other)-1] = (other[len(other)-1] + 1) % prf.Elem(params.Q)
+		_, pub2, wit2, _, _ := buildNymFixture(t, "shop.example", other)
+		proof, err := PIOP.BuildShowingCombined(pub2, wit2, opts)
+		if err != nil {
+			return
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("pseudonym under a key other than M2's verified")
+		}
+	})
+
+	t.Run("foreign-nonce", func(t *testing.T) {
+		pub2 := pub
+		pub2.Nonce = append([][]int64(nil), pub.Nonce...)
+		pub2.Nonce[0] = buildConstLane(opts.NCols, pub.Nonce[0][0]+1)
+		if _, err := PIOP.BuildShowingCombined(pub2, wit, opts); err == nil {
+			t.Fatal("pseudonym showing with a nonce other than the scope's built")
+		}
+	})
+}
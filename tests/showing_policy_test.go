@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"testing"
+
+	"vSIS-Signature/PIOP"
+)
+
+func TestShowingPolicy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds four policy showing proofs")
+	}
+	_, pub, wit, opts := buildShowingFixture(t)
+	// The fixture's M1 holds 1 on every attribute slot.
+	pub.Policy = &PIOP.ShowingPolicy{
+		Disclosed:  []PIOP.DisclosedAttr{{Attr: 0, Value: 1}, {Attr: 5, Value: 1}},
+		Predicates: []PIOP.AttrPredicate{{Attr: 3, Set: []int64{0, 1, 2}}},
+	}
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	report, err := PIOP.VerifyWithConstraintsReport(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub, opts, PIOP.FSModeCredential)
+	if err != nil {
+		t.Fatalf("verify: %v (failed families %v)", err, report.FailedFamilies())
+	}
+	for _, name := range []string{"disclose", "predicate"} {
+		found := false
+		for _, f := range report.Families {
+			found = found || f.Name == name
+		}
+		if !found {
+			t.Fatalf("report has no %s family", name)
+		}
+	}
+
+	t.Run("stripped-policy", func(t *testing.T) {
+		pub2 := pub
+		pub2.Policy = nil
+		if ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("proof verified without its policy")
+		}
+	})
+
+	t.Run("false-disclosure", func(t *testing.T) {
+		pub2 := pub
+		pub2.Policy = &PIOP.ShowingPolicy{Disclosed: []PIOP.DisclosedAttr{{Attr: 0, Value: 2}}}
+		proof, err := PIOP.BuildShowingCombined(pub2, wit, opts)
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("false disclosure verified")
+		}
+	})
+
+	t.Run("false-predicate", func(t *testing.T) {
+		pub2 := pub
+		pub2.Policy = &PIOP.ShowingPolicy{Predicates: []PIOP.AttrPredicate{{Attr: 3, Set: []int64{-1, 2}}}}
+		proof, err := PIOP.BuildShowingCombined(pub2, wit, opts)
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof, PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("false predicate verified")
+		}
+	})
+
+	t.Run("invalid-policy", func(t *testing.T) {
+		pub2 := pub
+		pub2.Policy = &PIOP.ShowingPolicy{Predicates: []PIOP.AttrPredicate{{Attr: 1, Set: []int64{9}}}}
+		if _, err := PIOP.BuildShowingCombined(pub2, wit, opts); err == nil {
+			t.Fatal("value outside [-B,B] accepted")
+		}
+	})
+}
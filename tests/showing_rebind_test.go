@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/commitment"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+)
+
+func TestShowingRebound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds three re-bound showing proofs")
+	}
+	ringQ, pub, wit, opts := buildShowingFixture(t)
+	// Issuance openings: RU0, RU1 and R are short and nonzero.
+	wit.RU0 = []*ring.Poly{makePolyConst(ringQ, 2)}
+	wit.RU1 = []*ring.Poly{makePolyConst(ringQ, -5)}
+	wit.R = []*ring.Poly{makePolyConst(ringQ, 7)}
+
+	var seed commitment.Seed
+	for i := range seed {
+		seed[i] = byte(i + 1)
+	}
+	Ac, err := commitment.Expand(ringQ, commitment.Params{Height: 2, Width: 5, Seed: seed})
+	if err != nil {
+		t.Fatalf("expand Ac: %v", err)
+	}
+	var vec []*ring.Poly
+	for _, blk := range [][]*ring.Poly{wit.M1, wit.M2, wit.RU0, wit.RU1, wit.R} {
+		for _, p := range blk {
+			vec = append(vec, nttCopy(ringQ, p))
+		}
+	}
+	com, err := commitment.Commit(ringQ, Ac, vec)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	pub.Ac, pub.Com = Ac, com
+
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	set := PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}
+	report, err := PIOP.VerifyWithConstraintsReport(proof, set, pub, opts, PIOP.FSModeCredential)
+	if err != nil {
+		t.Fatalf("verify: %v (failed families %v)", err, report.FailedFamilies())
+	}
+	found := false
+	for _, f := range report.Families {
+		found = found || f.Name == "commit"
+	}
+	if !found {
+		t.Fatal("report has no commit family")
+	}
+
+	t.Run("stripped-commitment", func(t *testing.T) {
+		pub2 := pub
+		pub2.Com = nil
+		if ok, err := PIOP.VerifyWithConstraints(proof, set, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("re-bound showing verified as a plain showing")
+		}
+	})
+
+	t.Run("other-commitment", func(t *testing.T) {
+		pub2 := pub
+		pub2.Com = []*ring.Poly{com[0], com[1].CopyNew()}
+		pub2.Com[1].Coeffs[0][0] = (pub2.Com[1].Coeffs[0][0] + 1) % ringQ.Modulus[0]
+		if ok, err := PIOP.VerifyWithConstraints(proof, set, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("showing verified against another commitment")
+		}
+		proof2, err := PIOP.BuildShowingCombined(pub2, wit, opts)
+		if err != nil {
+			return
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof2, PIOP.ConstraintSet{PRFLayout: proof2.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("showing for a commitment the credential does not open verified")
+		}
+	})
+
+	t.Run("long-opening", func(t *testing.T) {
+		wit2 := wit
+		wit2.R = []*ring.Poly{makePolyConst(ringQ, 9)}
+		vec2 := append(append([]*ring.Poly(nil), vec[:4]...), nttCopy(ringQ, wit2.R[0]))
+		com2, err := commitment.Commit(ringQ, Ac, vec2)
+		if err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+		pub2 := pub
+		pub2.Com = com2
+		proof2, err := PIOP.BuildShowingCombined(pub2, wit2, opts)
+		if err != nil {
+			return
+		}
+		if ok, err := PIOP.VerifyWithConstraints(proof2, PIOP.ConstraintSet{PRFLayout: proof2.PRFLayout}, pub2, opts, PIOP.FSModeCredential); err == nil && ok {
+			t.Fatal("opening outside [-B,B] verified")
+		}
+	})
+}
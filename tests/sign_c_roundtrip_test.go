@@ -15,7 +15,7 @@ func TestCSignVerify_Roundtrip(t *testing.T) {
 		t.Skip("set NTRU_RAND=1 to enable C-style sign/verify roundtrip test")
 	}
 	// Use realistic preset and generate small trapdoor
-	par, opts, err := ntru.PresetPower2_512_Q12289()
+	par, opts, err := ntru.PresetPower2_512_Q1038337()
 	if err != nil {
 		t.Fatalf("Preset: %v", err)
 	}
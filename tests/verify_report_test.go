@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+)
+
+func TestVerifyWithConstraintsReportShowing(t *testing.T) {
+	_, pub, wit, opts := buildShowingFixture(t)
+	proof, err := PIOP.BuildShowingCombined(pub, wit, opts)
+	if err != nil {
+		t.Fatalf("build showing: %v", err)
+	}
+	set := PIOP.ConstraintSet{PRFLayout: proof.PRFLayout}
+	report, err := PIOP.VerifyWithConstraintsReport(proof, set, pub, opts, PIOP.FSModeCredential)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !report.OK {
+		t.Fatalf("report not OK: %+v", report)
+	}
+	if failed := report.FailedFamilies(); len(failed) != 0 {
+		t.Fatalf("honest proof reported failing families %v", failed)
+	}
+	names := map[string]PIOP.FamilyReport{}
+	for _, fam := range report.Families {
+		names[fam.Name] = fam
+	}
+	for _, want := range []string{"signature", "hash", "prf.round[0]", "prf.tag", "bounds"} {
+		fam, ok := names[want]
+		if !ok {
+			t.Fatalf("family %q missing from report", want)
+		}
+		if fam.Checked == 0 {
+			t.Fatalf("family %q was not checked", want)
+		}
+	}
+	if _, ok := names["unclassified"]; ok {
+		t.Fatalf("residual layout does not cover the evaluator output")
+	}
+
+	// Corrupt the committed tag-binding residual: the plain verifier replays
+	// residuals from openings, but the report must point at prf.tag.
+	tagStart, off := -1, 0
+	for _, fam := range report.Families {
+		if fam.Name == "prf.tag" {
+			tagStart = off
+		}
+		off += fam.Constraints
+	}
+	tampered := proof.Snapshot().Restore()
+	for i := range tampered.FparNTT[tagStart] {
+		tampered.FparNTT[tagStart][i]++
+	}
+	report, _ = PIOP.VerifyWithConstraintsReport(tampered, set, pub, opts, PIOP.FSModeCredential)
+	failed := report.FailedFamilies()
+	if len(failed) != 1 || failed[0] != "prf.tag" {
+		t.Fatalf("expected only prf.tag to fail, got %v", failed)
+	}
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+//go:build testonly
+
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"vSIS-Signature/PIOP"
+	"vSIS-Signature/credential"
+)
+
+// TestZKLeakReport compares the openings of real proofs with those of the
+// simulator under one set of programmed challenges. Every simulated proof
+// must verify, and no opened family may tell the two apart.
+func TestZKLeakReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("leak report builds ~80 proofs")
+	}
+	ringQ, err := credential.LoadDefaultRing()
+	if err != nil {
+		t.Fatalf("load ring: %v", err)
+	}
+	ncols := testNCols(ringQ)
+	pub, wit := buildPreSignFixture(t, ringQ, ncols, PIOP.CredentialBlocks{})
+	opts := PIOP.SimOpts{Credential: true, Theta: 2, EllPrime: 1, Rho: 1, NCols: ncols, Ell: 1}
+	ch, err := PIOP.SampleFSChallenges(opts.Kappa, rand.Reader)
+	if err != nil {
+		t.Fatalf("SampleFSChallenges: %v", err)
+	}
+	realOpts := opts
+	realOpts.SetFSOracle(ch.Oracle())
+	b := PIOP.NewCredentialBuilder(realOpts)
+
+	const samples = 40
+	var real, sim []PIOP.OpenedValues
+	for i := 0; i < samples; i++ {
+		proof, err := b.Build(pub, wit, PIOP.MaskConfig{})
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if i == 0 {
+			if ok, err := b.Verify(pub, proof); err != nil || !ok {
+				t.Fatalf("programmed proof rejected: ok=%v err=%v", ok, err)
+			}
+		}
+		v, err := PIOP.OpenedValuesOf(proof)
+		if err != nil {
+			t.Fatalf("OpenedValuesOf(real): %v", err)
+		}
+		real = append(real, v)
+
+		sp, err := PIOP.SimulateProof(context.Background(), pub, opts, ch)
+		if err != nil {
+			t.Fatalf("SimulateProof: %v", err)
+		}
+		if ok, err := b.Verify(pub, sp); err != nil || !ok {
+			t.Fatalf("simulated proof %d rejected: ok=%v err=%v", i, ok, err)
+		}
+		if proof.Tail[0] != sp.Tail[0] {
+			t.Fatalf("simulated proof ignored the programmed tail challenge")
+		}
+		v, err = PIOP.OpenedValuesOf(sp)
+		if err != nil {
+			t.Fatalf("OpenedValuesOf(sim): %v", err)
+		}
+		sim = append(sim, v)
+	}
+	rep, err := PIOP.CompareOpenedValues(ringQ.Modulus[0], real, sim, 16, 1e-4)
+	if err != nil {
+		t.Fatalf("CompareOpenedValues: %v", err)
+	}
+	for _, f := range rep.Families {
+		t.Logf("%-8s coords=%3d chi2=%7.2f df=%2d p=%.3g ranks real=%d sim=%d joint=%d saturated=%v distinguished=%v",
+			f.Family, f.Coords, f.ChiSq, f.DF, f.PValue, f.RealRank, f.BaseRank, f.JointRank, f.Saturated, f.Distinguished)
+		if f.Distinguished {
+			t.Errorf("%s: real and simulated openings distinguishable", f.Family)
+		}
+	}
+}
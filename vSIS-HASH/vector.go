@@ -0,0 +1,149 @@
+package vsishash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/tuneinsight/lattigo/v4/ring"
+	"golang.org/x/crypto/sha3"
+)
+
+// -----------------------------------------------------------------------------
+// Vector-valued inputs
+// -----------------------------------------------------------------------------
+
+// VecLayout gives the number of ring elements hashed for the message and the
+// two masks. With every length 1 the vector hash is ComputeBBSHash.
+//
+// The key is B0…B3 followed by one extra generator per additional block:
+// G_1…G_{Msg−1}, then H_1…H_{X0−1}, then D_1…D_{X1−1}, and
+//
+//	t = (B0 + B1 m_0 + Σ G_i m_i + B2 x0_0 + Σ H_j x0_j) · (B3 − x1_0 − Σ D_k x1_k)⁻¹.
+type VecLayout struct {
+	Msg int
+	X0  int
+	X1  int
+}
+
+// KeyLen returns the number of key polynomials the layout needs.
+func (l VecLayout) KeyLen() int { return 4 + (l.Msg - 1) + (l.X0 - 1) + (l.X1 - 1) }
+
+// MsgKey returns the key index multiplying message block i.
+func (l VecLayout) MsgKey(i int) int {
+	if i == 0 {
+		return 1
+	}
+	return 3 + i
+}
+
+// X0Key returns the key index multiplying x0 block j.
+func (l VecLayout) X0Key(j int) int {
+	if j == 0 {
+		return 2
+	}
+	return 3 + (l.Msg - 1) + j
+}
+
+// X1Key returns the key index multiplying x1 block k ≥ 1 in the denominator
+// (x1_0 enters with coefficient −1).
+func (l VecLayout) X1Key(k int) int { return 3 + (l.Msg - 1) + (l.X0 - 1) + k }
+
+func (l VecLayout) check() error {
+	if l.Msg < 1 || l.X0 < 1 || l.X1 < 1 {
+		return fmt.Errorf("invalid hash layout %+v", l)
+	}
+	return nil
+}
+
+// ExpandB extends B0…B3 (NTT) to the key of layout l. The extra generators
+// are derived from B with SHAKE-256, so they are fixed once B is, and are
+// returned directly as uniform NTT vectors.
+func ExpandB(ringQ *ring.Ring, B []*ring.Poly, l VecLayout) ([]*ring.Poly, error) {
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	if len(B) < 4 {
+		return nil, errors.New("need four B polynomials")
+	}
+	q := ringQ.Modulus[0]
+	seed := sha3.NewShake256()
+	seed.Write([]byte("vSIS-BBS/ExpandB"))
+	var buf [8]byte
+	for _, p := range B[:4] {
+		for _, c := range p.Coeffs[0] {
+			binary.LittleEndian.PutUint64(buf[:], c)
+			seed.Write(buf[:])
+		}
+	}
+	var root [32]byte
+	seed.Read(root[:])
+
+	mask := uint64(1)<<bits.Len64(q-1) - 1
+	out := make([]*ring.Poly, l.KeyLen())
+	copy(out, B[:4])
+	for i := 4; i < len(out); i++ {
+		xof := sha3.NewShake256()
+		xof.Write(root[:])
+		binary.LittleEndian.PutUint64(buf[:], uint64(i))
+		xof.Write(buf[:])
+		p := ringQ.NewPoly()
+		for j := range p.Coeffs[0] {
+			for {
+				xof.Read(buf[:])
+				if v := binary.LittleEndian.Uint64(buf[:]) & mask; v < q {
+					p.Coeffs[0][j] = v
+					break
+				}
+			}
+		}
+		out[i] = p
+	}
+	return out, nil
+}
+
+// ComputeBBSHashVec is ComputeBBSHash for vector-valued inputs: B is the
+// expanded key of VecLayout{len(m), len(x0), len(x1)} in NTT, the inputs are
+// in coefficient domain and are not modified. The result is in NTT form.
+func ComputeBBSHashVec(ringQ *ring.Ring, B []*ring.Poly, m, x0, x1 []*ring.Poly) (*ring.Poly, error) {
+	l := VecLayout{Msg: len(m), X0: len(x0), X1: len(x1)}
+	if err := l.check(); err != nil {
+		return nil, err
+	}
+	if len(B) != l.KeyLen() {
+		return nil, fmt.Errorf("need %d B polynomials, got %d", l.KeyLen(), len(B))
+	}
+	lift := func(p *ring.Poly) *ring.Poly {
+		cp := ringQ.NewPoly()
+		ring.Copy(p, cp)
+		ringQ.NTT(cp, cp)
+		return cp
+	}
+	tmp := ringQ.NewPoly()
+
+	num := ringQ.NewPoly()
+	ring.Copy(B[0], num)
+	for i, p := range m {
+		ringQ.MulCoeffs(B[l.MsgKey(i)], lift(p), tmp)
+		ringQ.Add(num, tmp, num)
+	}
+	for j, p := range x0 {
+		ringQ.MulCoeffs(B[l.X0Key(j)], lift(p), tmp)
+		ringQ.Add(num, tmp, num)
+	}
+
+	den := ringQ.NewPoly()
+	ringQ.Sub(B[3], lift(x1[0]), den)
+	for k := 1; k < len(x1); k++ {
+		ringQ.MulCoeffs(B[l.X1Key(k)], lift(x1[k]), tmp)
+		ringQ.Sub(den, tmp, den)
+	}
+	dInv, ok := polyInverseNTT(ringQ, den)
+	if !ok {
+		return nil, errors.New("denominator not invertible")
+	}
+	t := ringQ.NewPoly()
+	ringQ.MulCoeffs(num, dInv, t)
+	return t, nil
+}
@@ -0,0 +1,109 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// KDFParams are the Argon2id parameters the wallet key is derived with. They
+// are stored next to the ciphertext so a wallet stays readable when the
+// defaults change.
+type KDFParams struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"` // KiB
+	Threads uint8  `json:"threads"`
+}
+
+// DefaultKDF returns the RFC 9106 second recommended Argon2id setting
+// (t=3, 64 MiB, 4 lanes) with a fresh 16-byte salt.
+func DefaultKDF() (KDFParams, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return KDFParams{}, fmt.Errorf("wallet: salt: %w", err)
+	}
+	return KDFParams{Salt: salt, Time: 3, Memory: 64 * 1024, Threads: 4}, nil
+}
+
+// Bounds on stored KDF parameters. They are read before the file is
+// authenticated, so a tampered file must not be able to request a zero-cost
+// derivation or an allocation of gigabytes.
+const (
+	minSaltLen = 16
+	maxMemory  = 1 << 20 // KiB, 1 GiB
+)
+
+// check rejects parameters outside the bounds above with ErrCorrupt.
+func (k KDFParams) check() error {
+	switch {
+	case len(k.Salt) < minSaltLen:
+		return fmt.Errorf("%w: kdf salt length %d, want ≥ %d", ErrCorrupt, len(k.Salt), minSaltLen)
+	case k.Time < 1:
+		return fmt.Errorf("%w: kdf time %d", ErrCorrupt, k.Time)
+	case k.Threads < 1:
+		return fmt.Errorf("%w: kdf threads %d", ErrCorrupt, k.Threads)
+	case k.Memory < 8*uint32(k.Threads) || k.Memory > maxMemory:
+		return fmt.Errorf("%w: kdf memory %d KiB outside [%d, %d]", ErrCorrupt, k.Memory, 8*uint32(k.Threads), maxMemory)
+	}
+	return nil
+}
+
+func (k KDFParams) key(passphrase []byte) ([]byte, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	return argon2.IDKey(passphrase, k.Salt, k.Time, k.Memory, k.Threads, chacha20poly1305.KeySize), nil
+}
+
+// sealedFile is the on-disk wallet: the KDF parameters, the XChaCha20-Poly1305
+// nonce and the encrypted contents. The version and KDF parameters are the
+// associated data, so they cannot be changed without failing decryption.
+type sealedFile struct {
+	Version int       `json:"version"`
+	KDF     KDFParams `json:"kdf"`
+	Nonce   []byte    `json:"nonce"`
+	Data    []byte    `json:"data"`
+}
+
+func (f sealedFile) aad() []byte {
+	aad, _ := json.Marshal(struct {
+		Version int       `json:"version"`
+		KDF     KDFParams `json:"kdf"`
+	}{f.Version, f.KDF})
+	return append([]byte("vSIS-wallet"), aad...)
+}
+
+func seal(key []byte, kdf KDFParams, plain []byte) (sealedFile, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return sealedFile{}, err
+	}
+	f := sealedFile{Version: Version, KDF: kdf, Nonce: make([]byte, aead.NonceSize())}
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return sealedFile{}, fmt.Errorf("wallet: nonce: %w", err)
+	}
+	f.Data = aead.Seal(nil, f.Nonce, plain, f.aad())
+	return f, nil
+}
+
+func (f sealedFile) open(key []byte) ([]byte, error) {
+	if f.Version != Version {
+		return nil, fmt.Errorf("wallet: file version %d, want %d", f.Version, Version)
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: nonce length %d", ErrCorrupt, len(f.Nonce))
+	}
+	plain, err := aead.Open(nil, f.Nonce, f.Data, f.aad())
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return plain, nil
+}
@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoMatch is returned when no stored credential satisfies a query.
+	ErrNoMatch = errors.New("wallet: no credential satisfies the request")
+	// ErrNonceUsed is returned when a nonce was already shown to a verifier.
+	ErrNonceUsed = errors.New("wallet: nonce already used with this verifier")
+	// ErrRateLimit is returned when a context's showing budget is spent.
+	ErrRateLimit = errors.New("wallet: rate limit reached for this verifier")
+)
+
+// Query describes what a verifier's presentation request needs from the
+// wallet. Empty Issuer or Schema match any credential.
+type Query struct {
+	Issuer   string
+	Schema   string
+	Verifier string
+	// Context is the rate-limit scope (an epoch, a session, …); Limit is
+	// the number of showings allowed per context, 0 meaning unlimited.
+	Context string
+	Limit   int
+	// Nonce is the PRF nonce of the showing; a credential that already
+	// used it with Verifier does not match.
+	Nonce []byte
+}
+
+// Usage records the showings made to one verifier.
+type Usage struct {
+	// Counts is the number of showings per rate-limit context.
+	Counts map[string]int `json:"counts,omitempty"`
+	// Nonces are the hex-encoded nonces used, in order.
+	Nonces []string  `json:"nonces,omitempty"`
+	Last   time.Time `json:"last"`
+}
+
+func (u *Usage) usedNonce(nonce []byte) bool {
+	if u == nil || len(nonce) == 0 {
+		return false
+	}
+	h := hex.EncodeToString(nonce)
+	for _, n := range u.Nonces {
+		if n == h {
+			return true
+		}
+	}
+	return false
+}
+
+// check reports why c cannot serve q, or nil.
+func (c *Credential) check(q Query) error {
+	u := c.Usage[q.Verifier]
+	if u.usedNonce(q.Nonce) {
+		return ErrNonceUsed
+	}
+	if q.Limit > 0 && u != nil && u.Counts[q.Context] >= q.Limit {
+		return ErrRateLimit
+	}
+	return nil
+}
+
+// Select returns the oldest credential of the requested issuer and schema
+// that has showing budget left for q.Verifier and has not used q.Nonce
+// with it. If matching credentials exist but all are spent, the error wraps
+// ErrRateLimit or ErrNonceUsed instead of ErrNoMatch.
+func (w *Wallet) Select(q Query) (*Credential, error) {
+	var reason error
+	for _, c := range w.creds {
+		if q.Issuer != "" && c.Issuer != q.Issuer || q.Schema != "" && c.Schema != q.Schema {
+			continue
+		}
+		if err := c.check(q); err != nil {
+			reason = err
+			continue
+		}
+		return c, nil
+	}
+	if reason != nil {
+		return nil, reason
+	}
+	return nil, ErrNoMatch
+}
+
+// RecordShow records a showing of credential id (see Get) under q: the
+// context counter is incremented and the nonce marked as used. It fails,
+// recording nothing, if the showing would exceed the limit or reuse the
+// nonce. Call Save to persist the record.
+func (w *Wallet) RecordShow(id string, q Query) error {
+	c, err := w.Get(id)
+	if err != nil {
+		return err
+	}
+	if q.Verifier == "" {
+		return fmt.Errorf("wallet: showing without a verifier ID")
+	}
+	if err := c.check(q); err != nil {
+		return err
+	}
+	if c.Usage == nil {
+		c.Usage = make(map[string]*Usage)
+	}
+	u := c.Usage[q.Verifier]
+	if u == nil {
+		u = &Usage{}
+		c.Usage[q.Verifier] = u
+	}
+	if u.Counts == nil {
+		u.Counts = make(map[string]int)
+	}
+	u.Counts[q.Context]++
+	if len(q.Nonce) > 0 {
+		u.Nonces = append(u.Nonces, hex.EncodeToString(q.Nonce))
+	}
+	u.Last = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,283 @@
+// Package wallet keeps a holder's credentials from many issuers in one
+// passphrase-encrypted file. Credentials are indexed by issuer and schema,
+// and the wallet records the showings made to each verifier so that
+// rate-limit counters are honoured and no nonce is used twice.
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"vSIS-Signature/credential"
+	"vSIS-Signature/pubparams"
+)
+
+// Version is the wallet file version this package reads and writes.
+const Version = 1
+
+var (
+	// ErrPassphrase is returned when the wallet does not decrypt under the
+	// given passphrase (or its ciphertext was modified).
+	ErrPassphrase = errors.New("wallet: wrong passphrase or modified file")
+	// ErrCorrupt is returned for a wallet file that cannot be parsed.
+	ErrCorrupt = errors.New("wallet: corrupted file")
+	// ErrNotFound is returned when no credential has the given ID.
+	ErrNotFound = errors.New("wallet: credential not found")
+	// ErrExists is returned when importing a credential already stored.
+	ErrExists = errors.New("wallet: credential already stored")
+)
+
+// Credential is one stored credential with its index keys and the showings
+// made with it.
+type Credential struct {
+	ID     string    `json:"id"`
+	Issuer string    `json:"issuer"`
+	Schema string    `json:"schema"`
+	Label  string    `json:"label,omitempty"`
+	Added  time.Time `json:"added"`
+	// State is the holder state of credential.SaveState without the
+	// issuer's private key.
+	State credential.State `json:"state"`
+	// Usage maps a verifier ID to the showings made to it.
+	Usage map[string]*Usage `json:"usage,omitempty"`
+}
+
+// Wallet is an opened wallet file. Changes are kept in memory until Save.
+type Wallet struct {
+	path string
+	kdf  KDFParams
+	key  []byte
+
+	creds    []*Credential
+	byID     map[string]*Credential
+	byIssuer map[string][]*Credential
+	bySchema map[string][]*Credential
+}
+
+// contents is the plaintext of a wallet file.
+type contents struct {
+	Credentials []*Credential `json:"credentials"`
+}
+
+// Create starts an empty wallet at path. It fails if the file exists.
+func Create(path string, passphrase []byte) (*Wallet, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("wallet: %s already exists", path)
+	}
+	kdf, err := DefaultKDF()
+	if err != nil {
+		return nil, err
+	}
+	key, err := kdf.key(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	w := &Wallet{path: path, kdf: kdf, key: key}
+	w.reindex()
+	return w, nil
+}
+
+// Open decrypts the wallet at path.
+func Open(path string, passphrase []byte) (*Wallet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("wallet: %w", err)
+	}
+	var f sealedFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	key, err := f.KDF.key(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := f.open(key)
+	if err != nil {
+		return nil, err
+	}
+	var c contents
+	if err := json.Unmarshal(plain, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	w := &Wallet{path: path, kdf: f.KDF, key: key, creds: c.Credentials}
+	w.reindex()
+	return w, nil
+}
+
+// OpenOrCreate opens the wallet at path, or starts an empty one if there is
+// no file yet.
+func OpenOrCreate(path string, passphrase []byte) (*Wallet, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return Create(path, passphrase)
+	}
+	return Open(path, passphrase)
+}
+
+// Save encrypts the wallet under a fresh nonce and replaces the file.
+func (w *Wallet) Save() error {
+	plain, err := json.Marshal(contents{Credentials: w.creds})
+	if err != nil {
+		return fmt.Errorf("wallet: marshal: %w", err)
+	}
+	f, err := seal(w.key, w.kdf, plain)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o700); err != nil {
+		return err
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("wallet: write: %w", err)
+	}
+	return os.Rename(tmp, w.path)
+}
+
+// Path returns the file the wallet is saved to.
+func (w *Wallet) Path() string { return w.path }
+
+// Import stores st under the given schema (SchemaOf(st) if empty) and label.
+// The issuer's private key, which the issuance tools write into the same
+// state file, is dropped. The ID is derived from the commitment, so the same
+// credential cannot be imported twice.
+func (w *Wallet) Import(st credential.State, schema, label string) (*Credential, error) {
+	issuer, err := IssuerOf(st)
+	if err != nil {
+		return nil, err
+	}
+	if len(st.Com) == 0 || len(st.U) == 0 {
+		return nil, fmt.Errorf("wallet: state has no commitment or signature")
+	}
+	if schema == "" {
+		schema = SchemaOf(st)
+	}
+	id := credentialID(st)
+	if _, ok := w.byID[id]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrExists, id)
+	}
+	st.NTRUPrivate = nil
+	c := &Credential{
+		ID:     id,
+		Issuer: issuer,
+		Schema: schema,
+		Label:  label,
+		Added:  time.Now().UTC(),
+		State:  st,
+	}
+	w.creds = append(w.creds, c)
+	w.reindex()
+	return c, nil
+}
+
+// List returns every credential, oldest first.
+func (w *Wallet) List() []*Credential {
+	return append([]*Credential(nil), w.creds...)
+}
+
+// Get returns the credential whose ID is id or, failing that, the only one
+// whose ID starts with id.
+func (w *Wallet) Get(id string) (*Credential, error) {
+	if c, ok := w.byID[id]; ok {
+		return c, nil
+	}
+	var match *Credential
+	for _, c := range w.creds {
+		if id != "" && strings.HasPrefix(c.ID, id) {
+			if match != nil {
+				return nil, fmt.Errorf("wallet: ID prefix %q is ambiguous", id)
+			}
+			match = c
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return match, nil
+}
+
+// Delete removes a credential (see Get for id) and its usage records.
+func (w *Wallet) Delete(id string) error {
+	c, err := w.Get(id)
+	if err != nil {
+		return err
+	}
+	for i := range w.creds {
+		if w.creds[i] == c {
+			w.creds = append(w.creds[:i], w.creds[i+1:]...)
+			break
+		}
+	}
+	w.reindex()
+	return nil
+}
+
+// ByIssuer returns the credentials of one issuer, oldest first.
+func (w *Wallet) ByIssuer(issuer string) []*Credential {
+	return append([]*Credential(nil), w.byIssuer[issuer]...)
+}
+
+// BySchema returns the credentials of one schema, oldest first.
+func (w *Wallet) BySchema(schema string) []*Credential {
+	return append([]*Credential(nil), w.bySchema[schema]...)
+}
+
+// Issuers returns the issuer IDs in the wallet in sorted order.
+func (w *Wallet) Issuers() []string {
+	out := make([]string, 0, len(w.byIssuer))
+	for k := range w.byIssuer {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (w *Wallet) reindex() {
+	w.byID = make(map[string]*Credential, len(w.creds))
+	w.byIssuer = make(map[string][]*Credential)
+	w.bySchema = make(map[string][]*Credential)
+	for _, c := range w.creds {
+		w.byID[c.ID] = c
+		w.byIssuer[c.Issuer] = append(w.byIssuer[c.Issuer], c)
+		w.bySchema[c.Schema] = append(w.bySchema[c.Schema], c)
+	}
+}
+
+// IssuerOf returns the issuer ID of a credential: the pubparams.KeyDigest of
+// the issuer's NTRU public key in hex, i.e. the key_digest a public-parameter
+// manifest binds for A.
+func IssuerOf(st credential.State) (string, error) {
+	if len(st.NTRUPublic) == 0 || len(st.NTRUPublic[0]) == 0 {
+		return "", fmt.Errorf("wallet: state names no issuer public key")
+	}
+	d := pubparams.KeyDigest(st.NTRUPublic[0])
+	return hex.EncodeToString(d[:]), nil
+}
+
+// SchemaOf names the attribute layout of a credential by its message block
+// lengths, for states imported without an explicit schema.
+func SchemaOf(st credential.State) string {
+	return fmt.Sprintf("vsis/m1=%d/m2=%d", len(st.M1), len(st.M2))
+}
+
+// credentialID hashes the credential commitment.
+func credentialID(st credential.State) string {
+	h := sha256.New()
+	h.Write([]byte("vSIS-wallet/id"))
+	for _, row := range st.Com {
+		b, _ := json.Marshal(row)
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
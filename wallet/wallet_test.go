@@ -0,0 +1,152 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vSIS-Signature/credential"
+)
+
+func testState(seed int64, m2 int) credential.State {
+	h := make([]int64, 8)
+	for i := range h {
+		h[i] = seed + int64(i)
+	}
+	return credential.State{
+		M1:          [][]int64{{1}},
+		M2:          make([][]int64, m2),
+		Com:         [][]int64{{seed, 2, 3}},
+		U:           []int64{4, 5},
+		NTRUPublic:  [][]int64{h},
+		NTRUPrivate: [][]int64{{9, 9}},
+	}
+}
+
+func TestWalletRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	pass := []byte("correct horse")
+	w, err := Create(path, pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := w.Import(testState(1, 1), "", "first")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if a.State.NTRUPrivate != nil {
+		t.Fatal("issuer private key kept")
+	}
+	if _, err := w.Import(testState(1, 1), "", ""); !errors.Is(err, ErrExists) {
+		t.Fatalf("duplicate import: got %v, want ErrExists", err)
+	}
+	b, err := w.Import(testState(2, 2), "passport", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(path)
+	if bytes.Contains(raw, []byte(`"m1"`)) || bytes.Contains(raw, []byte("first")) {
+		t.Fatal("wallet stored in the clear")
+	}
+
+	w, err = Open(path, pass)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if len(w.List()) != 2 || len(w.ByIssuer(a.Issuer)) != 1 || len(w.BySchema("passport")) != 1 {
+		t.Fatalf("index after reopen: %d credentials", len(w.List()))
+	}
+	if c, err := w.Get(b.ID[:6]); err != nil || c.Schema != "passport" {
+		t.Fatalf("get by prefix: %v", err)
+	}
+	if err := w.Delete(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Get(a.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted credential: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := Open(path, []byte("wrong")); !errors.Is(err, ErrPassphrase) {
+		t.Fatalf("wrong passphrase: got %v, want ErrPassphrase", err)
+	}
+	var f sealedFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		t.Fatal(err)
+	}
+	f.KDF.Time++
+	tampered, _ := json.Marshal(f)
+	if err := os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, pass); !errors.Is(err, ErrPassphrase) {
+		t.Fatalf("changed KDF parameters: got %v, want ErrPassphrase", err)
+	}
+
+	// Parameters outside the KDF bounds are refused before any derivation.
+	f.KDF.Time--
+	for name, mut := range map[string]func(*KDFParams){
+		"zero time":    func(k *KDFParams) { k.Time = 0 },
+		"zero threads": func(k *KDFParams) { k.Threads = 0 },
+		"huge memory":  func(k *KDFParams) { k.Memory = 1 << 31 },
+		"tiny memory":  func(k *KDFParams) { k.Memory = 1 },
+		"short salt":   func(k *KDFParams) { k.Salt = k.Salt[:8] },
+	} {
+		g := f
+		mut(&g.KDF)
+		bad, _ := json.Marshal(g)
+		if err := os.WriteFile(path, bad, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(path, pass); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("%s: got %v, want ErrCorrupt", name, err)
+		}
+	}
+}
+
+func TestSelectAndRecordShow(t *testing.T) {
+	w, err := Create(filepath.Join(t.TempDir(), "wallet.json"), []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := w.Import(testState(1, 1), "", "")
+	b, _ := w.Import(testState(1+100, 1), "", "")
+	if _, err := w.Select(Query{Issuer: "unknown"}); !errors.Is(err, ErrNoMatch) {
+		t.Fatalf("unknown issuer: got %v, want ErrNoMatch", err)
+	}
+
+	q := Query{Issuer: a.Issuer, Verifier: "shop", Context: "2026-10", Limit: 2, Nonce: []byte{1}}
+	c, err := w.Select(q)
+	if err != nil || c.ID != a.ID {
+		t.Fatalf("select: %v", err)
+	}
+	if err := w.RecordShow(c.ID, q); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.RecordShow(c.ID, q); !errors.Is(err, ErrNonceUsed) {
+		t.Fatalf("reused nonce: got %v, want ErrNonceUsed", err)
+	}
+	q.Nonce = []byte{2}
+	if err := w.RecordShow(c.ID, q); err != nil {
+		t.Fatal(err)
+	}
+	q.Nonce = []byte{3}
+	if _, err := w.Select(q); !errors.Is(err, ErrRateLimit) {
+		t.Fatalf("spent budget: got %v, want ErrRateLimit", err)
+	}
+	q.Context = "2026-11"
+	if c, err := w.Select(q); err != nil || c.ID != a.ID {
+		t.Fatalf("new context: %v", err)
+	}
+
+	// Budgets are per credential: another issuer's credential is untouched.
+	q.Issuer, q.Context = b.Issuer, "2026-10"
+	if c, err := w.Select(q); err != nil || c.ID != b.ID {
+		t.Fatalf("other credential: %v", err)
+	}
+}